@@ -14,6 +14,7 @@ import (
 	"google.golang.org/api/calendar/v3"
 	"google.golang.org/api/docs/v1"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/drivelabels/v2"
 	"google.golang.org/api/gmail/v1"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
@@ -367,6 +368,15 @@ func buildAPIChecks() []apiCheck {
 				return err
 			}, false),
 
+		makeAPICheck("Drive Labels API", "drivelabels.googleapis.com",
+			func(ctx context.Context, client *http.Client) (*drivelabels.Service, error) {
+				return drivelabels.NewService(ctx, option.WithHTTPClient(client))
+			},
+			func(ctx context.Context, srv *drivelabels.Service) error {
+				_, err := srv.Labels.List().PageSize(1).Fields("labels(id)").Do()
+				return err
+			}, false),
+
 		// 404 means the API is enabled (document doesn't exist, which is expected)
 		makeAPICheck("Google Docs API", "docs.googleapis.com",
 			func(ctx context.Context, client *http.Client) (*docs.Service, error) {
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aliwatters/gsuite-mcp/internal/auth"
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/config"
+	"github.com/aliwatters/gsuite-mcp/internal/drive"
+)
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// runDrive dispatches drive subcommands.
+func runDrive() {
+	if len(os.Args) < 3 || os.Args[2] != "sync" {
+		fmt.Fprintf(os.Stderr, "Usage: %s drive sync <local_dir> <folder_id> [flags]\n", serverName)
+		os.Exit(2)
+	}
+	os.Exit(runDriveSync(os.Args[3:]))
+}
+
+// runDriveSync syncs a local directory with a Drive folder and returns the
+// exit code: 0 on success, 1 if any file failed, 2 on usage or setup errors.
+// The Drive access filter from config.json applies; local_files.allowed_dirs
+// does not, since the user names the directory directly.
+func runDriveSync(args []string) int {
+	fs := flag.NewFlagSet("drive sync", flag.ContinueOnError)
+	var opts drive.SyncOptions
+	var exclude stringList
+	fs.StringVar(&opts.Direction, "direction", drive.SyncPush, "push (local to Drive), pull (Drive to local) or both")
+	fs.StringVar(&opts.Conflict, "conflict", drive.ConflictNewer, "conflict policy: newer, local, remote or skip")
+	fs.BoolVar(&opts.Delete, "delete", false, "propagate deletions instead of restoring missing files")
+	fs.Var(&exclude, "exclude", "glob pattern to skip (repeatable)")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "print the plan without changing anything")
+	fs.StringVar(&opts.StatePath, "state", "", "state file (default: <local_dir>/"+drive.DefaultSyncStateFile+")")
+	fs.StringVar(&opts.Account, "account", "", "account email (default: the default account)")
+	jsonMode := fs.Bool("json", false, "output machine-readable JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s drive sync <local_dir> <folder_id|path> [flags]\n\nFlags:\n", serverName)
+		fs.PrintDefaults()
+	}
+
+	// Flags may come before, between or after the two positional arguments.
+	var positional []string
+	for rest := args; ; {
+		if err := fs.Parse(rest); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		return 2
+	}
+	opts.Exclude = exclude
+	opts.FolderID = common.ExtractGoogleResourceID(positional[1])
+
+	localDir, err := common.ResolveLocalPath(positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	opts.LocalDir = localDir
+	if err := drive.ValidateSyncOptions(&opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	ctx := context.Background()
+	srv, err := newCLIDriveService(ctx, &opts.Account)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if drive.IsDrivePath(opts.FolderID) {
+		folder, err := drive.NewPathResolver(srv).LookupPath(ctx, opts.FolderID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		opts.FolderID = folder.Id
+	}
+
+	report, err := drive.RunSync(ctx, srv, opts)
+	if report == nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if *jsonMode {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	} else {
+		printSyncReport(report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if report.Errors > 0 {
+		return 1
+	}
+	return 0
+}
+
+// newCLIDriveService builds a Drive service for the given account, or for
+// the default account when it is empty, applying the configured Drive
+// access filter. The account actually used is written back.
+func newCLIDriveService(ctx context.Context, account *string) (drive.DriveService, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	if *account == "" {
+		*account = config.GetDefaultEmail()
+		if *account == "" {
+			return nil, fmt.Errorf("no authenticated accounts; run '%s auth' first", serverName)
+		}
+	}
+	mgr, err := auth.NewManager()
+	if err != nil {
+		return nil, err
+	}
+	client, err := mgr.GetClientForEmail(ctx, *account)
+	if err != nil {
+		return nil, fmt.Errorf("account %s: %w", *account, err)
+	}
+	return drive.NewDriveServiceConstructor(common.NewDriveAccessFilter(cfg.DriveAccess))(ctx, client)
+}
+
+// printSyncReport prints a sync plan or result for humans.
+func printSyncReport(report *drive.SyncReport) {
+	verb := "Synced"
+	if report.DryRun {
+		verb = "Plan for"
+	}
+	fmt.Printf("%s %s <-> %s (%s, conflicts: %s)\n", verb, report.LocalDir, report.FolderID, report.Direction, report.Conflict)
+	for _, a := range report.Actions {
+		line := fmt.Sprintf("  %-12s %s", a.Action, a.Path)
+		if a.Reason != "" {
+			line += "  (" + a.Reason + ")"
+		}
+		if a.Error != "" {
+			line += "  ERROR: " + a.Error
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("%d changed, %d unchanged, %d errors\n", len(report.Actions), report.Unchanged, report.Errors)
+}
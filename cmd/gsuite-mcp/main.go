@@ -58,6 +58,9 @@ func main() {
 		case "check":
 			runCheck()
 			return
+		case "drive":
+			runDrive()
+			return
 		case "help", "--help", "-h":
 			printUsage()
 			return
@@ -142,6 +145,7 @@ func initializeApp() error {
 	}
 
 	driveFilter := common.NewDriveAccessFilter(cfg.DriveAccess)
+	driveFilter.SetLookupFactory(drive.NewAccessLookup)
 	if driveFilter != nil && driveFilter.IsActive() {
 		fmt.Fprintf(os.Stderr, "drive access filter active")
 		if cfg.DriveAccess != nil {
@@ -155,10 +159,23 @@ func initializeApp() error {
 		fmt.Fprintln(os.Stderr)
 	}
 
+	var allowedDirs []string
+	if cfg.LocalFiles != nil {
+		allowedDirs = cfg.LocalFiles.AllowedDirs
+	}
+	localDirs, err := common.NewLocalDirPolicy(allowedDirs)
+	if err != nil {
+		return fmt.Errorf("loading config: local_files: %w", err)
+	}
+	if localDirs != nil {
+		fmt.Fprintf(os.Stderr, "local file transfers limited to %v\n", localDirs.Dirs())
+	}
+
 	// Set up shared dependencies for all packages
 	appDeps := &common.Deps{
 		AuthManager:       authManager,
 		DriveAccessFilter: driveFilter,
+		LocalDirs:         localDirs,
 	}
 	// SetDeps retained for backward compatibility with WithDriveAccessCheck/WithLargeContentHint
 	// middleware that may receive nil deps. All handler factories now use explicit passing below.
@@ -178,6 +195,9 @@ func initializeApp() error {
 	slides.InitDefaultSlidesHandlerDeps(appDeps)
 	tasks.InitDefaultTasksHandlerDeps(appDeps)
 
+	// gmail imports calendar, so the meeting brief's Gmail reader is wired here.
+	calendar.DefaultBriefGmailHandlerDeps = common.NewDefaultHandlerDeps(gmail.NewGmailThreadReader, appDeps)
+
 	return nil
 }
 
@@ -191,6 +211,10 @@ Usage:
   %s auth         Authenticate a Google account (opens browser)
   %s accounts     List authenticated accounts
   %s check        Verify setup (config, tokens, API access)
+  %s drive sync <local_dir> <folder_id>
+                  Sync a local directory with a Drive folder
+                  (--direction push|pull|both, --conflict, --delete,
+                  --exclude GLOB, --dry-run, --account, --json)
 
 No configuration required - just authenticate any Google account on demand.
 When tools request an account without credentials, auth flow is triggered automatically.
@@ -215,7 +239,7 @@ Environment variables:
                             local JSON files (default behaviour).
 
 For more information, see README.md
-`, serverName, serverName, serverName, serverName, serverName, serverName,
+`, serverName, serverName, serverName, serverName, serverName, serverName, serverName,
 		config.DefaultConfigDir(),
 		config.DefaultConfigDir(), config.ConfigPath(), config.CredentialsDir(), config.ClientSecretPath(),
 		config.DefaultOAuthPort)
@@ -386,7 +410,11 @@ func registerCitationIfEnabled(s *server.MCPServer) {
 		}
 	}
 
-	citation.InitDefaultDeps(citCfg)
+	var driveFilter *common.DriveAccessFilter
+	if d := common.GetDeps(); d != nil {
+		driveFilter = d.DriveAccessFilter
+	}
+	citation.InitDefaultDeps(citCfg, driveFilter)
 	citation.RegisterTools(s)
 
 	// Enable citation hints on large content responses
@@ -104,8 +104,10 @@ type successPageData struct {
 // | Identity     | openid, userinfo.email                                    | Determine the authenticated email        |
 // | Gmail        | gmail.modify, gmail.compose, gmail.labels                 | Read/write messages, manage labels       |
 // |              | gmail.settings.basic                                      | Manage filters, send-as, vacation        |
+// |              | gmail.settings.sharing                                    | Manage forwarding, delegates             |
 // | Calendar     | calendar, calendar.events                                 | Full calendar and event access           |
 // | Drive        | drive                                                     | Read/write files and metadata            |
+// |              | drive.labels.readonly                                     | Read Drive label definitions             |
 // | Docs         | documents                                                 | Read/write Google Docs                   |
 // | Sheets       | spreadsheets                                              | Read/write Google Sheets                 |
 // | Slides       | presentations                                             | Read/write Google Slides                 |
@@ -122,6 +124,7 @@ var ScopesByService = map[string][]string{
 		"https://www.googleapis.com/auth/gmail.compose",
 		"https://www.googleapis.com/auth/gmail.labels",
 		"https://www.googleapis.com/auth/gmail.settings.basic",
+		"https://www.googleapis.com/auth/gmail.settings.sharing",
 	},
 	"calendar": {
 		"https://www.googleapis.com/auth/calendar",
@@ -129,6 +132,7 @@ var ScopesByService = map[string][]string{
 	},
 	"drive": {
 		"https://www.googleapis.com/auth/drive",
+		"https://www.googleapis.com/auth/drive.labels.readonly",
 	},
 	"docs": {
 		"https://www.googleapis.com/auth/documents",
@@ -162,6 +166,7 @@ var DefaultScopes = []string{
 	"https://www.googleapis.com/auth/gmail.compose",
 	"https://www.googleapis.com/auth/gmail.labels",
 	"https://www.googleapis.com/auth/gmail.settings.basic",
+	"https://www.googleapis.com/auth/gmail.settings.sharing",
 	// Calendar scopes
 	"https://www.googleapis.com/auth/calendar",
 	"https://www.googleapis.com/auth/calendar.events",
@@ -171,6 +176,7 @@ var DefaultScopes = []string{
 	"https://www.googleapis.com/auth/tasks",
 	// Drive scopes (broad access needed for citation feature and file operations)
 	"https://www.googleapis.com/auth/drive",
+	"https://www.googleapis.com/auth/drive.labels.readonly",
 	// Sheets scopes
 	"https://www.googleapis.com/auth/spreadsheets",
 	// Slides scopes
@@ -0,0 +1,159 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/drive"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/calendar/v3"
+	gdrive "google.golang.org/api/drive/v3"
+)
+
+const (
+	// maxEventAttachments is the most attachments Calendar keeps on an event.
+	maxEventAttachments = 25
+	// attachmentFileFields are the Drive fields needed to build an attachment.
+	attachmentFileFields = "id,name,mimeType,iconLink,webViewLink"
+)
+
+// attachmentRoles are the Drive roles that can be granted on attachments.
+var attachmentRoles = map[string]bool{"reader": true, "commenter": true, "writer": true}
+
+// AttachmentDriveHandlerDeps overrides the Drive deps used to resolve event
+// attachments. Nil falls back to drive.DefaultDriveHandlerDeps.
+var AttachmentDriveHandlerDeps *drive.DriveHandlerDeps
+
+// eventAttachments is the result of resolving the attachments argument.
+type eventAttachments struct {
+	driveSrv    drive.DriveService
+	attachments []*calendar.EventAttachment
+	share       bool
+	role        string
+}
+
+// resolveEventAttachments looks up the Drive files named in the attachments
+// argument (file IDs or URLs). It returns nil when the argument is absent; an
+// empty array yields an empty list, which clears attachments on update.
+func resolveEventAttachments(ctx context.Context, request mcp.CallToolRequest) (*eventAttachments, *mcp.CallToolResult) {
+	args := request.GetArguments()
+	raw, ok := args["attachments"].([]any)
+	if !ok {
+		return nil, nil
+	}
+	if len(raw) > maxEventAttachments {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Too many attachments: %d (maximum %d)", len(raw), maxEventAttachments))
+	}
+
+	result := &eventAttachments{
+		attachments: make([]*calendar.EventAttachment, 0, len(raw)),
+		share:       common.ParseBoolArg(args, "share_attachments", false),
+		role:        common.ParseStringArg(args, "attachment_role", "reader"),
+	}
+	if !attachmentRoles[result.role] {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid attachment_role %q: must be 'reader', 'commenter' or 'writer'", result.role))
+	}
+	if len(raw) == 0 {
+		return result, nil
+	}
+
+	driveSrv, errResult, ok := drive.ResolveDriveServiceOrError(ctx, request, AttachmentDriveHandlerDeps)
+	if !ok {
+		return nil, errResult
+	}
+	result.driveSrv = driveSrv
+
+	seen := make(map[string]bool)
+	for _, v := range raw {
+		s, _ := v.(string)
+		fileID := common.ExtractGoogleResourceID(strings.TrimSpace(s))
+		if fileID == "" || seen[fileID] {
+			continue
+		}
+		seen[fileID] = true
+		file, err := driveSrv.GetFile(ctx, fileID, attachmentFileFields)
+		if err != nil {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("Drive API error: cannot attach %s: %v", fileID, err))
+		}
+		result.attachments = append(result.attachments, &calendar.EventAttachment{
+			FileId:   file.Id,
+			FileUrl:  file.WebViewLink,
+			Title:    file.Name,
+			MimeType: file.MimeType,
+			IconLink: file.IconLink,
+		})
+	}
+	return result, nil
+}
+
+// grantAttachmentAccess shares each attachment with the event's attendees who
+// cannot already open it. Access through "anyone" or a matching "domain"
+// permission counts; rooms and the caller are skipped. Invitations already
+// reach the attendees, so Drive does not send its own notification.
+func (a *eventAttachments) grantAttachmentAccess(ctx context.Context, attendees []*calendar.EventAttendee) []map[string]any {
+	results := make([]map[string]any, 0, len(a.attachments))
+	for _, att := range a.attachments {
+		entry := map[string]any{"file_id": att.FileId, "title": att.Title}
+		perms, err := a.driveSrv.ListPermissions(ctx, att.FileId)
+		if err != nil {
+			entry["error"] = fmt.Sprintf("listing permissions: %v", err)
+			results = append(results, entry)
+			continue
+		}
+
+		var granted, failed []string
+		for _, attendee := range attendees {
+			if attendee.Email == "" || attendee.Self || attendee.Resource || hasDriveAccess(perms.Permissions, attendee.Email) {
+				continue
+			}
+			perm := &gdrive.Permission{Type: "user", Role: a.role, EmailAddress: attendee.Email}
+			if _, err := a.driveSrv.CreatePermission(ctx, att.FileId, perm, false); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", attendee.Email, err))
+				continue
+			}
+			granted = append(granted, attendee.Email)
+		}
+		entry["granted"] = granted
+		if len(failed) > 0 {
+			entry["errors"] = failed
+		}
+		results = append(results, entry)
+	}
+	return results
+}
+
+// addAttachmentResults adds the saved event's attachments to result and, when
+// share_attachments was set, grants attendees access and reports what changed.
+func addAttachmentResults(ctx context.Context, result map[string]any, event *calendar.Event, attachments *eventAttachments) {
+	if len(event.Attachments) > 0 {
+		result["attachments"] = formatAttachments(event.Attachments)
+	}
+	if attachments != nil && attachments.share && len(attachments.attachments) > 0 {
+		result["attachment_access"] = attachments.grantAttachmentAccess(ctx, event.Attendees)
+	}
+}
+
+// hasDriveAccess reports whether any permission already lets email open a file.
+func hasDriveAccess(perms []*gdrive.Permission, email string) bool {
+	domain := ""
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		domain = email[at+1:]
+	}
+	for _, p := range perms {
+		switch p.Type {
+		case "anyone":
+			return true
+		case "domain":
+			if strings.EqualFold(p.Domain, domain) {
+				return true
+			}
+		case "user", "group":
+			if strings.EqualFold(p.EmailAddress, email) {
+				return true
+			}
+		}
+	}
+	return false
+}
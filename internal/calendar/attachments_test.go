@@ -0,0 +1,146 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/drive"
+	"google.golang.org/api/calendar/v3"
+	gdrive "google.golang.org/api/drive/v3"
+)
+
+// useAttachmentDrive routes attachment lookups to a Drive mock holding files.
+func useAttachmentDrive(t *testing.T, files map[string]*gdrive.File, perms map[string][]*gdrive.Permission) *[]string {
+	t.Helper()
+	fixtures := drive.NewDriveTestFixtures()
+	var granted []string
+	fixtures.MockService.GetFileFunc = func(_ context.Context, fileID string, _ string) (*gdrive.File, error) {
+		if f, ok := files[fileID]; ok {
+			return f, nil
+		}
+		return nil, errors.New("file not found")
+	}
+	fixtures.MockService.ListPermissionsFunc = func(_ context.Context, fileID string) (*gdrive.PermissionList, error) {
+		return &gdrive.PermissionList{Permissions: perms[fileID]}, nil
+	}
+	fixtures.MockService.CreatePermissionFunc = func(_ context.Context, fileID string, p *gdrive.Permission, notify bool) (*gdrive.Permission, error) {
+		if notify {
+			t.Error("attachment grants should not send Drive notifications")
+		}
+		granted = append(granted, fileID+":"+p.Role+":"+p.EmailAddress)
+		return p, nil
+	}
+	AttachmentDriveHandlerDeps = fixtures.Deps
+	t.Cleanup(func() { AttachmentDriveHandlerDeps = nil })
+	return &granted
+}
+
+func TestCalendarCreateEvent_Attachments(t *testing.T) {
+	granted := useAttachmentDrive(t, map[string]*gdrive.File{
+		"doc-1": {Id: "doc-1", Name: "Agenda", MimeType: "application/vnd.google-apps.document", IconLink: "https://icon/doc", WebViewLink: "https://docs.google.com/document/d/doc-1/edit"},
+		"pdf-2": {Id: "pdf-2", Name: "Budget.pdf", MimeType: "application/pdf", WebViewLink: "https://drive.google.com/file/d/pdf-2/view"},
+	}, map[string][]*gdrive.Permission{
+		"doc-1": {{Type: "user", Role: "owner", EmailAddress: "test@example.com"}, {Type: "user", Role: "reader", EmailAddress: "bob@partner.com"}},
+		"pdf-2": {{Type: "domain", Role: "reader", Domain: "example.com"}},
+	})
+	fixtures := NewCalendarTestFixtures()
+
+	data := runCalendarTool(t, fixtures, TestableCalendarCreateEvent, map[string]any{
+		"summary":           "Budget review",
+		"start_time":        "2026-03-02T10:00:00Z",
+		"attendees":         []any{"alice@example.com", "bob@partner.com", "carol@partner.com"},
+		"attachments":       []any{"doc-1", "https://drive.google.com/file/d/pdf-2/view", "doc-1"},
+		"share_attachments": true,
+		"attachment_role":   "commenter",
+	})
+
+	attachments, _ := data["attachments"].([]any)
+	if len(attachments) != 2 {
+		t.Fatalf("expected 2 attachments, got %v", data["attachments"])
+	}
+	first := attachments[0].(map[string]any)
+	if first["title"] != "Agenda" || first["file_id"] != "doc-1" || first["icon_link"] != "https://icon/doc" || first["file_url"] != "https://docs.google.com/document/d/doc-1/edit" {
+		t.Errorf("unexpected attachment: %v", first)
+	}
+
+	// Bob can already open the doc and Alice's domain the PDF; everyone
+	// else is granted access to each file.
+	want := []string{
+		"doc-1:commenter:alice@example.com",
+		"doc-1:commenter:carol@partner.com",
+		"pdf-2:commenter:bob@partner.com",
+		"pdf-2:commenter:carol@partner.com",
+	}
+	if strings.Join(*granted, ",") != strings.Join(want, ",") {
+		t.Errorf("granted = %v, want %v", *granted, want)
+	}
+	if access, _ := data["attachment_access"].([]any); len(access) != 2 {
+		t.Errorf("expected per-file access results, got %v", data["attachment_access"])
+	}
+}
+
+func TestCalendarUpdateEvent_Attachments(t *testing.T) {
+	granted := useAttachmentDrive(t, map[string]*gdrive.File{
+		"doc-1": {Id: "doc-1", Name: "Agenda", WebViewLink: "https://docs.google.com/document/d/doc-1/edit"},
+	}, nil)
+	fixtures := NewCalendarTestFixtures()
+	event := fixtures.MockService.Events["primary"]["event001"]
+	event.Attachments = []*calendar.EventAttachment{{FileId: "old", Title: "Old notes", FileUrl: "https://old"}}
+
+	data := runCalendarTool(t, fixtures, TestableCalendarUpdateEvent, map[string]any{
+		"event_id":    "event001",
+		"attachments": []any{"doc-1"},
+	})
+	if attachments, _ := data["attachments"].([]any); len(attachments) != 1 || attachments[0].(map[string]any)["title"] != "Agenda" {
+		t.Errorf("expected attachments replaced, got %v", data["attachments"])
+	}
+	if len(*granted) != 0 || data["attachment_access"] != nil {
+		t.Error("access should only be granted when share_attachments is set")
+	}
+
+	runCalendarTool(t, fixtures, TestableCalendarUpdateEvent, map[string]any{"event_id": "event001", "attachments": []any{}})
+	if got := fixtures.MockService.Events["primary"]["event001"].Attachments; len(got) != 0 {
+		t.Errorf("expected attachments cleared, got %v", got)
+	}
+
+	get := runCalendarTool(t, fixtures, TestableCalendarGetEvent, map[string]any{"event_id": "event002"})
+	if _, ok := get["attachments"]; ok {
+		t.Errorf("did not expect attachments on an event without any: %v", get)
+	}
+}
+
+func TestCalendarCreateEvent_AttachmentErrors(t *testing.T) {
+	useAttachmentDrive(t, nil, nil)
+	fixtures := NewCalendarTestFixtures()
+
+	tooMany := make([]any, maxEventAttachments+1)
+	for i := range tooMany {
+		tooMany[i] = "f"
+	}
+	for _, tc := range []struct {
+		args       map[string]any
+		errContain string
+	}{
+		{map[string]any{"attachments": []any{"missing"}}, "cannot attach missing"},
+		{map[string]any{"attachments": []any{"x"}, "attachment_role": "owner"}, "Invalid attachment_role"},
+		{map[string]any{"attachments": tooMany}, "Too many attachments"},
+	} {
+		tc.args["summary"] = "X"
+		tc.args["start_time"] = "2026-03-02T10:00:00Z"
+		result, _ := TestableCalendarCreateEvent(context.Background(), CreateMCPRequest(tc.args), fixtures.Deps)
+		if !result.IsError || !strings.Contains(getCalendarTextContent(result), tc.errContain) {
+			t.Errorf("expected error containing %q, got %s", tc.errContain, getCalendarTextContent(result))
+		}
+	}
+}
+
+func TestFormatEventFull_Attachments(t *testing.T) {
+	event := createTestEvent("e1", "Review", "", "2026-03-02T10:00:00Z", "2026-03-02T11:00:00Z", false)
+	event.Attachments = []*calendar.EventAttachment{{FileId: "doc-1", Title: "Agenda", FileUrl: "https://docs.google.com/document/d/doc-1/edit", MimeType: "application/vnd.google-apps.document"}}
+	attachments, _ := formatEventFull(event)["attachments"].([]map[string]any)
+	if len(attachments) != 1 || attachments[0]["mime_type"] != "application/vnd.google-apps.document" {
+		t.Errorf("unexpected attachments: %v", attachments)
+	}
+}
@@ -26,6 +26,9 @@ func NewCalendarService(ctx context.Context, client *http.Client) (CalendarServi
 // InitDefaultCalendarHandlerDeps initializes the default Calendar handler deps with explicit deps,
 // avoiding reliance on the global singleton at call time.
 func InitDefaultCalendarHandlerDeps(appDeps *common.Deps) {
+	if appDeps != nil {
+		icsDirs = appDeps.LocalDirs
+	}
 	DefaultCalendarHandlerDeps = common.NewDefaultHandlerDeps(NewCalendarService, appDeps)
 }
 
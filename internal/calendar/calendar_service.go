@@ -12,14 +12,29 @@ import (
 type CalendarService interface {
 	// Calendars
 	ListCalendars(ctx context.Context, fields string) (*calendar.CalendarList, error)
+	CreateCalendar(ctx context.Context, cal *calendar.Calendar) (*calendar.Calendar, error)
+	PatchCalendar(ctx context.Context, calendarID string, cal *calendar.Calendar) (*calendar.Calendar, error)
+	DeleteCalendar(ctx context.Context, calendarID string) error
+
+	// Calendar list (subscriptions)
+	InsertCalendarListEntry(ctx context.Context, entry *calendar.CalendarListEntry) (*calendar.CalendarListEntry, error)
+	DeleteCalendarListEntry(ctx context.Context, calendarID string) error
+
+	// ACL
+	ListACL(ctx context.Context, calendarID string, pageToken string) (*calendar.Acl, error)
+	InsertACL(ctx context.Context, calendarID string, rule *calendar.AclRule, sendNotifications bool) (*calendar.AclRule, error)
+	PatchACL(ctx context.Context, calendarID string, ruleID string, rule *calendar.AclRule) (*calendar.AclRule, error)
+	DeleteACL(ctx context.Context, calendarID string, ruleID string) error
 
 	// Events
 	ListEvents(ctx context.Context, calendarID string, opts *ListEventsOptions) (*calendar.Events, error)
 	GetEvent(ctx context.Context, calendarID string, eventID string, fields string) (*calendar.Event, error)
 	CreateEvent(ctx context.Context, calendarID string, event *calendar.Event, conferenceDataVersion int) (*calendar.Event, error)
 	UpdateEvent(ctx context.Context, calendarID string, eventID string, event *calendar.Event) (*calendar.Event, error)
+	PatchEvent(ctx context.Context, calendarID string, eventID string, event *calendar.Event) (*calendar.Event, error)
 	DeleteEvent(ctx context.Context, calendarID string, eventID string) error
 	QuickAddEvent(ctx context.Context, calendarID string, text string) (*calendar.Event, error)
+	ImportEvent(ctx context.Context, calendarID string, event *calendar.Event) (*calendar.Event, error)
 
 	// Recurring Events
 	ListInstances(ctx context.Context, calendarID string, eventID string, opts *ListInstancesOptions) (*calendar.Events, error)
@@ -41,15 +56,17 @@ type ListEventsOptions struct {
 	UpdatedMin   string   // RFC3339 timestamp
 	Fields       string   // Selector specifying which fields to include in a partial response
 	EventTypes   []string // Filter by event types (e.g., "default", "focusTime", "outOfOffice")
+	ICalUID      string   // Only return events with this iCalendar UID (series master and exceptions)
 }
 
 // ListInstancesOptions contains optional parameters for listing recurring event instances.
 type ListInstancesOptions struct {
-	MaxResults int64
-	PageToken  string
-	TimeMin    string // RFC3339 timestamp
-	TimeMax    string // RFC3339 timestamp
-	Fields     string // Selector specifying which fields to include in a partial response
+	MaxResults  int64
+	PageToken   string
+	TimeMin     string // RFC3339 timestamp
+	TimeMax     string // RFC3339 timestamp
+	Fields      string // Selector specifying which fields to include in a partial response
+	ShowDeleted bool   // Include cancelled instances
 }
 
 // RealCalendarService wraps the Calendar API client and implements CalendarService.
@@ -71,6 +88,55 @@ func (s *RealCalendarService) ListCalendars(ctx context.Context, fields string)
 	return call.Do()
 }
 
+// CreateCalendar creates a secondary calendar owned by the user.
+func (s *RealCalendarService) CreateCalendar(ctx context.Context, cal *calendar.Calendar) (*calendar.Calendar, error) {
+	return s.service.Calendars.Insert(cal).Context(ctx).Do()
+}
+
+// PatchCalendar updates only the calendar metadata fields set on cal.
+func (s *RealCalendarService) PatchCalendar(ctx context.Context, calendarID string, cal *calendar.Calendar) (*calendar.Calendar, error) {
+	return s.service.Calendars.Patch(calendarID, cal).Context(ctx).Do()
+}
+
+// DeleteCalendar permanently deletes a secondary calendar and its events.
+func (s *RealCalendarService) DeleteCalendar(ctx context.Context, calendarID string) error {
+	return s.service.Calendars.Delete(calendarID).Context(ctx).Do()
+}
+
+// InsertCalendarListEntry adds an existing calendar to the user's calendar list.
+func (s *RealCalendarService) InsertCalendarListEntry(ctx context.Context, entry *calendar.CalendarListEntry) (*calendar.CalendarListEntry, error) {
+	return s.service.CalendarList.Insert(entry).Context(ctx).Do()
+}
+
+// DeleteCalendarListEntry removes a calendar from the user's calendar list.
+func (s *RealCalendarService) DeleteCalendarListEntry(ctx context.Context, calendarID string) error {
+	return s.service.CalendarList.Delete(calendarID).Context(ctx).Do()
+}
+
+// ListACL lists the access control rules of a calendar.
+func (s *RealCalendarService) ListACL(ctx context.Context, calendarID string, pageToken string) (*calendar.Acl, error) {
+	call := s.service.Acl.List(calendarID).Context(ctx)
+	if pageToken != "" {
+		call = call.PageToken(pageToken)
+	}
+	return call.Do()
+}
+
+// InsertACL creates an access control rule on a calendar.
+func (s *RealCalendarService) InsertACL(ctx context.Context, calendarID string, rule *calendar.AclRule, sendNotifications bool) (*calendar.AclRule, error) {
+	return s.service.Acl.Insert(calendarID, rule).SendNotifications(sendNotifications).Context(ctx).Do()
+}
+
+// PatchACL updates an existing access control rule.
+func (s *RealCalendarService) PatchACL(ctx context.Context, calendarID string, ruleID string, rule *calendar.AclRule) (*calendar.AclRule, error) {
+	return s.service.Acl.Patch(calendarID, ruleID, rule).Context(ctx).Do()
+}
+
+// DeleteACL deletes an access control rule.
+func (s *RealCalendarService) DeleteACL(ctx context.Context, calendarID string, ruleID string) error {
+	return s.service.Acl.Delete(calendarID, ruleID).Context(ctx).Do()
+}
+
 // ListEvents lists events from a calendar with optional filters.
 func (s *RealCalendarService) ListEvents(ctx context.Context, calendarID string, opts *ListEventsOptions) (*calendar.Events, error) {
 	call := s.service.Events.List(calendarID).Context(ctx)
@@ -109,6 +175,9 @@ func (s *RealCalendarService) ListEvents(ctx context.Context, calendarID string,
 		if len(opts.EventTypes) > 0 {
 			call = call.EventTypes(opts.EventTypes...)
 		}
+		if opts.ICalUID != "" {
+			call = call.ICalUID(opts.ICalUID)
+		}
 	}
 
 	return call.Do()
@@ -125,7 +194,7 @@ func (s *RealCalendarService) GetEvent(ctx context.Context, calendarID string, e
 
 // CreateEvent creates a new calendar event.
 func (s *RealCalendarService) CreateEvent(ctx context.Context, calendarID string, event *calendar.Event, conferenceDataVersion int) (*calendar.Event, error) {
-	call := s.service.Events.Insert(calendarID, event).Context(ctx)
+	call := s.service.Events.Insert(calendarID, event).Context(ctx).SupportsAttachments(true)
 	if conferenceDataVersion > 0 {
 		call = call.ConferenceDataVersion(int64(conferenceDataVersion))
 	}
@@ -134,7 +203,12 @@ func (s *RealCalendarService) CreateEvent(ctx context.Context, calendarID string
 
 // UpdateEvent updates an existing calendar event.
 func (s *RealCalendarService) UpdateEvent(ctx context.Context, calendarID string, eventID string, event *calendar.Event) (*calendar.Event, error) {
-	return s.service.Events.Update(calendarID, eventID, event).Context(ctx).Do()
+	return s.service.Events.Update(calendarID, eventID, event).Context(ctx).SupportsAttachments(true).Do()
+}
+
+// PatchEvent updates only the fields set on event, leaving the rest of the stored event untouched.
+func (s *RealCalendarService) PatchEvent(ctx context.Context, calendarID string, eventID string, event *calendar.Event) (*calendar.Event, error) {
+	return s.service.Events.Patch(calendarID, eventID, event).Context(ctx).SupportsAttachments(true).Do()
 }
 
 // DeleteEvent deletes a calendar event.
@@ -147,6 +221,12 @@ func (s *RealCalendarService) QuickAddEvent(ctx context.Context, calendarID stri
 	return s.service.Events.QuickAdd(calendarID, text).Context(ctx).Do()
 }
 
+// ImportEvent imports a private copy of an event, creating it or updating the
+// existing event with the same iCalUID (and originalStartTime, for exceptions).
+func (s *RealCalendarService) ImportEvent(ctx context.Context, calendarID string, event *calendar.Event) (*calendar.Event, error) {
+	return s.service.Events.Import(calendarID, event).Context(ctx).SupportsAttachments(true).Do()
+}
+
 // ListInstances lists instances of a recurring event.
 func (s *RealCalendarService) ListInstances(ctx context.Context, calendarID string, eventID string, opts *ListInstancesOptions) (*calendar.Events, error) {
 	call := s.service.Events.Instances(calendarID, eventID).Context(ctx)
@@ -167,6 +247,9 @@ func (s *RealCalendarService) ListInstances(ctx context.Context, calendarID stri
 		if opts.Fields != "" {
 			call = call.Fields(googleapi.Field(opts.Fields))
 		}
+		if opts.ShowDeleted {
+			call = call.ShowDeleted(true)
+		}
 	}
 
 	return call.Do()
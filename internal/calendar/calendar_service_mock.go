@@ -3,6 +3,10 @@ package calendar
 import (
 	"context"
 	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"google.golang.org/api/calendar/v3"
@@ -16,12 +20,21 @@ type MockCalendarService struct {
 	// Storage
 	Calendars map[string]*calendar.CalendarListEntry
 	Events    map[string]map[string]*calendar.Event // calendarID -> eventID -> event
+	FreeBusy  map[string][]*calendar.TimePeriod     // calendarID -> busy periods
+	// FreeBusyErrors makes GetFreeBusy report an error reason for a calendar.
+	FreeBusyErrors map[string]string
+	ACL            map[string]map[string]*calendar.AclRule // calendarID -> ruleID -> rule
+	// EventsNextPageToken is returned by every ListEvents call, so callers
+	// that page see an endless listing.
+	EventsNextPageToken string
 
 	// Error injection for testing error handling
 	Error error
 
-	// Track method calls for verification
+	// Track method calls for verification. Handlers may call the mock from
+	// several goroutines, so read them through Calls.
 	MethodCalls []MethodCall
+	callsMu     sync.Mutex
 }
 
 // NewMockCalendarService creates a new mock Calendar service with empty storage.
@@ -29,13 +42,24 @@ func NewMockCalendarService() *MockCalendarService {
 	return &MockCalendarService{
 		Calendars: make(map[string]*calendar.CalendarListEntry),
 		Events:    make(map[string]map[string]*calendar.Event),
+		FreeBusy:  make(map[string][]*calendar.TimePeriod),
+		ACL:       make(map[string]map[string]*calendar.AclRule),
 	}
 }
 
 func (m *MockCalendarService) recordCall(method string, args ...any) {
+	m.callsMu.Lock()
+	defer m.callsMu.Unlock()
 	m.MethodCalls = append(m.MethodCalls, MethodCall{Method: method, Args: args})
 }
 
+// Calls returns a copy of the method calls recorded so far.
+func (m *MockCalendarService) Calls() []MethodCall {
+	m.callsMu.Lock()
+	defer m.callsMu.Unlock()
+	return slices.Clone(m.MethodCalls)
+}
+
 // ListCalendars returns all calendars.
 func (m *MockCalendarService) ListCalendars(ctx context.Context, fields string) (*calendar.CalendarList, error) {
 	m.recordCall("ListCalendars", fields)
@@ -51,6 +75,162 @@ func (m *MockCalendarService) ListCalendars(ctx context.Context, fields string)
 	return &calendar.CalendarList{Items: items}, nil
 }
 
+// CreateCalendar creates a calendar and adds it to the calendar list, as the API does.
+func (m *MockCalendarService) CreateCalendar(ctx context.Context, cal *calendar.Calendar) (*calendar.Calendar, error) {
+	m.recordCall("CreateCalendar", cal.Summary)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+
+	eventIDCounter++
+	cal.Id = "cal-" + string(rune('a'+eventIDCounter-1)) + "@group.calendar.google.com"
+	m.Calendars[cal.Id] = &calendar.CalendarListEntry{
+		Id:          cal.Id,
+		Summary:     cal.Summary,
+		Description: cal.Description,
+		Location:    cal.Location,
+		TimeZone:    cal.TimeZone,
+		AccessRole:  "owner",
+	}
+	m.Events[cal.Id] = make(map[string]*calendar.Event)
+	m.ACL[cal.Id] = map[string]*calendar.AclRule{
+		"user:" + common.TestEmail: {Id: "user:" + common.TestEmail, Role: "owner", Scope: &calendar.AclRuleScope{Type: "user", Value: common.TestEmail}},
+	}
+	return cal, nil
+}
+
+// PatchCalendar merges the non-empty metadata fields of cal into the calendar.
+func (m *MockCalendarService) PatchCalendar(ctx context.Context, calendarID string, cal *calendar.Calendar) (*calendar.Calendar, error) {
+	m.recordCall("PatchCalendar", calendarID, cal)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+
+	entry, ok := m.Calendars[calendarID]
+	if !ok {
+		return nil, errors.New("calendar not found")
+	}
+	if cal.Summary != "" {
+		entry.Summary = cal.Summary
+	}
+	if cal.Description != "" {
+		entry.Description = cal.Description
+	}
+	if cal.Location != "" {
+		entry.Location = cal.Location
+	}
+	if cal.TimeZone != "" {
+		entry.TimeZone = cal.TimeZone
+	}
+	return &calendar.Calendar{Id: calendarID, Summary: entry.Summary, Description: entry.Description, Location: entry.Location, TimeZone: entry.TimeZone}, nil
+}
+
+// DeleteCalendar removes a calendar with its events and rules.
+func (m *MockCalendarService) DeleteCalendar(ctx context.Context, calendarID string) error {
+	m.recordCall("DeleteCalendar", calendarID)
+	if m.Error != nil {
+		return m.Error
+	}
+	if _, ok := m.Calendars[calendarID]; !ok {
+		return errors.New("calendar not found")
+	}
+	delete(m.Calendars, calendarID)
+	delete(m.Events, calendarID)
+	delete(m.ACL, calendarID)
+	return nil
+}
+
+// InsertCalendarListEntry subscribes to a calendar. Unknown calendars are
+// added with reader access.
+func (m *MockCalendarService) InsertCalendarListEntry(ctx context.Context, entry *calendar.CalendarListEntry) (*calendar.CalendarListEntry, error) {
+	m.recordCall("InsertCalendarListEntry", entry.Id)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	if existing, ok := m.Calendars[entry.Id]; ok {
+		return existing, nil
+	}
+	if entry.Summary == "" {
+		entry.Summary = entry.Id
+	}
+	if entry.AccessRole == "" {
+		entry.AccessRole = "reader"
+	}
+	m.Calendars[entry.Id] = entry
+	return entry, nil
+}
+
+// DeleteCalendarListEntry unsubscribes from a calendar.
+func (m *MockCalendarService) DeleteCalendarListEntry(ctx context.Context, calendarID string) error {
+	m.recordCall("DeleteCalendarListEntry", calendarID)
+	if m.Error != nil {
+		return m.Error
+	}
+	if _, ok := m.Calendars[calendarID]; !ok {
+		return errors.New("calendar not found")
+	}
+	delete(m.Calendars, calendarID)
+	return nil
+}
+
+// ListACL returns a calendar's rules sorted by ID.
+func (m *MockCalendarService) ListACL(ctx context.Context, calendarID string, pageToken string) (*calendar.Acl, error) {
+	m.recordCall("ListACL", calendarID, pageToken)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	items := make([]*calendar.AclRule, 0, len(m.ACL[calendarID]))
+	for _, rule := range m.ACL[calendarID] {
+		items = append(items, rule)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Id < items[j].Id })
+	return &calendar.Acl{Items: items}, nil
+}
+
+// InsertACL stores a rule keyed "<scope type>:<scope value>", like the API.
+func (m *MockCalendarService) InsertACL(ctx context.Context, calendarID string, rule *calendar.AclRule, sendNotifications bool) (*calendar.AclRule, error) {
+	m.recordCall("InsertACL", calendarID, rule, sendNotifications)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	if m.ACL[calendarID] == nil {
+		m.ACL[calendarID] = make(map[string]*calendar.AclRule)
+	}
+	rule.Id = rule.Scope.Type
+	if rule.Scope.Value != "" {
+		rule.Id += ":" + rule.Scope.Value
+	}
+	m.ACL[calendarID][rule.Id] = rule
+	return rule, nil
+}
+
+// PatchACL updates a rule's role.
+func (m *MockCalendarService) PatchACL(ctx context.Context, calendarID string, ruleID string, rule *calendar.AclRule) (*calendar.AclRule, error) {
+	m.recordCall("PatchACL", calendarID, ruleID, rule)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	existing, ok := m.ACL[calendarID][ruleID]
+	if !ok {
+		return nil, errors.New("rule not found")
+	}
+	existing.Role = rule.Role
+	return existing, nil
+}
+
+// DeleteACL removes a rule.
+func (m *MockCalendarService) DeleteACL(ctx context.Context, calendarID string, ruleID string) error {
+	m.recordCall("DeleteACL", calendarID, ruleID)
+	if m.Error != nil {
+		return m.Error
+	}
+	if _, ok := m.ACL[calendarID][ruleID]; !ok {
+		return errors.New("rule not found")
+	}
+	delete(m.ACL[calendarID], ruleID)
+	return nil
+}
+
 // ListEvents returns events from a calendar.
 func (m *MockCalendarService) ListEvents(ctx context.Context, calendarID string, opts *ListEventsOptions) (*calendar.Events, error) {
 	m.recordCall("ListEvents", calendarID, opts)
@@ -65,10 +245,22 @@ func (m *MockCalendarService) ListEvents(ctx context.Context, calendarID string,
 
 	items := make([]*calendar.Event, 0, len(calEvents))
 	for _, event := range calEvents {
+		if opts != nil && opts.ICalUID != "" && event.ICalUID != opts.ICalUID {
+			continue
+		}
+		if opts != nil && len(opts.EventTypes) > 0 {
+			eventType := event.EventType
+			if eventType == "" {
+				eventType = "default"
+			}
+			if !slices.Contains(opts.EventTypes, eventType) {
+				continue
+			}
+		}
 		items = append(items, event)
 	}
 
-	return &calendar.Events{Items: items}, nil
+	return &calendar.Events{Items: items, NextPageToken: m.EventsNextPageToken}, nil
 }
 
 // GetEvent returns an event by ID.
@@ -138,6 +330,55 @@ func (m *MockCalendarService) UpdateEvent(ctx context.Context, calendarID string
 	return event, nil
 }
 
+// PatchEvent merges the non-empty fields of patch into an existing event.
+// With AttendeesOmitted set, attendees are matched by email and only their
+// response is changed, mirroring how the API treats a partial attendee list.
+func (m *MockCalendarService) PatchEvent(ctx context.Context, calendarID string, eventID string, patch *calendar.Event) (*calendar.Event, error) {
+	m.recordCall("PatchEvent", calendarID, eventID, patch)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+
+	event, ok := m.Events[calendarID][eventID]
+	if !ok {
+		return nil, errors.New("event not found")
+	}
+
+	if patch.Summary != "" {
+		event.Summary = patch.Summary
+	}
+	if patch.Description != "" {
+		event.Description = patch.Description
+	}
+	if patch.Location != "" {
+		event.Location = patch.Location
+	}
+	if patch.Start != nil {
+		event.Start = patch.Start
+	}
+	if patch.End != nil {
+		event.End = patch.End
+	}
+	if patch.Recurrence != nil {
+		event.Recurrence = patch.Recurrence
+	}
+	if patch.AttendeesOmitted {
+		for _, pa := range patch.Attendees {
+			for _, a := range event.Attendees {
+				if strings.EqualFold(a.Email, pa.Email) {
+					a.ResponseStatus = pa.ResponseStatus
+					a.Comment = pa.Comment
+				}
+			}
+		}
+	} else if patch.Attendees != nil {
+		event.Attendees = patch.Attendees
+	}
+	event.Updated = "2024-01-01T13:00:00Z"
+
+	return event, nil
+}
+
 // DeleteEvent deletes an event.
 func (m *MockCalendarService) DeleteEvent(ctx context.Context, calendarID string, eventID string) error {
 	m.recordCall("DeleteEvent", calendarID, eventID)
@@ -189,12 +430,59 @@ func (m *MockCalendarService) ListInstances(ctx context.Context, calendarID stri
 		return nil, errors.New("event not found")
 	}
 
-	// For mock purposes, return the parent event as an instance
-	items := []*calendar.Event{event}
+	// Return stored instances of the series; without any, return the parent
+	// event as its only instance.
+	var items []*calendar.Event
+	for _, ev := range calEvents {
+		if ev.RecurringEventId == eventID {
+			items = append(items, ev)
+		}
+	}
+	if len(items) == 0 {
+		items = []*calendar.Event{event}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Id < items[j].Id })
 
 	return &calendar.Events{Items: items}, nil
 }
 
+// ImportEvent upserts an event keyed by iCalUID and, for exceptions, originalStartTime.
+func (m *MockCalendarService) ImportEvent(ctx context.Context, calendarID string, event *calendar.Event) (*calendar.Event, error) {
+	m.recordCall("ImportEvent", calendarID, event)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	if event.ICalUID == "" {
+		return nil, errors.New("missing iCalUID")
+	}
+	if m.Events[calendarID] == nil {
+		m.Events[calendarID] = make(map[string]*calendar.Event)
+	}
+
+	originalStart := func(e *calendar.Event) string {
+		if e.OriginalStartTime == nil {
+			return ""
+		}
+		return e.OriginalStartTime.DateTime + e.OriginalStartTime.Date
+	}
+	for id, existing := range m.Events[calendarID] {
+		if existing.ICalUID == event.ICalUID && originalStart(existing) == originalStart(event) {
+			event.Id = id
+			event.Created = existing.Created
+			event.Updated = "2024-01-02T12:00:00Z"
+			m.Events[calendarID][id] = event
+			return event, nil
+		}
+	}
+
+	eventIDCounter++
+	event.Id = "import-" + string(rune('a'+eventIDCounter-1)) + "123"
+	event.Created = "2024-01-01T12:00:00Z"
+	event.Updated = "2024-01-01T12:00:00Z"
+	m.Events[calendarID][event.Id] = event
+	return event, nil
+}
+
 // GetFreeBusy queries free/busy information.
 func (m *MockCalendarService) GetFreeBusy(ctx context.Context, req *calendar.FreeBusyRequest) (*calendar.FreeBusyResponse, error) {
 	m.recordCall("GetFreeBusy", req.TimeMin, req.TimeMax)
@@ -204,8 +492,16 @@ func (m *MockCalendarService) GetFreeBusy(ctx context.Context, req *calendar.Fre
 
 	calendars := make(map[string]calendar.FreeBusyCalendar)
 	for _, item := range req.Items {
+		busy := m.FreeBusy[item.Id]
+		if busy == nil {
+			busy = []*calendar.TimePeriod{}
+		}
+		if reason, ok := m.FreeBusyErrors[item.Id]; ok {
+			calendars[item.Id] = calendar.FreeBusyCalendar{Errors: []*calendar.Error{{Domain: "global", Reason: reason}}}
+			continue
+		}
 		calendars[item.Id] = calendar.FreeBusyCalendar{
-			Busy: []*calendar.TimePeriod{},
+			Busy: busy,
 		}
 	}
 
@@ -1,6 +1,7 @@
 package calendar
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -17,7 +18,7 @@ const (
 	// CalendarEventListFields contains fields for event listings (compact format)
 	CalendarEventListFields = "nextPageToken,items(id,summary,status,start,end,location,colorId,htmlLink,hangoutLink,eventType)"
 	// CalendarEventGetFields contains fields for single event retrieval (full format)
-	CalendarEventGetFields = "id,summary,status,description,location,colorId,start,end,htmlLink,created,updated,creator,organizer,attendees,reminders,recurrence,recurringEventId,hangoutLink,conferenceData,eventType"
+	CalendarEventGetFields = "id,summary,status,description,location,colorId,start,end,htmlLink,created,updated,creator,organizer,attendees,reminders,recurrence,recurringEventId,hangoutLink,conferenceData,eventType,attachments"
 	// CalendarListFields contains fields for calendar list
 	CalendarListFields = "items(id,summary,description,primary,backgroundColor,accessRole,timeZone)"
 )
@@ -59,6 +60,94 @@ func updateEventTimes(event *calendar.Event, args map[string]any) *mcp.CallToolR
 	return nil
 }
 
+// resolveTimeArgs rewrites natural time expressions in args[startKey] and
+// args[endKey] ("tomorrow 3pm", "next tuesday 10:00-11:30") to RFC3339. They
+// are resolved in the zone the expression names, else the timezone argument,
+// else the calendar's own time zone, and an end without a date falls on the
+// start's day. A range or whole day given as the start also fills an absent
+// end. Expressions naming a day without a time of day are rejected unless
+// allowDates is set.
+//
+// It returns the rewritten arguments and, when any expression was resolved,
+// a summary to echo in the result; RFC3339 values pass through unchanged.
+func resolveTimeArgs(ctx context.Context, srv CalendarService, calendarID string, args map[string]any, startKey, endKey string, allowDates bool) (map[string]any, map[string]any, *mcp.CallToolResult) {
+	startExpr := common.ParseStringArg(args, startKey, "")
+	endExpr := common.ParseStringArg(args, endKey, "")
+	if isRFC3339OrEmpty(startExpr) && isRFC3339OrEmpty(endExpr) {
+		return args, nil, nil
+	}
+
+	tz := common.ParseStringArg(args, "timezone", "")
+	if tz == "" {
+		tz = calendarTimeZone(ctx, srv, calendarID)
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("Invalid timezone %q: %v", tz, err))
+	}
+
+	resolved := make(map[string]any, len(args)+1)
+	for k, v := range args {
+		resolved[k] = v
+	}
+	inputs := map[string]string{}
+	echo := map[string]any{"timezone": tz, "inputs": inputs}
+
+	ref := time.Now().In(loc)
+	if startExpr != "" {
+		r, errResult := resolveTimeArg(startKey, startExpr, ref, allowDates)
+		if errResult != nil {
+			return nil, nil, errResult
+		}
+		resolved[startKey] = r.Start.Format(time.RFC3339)
+		ref = r.Start.In(loc)
+		if !isRFC3339OrEmpty(startExpr) {
+			ref = r.Start
+			inputs[startKey] = startExpr
+			echo[startKey] = resolved[startKey]
+			if endExpr == "" && !r.End.IsZero() {
+				resolved[endKey] = r.End.Format(time.RFC3339)
+				echo[endKey] = resolved[endKey]
+			}
+		}
+	}
+	if !isRFC3339OrEmpty(endExpr) {
+		r, errResult := resolveTimeArg(endKey, endExpr, ref, allowDates)
+		if errResult != nil {
+			return nil, nil, errResult
+		}
+		end := r.Start
+		if !r.End.IsZero() {
+			end = r.End
+		}
+		inputs[endKey] = endExpr
+		resolved[endKey] = end.Format(time.RFC3339)
+		echo[endKey] = resolved[endKey]
+	}
+	return resolved, echo, nil
+}
+
+// resolveTimeArg parses one time expression argument relative to ref.
+func resolveTimeArg(key, expr string, ref time.Time, allowDates bool) (resolvedTime, *mcp.CallToolResult) {
+	r, err := parseTimeExpression(expr, ref)
+	if err != nil {
+		return resolvedTime{}, mcp.NewToolResultError(fmt.Sprintf("Invalid %s: %v", key, err))
+	}
+	if r.DateOnly && !allowDates {
+		return resolvedTime{}, mcp.NewToolResultError(fmt.Sprintf("Invalid %s: %q names a day but no time of day", key, expr))
+	}
+	return r, nil
+}
+
+// isRFC3339OrEmpty reports whether s needs no time expression parsing.
+func isRFC3339OrEmpty(s string) bool {
+	if s == "" {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339, s)
+	return err == nil
+}
+
 // setNewEventTimes sets start/end times on a new event from request arguments.
 // Requires start_time. Branches on all_day: all-day uses Date fields with same-day default,
 // timed uses DateTime fields with +1 hour default. Applies timezone to both Start and End.
@@ -178,8 +267,26 @@ var (
 	HandleCalendarListCalendars   = common.WrapHandler[CalendarService](TestableCalendarListCalendars)
 	HandleCalendarQuickAdd        = common.WrapHandler[CalendarService](TestableCalendarQuickAdd)
 	HandleCalendarFreeBusy        = common.WrapHandler[CalendarService](TestableCalendarFreeBusy)
+	HandleCalendarFindSlots       = common.WrapHandler[CalendarService](TestableCalendarFindSlots)
 	HandleCalendarListInstances   = common.WrapHandler[CalendarService](TestableCalendarListInstances)
 	HandleCalendarUpdateInstance  = common.WrapHandler[CalendarService](TestableCalendarUpdateInstance)
+	HandleCalendarListExceptions  = common.WrapHandler[CalendarService](TestableCalendarListExceptions)
+	HandleCalendarRespond         = common.WrapHandler[CalendarService](TestableCalendarRespond)
+	HandleCalendarListPending     = common.WrapHandler[CalendarService](TestableCalendarListPendingInvites)
 	HandleCalendarCreateFocusTime = common.WrapHandler[CalendarService](TestableCalendarCreateFocusTime)
 	HandleCalendarCreateOOO       = common.WrapHandler[CalendarService](TestableCalendarCreateOutOfOffice)
+	HandleCalendarSetWorkingLoc   = common.WrapHandler[CalendarService](TestableCalendarSetWorkingLocation)
+	HandleCalendarGetWorkingLocs  = common.WrapHandler[CalendarService](TestableCalendarGetWorkingLocations)
+	HandleCalendarExportICS       = common.WrapHandler[CalendarService](TestableCalendarExportICS)
+	HandleCalendarImportICS       = common.WrapHandler[CalendarService](TestableCalendarImportICS)
+	HandleCalendarCreateCalendar  = common.WrapHandler[CalendarService](TestableCalendarCreateCalendar)
+	HandleCalendarUpdateCalendar  = common.WrapHandler[CalendarService](TestableCalendarUpdateCalendar)
+	HandleCalendarDeleteCalendar  = common.WrapHandler[CalendarService](TestableCalendarDeleteCalendar)
+	HandleCalendarListACL         = common.WrapHandler[CalendarService](TestableCalendarListACL)
+	HandleCalendarShare           = common.WrapHandler[CalendarService](TestableCalendarShare)
+	HandleCalendarUnshare         = common.WrapHandler[CalendarService](TestableCalendarUnshare)
+	HandleCalendarSubscribe       = common.WrapHandler[CalendarService](TestableCalendarSubscribe)
+	HandleCalendarUnsubscribe     = common.WrapHandler[CalendarService](TestableCalendarUnsubscribe)
+	HandleCalendarAgenda          = common.WrapHandler[CalendarService](TestableCalendarAgenda)
+	HandleCalendarMeetingBrief    = common.WrapHandler[CalendarService](TestableCalendarMeetingBrief)
 )
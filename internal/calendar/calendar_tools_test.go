@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -412,6 +413,129 @@ func TestCalendarCreateEvent(t *testing.T) {
 	}
 }
 
+func TestCalendarCreateEvent_TimeExpression(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	fixtures.MockService.Calendars["primary"].TimeZone = "America/New_York"
+
+	// The range is resolved in the calendar's zone, after the DST change.
+	data := runCalendarTool(t, fixtures, TestableCalendarCreateEvent, map[string]any{
+		"summary":    "Planning",
+		"start_time": "2026-11-03 10:00-11:30",
+	})
+	if data["start"] != "2026-11-03T10:00:00-05:00" || data["end"] != "2026-11-03T11:30:00-05:00" {
+		t.Errorf("unexpected times: %v - %v", data["start"], data["end"])
+	}
+	resolved, _ := data["resolved_times"].(map[string]any)
+	if resolved["timezone"] != "America/New_York" || resolved["start_time"] != "2026-11-03T10:00:00-05:00" {
+		t.Errorf("expected resolved times echoed, got %v", resolved)
+	}
+	if inputs, _ := resolved["inputs"].(map[string]any); inputs["start_time"] != "2026-11-03 10:00-11:30" {
+		t.Errorf("expected input echoed, got %v", resolved["inputs"])
+	}
+
+	// An explicit zone wins, and an end time of day falls on the start's day.
+	data = runCalendarTool(t, fixtures, TestableCalendarCreateEvent, map[string]any{
+		"summary":    "Berlin sync",
+		"start_time": "2026-11-02 09:00 Europe/Berlin",
+		"end_time":   "9:45",
+	})
+	if data["start"] != "2026-11-02T09:00:00+01:00" || data["end"] != "2026-11-02T09:45:00+01:00" {
+		t.Errorf("unexpected times: %v - %v", data["start"], data["end"])
+	}
+
+	// RFC3339 input is passed through without an echo.
+	data = runCalendarTool(t, fixtures, TestableCalendarCreateEvent, map[string]any{
+		"summary":    "Plain",
+		"start_time": "2026-11-02T09:00:00Z",
+	})
+	if _, ok := data["resolved_times"]; ok {
+		t.Errorf("did not expect resolved_times for RFC3339 input: %v", data)
+	}
+
+	// A whole day is an all-day event's exclusive range.
+	data = runCalendarTool(t, fixtures, TestableCalendarCreateEvent, map[string]any{
+		"summary":    "Offsite",
+		"start_time": "2026-11-02",
+		"all_day":    true,
+	})
+	if data["start"] != "2026-11-02" || data["end"] != "2026-11-03" {
+		t.Errorf("unexpected all-day range: %v - %v", data["start"], data["end"])
+	}
+
+	result, _ := TestableCalendarCreateEvent(context.Background(), CreateMCPRequest(map[string]any{
+		"summary":    "Vague",
+		"start_time": "2026-11-02",
+	}), fixtures.Deps)
+	if !result.IsError || !strings.Contains(getCalendarTextContent(result), "no time of day") {
+		t.Errorf("expected error for a timed event without a time, got %s", getCalendarTextContent(result))
+	}
+}
+
+func TestCalendarListEvents_TimeExpression(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	fixtures.MockService.Calendars["primary"].TimeZone = "Europe/London"
+
+	data := runCalendarTool(t, fixtures, TestableCalendarListEvents, map[string]any{"time_min": "2026-03-30"})
+	resolved, _ := data["resolved_times"].(map[string]any)
+	if resolved["time_min"] != "2026-03-30T00:00:00+01:00" || resolved["time_max"] != "2026-03-31T00:00:00+01:00" {
+		t.Errorf("expected the whole day, got %v", resolved)
+	}
+	for _, call := range fixtures.MockService.Calls() {
+		if call.Method == "ListEvents" {
+			opts := call.Args[1].(*ListEventsOptions)
+			if opts.TimeMin != "2026-03-30T00:00:00+01:00" || opts.TimeMax != "2026-03-31T00:00:00+01:00" {
+				t.Errorf("unexpected query range: %s - %s", opts.TimeMin, opts.TimeMax)
+			}
+		}
+	}
+}
+
+func TestCalendarTimeExpression_RangeTools(t *testing.T) {
+	const day, next = "2026-03-30T00:00:00+01:00", "2026-03-31T00:00:00+01:00"
+	tests := []struct {
+		name     string
+		tool     calendarTool
+		args     map[string]any
+		min, max string
+	}{
+		{"find slots", TestableCalendarFindSlots, map[string]any{"duration_minutes": float64(30), "window_start": "2026-03-30"}, "window_start", "window_end"},
+		{"pending invites", TestableCalendarListPendingInvites, map[string]any{"time_min": "2026-03-30"}, "time_min", "time_max"},
+		{"export ics", TestableCalendarExportICS, map[string]any{"time_min": "2026-03-30", "output_path": filepath.Join(t.TempDir(), "out.ics")}, "time_min", "time_max"},
+		{"agenda", TestableCalendarAgenda, map[string]any{"accounts": []any{"me@example.com"}, "time_min": "2026-03-30"}, "time_min", "time_max"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fixtures := NewCalendarTestFixtures()
+			fixtures.MockService.Calendars["primary"].TimeZone = "Europe/London"
+
+			data := runCalendarTool(t, fixtures, tc.tool, tc.args)
+			resolved, _ := data["resolved_times"].(map[string]any)
+			if resolved[tc.min] != day || resolved[tc.max] != next {
+				t.Errorf("expected the whole day, got %v", resolved)
+			}
+		})
+	}
+}
+
+func TestCalendarUpdateInstance_TimeExpression(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	addWeeklySeries(fixtures.MockService)
+
+	data := runCalendarTool(t, fixtures, TestableCalendarUpdateInstance, map[string]any{
+		"instance_id": "weekly_20240311T140000Z",
+		"scope":       "following",
+		"start_time":  "2024-03-11 11:00-11:30",
+		"timezone":    "America/New_York",
+	})
+	newSeries, _ := data["new_series"].(map[string]any)
+	if newSeries["start"] != "2024-03-11T11:00:00-04:00" || newSeries["end"] != "2024-03-11T11:30:00-04:00" {
+		t.Errorf("unexpected new series times: %v - %v", newSeries["start"], newSeries["end"])
+	}
+	if resolved, _ := data["resolved_times"].(map[string]any); resolved["end_time"] != "2024-03-11T11:30:00-04:00" {
+		t.Errorf("expected resolved times echoed, got %v", data["resolved_times"])
+	}
+}
+
 func TestCalendarCreateEventWithColorID(t *testing.T) {
 	fixtures := NewCalendarTestFixtures()
 
@@ -1049,14 +1173,14 @@ func TestMockCalendarService(t *testing.T) {
 		_, _ = mock.ListEvents(context.Background(), "primary", nil)
 		_, _ = mock.GetEvent(context.Background(), "primary", "event1", "")
 
-		if len(mock.MethodCalls) != 2 {
-			t.Errorf("expected 2 method calls, got %d", len(mock.MethodCalls))
+		if len(mock.Calls()) != 2 {
+			t.Errorf("expected 2 method calls, got %d", len(mock.Calls()))
 		}
-		if mock.MethodCalls[0].Method != "ListEvents" {
-			t.Errorf("expected first call to be ListEvents, got %s", mock.MethodCalls[0].Method)
+		if mock.Calls()[0].Method != "ListEvents" {
+			t.Errorf("expected first call to be ListEvents, got %s", mock.Calls()[0].Method)
 		}
-		if mock.MethodCalls[1].Method != "GetEvent" {
-			t.Errorf("expected second call to be GetEvent, got %s", mock.MethodCalls[1].Method)
+		if mock.Calls()[1].Method != "GetEvent" {
+			t.Errorf("expected second call to be GetEvent, got %s", mock.Calls()[1].Method)
 		}
 	})
 
@@ -154,5 +154,31 @@ func formatEventFull(event *calendar.Event) map[string]any {
 		result["conference_data"] = confData
 	}
 
+	if len(event.Attachments) > 0 {
+		result["attachments"] = formatAttachments(event.Attachments)
+	}
+
+	return result
+}
+
+// formatAttachments extracts the Drive file details of event attachments.
+func formatAttachments(attachments []*calendar.EventAttachment) []map[string]any {
+	result := make([]map[string]any, 0, len(attachments))
+	for _, a := range attachments {
+		attachment := map[string]any{
+			"title":    a.Title,
+			"file_url": a.FileUrl,
+		}
+		if a.FileId != "" {
+			attachment["file_id"] = a.FileId
+		}
+		if a.MimeType != "" {
+			attachment["mime_type"] = a.MimeType
+		}
+		if a.IconLink != "" {
+			attachment["icon_link"] = a.IconLink
+		}
+		result = append(result, attachment)
+	}
 	return result
 }
@@ -0,0 +1,400 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICSProperty is a single iCalendar content line (RFC 5545 §3.1), e.g.
+// "DTSTART;TZID=Europe/Berlin:20261102T090000". Parameter names are upper-cased;
+// parameter values and the property value are stored unquoted but not unescaped.
+type ICSProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Param returns the named parameter value, or "" when absent.
+func (p *ICSProperty) Param(name string) string {
+	if p == nil || p.Params == nil {
+		return ""
+	}
+	return p.Params[strings.ToUpper(name)]
+}
+
+// Text returns the property value with RFC 5545 TEXT escapes (\n, \, \; \\) decoded.
+func (p *ICSProperty) Text() string {
+	if p == nil {
+		return ""
+	}
+	return unescapeICSText(p.Value)
+}
+
+// String formats the property as an unfolded content line. Parameters are
+// written in name order and quoted when their value contains ':', ';' or ','.
+func (p *ICSProperty) String() string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+	names := make([]string, 0, len(p.Params))
+	for name := range p.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.ReplaceAll(p.Params[name], `"`, "'")
+		b.WriteString(";" + name + "=")
+		if strings.ContainsAny(value, ":;,") {
+			b.WriteString(`"` + value + `"`)
+		} else {
+			b.WriteString(value)
+		}
+	}
+	b.WriteString(":" + p.Value)
+	return b.String()
+}
+
+// ICSComponent is a BEGIN:<NAME> … END:<NAME> block with its properties and
+// nested components (VCALENDAR → VEVENT → VALARM, VTIMEZONE → STANDARD, …).
+type ICSComponent struct {
+	Name       string
+	Properties []*ICSProperty
+	Components []*ICSComponent
+}
+
+// Get returns the first property with the given name, or nil.
+func (c *ICSComponent) Get(name string) *ICSProperty {
+	name = strings.ToUpper(name)
+	for _, p := range c.Properties {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// GetAll returns every property with the given name, in document order.
+func (c *ICSComponent) GetAll(name string) []*ICSProperty {
+	name = strings.ToUpper(name)
+	var out []*ICSProperty
+	for _, p := range c.Properties {
+		if p.Name == name {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Children returns the nested components with the given name.
+func (c *ICSComponent) Children(name string) []*ICSComponent {
+	name = strings.ToUpper(name)
+	var out []*ICSComponent
+	for _, child := range c.Components {
+		if child.Name == name {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
+// Add appends a property with an already-encoded value and returns it.
+func (c *ICSComponent) Add(name, value string, params map[string]string) *ICSProperty {
+	prop := &ICSProperty{Name: strings.ToUpper(name), Params: params, Value: value}
+	c.Properties = append(c.Properties, prop)
+	return prop
+}
+
+// AddText appends a TEXT property, escaping value. Empty values are skipped.
+func (c *ICSComponent) AddText(name, value string) {
+	if value != "" {
+		c.Add(name, escapeICSText(value), nil)
+	}
+}
+
+// String serialises the component as an iCalendar document: CRLF line endings
+// with content lines folded at 75 octets (RFC 5545 §3.1).
+func (c *ICSComponent) String() string {
+	var b strings.Builder
+	c.write(&b)
+	return b.String()
+}
+
+func (c *ICSComponent) write(b *strings.Builder) {
+	writeICSLine(b, "BEGIN:"+c.Name)
+	for _, p := range c.Properties {
+		writeICSLine(b, p.String())
+	}
+	for _, child := range c.Components {
+		child.write(b)
+	}
+	writeICSLine(b, "END:"+c.Name)
+}
+
+// writeICSLine writes line folded into chunks of at most 75 octets, never
+// splitting a UTF-8 sequence. Continuation lines start with a single space.
+func writeICSLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line + "\r\n")
+}
+
+// ParseICS parses an iCalendar document and returns its top-level VCALENDAR component.
+// Folded lines are unfolded and both CRLF and bare LF line endings are accepted.
+func ParseICS(data string) (*ICSComponent, error) {
+	lines := unfoldICSLines(data)
+
+	var root *ICSComponent
+	var stack []*ICSComponent
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseICSContentLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			comp := &ICSComponent{Name: strings.ToUpper(prop.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, comp)
+			} else if root == nil {
+				root = comp
+			} else {
+				return nil, fmt.Errorf("line %d: multiple top-level components", i+1)
+			}
+			stack = append(stack, comp)
+		case "END":
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: END:%s without BEGIN", i+1, prop.Value)
+			}
+			top := stack[len(stack)-1]
+			if top.Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("line %d: END:%s does not match BEGIN:%s", i+1, prop.Value, top.Name)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: property %s outside of a component", i+1, prop.Name)
+			}
+			top := stack[len(stack)-1]
+			top.Properties = append(top.Properties, prop)
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("no BEGIN:VCALENDAR found")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("unterminated component %s", stack[len(stack)-1].Name)
+	}
+	if root.Name != "VCALENDAR" {
+		return nil, fmt.Errorf("expected VCALENDAR, got %s", root.Name)
+	}
+	return root, nil
+}
+
+// unfoldICSLines splits data into logical content lines, joining continuation
+// lines that begin with a space or horizontal tab.
+func unfoldICSLines(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\r", "\n")
+
+	var lines []string
+	for _, raw := range strings.Split(data, "\n") {
+		if (strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += raw[1:]
+			continue
+		}
+		lines = append(lines, raw)
+	}
+	return lines
+}
+
+// parseICSContentLine parses "NAME;P1=V1;P2="V:2":value". Colons and semicolons
+// inside double-quoted parameter values do not terminate the parameter.
+func parseICSContentLine(line string) (*ICSProperty, error) {
+	inQuote := false
+	colon := -1
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuote = !inQuote
+		case ':':
+			if !inQuote {
+				colon = i
+			}
+		}
+		if colon >= 0 {
+			break
+		}
+	}
+	if colon < 0 {
+		return nil, fmt.Errorf("missing ':' in %q", line)
+	}
+
+	head, value := line[:colon], line[colon+1:]
+	segments := splitICSUnquoted(head, ';')
+	name := strings.ToUpper(strings.TrimSpace(segments[0]))
+	if name == "" {
+		return nil, fmt.Errorf("empty property name in %q", line)
+	}
+
+	prop := &ICSProperty{Name: name, Value: value}
+	for _, seg := range segments[1:] {
+		k, v, ok := strings.Cut(seg, "=")
+		if !ok {
+			continue
+		}
+		if prop.Params == nil {
+			prop.Params = make(map[string]string)
+		}
+		prop.Params[strings.ToUpper(strings.TrimSpace(k))] = strings.Trim(v, `"`)
+	}
+	return prop, nil
+}
+
+// splitICSUnquoted splits s on sep, ignoring separators inside double quotes.
+func splitICSUnquoted(s string, sep byte) []string {
+	var parts []string
+	inQuote := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			inQuote = !inQuote
+		case sep:
+			if !inQuote {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescapeICSText decodes RFC 5545 TEXT escapes.
+func unescapeICSText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// escapeICSText encodes a TEXT value: backslash, ';' and ',' are escaped and
+// newlines become \n.
+func escapeICSText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\', ';', ',':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ICSTime is a resolved DATE or DATE-TIME property value.
+type ICSTime struct {
+	Time     time.Time
+	AllDay   bool   // VALUE=DATE
+	TimeZone string // TZID parameter, or "UTC" for Z-suffixed values
+	Floating bool   // no TZID and no Z suffix: local time of whoever reads it
+}
+
+// ParseICSTime resolves a DTSTART/DTEND/RECURRENCE-ID style property. TZIDs that
+// are not IANA names (e.g. Outlook's "Pacific Standard Time") are reported as a
+// floating time with TimeZone preserved so callers can still display it.
+func ParseICSTime(p *ICSProperty) (*ICSTime, error) {
+	if p == nil {
+		return nil, fmt.Errorf("missing time property")
+	}
+	value := strings.TrimSpace(p.Value)
+
+	if strings.EqualFold(p.Param("VALUE"), "DATE") || len(value) == 8 {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DATE %q: %w", value, err)
+		}
+		return &ICSTime{Time: t, AllDay: true}, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DATE-TIME %q: %w", value, err)
+		}
+		return &ICSTime{Time: t, TimeZone: "UTC"}, nil
+	}
+
+	tzid := p.Param("TZID")
+	if tzid != "" {
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			t, err := time.ParseInLocation("20060102T150405", value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DATE-TIME %q: %w", value, err)
+			}
+			return &ICSTime{Time: t, TimeZone: tzid}, nil
+		}
+	}
+
+	t, err := time.Parse("20060102T150405", value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DATE-TIME %q: %w", value, err)
+	}
+	return &ICSTime{Time: t, TimeZone: tzid, Floating: true}, nil
+}
+
+// Format returns YYYY-MM-DD for all-day values, a local timestamp without offset
+// for floating values, and RFC3339 otherwise.
+func (t *ICSTime) Format() string {
+	switch {
+	case t.AllDay:
+		return t.Time.Format("2006-01-02")
+	case t.Floating:
+		return t.Time.Format("2006-01-02T15:04:05")
+	default:
+		return t.Time.Format(time.RFC3339)
+	}
+}
+
+// ICSMailto strips a "mailto:" prefix (case-insensitive) from a CAL-ADDRESS value.
+func ICSMailto(value string) string {
+	if len(value) >= 7 && strings.EqualFold(value[:7], "mailto:") {
+		return value[7:]
+	}
+	return value
+}
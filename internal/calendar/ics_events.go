@@ -0,0 +1,728 @@
+package calendar
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// icsLocalLayout and icsDateLayout are the local DATE-TIME and DATE value forms.
+const (
+	icsLocalLayout = "20060102T150405"
+	icsDateLayout  = "20060102"
+)
+
+// icsPartStat maps Calendar responseStatus values to ATTENDEE PARTSTAT values.
+var icsPartStat = map[string]string{
+	"needsAction": "NEEDS-ACTION",
+	"accepted":    "ACCEPTED",
+	"declined":    "DECLINED",
+	"tentative":   "TENTATIVE",
+}
+
+// windowsTimeZones maps common Outlook/Exchange TZIDs to IANA names.
+var windowsTimeZones = map[string]string{
+	"Dateline Standard Time":         "Etc/GMT+12",
+	"Hawaiian Standard Time":         "Pacific/Honolulu",
+	"Alaskan Standard Time":          "America/Anchorage",
+	"Pacific Standard Time":          "America/Los_Angeles",
+	"Mountain Standard Time":         "America/Denver",
+	"US Mountain Standard Time":      "America/Phoenix",
+	"Central Standard Time":          "America/Chicago",
+	"Eastern Standard Time":          "America/New_York",
+	"Atlantic Standard Time":         "America/Halifax",
+	"E. South America Standard Time": "America/Sao_Paulo",
+	"UTC":                            "UTC",
+	"GMT Standard Time":              "Europe/London",
+	"W. Europe Standard Time":        "Europe/Berlin",
+	"Romance Standard Time":          "Europe/Paris",
+	"Central Europe Standard Time":   "Europe/Budapest",
+	"FLE Standard Time":              "Europe/Kiev",
+	"Russian Standard Time":          "Europe/Moscow",
+	"India Standard Time":            "Asia/Kolkata",
+	"China Standard Time":            "Asia/Shanghai",
+	"Singapore Standard Time":        "Asia/Singapore",
+	"Tokyo Standard Time":            "Asia/Tokyo",
+	"AUS Eastern Standard Time":      "Australia/Sydney",
+	"New Zealand Standard Time":      "Pacific/Auckland",
+}
+
+// icsExport accumulates the VEVENTs of an export and the TZIDs they reference.
+type icsExport struct {
+	defaultLoc *time.Location
+	stamp      string
+	tzids      map[string]bool
+	first      time.Time
+	last       time.Time
+}
+
+// newICSExport prepares an export whose floating date-times are written in defaultTZ.
+func newICSExport(defaultTZ string, now time.Time) *icsExport {
+	loc, err := time.LoadLocation(defaultTZ)
+	if err != nil {
+		loc = time.UTC
+	}
+	return &icsExport{defaultLoc: loc, stamp: now.UTC().Format(rruleUntilLayout), tzids: make(map[string]bool)}
+}
+
+// dateTimeProp encodes dt as a DTSTART-style property: VALUE=DATE for all-day
+// values, local time with TZID otherwise (the event's own time zone, falling
+// back to the calendar's), or UTC when no zone can be loaded.
+func (x *icsExport) dateTimeProp(name string, dt *calendar.EventDateTime) (*ICSProperty, error) {
+	if dt == nil {
+		return nil, fmt.Errorf("missing %s", name)
+	}
+	if dt.DateTime == "" {
+		t, err := time.Parse("2006-01-02", dt.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s date %q: %w", name, dt.Date, err)
+		}
+		x.track(t)
+		return &ICSProperty{Name: name, Params: map[string]string{"VALUE": "DATE"}, Value: t.Format(icsDateLayout)}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, dt.DateTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", name, dt.DateTime, err)
+	}
+	x.track(t)
+	loc := x.defaultLoc
+	if dt.TimeZone != "" {
+		if l, err := time.LoadLocation(dt.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	if loc == time.UTC {
+		return &ICSProperty{Name: name, Value: t.UTC().Format(rruleUntilLayout)}, nil
+	}
+	x.tzids[loc.String()] = true
+	return &ICSProperty{Name: name, Params: map[string]string{"TZID": loc.String()}, Value: t.In(loc).Format(icsLocalLayout)}, nil
+}
+
+func (x *icsExport) track(t time.Time) {
+	if x.first.IsZero() || t.Before(x.first) {
+		x.first = t
+	}
+	if t.After(x.last) {
+		x.last = t
+	}
+}
+
+// vevent converts an event to a VEVENT. Recurrence lines are copied verbatim;
+// exceptions carry RECURRENCE-ID from their original start time.
+func (x *icsExport) vevent(ev *calendar.Event) (*ICSComponent, error) {
+	vev := &ICSComponent{Name: "VEVENT"}
+	uid := ev.ICalUID
+	if uid == "" {
+		uid = ev.Id + "@google.com"
+	}
+	vev.Add("UID", uid, nil)
+	stamp := x.stamp
+	if t, err := time.Parse(time.RFC3339, ev.Updated); err == nil {
+		stamp = t.UTC().Format(rruleUntilLayout)
+		vev.Add("LAST-MODIFIED", stamp, nil)
+	}
+	vev.Add("DTSTAMP", stamp, nil)
+	if t, err := time.Parse(time.RFC3339, ev.Created); err == nil {
+		vev.Add("CREATED", t.UTC().Format(rruleUntilLayout), nil)
+	}
+
+	for _, part := range []struct {
+		name string
+		dt   *calendar.EventDateTime
+	}{{"DTSTART", ev.Start}, {"DTEND", ev.End}, {"RECURRENCE-ID", ev.OriginalStartTime}} {
+		if part.dt == nil && part.name == "RECURRENCE-ID" {
+			continue
+		}
+		prop, err := x.dateTimeProp(part.name, part.dt)
+		if err != nil {
+			return nil, err
+		}
+		vev.Properties = append(vev.Properties, prop)
+	}
+
+	for _, line := range ev.Recurrence {
+		prop, err := parseICSContentLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("recurrence: %w", err)
+		}
+		if tzid := prop.Param("TZID"); tzid != "" {
+			x.tzids[tzid] = true
+		}
+		vev.Properties = append(vev.Properties, prop)
+	}
+
+	vev.AddText("SUMMARY", ev.Summary)
+	vev.AddText("DESCRIPTION", ev.Description)
+	vev.AddText("LOCATION", ev.Location)
+	if ev.Status != "" {
+		vev.Add("STATUS", strings.ToUpper(ev.Status), nil)
+	}
+	if ev.Transparency == "transparent" {
+		vev.Add("TRANSP", "TRANSPARENT", nil)
+	} else {
+		vev.Add("TRANSP", "OPAQUE", nil)
+	}
+	if ev.Sequence > 0 {
+		vev.Add("SEQUENCE", strconv.FormatInt(ev.Sequence, 10), nil)
+	}
+	if ev.Visibility == "private" || ev.Visibility == "confidential" {
+		vev.Add("CLASS", strings.ToUpper(ev.Visibility), nil)
+	}
+	if ev.HtmlLink != "" {
+		vev.Add("URL", ev.HtmlLink, nil)
+	}
+	if ev.HangoutLink != "" {
+		vev.Add("X-GOOGLE-CONFERENCE", ev.HangoutLink, nil)
+	}
+
+	if ev.Organizer != nil && ev.Organizer.Email != "" {
+		params := map[string]string{}
+		if ev.Organizer.DisplayName != "" {
+			params["CN"] = ev.Organizer.DisplayName
+		}
+		vev.Add("ORGANIZER", "mailto:"+ev.Organizer.Email, params)
+	}
+	for _, a := range ev.Attendees {
+		if a.Email == "" {
+			continue
+		}
+		params := map[string]string{"ROLE": "REQ-PARTICIPANT", "PARTSTAT": "NEEDS-ACTION"}
+		if a.Optional {
+			params["ROLE"] = "OPT-PARTICIPANT"
+		}
+		if ps, ok := icsPartStat[a.ResponseStatus]; ok {
+			params["PARTSTAT"] = ps
+		}
+		if a.Resource {
+			params["CUTYPE"] = "RESOURCE"
+		}
+		if a.DisplayName != "" {
+			params["CN"] = a.DisplayName
+		}
+		if a.ResponseStatus == "needsAction" {
+			params["RSVP"] = "TRUE"
+		}
+		vev.Add("ATTENDEE", "mailto:"+a.Email, params)
+	}
+	return vev, nil
+}
+
+// exdate returns an EXDATE property for a cancelled occurrence's original start.
+func (x *icsExport) exdate(original *calendar.EventDateTime) (*ICSProperty, error) {
+	return x.dateTimeProp("EXDATE", original)
+}
+
+// vtimezones returns a VTIMEZONE for every IANA TZID referenced by the export,
+// covering the span of exported dates (from a year before the earliest to a
+// year after the latest or until, whichever is later).
+func (x *icsExport) vtimezones(until time.Time) []*ICSComponent {
+	from, to := x.first, x.last
+	if until.After(to) {
+		to = until
+	}
+	if from.IsZero() {
+		from = time.Now()
+		to = from
+	}
+	from, to = from.AddDate(-1, 0, 0), to.AddDate(1, 0, 0)
+
+	names := make([]string, 0, len(x.tzids))
+	for name := range x.tzids {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []*ICSComponent
+	for _, name := range names {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			continue
+		}
+		out = append(out, buildVTimezone(name, loc, from, to))
+	}
+	return out
+}
+
+// zoneObservance is one STANDARD or DAYLIGHT period onset.
+type zoneObservance struct {
+	onset      time.Time // instant the offset takes effect
+	offsetFrom int
+	offsetTo   int
+	name       string
+	dst        bool
+}
+
+// buildVTimezone describes loc between from and to using Go's zone data. Each
+// offset change becomes a STANDARD/DAYLIGHT observance; the final observance
+// of each kind gets a yearly RRULE when the zone keeps following it, so
+// readers resolve times beyond to correctly.
+func buildVTimezone(tzid string, loc *time.Location, from, to time.Time) *ICSComponent {
+	var obs []zoneObservance
+	t := from.In(loc)
+	start, end := t.ZoneBounds()
+	name, offset := t.Zone()
+	prev := offset
+	if !start.IsZero() {
+		_, prev = start.Add(-time.Second).Zone()
+	}
+	obs = append(obs, zoneObservance{onset: start, offsetFrom: prev, offsetTo: offset, name: name, dst: t.IsDST()})
+	for i := 0; !end.IsZero() && end.Before(to) && i < 1000; i++ {
+		t = end.In(loc)
+		name, offset = t.Zone()
+		obs = append(obs, zoneObservance{onset: end, offsetFrom: obs[len(obs)-1].offsetTo, offsetTo: offset, name: name, dst: t.IsDST()})
+		_, end = t.ZoneBounds()
+	}
+
+	lastOf := map[bool]int{}
+	for i, o := range obs {
+		lastOf[o.dst] = i
+	}
+
+	vtz := &ICSComponent{Name: "VTIMEZONE"}
+	vtz.Add("TZID", tzid, nil)
+	for i, o := range obs {
+		kind := "STANDARD"
+		if o.dst {
+			kind = "DAYLIGHT"
+		}
+		comp := &ICSComponent{Name: kind}
+		if o.onset.IsZero() {
+			comp.Add("DTSTART", "19700101T000000", nil)
+		} else {
+			local := o.onset.In(time.FixedZone("", o.offsetFrom))
+			comp.Add("DTSTART", local.Format(icsLocalLayout), nil)
+			if lastOf[o.dst] == i && !o.onset.Before(from) {
+				if rule := yearlyOnsetRule(loc, local, o); rule != "" {
+					comp.Add("RRULE", rule, nil)
+				}
+			}
+		}
+		comp.Add("TZOFFSETFROM", formatUTCOffset(o.offsetFrom), nil)
+		comp.Add("TZOFFSETTO", formatUTCOffset(o.offsetTo), nil)
+		if o.name != "" && !strings.HasPrefix(o.name, "+") && !strings.HasPrefix(o.name, "-") {
+			comp.Add("TZNAME", o.name, nil)
+		}
+		vtz.Components = append(vtz.Components, comp)
+	}
+	return vtz
+}
+
+// yearlyOnsetRule returns "FREQ=YEARLY;BYMONTH=m;BYDAY=nDD" when the onset at
+// local wall time recurs on the same nth (or last) weekday for the next two
+// years, or "" when the zone stops following the pattern.
+func yearlyOnsetRule(loc *time.Location, local time.Time, o zoneObservance) string {
+	nth := (local.Day()-1)/7 + 1
+	if local.AddDate(0, 0, 7).Month() != local.Month() {
+		nth = -1
+	}
+	for year := local.Year() + 1; year <= local.Year()+2; year++ {
+		day := nthWeekday(year, local.Month(), local.Weekday(), nth)
+		onset := time.Date(year, local.Month(), day, local.Hour(), local.Minute(), local.Second(), 0, time.FixedZone("", o.offsetFrom))
+		_, before := onset.Add(-time.Second).In(loc).Zone()
+		_, after := onset.In(loc).Zone()
+		if before != o.offsetFrom || after != o.offsetTo {
+			return ""
+		}
+	}
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(local.Month()), nth, icsWeekdays[local.Weekday()])
+}
+
+// icsWeekdays are the RFC 5545 two-letter weekday codes.
+var icsWeekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// nthWeekday returns the day of month of the nth (1-5, or -1 for last) weekday.
+func nthWeekday(year int, month time.Month, wd time.Weekday, nth int) int {
+	if nth < 0 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.Day() - (int(last.Weekday())-int(wd)+7)%7
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return 1 + (int(wd)-int(first.Weekday())+7)%7 + 7*(nth-1)
+}
+
+// formatUTCOffset formats seconds east of UTC as ±HHMM (±HHMMSS when needed).
+func formatUTCOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	s := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+	if seconds%60 != 0 {
+		s += fmt.Sprintf("%02d", seconds%60)
+	}
+	return s
+}
+
+// parseUTCOffset parses a TZOFFSETFROM/TZOFFSETTO value into seconds east of UTC.
+func parseUTCOffset(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if len(s) != 5 && len(s) != 7 || (s[0] != '+' && s[0] != '-') {
+		return 0, fmt.Errorf("invalid UTC offset %q", s)
+	}
+	var parts [3]int
+	for i := 0; 1+2*i < len(s); i++ {
+		n, err := strconv.Atoi(s[1+2*i : 3+2*i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid UTC offset %q", s)
+		}
+		parts[i] = n
+	}
+	seconds := parts[0]*3600 + parts[1]*60 + parts[2]
+	if s[0] == '-' {
+		seconds = -seconds
+	}
+	return seconds, nil
+}
+
+// icsImport converts VEVENTs to Calendar events, resolving TZIDs against the
+// document's VTIMEZONE definitions.
+type icsImport struct {
+	zones      map[string]*ICSComponent
+	defaultTZ  string
+	defaultLoc *time.Location
+}
+
+// newICSImport indexes root's VTIMEZONEs. Floating times and TZIDs that cannot
+// be resolved to an IANA zone are read in defaultTZ.
+func newICSImport(root *ICSComponent, defaultTZ string) *icsImport {
+	loc, err := time.LoadLocation(defaultTZ)
+	if err != nil {
+		defaultTZ, loc = "UTC", time.UTC
+	}
+	imp := &icsImport{zones: make(map[string]*ICSComponent), defaultTZ: defaultTZ, defaultLoc: loc}
+	for _, vtz := range root.Children("VTIMEZONE") {
+		if tzid := vtz.Get("TZID"); tzid != nil {
+			imp.zones[tzid.Value] = vtz
+		}
+	}
+	return imp
+}
+
+// ianaZone maps a TZID to an IANA name, or "" when it is not one or a known alias.
+func ianaZone(tzid string) string {
+	if tzid == "" {
+		return ""
+	}
+	if _, err := time.LoadLocation(tzid); err == nil {
+		return tzid
+	}
+	if name, ok := windowsTimeZones[tzid]; ok {
+		return name
+	}
+	return ""
+}
+
+// resolveTime returns the instant for a DATE-TIME property plus the IANA zone
+// to record with it ("" when the TZID is custom and was resolved through its
+// VTIMEZONE offsets).
+func (imp *icsImport) resolveTime(p *ICSProperty) (*ICSTime, string, error) {
+	t, err := ParseICSTime(p)
+	if err != nil {
+		return nil, "", err
+	}
+	if t.AllDay || !t.Floating {
+		return t, t.TimeZone, nil
+	}
+
+	wall := t.Time
+	tzid := p.Param("TZID")
+	if name := ianaZone(tzid); name != "" {
+		loc, _ := time.LoadLocation(name)
+		t.Time = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+		t.TimeZone, t.Floating = name, false
+		return t, name, nil
+	}
+	if vtz := imp.zones[tzid]; vtz != nil {
+		if offset, ok := vtimezoneOffset(vtz, wall); ok {
+			t.Time = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, time.FixedZone(tzid, offset))
+			t.Floating = false
+			return t, "", nil
+		}
+	}
+	t.Time = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, imp.defaultLoc)
+	t.TimeZone, t.Floating = imp.defaultTZ, false
+	return t, imp.defaultTZ, nil
+}
+
+// eventDateTime converts a DTSTART-style property to an EventDateTime.
+// Recurring events always get a time zone so the series expands correctly.
+func (imp *icsImport) eventDateTime(p *ICSProperty, recurring bool) (*calendar.EventDateTime, *ICSTime, error) {
+	t, zone, err := imp.resolveTime(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.AllDay {
+		return &calendar.EventDateTime{Date: t.Time.Format("2006-01-02")}, t, nil
+	}
+	if zone == "" && recurring {
+		zone = imp.defaultTZ
+	}
+	return &calendar.EventDateTime{DateTime: t.Time.Format(time.RFC3339), TimeZone: zone}, t, nil
+}
+
+// vtimezoneOffset evaluates a VTIMEZONE at a wall-clock time: the observance
+// with the latest onset at or before wall wins. Observances may recur through
+// a yearly BYMONTH/BYDAY rule (the form Outlook and most exporters emit).
+func vtimezoneOffset(vtz *ICSComponent, wall time.Time) (int, bool) {
+	var best time.Time
+	bestOffset, found := 0, false
+	earliest, earliestFrom := time.Time{}, 0
+	for _, obs := range vtz.Components {
+		start, err := time.Parse(icsLocalLayout, valueOf(obs.Get("DTSTART")))
+		if err != nil {
+			continue
+		}
+		to, err := parseUTCOffset(valueOf(obs.Get("TZOFFSETTO")))
+		if err != nil {
+			continue
+		}
+		if from, err := parseUTCOffset(valueOf(obs.Get("TZOFFSETFROM"))); err == nil && (earliest.IsZero() || start.Before(earliest)) {
+			earliest, earliestFrom = start, from
+		}
+
+		candidates := []time.Time{start}
+		if rule, err := ParseRRule(valueOf(obs.Get("RRULE"))); err == nil && strings.EqualFold(rule.Get("FREQ"), "YEARLY") {
+			candidates = yearlyOnsets(rule, start, wall)
+		}
+		for _, c := range candidates {
+			if !c.After(wall) && (!found || c.After(best)) {
+				best, bestOffset, found = c, to, true
+			}
+		}
+	}
+	if !found && !earliest.IsZero() {
+		return earliestFrom, true
+	}
+	return bestOffset, found
+}
+
+// yearlyOnsets returns the onsets of a yearly BYMONTH/BYDAY rule in the year of
+// wall and the year before, no earlier than the observance's DTSTART.
+func yearlyOnsets(rule *RRule, start, wall time.Time) []time.Time {
+	month, err := strconv.Atoi(rule.Get("BYMONTH"))
+	if err != nil || month < 1 || month > 12 {
+		return []time.Time{start}
+	}
+	byDay := strings.ToUpper(rule.Get("BYDAY"))
+	if len(byDay) < 2 {
+		return []time.Time{start}
+	}
+	wd := -1
+	for i, code := range icsWeekdays {
+		if strings.HasSuffix(byDay, code) {
+			wd = i
+		}
+	}
+	nth, err := strconv.Atoi(byDay[:len(byDay)-2])
+	if wd < 0 || err != nil || nth == 0 {
+		return []time.Time{start}
+	}
+
+	var out []time.Time
+	for _, year := range []int{wall.Year() - 1, wall.Year()} {
+		day := nthWeekday(year, time.Month(month), time.Weekday(wd), nth)
+		onset := time.Date(year, time.Month(month), day, start.Hour(), start.Minute(), start.Second(), 0, time.UTC)
+		if !onset.Before(start) {
+			out = append(out, onset)
+		}
+	}
+	return out
+}
+
+func valueOf(p *ICSProperty) string {
+	if p == nil {
+		return ""
+	}
+	return p.Value
+}
+
+// event converts a VEVENT to a Calendar event suitable for events.import.
+// A VEVENT without a UID gets a stable one derived from its summary and
+// start so that re-importing the same file stays idempotent.
+func (imp *icsImport) event(vev *ICSComponent) (*calendar.Event, error) {
+	ev := &calendar.Event{
+		Summary:     vev.Get("SUMMARY").Text(),
+		Description: vev.Get("DESCRIPTION").Text(),
+		Location:    vev.Get("LOCATION").Text(),
+	}
+
+	for _, name := range []string{"RRULE", "EXRULE", "RDATE", "EXDATE"} {
+		for _, p := range vev.GetAll(name) {
+			line, err := imp.recurrenceLine(p)
+			if err != nil {
+				return nil, err
+			}
+			ev.Recurrence = append(ev.Recurrence, line)
+		}
+	}
+	recurring := len(ev.Recurrence) > 0
+
+	dtstart := vev.Get("DTSTART")
+	if dtstart == nil {
+		return nil, fmt.Errorf("VEVENT has no DTSTART")
+	}
+	start, startTime, err := imp.eventDateTime(dtstart, recurring)
+	if err != nil {
+		return nil, fmt.Errorf("DTSTART: %w", err)
+	}
+	ev.Start = start
+
+	switch {
+	case vev.Get("DTEND") != nil:
+		if ev.End, _, err = imp.eventDateTime(vev.Get("DTEND"), recurring); err != nil {
+			return nil, fmt.Errorf("DTEND: %w", err)
+		}
+	case vev.Get("DURATION") != nil:
+		d, err := parseICSDuration(vev.Get("DURATION").Value)
+		if err != nil {
+			return nil, err
+		}
+		end := startTime.Time.Add(d)
+		if startTime.AllDay {
+			ev.End = &calendar.EventDateTime{Date: end.Format("2006-01-02")}
+		} else {
+			ev.End = &calendar.EventDateTime{DateTime: end.Format(time.RFC3339), TimeZone: start.TimeZone}
+		}
+	case startTime.AllDay:
+		ev.End = &calendar.EventDateTime{Date: startTime.Time.AddDate(0, 0, 1).Format("2006-01-02")}
+	default:
+		ev.End = &calendar.EventDateTime{DateTime: start.DateTime, TimeZone: start.TimeZone}
+	}
+
+	if rid := vev.Get("RECURRENCE-ID"); rid != nil {
+		if ev.OriginalStartTime, _, err = imp.eventDateTime(rid, true); err != nil {
+			return nil, fmt.Errorf("RECURRENCE-ID: %w", err)
+		}
+	}
+
+	ev.ICalUID = strings.TrimSpace(valueOf(vev.Get("UID")))
+	if ev.ICalUID == "" {
+		sum := sha1.Sum([]byte(ev.Summary + "|" + dtstart.Value))
+		ev.ICalUID = hex.EncodeToString(sum[:10]) + "@gsuite-mcp"
+	}
+
+	switch strings.ToUpper(valueOf(vev.Get("STATUS"))) {
+	case "TENTATIVE":
+		ev.Status = "tentative"
+	case "CANCELLED":
+		ev.Status = "cancelled"
+	default:
+		ev.Status = "confirmed"
+	}
+	if strings.EqualFold(valueOf(vev.Get("TRANSP")), "TRANSPARENT") {
+		ev.Transparency = "transparent"
+	}
+	switch strings.ToUpper(valueOf(vev.Get("CLASS"))) {
+	case "PRIVATE":
+		ev.Visibility = "private"
+	case "CONFIDENTIAL":
+		ev.Visibility = "confidential"
+	}
+	if seq, err := strconv.ParseInt(valueOf(vev.Get("SEQUENCE")), 10, 64); err == nil {
+		ev.Sequence = seq
+	}
+
+	if org := vev.Get("ORGANIZER"); org != nil {
+		ev.Organizer = &calendar.EventOrganizer{Email: ICSMailto(org.Value), DisplayName: org.Param("CN")}
+	}
+	for _, att := range vev.GetAll("ATTENDEE") {
+		email := ICSMailto(att.Value)
+		if email == "" {
+			continue
+		}
+		a := &calendar.EventAttendee{
+			Email:          email,
+			DisplayName:    att.Param("CN"),
+			Optional:       strings.EqualFold(att.Param("ROLE"), "OPT-PARTICIPANT"),
+			Resource:       strings.EqualFold(att.Param("CUTYPE"), "RESOURCE") || strings.EqualFold(att.Param("CUTYPE"), "ROOM"),
+			ResponseStatus: "needsAction",
+		}
+		for status, partstat := range icsPartStat {
+			if strings.EqualFold(att.Param("PARTSTAT"), partstat) {
+				a.ResponseStatus = status
+			}
+		}
+		ev.Attendees = append(ev.Attendees, a)
+	}
+	return ev, nil
+}
+
+// recurrenceLine re-encodes an RRULE/EXDATE/RDATE property for the Calendar
+// API. Date lists with a custom TZID are rewritten in UTC, since the API only
+// understands IANA zone names.
+func (imp *icsImport) recurrenceLine(p *ICSProperty) (string, error) {
+	tzid := p.Param("TZID")
+	if p.Name == "RRULE" || p.Name == "EXRULE" || tzid == "" {
+		return p.String(), nil
+	}
+	if name := ianaZone(tzid); name != "" {
+		params := map[string]string{}
+		for k, v := range p.Params {
+			params[k] = v
+		}
+		params["TZID"] = name
+		return (&ICSProperty{Name: p.Name, Params: params, Value: p.Value}).String(), nil
+	}
+
+	var values []string
+	for _, v := range strings.Split(p.Value, ",") {
+		t, _, err := imp.resolveTime(&ICSProperty{Name: p.Name, Params: p.Params, Value: v})
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", p.Name, err)
+		}
+		values = append(values, t.Time.UTC().Format(rruleUntilLayout))
+	}
+	return p.Name + ":" + strings.Join(values, ","), nil
+}
+
+// parseICSDuration parses an RFC 5545 DURATION such as "PT1H30M", "P1D" or "P2W".
+func parseICSDuration(s string) (time.Duration, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	neg := strings.HasPrefix(value, "-")
+	value = strings.TrimLeft(value, "+-")
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return 0, fmt.Errorf("invalid DURATION %q", s)
+	}
+
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range value[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid DURATION %q", s)
+		}
+		num = ""
+		unit := map[rune]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}[r]
+		if inTime {
+			unit = map[rune]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}[r]
+		}
+		if unit == 0 {
+			return 0, fmt.Errorf("invalid DURATION %q", s)
+		}
+		d += time.Duration(n) * unit
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid DURATION %q", s)
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
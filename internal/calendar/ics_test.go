@@ -0,0 +1,248 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testInviteICS = "BEGIN:VCALENDAR\r\n" +
+	"PRODID:-//Google Inc//Google Calendar 70.9054//EN\r\n" +
+	"VERSION:2.0\r\n" +
+	"METHOD:REQUEST\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20261102T090000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20261102T093000\r\n" +
+	"ORGANIZER;CN=Alice Example:mailto:alice@example.com\r\n" +
+	"ATTENDEE;CUTYPE=INDIVIDUAL;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=\r\n" +
+	" TRUE;CN=\"Smith, Bob\";X-NUM-GUESTS=0:mailto:bob@example.com\r\n" +
+	"UID:abc123@google.com\r\n" +
+	"SUMMARY:Planning\\, Q4\r\n" +
+	"DESCRIPTION:Line one\\nLine two\r\n" +
+	"SEQUENCE:2\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"TRIGGER:-P0DT0H10M0S\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS_Structure(t *testing.T) {
+	root, err := ParseICS(testInviteICS)
+	if err != nil {
+		t.Fatalf("ParseICS: %v", err)
+	}
+	if got := root.Get("METHOD").Value; got != "REQUEST" {
+		t.Errorf("METHOD = %q, want REQUEST", got)
+	}
+	events := root.Children("VEVENT")
+	if len(events) != 1 {
+		t.Fatalf("expected 1 VEVENT, got %d", len(events))
+	}
+	ev := events[0]
+	if len(ev.Children("VALARM")) != 1 {
+		t.Error("expected nested VALARM")
+	}
+	if got := ev.Get("SUMMARY").Text(); got != "Planning, Q4" {
+		t.Errorf("SUMMARY = %q", got)
+	}
+	if got := ev.Get("DESCRIPTION").Text(); got != "Line one\nLine two" {
+		t.Errorf("DESCRIPTION = %q", got)
+	}
+
+	att := ev.Get("ATTENDEE")
+	if att == nil {
+		t.Fatal("missing ATTENDEE")
+	}
+	if got := att.Param("cn"); got != "Smith, Bob" {
+		t.Errorf("CN = %q, want quoted value unquoted", got)
+	}
+	if got := att.Param("RSVP"); got != "TRUE" {
+		t.Errorf("RSVP = %q, want folded value joined", got)
+	}
+	if got := ICSMailto(att.Value); got != "bob@example.com" {
+		t.Errorf("attendee email = %q", got)
+	}
+}
+
+func TestParseICS_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"no vcalendar", "BEGIN:VEVENT\nEND:VEVENT\n"},
+		{"unterminated", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR\n"},
+		{"missing colon", "BEGIN:VCALENDAR\nGARBAGE\nEND:VCALENDAR\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseICS(tc.data); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseICSTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		want     string
+		allDay   bool
+		floating bool
+		wantUTC  string
+	}{
+		{"tzid", "DTSTART;TZID=Europe/Berlin:20261102T090000", "2026-11-02T09:00:00+01:00", false, false, "2026-11-02T08:00:00Z"},
+		{"tzid summer", "DTSTART;TZID=America/New_York:20260701T090000", "2026-07-01T09:00:00-04:00", false, false, "2026-07-01T13:00:00Z"},
+		{"utc", "DTSTART:20261102T090000Z", "2026-11-02T09:00:00Z", false, false, "2026-11-02T09:00:00Z"},
+		{"date", "DTSTART;VALUE=DATE:20261102", "2026-11-02", true, false, ""},
+		{"floating", "DTSTART:20261102T090000", "2026-11-02T09:00:00", false, true, ""},
+		{"windows tzid", "DTSTART;TZID=Pacific Standard Time:20261102T090000", "2026-11-02T09:00:00", false, true, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			prop, err := parseICSContentLine(tc.line)
+			if err != nil {
+				t.Fatalf("parse line: %v", err)
+			}
+			got, err := ParseICSTime(prop)
+			if err != nil {
+				t.Fatalf("ParseICSTime: %v", err)
+			}
+			if got.Format() != tc.want {
+				t.Errorf("Format() = %q, want %q", got.Format(), tc.want)
+			}
+			if got.AllDay != tc.allDay || got.Floating != tc.floating {
+				t.Errorf("AllDay=%v Floating=%v, want %v/%v", got.AllDay, got.Floating, tc.allDay, tc.floating)
+			}
+			if tc.wantUTC != "" && got.Time.UTC().Format(time.RFC3339) != tc.wantUTC {
+				t.Errorf("UTC = %s, want %s", got.Time.UTC().Format(time.RFC3339), tc.wantUTC)
+			}
+		})
+	}
+}
+
+func TestParseICS_LFOnlyAndTabFolding(t *testing.T) {
+	data := strings.ReplaceAll(testInviteICS, "\r\n", "\n")
+	data = strings.Replace(data, "\n TRUE", "\n\tTRUE", 1)
+	root, err := ParseICS(data)
+	if err != nil {
+		t.Fatalf("ParseICS: %v", err)
+	}
+	if got := root.Children("VEVENT")[0].Get("ATTENDEE").Param("RSVP"); got != "TRUE" {
+		t.Errorf("RSVP = %q", got)
+	}
+}
+
+func TestICSComponentString_RoundTrip(t *testing.T) {
+	vev := &ICSComponent{Name: "VEVENT"}
+	vev.Add("DTSTART", "20261102T090000", map[string]string{"TZID": "Europe/Berlin"})
+	vev.AddText("SUMMARY", "Planning, Q4; budget")
+	vev.AddText("DESCRIPTION", strings.Repeat("Überblick ", 20)+"\nend")
+	vev.Add("ATTENDEE", "mailto:bob@example.com", map[string]string{"CN": "Smith, Bob", "PARTSTAT": "ACCEPTED"})
+	root := &ICSComponent{Name: "VCALENDAR", Components: []*ICSComponent{vev}}
+
+	data := root.String()
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	if !strings.Contains(data, `ATTENDEE;CN="Smith, Bob";PARTSTAT=ACCEPTED:mailto:bob@example.com`) {
+		t.Errorf("attendee not encoded with sorted, quoted params:\n%s", data)
+	}
+
+	parsed, err := ParseICS(data)
+	if err != nil {
+		t.Fatalf("ParseICS: %v", err)
+	}
+	got := parsed.Children("VEVENT")[0]
+	if got.Get("SUMMARY").Text() != "Planning, Q4; budget" {
+		t.Errorf("SUMMARY = %q", got.Get("SUMMARY").Text())
+	}
+	if got.Get("DESCRIPTION").Text() != strings.Repeat("Überblick ", 20)+"\nend" {
+		t.Errorf("DESCRIPTION did not survive folding: %q", got.Get("DESCRIPTION").Text())
+	}
+	if got.Get("ATTENDEE").Param("CN") != "Smith, Bob" {
+		t.Errorf("CN = %q", got.Get("ATTENDEE").Param("CN"))
+	}
+}
+
+func TestBuildVTimezone(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	vtz := buildVTimezone("America/New_York", ny, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	rules := map[string]string{}
+	for _, obs := range vtz.Components {
+		if rule := obs.Get("RRULE"); rule != nil {
+			rules[obs.Name] = rule.Value + " " + obs.Get("DTSTART").Value + " " + obs.Get("TZOFFSETTO").Value
+		}
+	}
+	if rules["DAYLIGHT"] != "FREQ=YEARLY;BYMONTH=3;BYDAY=2SU 20240310T020000 -0400" {
+		t.Errorf("DAYLIGHT rule = %q", rules["DAYLIGHT"])
+	}
+	if rules["STANDARD"] != "FREQ=YEARLY;BYMONTH=11;BYDAY=1SU 20241103T020000 -0500" {
+		t.Errorf("STANDARD rule = %q", rules["STANDARD"])
+	}
+
+	// A generated VTIMEZONE resolves times outside the window it was built for.
+	for _, tc := range []struct {
+		wall time.Time
+		want int
+	}{
+		{time.Date(2027, 7, 1, 9, 0, 0, 0, time.UTC), -4 * 3600},
+		{time.Date(2027, 12, 1, 9, 0, 0, 0, time.UTC), -5 * 3600},
+	} {
+		if got, ok := vtimezoneOffset(vtz, tc.wall); !ok || got != tc.want {
+			t.Errorf("offset at %s = %d, want %d", tc.wall, got, tc.want)
+		}
+	}
+
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	vtz = buildVTimezone("Asia/Tokyo", tokyo, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(vtz.Components) != 1 || vtz.Components[0].Get("TZOFFSETTO").Value != "+0900" {
+		t.Errorf("expected a single +0900 STANDARD observance, got %s", vtz)
+	}
+}
+
+func TestVTimezoneOffset_Outlook(t *testing.T) {
+	root, err := ParseICS("BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTIMEZONE\r\nTZID:Custom Central Time\r\n" +
+		"BEGIN:STANDARD\r\nDTSTART:16010101T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0600\r\nRRULE:FREQ=YEARLY;BYDAY=1SU;BYMONTH=11\r\nEND:STANDARD\r\n" +
+		"BEGIN:DAYLIGHT\r\nDTSTART:16010101T020000\r\nTZOFFSETFROM:-0600\r\nTZOFFSETTO:-0500\r\nRRULE:FREQ=YEARLY;BYDAY=2SU;BYMONTH=3\r\nEND:DAYLIGHT\r\n" +
+		"END:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\nUID:x\r\nDTSTART;TZID=Custom Central Time:20260715T090000\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n")
+	if err != nil {
+		t.Fatalf("ParseICS: %v", err)
+	}
+	imp := newICSImport(root, "Europe/London")
+	got, zone, err := imp.resolveTime(root.Children("VEVENT")[0].Get("DTSTART"))
+	if err != nil {
+		t.Fatalf("resolveTime: %v", err)
+	}
+	if got.Time.Format(time.RFC3339) != "2026-07-15T09:00:00-05:00" || zone != "" {
+		t.Errorf("resolved %s (zone %q)", got.Time.Format(time.RFC3339), zone)
+	}
+}
+
+func TestParseICSDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P2W":     14 * 24 * time.Hour,
+		"P1DT2H":  26 * time.Hour,
+		"-PT15M":  -15 * time.Minute,
+	}
+	for in, want := range tests {
+		if got, err := parseICSDuration(in); err != nil || got != want {
+			t.Errorf("parseICSDuration(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "P", "1H", "PT1X", "PT5"} {
+		if _, err := parseICSDuration(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package calendar
+
+import (
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/contacts"
+	"github.com/aliwatters/gsuite-mcp/internal/drive"
+	"github.com/aliwatters/gsuite-mcp/internal/meet"
+)
+
+// BriefGmailHandlerDeps resolves the Gmail threads the meeting brief reads.
+// The gmail package imports calendar, so mail is read through the shared
+// common.GmailThreadReader instead of the gmail package's service.
+type BriefGmailHandlerDeps = common.HandlerDeps[common.GmailThreadReader]
+
+// DefaultBriefGmailHandlerDeps holds the default Gmail deps for the meeting
+// brief. It is nil until set at startup from gmail.NewGmailThreadReader.
+var DefaultBriefGmailHandlerDeps *BriefGmailHandlerDeps
+
+// MeetingBriefDeps overrides the services calendar_meeting_brief reads from.
+// Nil fields fall back to each package's default deps.
+type MeetingBriefDeps struct {
+	Gmail    *BriefGmailHandlerDeps
+	Contacts *contacts.ContactsHandlerDeps
+	Drive    *drive.DriveHandlerDeps
+	Meet     *meet.MeetHandlerDeps
+}
+
+// MeetingBriefHandlerDeps holds the deps used by calendar_meeting_brief.
+var MeetingBriefHandlerDeps MeetingBriefDeps
@@ -14,8 +14,8 @@ func RegisterTools(s *server.MCPServer) {
 	s.AddTool(mcp.NewTool("calendar_list_events",
 		mcp.WithDescription("List upcoming calendar events with optional date/time filtering."),
 		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary' for main calendar)")),
-		mcp.WithString("time_min", mcp.Description("Start of time range (RFC3339, e.g., 2024-01-15T00:00:00Z). Defaults to now.")),
-		mcp.WithString("time_max", mcp.Description("End of time range (RFC3339)")),
+		mcp.WithString("time_min", mcp.Description("Start of time range: RFC3339 or an expression like 'tomorrow' or 'next Monday 9am' (resolved in the calendar's time zone; a day also sets time_max). Defaults to now.")),
+		mcp.WithString("time_max", mcp.Description("End of time range (RFC3339 or an expression like 'friday', meaning the end of that day)")),
 		mcp.WithNumber("max_results", mcp.Description("Maximum events to return (1-250, default 25)")),
 		common.WithPageToken(),
 		mcp.WithString("query", mcp.Description("Free text search query")),
@@ -36,8 +36,8 @@ func RegisterTools(s *server.MCPServer) {
 	s.AddTool(mcp.NewTool("calendar_create_event",
 		mcp.WithDescription("Create a new calendar event."),
 		mcp.WithString("summary", mcp.Required(), mcp.Description("Event title")),
-		mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time (RFC3339, e.g., 2024-01-15T09:00:00-08:00)")),
-		mcp.WithString("end_time", mcp.Description("End time (RFC3339). Defaults to 1 hour after start.")),
+		mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time: RFC3339, or an expression like 'tomorrow 3pm', 'next Tuesday 10:00-11:30' or '2026-11-02 09:00 Europe/Berlin' (resolved in the calendar's time zone; a range also sets the end)")),
+		mcp.WithString("end_time", mcp.Description("End time: RFC3339 or an expression; a bare time of day is on the start's day. Defaults to 1 hour after start.")),
 		mcp.WithString("description", mcp.Description("Event description")),
 		mcp.WithString("location", mcp.Description("Event location")),
 		mcp.WithString("color_id", mcp.Description("Event color ID (valid values: 1-11). Omit or leave empty to use the default color.")),
@@ -46,6 +46,9 @@ func RegisterTools(s *server.MCPServer) {
 		mcp.WithBoolean("add_conferencing", mcp.Description("Add Google Meet video conferencing to the event")),
 		mcp.WithArray("attendees", mcp.Description("List of attendee email addresses")),
 		mcp.WithArray("reminders", mcp.Description("List of reminder times in minutes before event")),
+		mcp.WithArray("attachments", mcp.Description("Drive file IDs or URLs to attach (up to 25)")),
+		mcp.WithBoolean("share_attachments", mcp.Description("Grant attendees who cannot open an attachment access to it (default: false)")),
+		mcp.WithString("attachment_role", mcp.Description("Role granted by share_attachments: 'reader', 'commenter' or 'writer' (default: 'reader')")),
 		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
 		common.WithAccountParam(),
 	), HandleCalendarCreateEvent)
@@ -55,12 +58,15 @@ func RegisterTools(s *server.MCPServer) {
 		mcp.WithDescription("Update an existing calendar event. Only provided fields are updated."),
 		mcp.WithString("event_id", mcp.Required(), mcp.Description("Calendar event ID")),
 		mcp.WithString("summary", mcp.Description("Event title")),
-		mcp.WithString("start_time", mcp.Description("Start time (RFC3339)")),
-		mcp.WithString("end_time", mcp.Description("End time (RFC3339)")),
+		mcp.WithString("start_time", mcp.Description("Start time: RFC3339, or an expression like 'tomorrow 3pm', 'next Tuesday 10:00-11:30' or '2026-11-02 09:00 Europe/Berlin' (resolved in the calendar's time zone; a range also sets the end)")),
+		mcp.WithString("end_time", mcp.Description("End time: RFC3339 or an expression; a bare time of day is on the start's day")),
 		mcp.WithString("description", mcp.Description("Event description")),
 		mcp.WithString("location", mcp.Description("Event location")),
 		mcp.WithString("color_id", mcp.Description("Event color ID (valid values: 1-11). Omit or leave empty to preserve the existing color.")),
 		mcp.WithArray("attendees", mcp.Description("List of attendee email addresses (replaces existing)")),
+		mcp.WithArray("attachments", mcp.Description("Drive file IDs or URLs to attach (replaces existing; empty list removes all)")),
+		mcp.WithBoolean("share_attachments", mcp.Description("Grant attendees who cannot open an attachment access to it (default: false)")),
+		mcp.WithString("attachment_role", mcp.Description("Role granted by share_attachments: 'reader', 'commenter' or 'writer' (default: 'reader')")),
 		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
 		common.WithAccountParam(),
 	), HandleCalendarUpdateEvent)
@@ -92,19 +98,42 @@ func RegisterTools(s *server.MCPServer) {
 	// calendar_free_busy - Query free/busy information
 	s.AddTool(mcp.NewTool("calendar_free_busy",
 		mcp.WithDescription("Query free/busy information for one or more calendars."),
-		mcp.WithString("time_min", mcp.Required(), mcp.Description("Start of time range (RFC3339)")),
-		mcp.WithString("time_max", mcp.Required(), mcp.Description("End of time range (RFC3339)")),
+		mcp.WithString("time_min", mcp.Required(), mcp.Description("Start of time range: RFC3339 or an expression like 'tomorrow' (a day also sets time_max)")),
+		mcp.WithString("time_max", mcp.Required(), mcp.Description("End of time range (RFC3339 or an expression)")),
 		mcp.WithArray("calendar_ids", mcp.Description("Calendar IDs to query (default: ['primary'])")),
 		common.WithAccountParam(),
 	), HandleCalendarFreeBusy)
 
+	// calendar_find_slots - Find meeting times that suit every attendee
+	s.AddTool(mcp.NewTool("calendar_find_slots",
+		mcp.WithDescription("Find meeting slots when all attendees are free and within their working hours. Uses free/busy data, handles per-attendee time zones, and ranks slots to prefer times well inside everyone's working day and earlier dates. Optionally books the chosen slot."),
+		mcp.WithArray("attendees", mcp.Required(), mcp.Description("Attendee email addresses or calendar IDs. An entry may also be an object {email, timezone, working_hours} to override the defaults for that attendee.")),
+		mcp.WithNumber("duration_minutes", mcp.Required(), mcp.Description("Meeting length in minutes")),
+		mcp.WithString("window_start", mcp.Description("Start of search window: RFC3339 or an expression like 'tomorrow' or 'next Monday' (a day also sets window_end). Defaults to now.")),
+		mcp.WithString("window_end", mcp.Description("End of search window (RFC3339 or an expression like 'friday', meaning the end of that day). Defaults to 7 days after window_start; maximum 62 days.")),
+		mcp.WithString("working_hours", mcp.Description("Default daily working hours in each attendee's local time, HH:MM-HH:MM (default: 09:00-17:00)")),
+		mcp.WithString("timezone", mcp.Description("Time zone for results and default attendee time zone (default: the calendar's time zone)")),
+		mcp.WithNumber("buffer_minutes", mcp.Description("Minimum gap to keep before and after existing meetings (default: 0)")),
+		mcp.WithNumber("granularity_minutes", mcp.Description("Step between candidate start times (default: 30)")),
+		mcp.WithBoolean("include_self", mcp.Description("Include your own calendar in the search (default: true)")),
+		mcp.WithBoolean("include_weekends", mcp.Description("Allow slots on Saturday and Sunday (default: false)")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum slots to return (1-50, default 10)")),
+		mcp.WithBoolean("create_event", mcp.Description("Create the event in the slot at slot_index and invite the attendees (default: false)")),
+		mcp.WithNumber("slot_index", mcp.Description("Index into the returned slots to book when create_event is true (default: 0, the best slot)")),
+		mcp.WithString("summary", mcp.Description("Event title when create_event is true (default: 'Meeting')")),
+		mcp.WithString("description", mcp.Description("Event description when create_event is true")),
+		mcp.WithBoolean("add_conferencing", mcp.Description("Add Google Meet to the created event")),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID to search as yourself and to create the event in (default: 'primary')")),
+		common.WithAccountParam(),
+	), HandleCalendarFindSlots)
+
 	// calendar_list_instances - List recurring event instances
 	s.AddTool(mcp.NewTool("calendar_list_instances",
 		mcp.WithDescription("List instances of a recurring event."),
 		mcp.WithString("event_id", mcp.Required(), mcp.Description("Recurring event ID")),
 		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
-		mcp.WithString("time_min", mcp.Description("Start of time range (RFC3339)")),
-		mcp.WithString("time_max", mcp.Description("End of time range (RFC3339)")),
+		mcp.WithString("time_min", mcp.Description("Start of time range (RFC3339 or an expression like 'next monday')")),
+		mcp.WithString("time_max", mcp.Description("End of time range (RFC3339 or an expression)")),
 		mcp.WithNumber("max_results", mcp.Description("Maximum instances to return (1-250, default 25)")),
 		common.WithPageToken(),
 		common.WithAccountParam(),
@@ -112,24 +141,57 @@ func RegisterTools(s *server.MCPServer) {
 
 	// calendar_update_instance - Update single recurring event instance
 	s.AddTool(mcp.NewTool("calendar_update_instance",
-		mcp.WithDescription("Update a single instance of a recurring event."),
+		mcp.WithDescription("Update a single instance of a recurring event, or with scope 'following' this and all later instances. A 'following' edit splits the series: the original ends before this instance and a new series carrying the changes starts at it. Later cancelled instances stay cancelled; later modified instances are returned as orphaned_exceptions."),
 		mcp.WithString("instance_id", mcp.Required(), mcp.Description("Instance ID (from calendar_list_instances)")),
+		mcp.WithString("scope", mcp.Description("'this' (default) edits only this instance; 'following' edits this and all later instances")),
 		mcp.WithString("summary", mcp.Description("Event title")),
-		mcp.WithString("start_time", mcp.Description("Start time (RFC3339)")),
-		mcp.WithString("end_time", mcp.Description("End time (RFC3339)")),
+		mcp.WithString("start_time", mcp.Description("Start time: RFC3339 or an expression like 'thursday 3pm' (resolved in the calendar's time zone; a range also sets the end)")),
+		mcp.WithString("end_time", mcp.Description("End time: RFC3339 or an expression; a bare time of day is on the start's day")),
 		mcp.WithString("description", mcp.Description("Event description")),
 		mcp.WithString("location", mcp.Description("Event location")),
+		mcp.WithArray("attendees", mcp.Description("List of attendee email addresses (replaces existing; scope 'following' only)")),
 		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
 		common.WithAccountParam(),
 	), HandleCalendarUpdateInstance)
 
+	// calendar_list_exceptions - Modified and cancelled occurrences of a series
+	s.AddTool(mcp.NewTool("calendar_list_exceptions",
+		mcp.WithDescription("List the exceptions of a recurring event: occurrences that were modified (with what changed), cancelled, or excluded by EXDATE."),
+		mcp.WithString("event_id", mcp.Required(), mcp.Description("Recurring event ID (an instance ID is resolved to its series)")),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
+		common.WithAccountParam(),
+	), HandleCalendarListExceptions)
+
+	// calendar_respond - RSVP to an invitation
+	s.AddTool(mcp.NewTool("calendar_respond",
+		mcp.WithDescription("Accept, decline, or tentatively accept an event you were invited to. Only your own attendee response is changed; the rest of the event is untouched. Use instance_start to answer a single occurrence of a recurring event."),
+		mcp.WithString("event_id", mcp.Required(), mcp.Description("Event ID (a series ID, or an instance ID from calendar_list_instances)")),
+		mcp.WithString("response", mcp.Required(), mcp.Description("Response: 'accepted', 'declined', or 'tentative'")),
+		mcp.WithString("comment", mcp.Description("Optional note to the organizer, e.g. to suggest another time")),
+		mcp.WithString("instance_start", mcp.Description("Original start of the occurrence to answer (RFC3339, or YYYY-MM-DD for all-day series). Omit to answer the whole series.")),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
+		common.WithAccountParam(),
+	), HandleCalendarRespond)
+
+	// calendar_list_pending_invites - Invitations awaiting a response
+	s.AddTool(mcp.NewTool("calendar_list_pending_invites",
+		mcp.WithDescription("List events you were invited to but have not yet responded to (response status needsAction)."),
+		mcp.WithString("time_min", mcp.Description("Start of time range (RFC3339 or an expression like 'next monday'; a day also sets time_max). Defaults to now.")),
+		mcp.WithString("time_max", mcp.Description("End of time range (RFC3339 or an expression). Defaults to 30 days from now.")),
+		mcp.WithBoolean("expand_recurring", mcp.Description("List each pending occurrence of recurring series instead of the series itself (default: false)")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum events to scan (1-250, default 25)")),
+		common.WithPageToken(),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
+		common.WithAccountParam(),
+	), HandleCalendarListPending)
+
 	// === Calendar Special Events (Phase 3) ===
 
 	// calendar_create_focus_time - Create Focus Time event
 	s.AddTool(mcp.NewTool("calendar_create_focus_time",
 		mcp.WithDescription("Create a Focus Time event that auto-declines conflicting meetings."),
-		mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time (RFC3339, e.g., 2024-01-15T09:00:00-08:00)")),
-		mcp.WithString("end_time", mcp.Description("End time (RFC3339). Defaults to 1 hour after start.")),
+		mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time: RFC3339, or an expression like 'tomorrow 3pm', 'next Tuesday 10:00-11:30' or '2026-11-02 09:00 Europe/Berlin' (resolved in the calendar's time zone; a range also sets the end)")),
+		mcp.WithString("end_time", mcp.Description("End time: RFC3339 or an expression. Defaults to 1 hour after start.")),
 		mcp.WithString("summary", mcp.Description("Event title (default: 'Focus Time')")),
 		mcp.WithString("description", mcp.Description("Event description")),
 		mcp.WithString("timezone", mcp.Description("Timezone (e.g., America/Los_Angeles)")),
@@ -144,8 +206,8 @@ func RegisterTools(s *server.MCPServer) {
 	// calendar_create_out_of_office - Create Out of Office event
 	s.AddTool(mcp.NewTool("calendar_create_out_of_office",
 		mcp.WithDescription("Create an Out of Office event that auto-declines meetings."),
-		mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time (RFC3339, e.g., 2024-01-15T09:00:00-08:00)")),
-		mcp.WithString("end_time", mcp.Description("End time (RFC3339). Defaults to 1 hour after start.")),
+		mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time: RFC3339, or an expression like 'tomorrow 3pm', 'next Tuesday 10:00-11:30' or '2026-11-02 09:00 Europe/Berlin' (resolved in the calendar's time zone; a range also sets the end)")),
+		mcp.WithString("end_time", mcp.Description("End time: RFC3339 or an expression. Defaults to 1 hour after start.")),
 		mcp.WithString("summary", mcp.Description("Event title (default: 'Out of office')")),
 		mcp.WithString("description", mcp.Description("Event description")),
 		mcp.WithString("timezone", mcp.Description("Timezone (e.g., America/Los_Angeles)")),
@@ -156,4 +218,157 @@ func RegisterTools(s *server.MCPServer) {
 		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
 		common.WithAccountParam(),
 	), HandleCalendarCreateOOO)
+
+	// calendar_set_working_location - Set where you are working
+	s.AddTool(mcp.NewTool("calendar_set_working_location",
+		mcp.WithDescription("Set your working location (home, an office, or a custom place) for a day, a range of days or part of a day. Use recurrence for a regular hybrid schedule."),
+		mcp.WithString("location_type", mcp.Required(), mcp.Description("'home', 'office' or 'custom'")),
+		mcp.WithString("date", mcp.Description("Day to set (YYYY-MM-DD). Required unless start_time is given.")),
+		mcp.WithString("end_date", mcp.Description("Last day of a multi-day range (YYYY-MM-DD, inclusive)")),
+		mcp.WithString("start_time", mcp.Description("Start of a part-day location (RFC3339 or an expression like 'thursday 1pm-5pm'); use instead of date")),
+		mcp.WithString("end_time", mcp.Description("End of a part-day location (RFC3339 or an expression). Defaults to 1 hour after start.")),
+		mcp.WithString("timezone", mcp.Description("Timezone for start_time/end_time (e.g., America/Los_Angeles)")),
+		mcp.WithString("label", mcp.Description("Office or place name (required for 'custom')")),
+		mcp.WithString("building_id", mcp.Description("Office building ID (office only)")),
+		mcp.WithString("floor_id", mcp.Description("Office floor ID (office only)")),
+		mcp.WithString("floor_section_id", mcp.Description("Office floor section ID (office only)")),
+		mcp.WithString("desk_id", mcp.Description("Office desk ID (office only)")),
+		mcp.WithString("recurrence", mcp.Description("RRULE for a recurring location (e.g., 'RRULE:FREQ=WEEKLY;BYDAY=TU,TH')")),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
+		common.WithAccountParam(),
+	), HandleCalendarSetWorkingLoc)
+
+	// calendar_get_working_locations - Working locations per day across calendars
+	s.AddTool(mcp.NewTool("calendar_get_working_locations",
+		mcp.WithDescription("Show where the owners of one or more calendars are working on each day of a range (home, office or custom), e.g. to find who is in the office on Thursday. Only calendars the account can read are included."),
+		mcp.WithArray("calendar_ids", mcp.Description("Calendar IDs or colleague emails to check (default: ['primary'])")),
+		mcp.WithString("date_min", mcp.Description("First day (YYYY-MM-DD, default: today)")),
+		mcp.WithString("date_max", mcp.Description("Last day, inclusive (YYYY-MM-DD, default: 6 days after date_min, max range 62 days)")),
+		mcp.WithString("location_type", mcp.Description("Only report 'home', 'office' or 'custom' locations")),
+		mcp.WithString("timezone", mcp.Description("Time zone that defines the days (IANA name, default: the primary calendar's time zone)")),
+		common.WithAccountParam(),
+	), HandleCalendarGetWorkingLocs)
+
+	// === Calendar Import/Export ===
+
+	// calendar_export_ics - Export events to an .ics file
+	s.AddTool(mcp.NewTool("calendar_export_ics",
+		mcp.WithDescription("Export a calendar's events to an RFC 5545 .ics file, including recurrence rules, modified and cancelled occurrences, attendees and VTIMEZONE definitions."),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
+		mcp.WithString("time_min", mcp.Description("Only export events ending after this time (RFC3339 or an expression like 'jan 1' or 'in 2 weeks')")),
+		mcp.WithString("time_max", mcp.Description("Only export events starting before this time (RFC3339 or an expression)")),
+		mcp.WithString("output_path", mcp.Description("File to write (default: <temp dir>/gsuite-mcp-exports/<calendar_id>.ics). Must be inside local_files.allowed_dirs when configured.")),
+		mcp.WithBoolean("overwrite", mcp.Description("Replace an existing file (default: false)")),
+		common.WithAccountParam(),
+	), HandleCalendarExportICS)
+
+	// calendar_import_ics - Import events from an .ics file
+	s.AddTool(mcp.NewTool("calendar_import_ics",
+		mcp.WithDescription("Import the events of a local .ics file. Events are matched by UID, so re-importing a file updates the events it created instead of duplicating them."),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path of the .ics file to import. Must be inside local_files.allowed_dirs when configured.")),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
+		common.WithAccountParam(),
+	), HandleCalendarImportICS)
+
+	// === Calendar Management ===
+
+	// calendar_create_calendar - Create a secondary calendar
+	s.AddTool(mcp.NewTool("calendar_create_calendar",
+		mcp.WithDescription("Create a secondary calendar owned by the user."),
+		mcp.WithString("summary", mcp.Required(), mcp.Description("Calendar name")),
+		mcp.WithString("description", mcp.Description("Calendar description")),
+		mcp.WithString("location", mcp.Description("Geographic location of the calendar")),
+		mcp.WithString("timezone", mcp.Description("Time zone (IANA name, e.g., Europe/Berlin)")),
+		common.WithAccountParam(),
+	), HandleCalendarCreateCalendar)
+
+	// calendar_update_calendar - Update calendar metadata
+	s.AddTool(mcp.NewTool("calendar_update_calendar",
+		mcp.WithDescription("Rename a calendar or change its description, location or time zone."),
+		mcp.WithString("calendar_id", mcp.Required(), mcp.Description("Calendar ID")),
+		mcp.WithString("summary", mcp.Description("New calendar name")),
+		mcp.WithString("description", mcp.Description("New description")),
+		mcp.WithString("location", mcp.Description("New location")),
+		mcp.WithString("timezone", mcp.Description("New time zone (IANA name)")),
+		common.WithAccountParam(),
+	), HandleCalendarUpdateCalendar)
+
+	// calendar_delete_calendar - Delete a secondary calendar
+	s.AddTool(mcp.NewTool("calendar_delete_calendar",
+		mcp.WithDescription("Permanently delete a secondary calendar and all of its events. The primary calendar cannot be deleted."),
+		mcp.WithString("calendar_id", mcp.Required(), mcp.Description("Calendar ID")),
+		common.WithAccountParam(),
+	), HandleCalendarDeleteCalendar)
+
+	// calendar_list_acl - List calendar sharing rules
+	s.AddTool(mcp.NewTool("calendar_list_acl",
+		mcp.WithDescription("List who a calendar is shared with and at which role."),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
+		common.WithAccountParam(),
+	), HandleCalendarListACL)
+
+	// calendar_share - Grant access to a calendar
+	s.AddTool(mcp.NewTool("calendar_share",
+		mcp.WithDescription("Share a calendar with a user, group, domain or the public. If the scope already has access its role is changed."),
+		mcp.WithString("role", mcp.Required(), mcp.Description("Role: 'freeBusyReader', 'reader', 'writer' or 'owner'")),
+		mcp.WithString("scope_type", mcp.Description("Who to share with: 'user' (default), 'group', 'domain' or 'public'")),
+		mcp.WithString("scope_value", mcp.Description("Email address (user/group) or domain name; not used for 'public'")),
+		mcp.WithBoolean("send_notifications", mcp.Description("Email the grantee about the new access (default: true)")),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
+		common.WithAccountParam(),
+	), HandleCalendarShare)
+
+	// calendar_unshare - Remove access to a calendar
+	s.AddTool(mcp.NewTool("calendar_unshare",
+		mcp.WithDescription("Remove a sharing rule from a calendar, by rule_id or by scope_type/scope_value."),
+		mcp.WithString("rule_id", mcp.Description("ACL rule ID from calendar_list_acl (e.g., 'user:bob@example.com')")),
+		mcp.WithString("scope_type", mcp.Description("'user' (default), 'group', 'domain' or 'public' when rule_id is not given")),
+		mcp.WithString("scope_value", mcp.Description("Email address or domain name when rule_id is not given")),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
+		common.WithAccountParam(),
+	), HandleCalendarUnshare)
+
+	// calendar_subscribe - Add a calendar to the calendar list
+	s.AddTool(mcp.NewTool("calendar_subscribe",
+		mcp.WithDescription("Subscribe to an existing calendar by adding it to the user's calendar list."),
+		mcp.WithString("calendar_id", mcp.Required(), mcp.Description("ID of the calendar to add (e.g., a colleague's email or a group calendar ID)")),
+		mcp.WithString("summary_override", mcp.Description("Name to show for this calendar instead of its own")),
+		mcp.WithString("color_id", mcp.Description("Calendar color ID")),
+		mcp.WithBoolean("hidden", mcp.Description("Add the calendar hidden from the list (default: false)")),
+		common.WithAccountParam(),
+	), HandleCalendarSubscribe)
+
+	// calendar_unsubscribe - Remove a calendar from the calendar list
+	s.AddTool(mcp.NewTool("calendar_unsubscribe",
+		mcp.WithDescription("Remove a calendar from the user's calendar list without deleting it."),
+		mcp.WithString("calendar_id", mcp.Required(), mcp.Description("Calendar ID")),
+		common.WithAccountParam(),
+	), HandleCalendarUnsubscribe)
+
+	// === Cross-Account ===
+
+	// calendar_agenda - Merged agenda across accounts
+	s.AddTool(mcp.NewTool("calendar_agenda",
+		mcp.WithDescription("Merge the primary calendars of several accounts into one time-ordered agenda. Events several accounts are invited to are listed once; overlapping busy events from different accounts are marked as conflicts."),
+		mcp.WithArray("accounts", mcp.Description("Account emails to include (default: all authenticated accounts)")),
+		mcp.WithString("time_min", mcp.Description("Start of the range (RFC3339 or an expression like 'next monday', resolved in timezone or the first account's zone; default: now)")),
+		mcp.WithString("time_max", mcp.Description("End of the range (RFC3339 or an expression, default: 7 days after time_min)")),
+		mcp.WithString("timezone", mcp.Description("Time zone for returned times (IANA name, default: the first account's calendar time zone)")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum events read per account (1-1000, default 250)")),
+		common.WithAccountParam(),
+	), HandleCalendarAgenda)
+
+	// === Meeting Prep ===
+
+	// calendar_meeting_brief - Gather context before a meeting
+	s.AddTool(mcp.NewTool("calendar_meeting_brief",
+		mcp.WithDescription("Prepare for a meeting: returns the event with sections for attendee contact details, recent Gmail threads with the attendees, Drive files attached to the event or recently shared by attendees, and the Meet transcript of the previous occurrence of a recurring event. Sections are fetched in parallel and each is size-limited; a section that fails reports status 'error' without failing the others."),
+		mcp.WithString("event_id", mcp.Required(), mcp.Description("Event ID (for recurring events, the ID of an occurrence)")),
+		mcp.WithString("calendar_id", mcp.Description("Calendar ID (default: 'primary')")),
+		mcp.WithArray("sections", mcp.Description("Sections to include: 'attendees', 'emails', 'files', 'transcript' (default: all)")),
+		mcp.WithNumber("max_threads", mcp.Description("Maximum Gmail threads (1-20, default 5)")),
+		mcp.WithNumber("lookback_days", mcp.Description("How far back to search mail and shared files (default 30, max 365)")),
+		mcp.WithNumber("max_section_bytes", mcp.Description("Size budget per section in bytes (minimum 500; default depends on the section)")),
+		common.WithAccountParam(),
+	), HandleCalendarMeetingBrief)
 }
@@ -0,0 +1,275 @@
+package calendar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RRule is a parsed RFC 5545 recurrence rule (RRULE). Parts keep their
+// original order so a rule round-trips unchanged apart from explicit edits.
+type RRule struct {
+	parts []rrulePart
+}
+
+type rrulePart struct {
+	Key   string
+	Value string
+}
+
+// rruleUntilLayout and rruleDateLayout are the UTC date-time and date forms of UNTIL.
+const (
+	rruleUntilLayout = "20060102T150405Z"
+	rruleDateLayout  = "20060102"
+)
+
+// ParseRRule parses "RRULE:FREQ=WEEKLY;BYDAY=MO" (the "RRULE:" prefix is optional).
+func ParseRRule(s string) (*RRule, error) {
+	body := strings.TrimSpace(s)
+	if len(body) >= 6 && strings.EqualFold(body[:6], "RRULE:") {
+		body = body[6:]
+	}
+	if body == "" {
+		return nil, fmt.Errorf("empty RRULE")
+	}
+
+	r := &RRule{}
+	for _, seg := range strings.Split(body, ";") {
+		if seg == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(seg, "=")
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("invalid RRULE part %q", seg)
+		}
+		r.parts = append(r.parts, rrulePart{Key: strings.ToUpper(k), Value: v})
+	}
+	if r.Get("FREQ") == "" {
+		return nil, fmt.Errorf("RRULE %q has no FREQ", s)
+	}
+	if r.Get("COUNT") != "" && r.Get("UNTIL") != "" {
+		return nil, fmt.Errorf("RRULE %q sets both COUNT and UNTIL", s)
+	}
+	return r, nil
+}
+
+// Get returns the value of a rule part, or "" when absent.
+func (r *RRule) Get(key string) string {
+	key = strings.ToUpper(key)
+	for _, p := range r.parts {
+		if p.Key == key {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// Set replaces a rule part in place, or appends it when absent.
+func (r *RRule) Set(key, value string) {
+	key = strings.ToUpper(key)
+	for i, p := range r.parts {
+		if p.Key == key {
+			r.parts[i].Value = value
+			return
+		}
+	}
+	r.parts = append(r.parts, rrulePart{Key: key, Value: value})
+}
+
+// Del removes a rule part.
+func (r *RRule) Del(key string) {
+	key = strings.ToUpper(key)
+	kept := r.parts[:0]
+	for _, p := range r.parts {
+		if p.Key != key {
+			kept = append(kept, p)
+		}
+	}
+	r.parts = kept
+}
+
+// Count returns the COUNT part, or 0 when the rule is not count-bounded.
+func (r *RRule) Count() int {
+	n, _ := strconv.Atoi(r.Get("COUNT"))
+	return n
+}
+
+// SetUntil bounds the rule to end at t (inclusive), replacing any COUNT.
+// Timed series use the UTC date-time form RFC 5545 requires when DTSTART has a
+// time zone; all-day series use a DATE.
+func (r *RRule) SetUntil(t time.Time, allDay bool) {
+	r.Del("COUNT")
+	if allDay {
+		r.Set("UNTIL", t.Format(rruleDateLayout))
+		return
+	}
+	r.Set("UNTIL", t.UTC().Format(rruleUntilLayout))
+}
+
+// String formats the rule as an "RRULE:" recurrence line.
+func (r *RRule) String() string {
+	segs := make([]string, len(r.parts))
+	for i, p := range r.parts {
+		segs[i] = p.Key + "=" + p.Value
+	}
+	return "RRULE:" + strings.Join(segs, ";")
+}
+
+// recurrenceHasCount reports whether any RRULE in recurrence is count-bounded.
+func recurrenceHasCount(recurrence []string) bool {
+	for _, line := range recurrence {
+		if !strings.HasPrefix(strings.ToUpper(line), "RRULE:") {
+			continue
+		}
+		if r, err := ParseRRule(line); err == nil && r.Count() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitRecurrence splits a series' recurrence lines at the occurrence starting
+// at. The before lines end the original series just ahead of at (UNTIL);
+// the after lines drive a new series starting at at. occurrencesBefore is the
+// number of occurrences the original series has before at, used to carry a
+// COUNT bound over to the new series. EXDATE and RDATE values are divided
+// between the two halves; floating values are read in loc.
+func SplitRecurrence(recurrence []string, at time.Time, allDay bool, loc *time.Location, occurrencesBefore int) (before, after []string, err error) {
+	for _, line := range recurrence {
+		name, _, _ := strings.Cut(line, ":")
+		name, _, _ = strings.Cut(name, ";")
+		switch strings.ToUpper(name) {
+		case "RRULE":
+			head, err := ParseRRule(line)
+			if err != nil {
+				return nil, nil, err
+			}
+			tail, _ := ParseRRule(line)
+			if count := head.Count(); count > 0 {
+				if count <= occurrencesBefore {
+					return nil, nil, fmt.Errorf("series has only %d occurrences; nothing to split at %s", count, at.Format(time.RFC3339))
+				}
+				tail.Set("COUNT", strconv.Itoa(count-occurrencesBefore))
+			}
+			if allDay {
+				head.SetUntil(at.AddDate(0, 0, -1), true)
+			} else {
+				head.SetUntil(at.Add(-time.Second), false)
+			}
+			before = append(before, head.String())
+			after = append(after, tail.String())
+		case "EXDATE", "RDATE":
+			early, late, err := splitRecurrenceDates(line, at, loc)
+			if err != nil {
+				return nil, nil, err
+			}
+			if early != "" {
+				before = append(before, early)
+			}
+			if late != "" {
+				after = append(after, late)
+			}
+		default:
+			before = append(before, line)
+			after = append(after, line)
+		}
+	}
+	return before, after, nil
+}
+
+// shiftRecurrenceDates moves the EXDATE and RDATE values of a recurrence by
+// the wall-clock offset in loc between from and to, so they still match the
+// occurrences of a series whose start moved from from to to. Shifted values
+// are written as UTC date-times, or as dates for an all-day series.
+func shiftRecurrenceDates(recurrence []string, from, to time.Time, allDay bool, loc *time.Location) ([]string, error) {
+	if allDay {
+		loc = time.UTC
+	}
+	wall := func(t time.Time) time.Time {
+		t = t.In(loc)
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	}
+	delta := wall(to).Sub(wall(from))
+	if delta == 0 {
+		return recurrence, nil
+	}
+
+	shifted := make([]string, 0, len(recurrence))
+	for _, line := range recurrence {
+		name, _, _ := strings.Cut(line, ":")
+		name, _, _ = strings.Cut(name, ";")
+		name = strings.ToUpper(name)
+		if name != "EXDATE" && name != "RDATE" {
+			shifted = append(shifted, line)
+			continue
+		}
+		_, values, err := parseRecurrenceDates(line, loc)
+		if err != nil {
+			return nil, err
+		}
+		vals := make([]string, 0, len(values))
+		for _, v := range values {
+			w := wall(v.Time).Add(delta)
+			t := time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), 0, loc)
+			if allDay {
+				vals = append(vals, t.Format("20060102"))
+			} else {
+				vals = append(vals, t.UTC().Format("20060102T150405Z"))
+			}
+		}
+		if allDay {
+			shifted = append(shifted, name+";VALUE=DATE:"+strings.Join(vals, ","))
+		} else {
+			shifted = append(shifted, name+":"+strings.Join(vals, ","))
+		}
+	}
+	return shifted, nil
+}
+
+// splitRecurrenceDates divides an EXDATE/RDATE line's values into those before
+// at and those at or after it, returning each half as a line ("" when empty).
+func splitRecurrenceDates(line string, at time.Time, loc *time.Location) (early, late string, err error) {
+	prop, values, err := parseRecurrenceDates(line, loc)
+	if err != nil {
+		return "", "", err
+	}
+	head, _, _ := strings.Cut(line, ":")
+	var earlyVals, lateVals []string
+	raw := strings.Split(prop.Value, ",")
+	for i, v := range values {
+		if v.Time.Before(at) {
+			earlyVals = append(earlyVals, raw[i])
+		} else {
+			lateVals = append(lateVals, raw[i])
+		}
+	}
+	if len(earlyVals) > 0 {
+		early = head + ":" + strings.Join(earlyVals, ",")
+	}
+	if len(lateVals) > 0 {
+		late = head + ":" + strings.Join(lateVals, ",")
+	}
+	return early, late, nil
+}
+
+// parseRecurrenceDates parses the comma-separated values of an EXDATE or RDATE
+// line. Floating date-times (no TZID, no Z) are interpreted in loc.
+func parseRecurrenceDates(line string, loc *time.Location) (*ICSProperty, []*ICSTime, error) {
+	prop, err := parseICSContentLine(line)
+	if err != nil {
+		return nil, nil, err
+	}
+	var values []*ICSTime
+	for _, v := range strings.Split(prop.Value, ",") {
+		t, err := ParseICSTime(&ICSProperty{Name: prop.Name, Params: prop.Params, Value: v})
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", prop.Name, err)
+		}
+		if t.Floating && loc != nil {
+			t.Time = time.Date(t.Time.Year(), t.Time.Month(), t.Time.Day(), t.Time.Hour(), t.Time.Minute(), t.Time.Second(), 0, loc)
+		}
+		values = append(values, t)
+	}
+	return prop, values, nil
+}
@@ -0,0 +1,129 @@
+package calendar
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	r, err := ParseRRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Get("freq") != "WEEKLY" || r.Get("BYDAY") != "MO,WE" || r.Count() != 10 {
+		t.Errorf("unexpected parts: %s", r)
+	}
+	if got := r.String(); got != "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10" {
+		t.Errorf("round trip = %q", got)
+	}
+
+	// The prefix is optional.
+	if r, err := ParseRRule("FREQ=DAILY"); err != nil || r.String() != "RRULE:FREQ=DAILY" {
+		t.Errorf("unprefixed rule: %v, %v", r, err)
+	}
+
+	for _, bad := range []string{"", "RRULE:", "RRULE:INTERVAL=2", "RRULE:FREQ=DAILY;COUNT=3;UNTIL=20240101", "RRULE:FREQ"} {
+		if _, err := ParseRRule(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestRRuleSetUntil(t *testing.T) {
+	r, _ := ParseRRule("RRULE:FREQ=WEEKLY;COUNT=10;BYDAY=MO")
+	ny, _ := time.LoadLocation("America/New_York")
+	r.SetUntil(time.Date(2024, 3, 11, 9, 59, 59, 0, ny), false)
+	if got := r.String(); got != "RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20240311T135959Z" {
+		t.Errorf("timed UNTIL = %q", got)
+	}
+	r.SetUntil(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), true)
+	if got := r.Get("UNTIL"); got != "20240310" {
+		t.Errorf("date UNTIL = %q", got)
+	}
+}
+
+func TestSplitRecurrence(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	at := time.Date(2024, 3, 11, 10, 0, 0, 0, ny)
+
+	tests := []struct {
+		name       string
+		recurrence []string
+		before     int
+		allDay     bool
+		at         time.Time
+		wantBefore []string
+		wantAfter  []string
+		wantErr    bool
+	}{
+		{
+			name:       "open-ended rule",
+			recurrence: []string{"RRULE:FREQ=WEEKLY;BYDAY=MO"},
+			at:         at,
+			wantBefore: []string{"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20240311T135959Z"},
+			wantAfter:  []string{"RRULE:FREQ=WEEKLY;BYDAY=MO"},
+		},
+		{
+			name:       "count carried over",
+			recurrence: []string{"RRULE:FREQ=WEEKLY;COUNT=10;BYDAY=MO"},
+			before:     4,
+			at:         at,
+			wantBefore: []string{"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20240311T135959Z"},
+			wantAfter:  []string{"RRULE:FREQ=WEEKLY;COUNT=6;BYDAY=MO"},
+		},
+		{
+			name:       "count exhausted",
+			recurrence: []string{"RRULE:FREQ=WEEKLY;COUNT=3"},
+			before:     3,
+			at:         at,
+			wantErr:    true,
+		},
+		{
+			name: "exdates divided",
+			recurrence: []string{
+				"RRULE:FREQ=WEEKLY;BYDAY=MO",
+				"EXDATE;TZID=America/New_York:20240304T100000,20240318T100000",
+				"EXDATE:20240325T140000Z",
+			},
+			at: at,
+			wantBefore: []string{
+				"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20240311T135959Z",
+				"EXDATE;TZID=America/New_York:20240304T100000",
+			},
+			wantAfter: []string{
+				"RRULE:FREQ=WEEKLY;BYDAY=MO",
+				"EXDATE;TZID=America/New_York:20240318T100000",
+				"EXDATE:20240325T140000Z",
+			},
+		},
+		{
+			name:       "all-day series",
+			recurrence: []string{"RRULE:FREQ=DAILY", "EXDATE;VALUE=DATE:20240301"},
+			allDay:     true,
+			at:         time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+			wantBefore: []string{"RRULE:FREQ=DAILY;UNTIL=20240310", "EXDATE;VALUE=DATE:20240301"},
+			wantAfter:  []string{"RRULE:FREQ=DAILY"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			before, after, err := SplitRecurrence(tc.recurrence, tc.at, tc.allDay, ny, tc.before)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(before, tc.wantBefore) {
+				t.Errorf("before = %q, want %q", before, tc.wantBefore)
+			}
+			if !reflect.DeepEqual(after, tc.wantAfter) {
+				t.Errorf("after = %q, want %q", after, tc.wantAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package calendar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// Slot-finder defaults.
+const (
+	slotDefaultWorkingHours = "09:00-17:00"
+	slotDefaultWindowDays   = 7
+	slotDefaultGranularity  = 30 // minutes
+	slotDefaultMaxResults   = 10
+	slotMaxResultsLimit     = 50
+	slotMaxWindowDays       = 62
+)
+
+// timeSpan is a half-open interval [Start, End).
+type timeSpan struct {
+	Start time.Time
+	End   time.Time
+}
+
+// workingHours is a daily local-time range, in minutes after midnight.
+type workingHours struct {
+	StartMin int
+	EndMin   int
+}
+
+// parseWorkingHours parses "HH:MM-HH:MM" (24-hour clock).
+func parseWorkingHours(s string) (workingHours, error) {
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return workingHours{}, fmt.Errorf("invalid working hours %q: expected HH:MM-HH:MM", s)
+	}
+	start, err := parseClock(startStr)
+	if err != nil {
+		return workingHours{}, fmt.Errorf("invalid working hours %q: %w", s, err)
+	}
+	end, err := parseClock(endStr)
+	if err != nil {
+		return workingHours{}, fmt.Errorf("invalid working hours %q: %w", s, err)
+	}
+	if end <= start {
+		return workingHours{}, fmt.Errorf("invalid working hours %q: end must be after start", s)
+	}
+	return workingHours{StartMin: start, EndMin: end}, nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight; "24:00" is allowed as an end of day.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		if strings.TrimSpace(s) == "24:00" {
+			return 24 * 60, nil
+		}
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// String formats the working hours back as "HH:MM-HH:MM".
+func (w workingHours) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.StartMin/60, w.StartMin%60, w.EndMin/60, w.EndMin%60)
+}
+
+// slotAttendee is one participant whose availability constrains the search.
+type slotAttendee struct {
+	ID       string // calendar ID or email address passed to FreeBusy
+	Location *time.Location
+	Hours    workingHours
+}
+
+// workingSpans returns the attendee's working-hour intervals that overlap window,
+// computed day by day in the attendee's own time zone so DST shifts are honoured.
+func (a slotAttendee) workingSpans(window timeSpan, includeWeekends bool) []timeSpan {
+	var spans []timeSpan
+	first := window.Start.In(a.Location).AddDate(0, 0, -1)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, a.Location)
+	for !day.After(window.End) {
+		if includeWeekends || (day.Weekday() != time.Saturday && day.Weekday() != time.Sunday) {
+			// Wall-clock times, not offsets from midnight, which move by an
+			// hour on the day DST starts or ends.
+			span := timeSpan{
+				Start: a.wallClock(day, a.Hours.StartMin),
+				End:   a.wallClock(day, a.Hours.EndMin),
+			}
+			if clipped, ok := intersectSpan(span, window); ok {
+				spans = append(spans, clipped)
+			}
+		}
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, a.Location)
+	}
+	return spans
+}
+
+// wallClock returns the time minutes after midnight on day's date, read on
+// the attendee's clock. 24:00 is midnight at the end of the day.
+func (a slotAttendee) wallClock(day time.Time, minutes int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minutes/60, minutes%60, 0, 0, a.Location)
+}
+
+// intersectSpan returns the overlap of a and b, if any.
+func intersectSpan(a, b timeSpan) (timeSpan, bool) {
+	start, end := a.Start, a.End
+	if b.Start.After(start) {
+		start = b.Start
+	}
+	if b.End.Before(end) {
+		end = b.End
+	}
+	if !end.After(start) {
+		return timeSpan{}, false
+	}
+	return timeSpan{Start: start, End: end}, true
+}
+
+// mergeSpans sorts spans and merges overlapping or adjacent ones.
+func mergeSpans(spans []timeSpan) []timeSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	sorted := append([]timeSpan(nil), spans...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+	merged := []timeSpan{sorted[0]}
+	for _, s := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if !s.Start.After(last.End) {
+			if s.End.After(last.End) {
+				last.End = s.End
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// subtractSpans removes every busy interval from free. Both inputs may be unsorted.
+func subtractSpans(free, busy []timeSpan) []timeSpan {
+	busy = mergeSpans(busy)
+	var out []timeSpan
+	for _, f := range mergeSpans(free) {
+		cur := f
+		for _, b := range busy {
+			if !b.End.After(cur.Start) || !b.Start.Before(cur.End) {
+				continue
+			}
+			if b.Start.After(cur.Start) {
+				out = append(out, timeSpan{Start: cur.Start, End: b.Start})
+			}
+			cur.Start = b.End
+			if !cur.End.After(cur.Start) {
+				break
+			}
+		}
+		if cur.End.After(cur.Start) {
+			out = append(out, cur)
+		}
+	}
+	return out
+}
+
+// intersectSpanSets returns the intervals present in both a and b.
+func intersectSpanSets(a, b []timeSpan) []timeSpan {
+	a, b = mergeSpans(a), mergeSpans(b)
+	var out []timeSpan
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if s, ok := intersectSpan(a[i], b[j]); ok {
+			out = append(out, s)
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+// busySpans converts FreeBusy periods into spans, widened by buffer on both sides.
+func busySpans(periods []*calendar.TimePeriod, buffer time.Duration) ([]timeSpan, error) {
+	spans := make([]timeSpan, 0, len(periods))
+	for _, p := range periods {
+		start, err := time.Parse(time.RFC3339, p.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid busy start %q: %w", p.Start, err)
+		}
+		end, err := time.Parse(time.RFC3339, p.End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid busy end %q: %w", p.End, err)
+		}
+		spans = append(spans, timeSpan{Start: start.Add(-buffer), End: end.Add(buffer)})
+	}
+	return spans, nil
+}
+
+// rankedSlot is a candidate meeting time with its ranking score.
+type rankedSlot struct {
+	timeSpan
+	Score float64
+}
+
+// candidateSlots walks each free interval in granularity steps, aligned on
+// loc's clock, and returns every start that fits duration, ranked best first.
+//
+// The score favours slots that sit comfortably inside every attendee's working
+// day (1.0 is mid-day for everyone, 0.0 touches someone's start or end of day)
+// and lightly penalises later days so earlier options win ties.
+func candidateSlots(free []timeSpan, duration, granularity time.Duration, attendees []slotAttendee, windowStart time.Time, loc *time.Location) []rankedSlot {
+	var slots []rankedSlot
+	for _, f := range free {
+		// Zones offset by :30 or :45 (India, Nepal) must not inherit UTC's grid.
+		_, offset := f.Start.In(loc).Zone()
+		shift := time.Duration(offset) * time.Second
+		start := f.Start.Add(shift).Truncate(granularity).Add(-shift)
+		if start.Before(f.Start) {
+			start = start.Add(granularity)
+		}
+		for ; !start.Add(duration).After(f.End); start = start.Add(granularity) {
+			span := timeSpan{Start: start, End: start.Add(duration)}
+			dayOffset := math.Floor(span.Start.Sub(windowStart).Hours() / 24)
+			score := slotComfort(span, attendees) - 0.05*dayOffset
+			slots = append(slots, rankedSlot{timeSpan: span, Score: math.Round(score*100) / 100})
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].Score != slots[j].Score {
+			return slots[i].Score > slots[j].Score
+		}
+		return slots[i].Start.Before(slots[j].Start)
+	})
+	return slots
+}
+
+// slotComfort is the worst-case distance, across attendees, between the slot
+// and the edge of that attendee's working day, normalised to [0, 1].
+func slotComfort(span timeSpan, attendees []slotAttendee) float64 {
+	comfort := 1.0
+	for _, a := range attendees {
+		local := span.Start.In(a.Location)
+		startMin := float64(local.Hour()*60 + local.Minute())
+		length := span.End.Sub(span.Start).Minutes()
+		endMin := startMin + length
+		half := (float64(a.Hours.EndMin-a.Hours.StartMin) - length) / 2
+		if half <= 0 {
+			continue
+		}
+		margin := math.Min(startMin-float64(a.Hours.StartMin), float64(a.Hours.EndMin)-endMin)
+		c := math.Max(0, math.Min(1, margin/half))
+		if c < comfort {
+			comfort = c
+		}
+	}
+	return comfort
+}
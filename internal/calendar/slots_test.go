@@ -0,0 +1,292 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return v
+}
+
+func TestParseWorkingHours(t *testing.T) {
+	wh, err := parseWorkingHours("08:30-17:00")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wh.StartMin != 510 || wh.EndMin != 1020 || wh.String() != "08:30-17:00" {
+		t.Errorf("got %+v (%s)", wh, wh)
+	}
+	if wh, err := parseWorkingHours("00:00-24:00"); err != nil || wh.EndMin != 1440 {
+		t.Errorf("24:00 end: %+v, %v", wh, err)
+	}
+	for _, bad := range []string{"9-5", "17:00-09:00", "09:00", "aa:bb-cc:dd"} {
+		if _, err := parseWorkingHours(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestSpanArithmetic(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2024, 3, 4, h, 0, 0, 0, time.UTC) }
+	free := []timeSpan{{at(9), at(17)}}
+	busy := []timeSpan{{at(12), at(13)}, {at(8), at(10)}, {at(12), at(14)}}
+
+	got := subtractSpans(free, busy)
+	want := []timeSpan{{at(10), at(12)}, {at(14), at(17)}}
+	if len(got) != len(want) {
+		t.Fatalf("subtract = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Errorf("subtract[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	inter := intersectSpanSets(got, []timeSpan{{at(11), at(15)}})
+	if len(inter) != 2 || !inter[0].Start.Equal(at(11)) || !inter[1].End.Equal(at(15)) {
+		t.Errorf("intersect = %v", inter)
+	}
+}
+
+func TestWorkingSpansTimeZones(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	london, _ := time.LoadLocation("Europe/London")
+	hours := workingHours{StartMin: 9 * 60, EndMin: 17 * 60}
+
+	// Monday 4 March 2024, whole UTC day.
+	window := timeSpan{Start: mustTime(t, "2024-03-04T00:00:00Z"), End: mustTime(t, "2024-03-05T00:00:00Z")}
+	nySpans := slotAttendee{Location: ny, Hours: hours}.workingSpans(window, false)
+	ldnSpans := slotAttendee{Location: london, Hours: hours}.workingSpans(window, false)
+
+	// New York 09:00-17:00 EST is 14:00-22:00 UTC; London is 09:00-17:00 UTC.
+	overlap := intersectSpanSets(nySpans, ldnSpans)
+	if len(overlap) != 1 ||
+		!overlap[0].Start.Equal(mustTime(t, "2024-03-04T14:00:00Z")) ||
+		!overlap[0].End.Equal(mustTime(t, "2024-03-04T17:00:00Z")) {
+		t.Errorf("overlap = %v, want 14:00-17:00 UTC", overlap)
+	}
+
+	// Weekends are skipped unless requested.
+	weekend := timeSpan{Start: mustTime(t, "2024-03-02T00:00:00Z"), End: mustTime(t, "2024-03-04T00:00:00Z")}
+	if spans := (slotAttendee{Location: london, Hours: hours}).workingSpans(weekend, false); len(spans) != 0 {
+		t.Errorf("expected no weekend spans, got %v", spans)
+	}
+	if spans := (slotAttendee{Location: london, Hours: hours}).workingSpans(weekend, true); len(spans) != 2 {
+		t.Errorf("expected 2 weekend spans, got %v", spans)
+	}
+}
+
+func TestWorkingSpansDST(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	attendee := slotAttendee{Location: ny, Hours: workingHours{StartMin: 9 * 60, EndMin: 17 * 60}}
+
+	// Clocks go forward on Sunday 10 March 2024 and back on Sunday 3 November
+	// 2024; working hours stay 09:00-17:00 local on both days.
+	for _, tc := range []struct{ day, start, end string }{
+		{"2024-03-10", "2024-03-10T13:00:00Z", "2024-03-10T21:00:00Z"},
+		{"2024-11-03", "2024-11-03T14:00:00Z", "2024-11-03T22:00:00Z"},
+	} {
+		window := timeSpan{Start: mustTime(t, tc.day+"T00:00:00Z"), End: mustTime(t, tc.day+"T23:59:00Z")}
+		spans := attendee.workingSpans(window, true)
+		if len(spans) != 1 || !spans[0].Start.Equal(mustTime(t, tc.start)) || !spans[0].End.Equal(mustTime(t, tc.end)) {
+			t.Errorf("%s: spans = %v, want %s-%s", tc.day, spans, tc.start, tc.end)
+		}
+	}
+}
+
+func TestCandidateSlotsRanking(t *testing.T) {
+	hours := workingHours{StartMin: 9 * 60, EndMin: 17 * 60}
+	attendees := []slotAttendee{{ID: "a", Location: time.UTC, Hours: hours}}
+	day := timeSpan{Start: mustTime(t, "2024-03-04T09:00:00Z"), End: mustTime(t, "2024-03-04T17:00:00Z")}
+
+	slots := candidateSlots([]timeSpan{day}, time.Hour, 30*time.Minute, attendees, day.Start, time.UTC)
+	if len(slots) != 15 {
+		t.Fatalf("got %d slots, want 15", len(slots))
+	}
+	// The centred slot ranks first; the day edges rank last.
+	if !slots[0].Start.Equal(mustTime(t, "2024-03-04T12:30:00Z")) || slots[0].Score != 1 {
+		t.Errorf("best slot = %v (score %v), want 12:30 with score 1", slots[0].Start, slots[0].Score)
+	}
+	if last := slots[len(slots)-1]; last.Score != 0 {
+		t.Errorf("worst slot score = %v, want 0", last.Score)
+	}
+}
+
+func TestCandidateSlotsAlignInTimeZone(t *testing.T) {
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	attendees := []slotAttendee{{ID: "a", Location: kolkata, Hours: workingHours{StartMin: 9 * 60, EndMin: 17 * 60}}}
+	// 09:10-11:00 in Kolkata (UTC+5:30).
+	free := timeSpan{Start: mustTime(t, "2024-03-04T03:40:00Z"), End: mustTime(t, "2024-03-04T05:30:00Z")}
+
+	slots := candidateSlots([]timeSpan{free}, 30*time.Minute, time.Hour, attendees, free.Start, kolkata)
+	if len(slots) != 1 || slots[0].Start.In(kolkata).Format("15:04") != "10:00" {
+		t.Errorf("slots = %v, want one starting 10:00 Kolkata time", slots)
+	}
+}
+
+func TestCalendarFindSlots(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	fixtures.MockService.Calendars["primary"].TimeZone = "Europe/London"
+	fixtures.MockService.FreeBusy["primary"] = []*calendar.TimePeriod{
+		{Start: "2024-03-04T14:00:00Z", End: "2024-03-04T15:00:00Z"},
+	}
+	fixtures.MockService.FreeBusy["bob@example.com"] = []*calendar.TimePeriod{
+		{Start: "2024-03-04T16:00:00Z", End: "2024-03-04T16:30:00Z"},
+	}
+
+	args := map[string]any{
+		"attendees": []any{
+			map[string]any{"email": "bob@example.com", "timezone": "America/New_York"},
+		},
+		"duration_minutes":    float64(30),
+		"window_start":        "2024-03-04T00:00:00Z",
+		"window_end":          "2024-03-05T00:00:00Z",
+		"buffer_minutes":      float64(15),
+		"granularity_minutes": float64(15),
+	}
+	result, err := TestableCalendarFindSlots(context.Background(), CreateMCPRequest(args), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if data["timezone"] != "Europe/London" {
+		t.Errorf("timezone = %v, want calendar time zone Europe/London", data["timezone"])
+	}
+
+	// Shared working time is 14:00-17:00 UTC (London GMT, New York EST). The
+	// buffered meetings block 13:45-15:15 and 15:45-16:45, leaving 15:15-15:45
+	// and 16:45-17:00; only the first fits 30 minutes.
+	slots := data["slots"].([]any)
+	if len(slots) != 1 {
+		t.Fatalf("got %d slots, want 1: %v", len(slots), slots)
+	}
+	slot := slots[0].(map[string]any)
+	if slot["start"] != "2024-03-04T15:15:00Z" || slot["end"] != "2024-03-04T15:45:00Z" {
+		t.Errorf("slot = %v-%v, want 15:15-15:45", slot["start"], slot["end"])
+	}
+	local := slot["local"].(map[string]any)
+	if local["bob@example.com"] != "Mon 2024-03-04 10:15-10:45 America/New_York" {
+		t.Errorf("bob local time = %v", local["bob@example.com"])
+	}
+}
+
+func TestCalendarFindSlots_UnreadableAttendeeHours(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	fixtures.MockService.FreeBusyErrors = map[string]string{"ext@partner.com": "notFound"}
+	args := map[string]any{
+		"attendees": []any{
+			map[string]any{"email": "ext@partner.com", "timezone": "America/New_York", "working_hours": "09:00-12:00"},
+		},
+		"duration_minutes":    float64(60),
+		"window_start":        "2024-03-04T00:00:00Z",
+		"window_end":          "2024-03-05T00:00:00Z",
+		"timezone":            "UTC",
+		"granularity_minutes": float64(60),
+	}
+	result, _ := TestableCalendarFindSlots(context.Background(), CreateMCPRequest(args), fixtures.Deps)
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	// The external calendar cannot be read, but its 09:00-12:00 New York hours
+	// (14:00-17:00 UTC) still limit the 09:00-17:00 UTC working day.
+	slots := data["slots"].([]any)
+	if len(slots) != 3 {
+		t.Fatalf("got %d slots, want 3: %v", len(slots), slots)
+	}
+	for _, s := range slots {
+		slot := s.(map[string]any)
+		start := mustTime(t, slot["start"].(string))
+		if start.Before(mustTime(t, "2024-03-04T14:00:00Z")) || start.After(mustTime(t, "2024-03-04T16:00:00Z")) {
+			t.Errorf("slot %v is outside the external attendee's working hours", slot["start"])
+		}
+		if _, ok := slot["local"].(map[string]any)["ext@partner.com"]; !ok {
+			t.Errorf("expected the external attendee's local time, got %v", slot["local"])
+		}
+	}
+}
+
+func TestCalendarFindSlots_CreateEvent(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	args := map[string]any{
+		"attendees":        []any{"bob@example.com"},
+		"duration_minutes": float64(60),
+		"window_start":     "2024-03-04T00:00:00Z",
+		"window_end":       "2024-03-05T00:00:00Z",
+		"timezone":         "UTC",
+		"create_event":     true,
+		"summary":          "Planning",
+	}
+	result, _ := TestableCalendarFindSlots(context.Background(), CreateMCPRequest(args), fixtures.Deps)
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	created, ok := data["created_event"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected created_event, got %s", text)
+	}
+	best := data["slots"].([]any)[0].(map[string]any)
+	if created["start"] != best["start"] || created["summary"] != "Planning" {
+		t.Errorf("created %v, want start %v", created, best["start"])
+	}
+	ev := fixtures.MockService.Events["primary"][created["id"].(string)]
+	if len(ev.Attendees) != 1 || ev.Attendees[0].Email != "bob@example.com" {
+		t.Errorf("attendees = %v, want only bob", ev.Attendees)
+	}
+}
+
+func TestCalendarFindSlots_Validation(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	fixtures.MockService.FreeBusyErrors = map[string]string{"ext@partner.com": "notFound"}
+	tests := []struct {
+		name       string
+		args       map[string]any
+		errContain string
+	}{
+		{"missing duration", map[string]any{"attendees": []any{"a@example.com"}}, "duration_minutes"},
+		{"bad working hours", map[string]any{"attendees": []any{"a@example.com"}, "duration_minutes": float64(30), "working_hours": "9-5"}, "working hours"},
+		{"bad timezone", map[string]any{"attendees": []any{"a@example.com"}, "duration_minutes": float64(30), "timezone": "Mars/Base"}, "timezone"},
+		{"window too long", map[string]any{"attendees": []any{"a@example.com"}, "duration_minutes": float64(30), "window_start": "2024-01-01T00:00:00Z", "window_end": "2024-06-01T00:00:00Z"}, "cannot exceed"},
+		{"slot out of range", map[string]any{"attendees": []any{"a@example.com"}, "duration_minutes": float64(30), "window_start": "2024-03-02T00:00:00Z", "window_end": "2024-03-03T00:00:00Z", "create_event": true}, "out of range"},
+		{"no readable calendar", map[string]any{"attendees": []any{"ext@partner.com"}, "duration_minutes": float64(30), "include_self": false}, "ext@partner.com: notFound"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := TestableCalendarFindSlots(context.Background(), CreateMCPRequest(tc.args), fixtures.Deps)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError || !strings.Contains(getCalendarTextContent(result), tc.errContain) {
+				t.Errorf("expected error containing %q, got %s", tc.errContain, getCalendarTextContent(result))
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	// agendaConcurrency bounds how many accounts are queried at once.
+	agendaConcurrency = 5
+	// agendaDefaultDays is the window used when time_max is not given.
+	agendaDefaultDays = 7
+	// agendaDefaultMaxResults and agendaMaxResultsLimit bound events read per account.
+	agendaDefaultMaxResults = 250
+	agendaMaxResultsLimit   = 1000
+)
+
+// agendaEntry is one event in the merged agenda, possibly seen from several accounts.
+type agendaEntry struct {
+	event     *calendar.Event
+	account   string // account the event was first read from
+	accounts  []string
+	responses map[string]string // account -> self response status
+	start     time.Time
+	end       time.Time
+	allDay    bool
+	conflicts []*agendaEntry
+}
+
+// busy reports whether the entry blocks time for conflict detection: timed,
+// opaque, and not declined by every account that sees it.
+func (e *agendaEntry) busy() bool {
+	if e.allDay || e.event.Transparency == "transparent" || e.event.Status == "cancelled" {
+		return false
+	}
+	for _, account := range e.accounts {
+		if e.responses[account] != "declined" {
+			return true
+		}
+	}
+	return false
+}
+
+// sharesAccount reports whether both entries are on a common account's
+// calendar. Such overlaps are already visible in that calendar, so only
+// overlaps between entries with no account in common count as conflicts.
+func (e *agendaEntry) sharesAccount(other *agendaEntry) bool {
+	for _, a := range e.accounts {
+		for _, b := range other.accounts {
+			if a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// agendaAccountResult is what one account contributed to the agenda.
+type agendaAccountResult struct {
+	account  string
+	events   []*calendar.Event
+	timeZone string
+	err      error
+}
+
+// TestableCalendarAgenda merges the primary calendars of several accounts into
+// one time-ordered agenda. Events that several accounts are invited to (same
+// iCalUID and start) are listed once, and overlapping busy events from
+// different accounts are marked as conflicts.
+func TestableCalendarAgenda(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	accounts := parseUniqueStrings(args, "accounts")
+	if account := common.ParseStringArg(args, "account", ""); len(accounts) == 0 && account != "" {
+		accounts = []string{account}
+	}
+	if len(accounts) == 0 {
+		emails, err := config.GetAuthenticatedEmails()
+		if err != nil || len(emails) == 0 {
+			return mcp.NewToolResultError("accounts parameter is required (no authenticated accounts found)"), nil
+		}
+		accounts = emails
+	}
+
+	// Time expressions resolve in the timezone argument, else the first
+	// account's zone; its service is only needed when that zone is read.
+	timeArgs, resolvedTimes := args, map[string]any(nil)
+	if !isRFC3339OrEmpty(common.ParseStringArg(args, "time_min", "")) || !isRFC3339OrEmpty(common.ParseStringArg(args, "time_max", "")) {
+		srv, errResult, ok := ResolveCalendarServiceOrError(ctx, requestForAccount(request, accounts[0]), deps)
+		if !ok {
+			return errResult, nil
+		}
+		timeArgs, resolvedTimes, errResult = resolveTimeArgs(ctx, srv, common.DefaultCalendarID, args, "time_min", "time_max", true)
+		if errResult != nil {
+			return errResult, nil
+		}
+	}
+
+	now := time.Now()
+	timeMin, timeMax := now, now.AddDate(0, 0, agendaDefaultDays)
+	if s := common.ParseStringArg(timeArgs, "time_min", ""); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid time_min (RFC3339 required): %v", err)), nil
+		}
+		timeMin, timeMax = t, t.AddDate(0, 0, agendaDefaultDays)
+	}
+	if s := common.ParseStringArg(timeArgs, "time_max", ""); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid time_max (RFC3339 required): %v", err)), nil
+		}
+		timeMax = t
+	}
+	if !timeMax.After(timeMin) {
+		return mcp.NewToolResultError("time_max must be after time_min"), nil
+	}
+
+	var loc *time.Location
+	if tz := common.ParseStringArg(args, "timezone", ""); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid timezone %q: %v", tz, err)), nil
+		}
+		loc = l
+	}
+	maxResults := common.ParseMaxResults(args, agendaDefaultMaxResults, agendaMaxResultsLimit)
+
+	// Fan out: one goroutine per account, at most agendaConcurrency at a time.
+	// Failures are kept per account so one bad account does not hide the rest.
+	results := make([]agendaAccountResult, len(accounts))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(agendaConcurrency)
+	for i, account := range accounts {
+		i, account := i, account
+		g.Go(func() error {
+			results[i] = fetchAgendaAccount(gCtx, requestForAccount(request, account), deps, account, timeMin, timeMax, maxResults, loc == nil && i == 0)
+			return nil
+		})
+	}
+	g.Wait() //nolint:errcheck // goroutines return nil unconditionally; failures stored in results
+
+	if loc == nil {
+		loc = time.UTC
+		if tz := results[0].timeZone; tz != "" {
+			if l, err := time.LoadLocation(tz); err == nil {
+				loc = l
+			}
+		}
+	}
+
+	entries, accountInfo := mergeAgenda(results)
+	conflicts := markAgendaConflicts(entries)
+
+	events := make([]map[string]any, 0, len(entries))
+	for _, e := range entries {
+		events = append(events, formatAgendaEntry(e, loc))
+	}
+	result := map[string]any{
+		"time_min":       timeMin.In(loc).Format(time.RFC3339),
+		"time_max":       timeMax.In(loc).Format(time.RFC3339),
+		"timezone":       loc.String(),
+		"accounts":       accountInfo,
+		"events":         events,
+		"count":          len(events),
+		"conflict_count": conflicts,
+	}
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
+	return common.MarshalToolResult(result)
+}
+
+// parseUniqueStrings reads a string array argument, dropping blanks and
+// case-insensitive duplicates.
+func parseUniqueStrings(args map[string]any, key string) []string {
+	raw, _ := args[key].([]any)
+	seen := make(map[string]bool)
+	var values []string
+	for _, v := range raw {
+		s, _ := v.(string)
+		s = strings.TrimSpace(s)
+		if s == "" || seen[strings.ToLower(s)] {
+			continue
+		}
+		seen[strings.ToLower(s)] = true
+		values = append(values, s)
+	}
+	return values
+}
+
+// requestForAccount copies request with its account argument replaced, so the
+// normal account resolution applies to each account of a fan-out.
+func requestForAccount(request mcp.CallToolRequest, account string) mcp.CallToolRequest {
+	args := make(map[string]any, len(request.GetArguments())+1)
+	for k, v := range request.GetArguments() {
+		args[k] = v
+	}
+	args["account"] = account
+	request.Params.Arguments = args
+	return request
+}
+
+// fetchAgendaAccount reads the expanded events of one account's primary calendar.
+func fetchAgendaAccount(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps, account string, timeMin, timeMax time.Time, maxResults int64, wantTimeZone bool) agendaAccountResult {
+	result := agendaAccountResult{account: account}
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		result.err = fmt.Errorf("%s", getResultText(errResult))
+		return result
+	}
+	if wantTimeZone {
+		result.timeZone = calendarTimeZone(ctx, srv, common.DefaultCalendarID)
+	}
+
+	opts := &ListEventsOptions{
+		TimeMin:      timeMin.Format(time.RFC3339),
+		TimeMax:      timeMax.Format(time.RFC3339),
+		SingleEvents: true,
+		OrderBy:      "startTime",
+		MaxResults:   min(maxResults, common.CalendarMaxResultsLimit),
+	}
+	for int64(len(result.events)) < maxResults {
+		resp, err := srv.ListEvents(ctx, common.DefaultCalendarID, opts)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		result.events = append(result.events, resp.Items...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = resp.NextPageToken
+	}
+	if int64(len(result.events)) > maxResults {
+		result.events = result.events[:maxResults]
+	}
+	return result
+}
+
+// getResultText returns the text of a tool result, for surfacing resolution errors.
+func getResultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return "unknown error"
+}
+
+// mergeAgenda de-duplicates events across accounts by iCalUID and start time
+// and returns the entries ordered by start, plus a per-account summary.
+func mergeAgenda(results []agendaAccountResult) ([]*agendaEntry, []map[string]any) {
+	byKey := make(map[string]*agendaEntry)
+	var entries []*agendaEntry
+	accountInfo := make([]map[string]any, 0, len(results))
+	for _, r := range results {
+		info := map[string]any{"account": r.account, "event_count": len(r.events)}
+		if r.err != nil {
+			info["error"] = r.err.Error()
+		}
+		accountInfo = append(accountInfo, info)
+
+		for _, ev := range r.events {
+			if ev.Status == "cancelled" || ev.Start == nil || ev.End == nil {
+				continue
+			}
+			start, err := parseEventDateTime(ev.Start)
+			if err != nil {
+				continue
+			}
+			end, err := parseEventDateTime(ev.End)
+			if err != nil {
+				end = start
+			}
+
+			key := r.account + "|" + ev.Id
+			if ev.ICalUID != "" {
+				key = ev.ICalUID + "|" + start.UTC().Format(time.RFC3339)
+			}
+			entry := byKey[key]
+			if entry == nil {
+				entry = &agendaEntry{event: ev, account: r.account, start: start, end: end, allDay: ev.Start.Date != "", responses: map[string]string{}}
+				byKey[key] = entry
+				entries = append(entries, entry)
+			}
+			if n := len(entry.accounts); n == 0 || entry.accounts[n-1] != r.account {
+				entry.accounts = append(entry.accounts, r.account)
+			}
+			if self := selfAttendee(ev, r.account); self != nil {
+				entry.responses[r.account] = self.ResponseStatus
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].start.Equal(entries[j].start) {
+			return entries[i].start.Before(entries[j].start)
+		}
+		return entries[i].end.Before(entries[j].end)
+	})
+	return entries, accountInfo
+}
+
+// markAgendaConflicts links overlapping busy entries that have no account in
+// common, and returns the number of conflicting pairs. entries must be sorted by start.
+func markAgendaConflicts(entries []*agendaEntry) int {
+	pairs := 0
+	for i, a := range entries {
+		if !a.busy() {
+			continue
+		}
+		for _, b := range entries[i+1:] {
+			if !b.start.Before(a.end) {
+				break
+			}
+			if !b.busy() || a.sharesAccount(b) || !b.end.After(a.start) {
+				continue
+			}
+			a.conflicts = append(a.conflicts, b)
+			b.conflicts = append(b.conflicts, a)
+			pairs++
+		}
+	}
+	return pairs
+}
+
+// formatAgendaEntry renders an entry with times normalised to loc.
+func formatAgendaEntry(e *agendaEntry, loc *time.Location) map[string]any {
+	result := map[string]any{
+		"id":       e.event.Id,
+		"account":  e.account,
+		"accounts": e.accounts,
+		"summary":  e.event.Summary,
+		"all_day":  e.allDay,
+	}
+	if e.allDay {
+		result["start"] = e.event.Start.Date
+		result["end"] = e.event.End.Date
+	} else {
+		result["start"] = e.start.In(loc).Format(time.RFC3339)
+		result["end"] = e.end.In(loc).Format(time.RFC3339)
+	}
+	if e.event.Location != "" {
+		result["location"] = e.event.Location
+	}
+	if e.event.HtmlLink != "" {
+		result["html_link"] = e.event.HtmlLink
+	}
+	if e.event.HangoutLink != "" {
+		result["hangout_link"] = e.event.HangoutLink
+	}
+	if len(e.responses) > 0 {
+		result["response_status"] = e.responses
+	}
+	if len(e.conflicts) > 0 {
+		conflicts := make([]map[string]any, 0, len(e.conflicts))
+		for _, c := range e.conflicts {
+			conflicts = append(conflicts, map[string]any{
+				"id":       c.event.Id,
+				"account":  c.account,
+				"summary":  c.event.Summary,
+				"start":    c.start.In(loc).Format(time.RFC3339),
+				"end":      c.end.In(loc).Format(time.RFC3339),
+				"accounts": c.accounts,
+			})
+		}
+		result["conflict"] = true
+		result["conflicts_with"] = conflicts
+	}
+	return result
+}
@@ -0,0 +1,148 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/calendar/v3"
+)
+
+// accountServiceFactory serves a separate mock per account email.
+type accountServiceFactory map[string]*MockCalendarService
+
+func (f accountServiceFactory) CreateService(ctx context.Context, email string) (CalendarService, error) {
+	if srv, ok := f[email]; ok {
+		return srv, nil
+	}
+	return nil, fmt.Errorf("no credentials for %s", email)
+}
+
+// newAccountMock returns a mock with an empty primary calendar in timeZone.
+func newAccountMock(timeZone string) *MockCalendarService {
+	mock := NewMockCalendarService()
+	mock.Calendars["primary"] = &calendar.CalendarListEntry{Id: "primary", Primary: true, TimeZone: timeZone}
+	mock.Events["primary"] = make(map[string]*calendar.Event)
+	return mock
+}
+
+func addAgendaEvent(mock *MockCalendarService, id, uid, start, end, self, response string) *calendar.Event {
+	ev := createTestEvent(id, "Event "+id, "", start, end, false)
+	ev.ICalUID = uid
+	if self != "" {
+		ev.Attendees = []*calendar.EventAttendee{{Email: self, ResponseStatus: response}}
+	}
+	mock.Events["primary"][id] = ev
+	return ev
+}
+
+func TestCalendarAgenda(t *testing.T) {
+	work := newAccountMock("America/New_York")
+	home := newAccountMock("Europe/London")
+	factory := accountServiceFactory{"me@work.com": work, "me@home.com": home}
+	deps := &CalendarHandlerDeps{
+		EmailResolver: func(r mcp.CallToolRequest) (string, error) {
+			return common.ParseStringArg(r.GetArguments(), "account", ""), nil
+		},
+		ServiceFactory: factory,
+	}
+
+	// The same invitation reaches both accounts.
+	addAgendaEvent(work, "w-shared", "shared@x", "2026-03-02T10:00:00-05:00", "2026-03-02T11:00:00-05:00", "me@work.com", "accepted")
+	addAgendaEvent(home, "h-shared", "shared@x", "2026-03-02T15:00:00Z", "2026-03-02T16:00:00Z", "me@home.com", "needsAction")
+	// A work meeting overlapping a personal appointment.
+	addAgendaEvent(work, "w-review", "review@x", "2026-03-02T12:00:00-05:00", "2026-03-02T13:00:00-05:00", "", "")
+	addAgendaEvent(home, "h-dentist", "dentist@x", "2026-03-02T17:30:00Z", "2026-03-02T18:30:00Z", "", "")
+	// Declined and all-day events never conflict.
+	addAgendaEvent(home, "h-declined", "declined@x", "2026-03-02T15:30:00Z", "2026-03-02T16:30:00Z", "me@home.com", "declined")
+	home.Events["primary"]["h-holiday"] = createTestAllDayEvent("h-holiday", "Holiday", "", "2026-03-02", "2026-03-03")
+
+	req := CreateMCPRequest(map[string]any{
+		"accounts": []any{"me@work.com", "me@home.com", "me@work.com", "ghost@nowhere.com"},
+		"time_min": "2026-03-02T00:00:00Z",
+		"time_max": "2026-03-03T00:00:00Z",
+		"timezone": "Europe/Berlin",
+	})
+	result, err := TestableCalendarAgenda(context.Background(), req, deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	var data struct {
+		Timezone string `json:"timezone"`
+		Accounts []struct {
+			Account string `json:"account"`
+			Error   string `json:"error"`
+		} `json:"accounts"`
+		Events []struct {
+			ID             string            `json:"id"`
+			Start          string            `json:"start"`
+			Accounts       []string          `json:"accounts"`
+			Conflict       bool              `json:"conflict"`
+			ConflictsWith  []map[string]any  `json:"conflicts_with"`
+			ResponseStatus map[string]string `json:"response_status"`
+		} `json:"events"`
+		ConflictCount int `json:"conflict_count"`
+	}
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(data.Accounts) != 3 || data.Accounts[2].Error == "" || data.Accounts[0].Error != "" {
+		t.Errorf("expected duplicate account dropped and ghost account to fail alone: %+v", data.Accounts)
+	}
+
+	var ids []string
+	for _, e := range data.Events {
+		ids = append(ids, e.ID)
+	}
+	want := []string{"h-holiday", "w-shared", "h-declined", "w-review", "h-dentist"}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Fatalf("events = %v, want %v", ids, want)
+	}
+
+	shared := data.Events[1]
+	if shared.Start != "2026-03-02T16:00:00+01:00" {
+		t.Errorf("start not normalised to Europe/Berlin: %s", shared.Start)
+	}
+	if len(shared.Accounts) != 2 || shared.ResponseStatus["me@home.com"] != "needsAction" || shared.ResponseStatus["me@work.com"] != "accepted" {
+		t.Errorf("shared event not merged: %+v", shared)
+	}
+	if shared.Conflict {
+		t.Errorf("overlap with a declined event is not a conflict: %+v", shared.ConflictsWith)
+	}
+	if !data.Events[3].Conflict || data.Events[3].ConflictsWith[0]["id"] != "h-dentist" || !data.Events[4].Conflict {
+		t.Errorf("expected review/dentist conflict: %+v", data.Events[3:])
+	}
+	if data.ConflictCount != 1 {
+		t.Errorf("conflict_count = %d, want 1", data.ConflictCount)
+	}
+}
+
+func TestCalendarAgenda_Defaults(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	fixtures.MockService.Calendars["primary"].TimeZone = "Asia/Tokyo"
+
+	// With only the account parameter the agenda covers that account, in its
+	// calendar's time zone.
+	data := runCalendarTool(t, fixtures, TestableCalendarAgenda, map[string]any{"account": common.TestEmail, "time_min": "2024-01-15T00:00:00Z"})
+	if data["timezone"] != "Asia/Tokyo" || data["time_max"] != "2024-01-22T09:00:00+09:00" {
+		t.Errorf("unexpected defaults: %v %v", data["timezone"], data["time_max"])
+	}
+	if data["count"].(float64) == 0 {
+		t.Error("expected the fixture events")
+	}
+
+	result, _ := TestableCalendarAgenda(context.Background(), CreateMCPRequest(map[string]any{
+		"accounts": []any{common.TestEmail}, "time_min": "2024-01-15T00:00:00Z", "time_max": "2024-01-14T00:00:00Z",
+	}), fixtures.Deps)
+	if !result.IsError {
+		t.Error("expected error for an inverted range")
+	}
+}
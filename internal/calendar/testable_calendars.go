@@ -0,0 +1,387 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/calendar/v3"
+)
+
+// aclMaxPages bounds how many pages of ACL rules are read.
+const aclMaxPages = 10
+
+// aclRoles are the roles a calendar ACL rule can grant.
+var aclRoles = map[string]bool{
+	"freeBusyReader": true,
+	"reader":         true,
+	"writer":         true,
+	"owner":          true,
+}
+
+// aclScopeTypes maps accepted scope_type values to API scope types;
+// "public" is an alias for the API's "default" scope.
+var aclScopeTypes = map[string]string{
+	"user":    "user",
+	"group":   "group",
+	"domain":  "domain",
+	"public":  "default",
+	"default": "default",
+}
+
+// TestableCalendarCreateCalendar creates a secondary calendar.
+func TestableCalendarCreateCalendar(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	summary, errResult := common.RequireStringArg(args, "summary")
+	if errResult != nil {
+		return errResult, nil
+	}
+	cal := &calendar.Calendar{Summary: summary}
+	if errResult := applyCalendarMetadata(cal, args); errResult != nil {
+		return errResult, nil
+	}
+
+	created, err := srv.CreateCalendar(ctx, cal)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+	return common.MarshalToolResult(formatCalendar(created))
+}
+
+// TestableCalendarUpdateCalendar changes a calendar's name, description, location or time zone.
+func TestableCalendarUpdateCalendar(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	calendarID, errResult := common.RequireStringArg(args, "calendar_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	cal := &calendar.Calendar{Summary: common.ParseStringArg(args, "summary", "")}
+	if errResult := applyCalendarMetadata(cal, args); errResult != nil {
+		return errResult, nil
+	}
+	if cal.Summary == "" && cal.Description == "" && cal.Location == "" && cal.TimeZone == "" {
+		return mcp.NewToolResultError("Nothing to update: provide summary, description, location or timezone"), nil
+	}
+
+	updated, err := srv.PatchCalendar(ctx, calendarID, cal)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+	return common.MarshalToolResult(formatCalendar(updated))
+}
+
+// TestableCalendarDeleteCalendar permanently deletes a secondary calendar.
+func TestableCalendarDeleteCalendar(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	calendarID, errResult := common.RequireStringArg(request.GetArguments(), "calendar_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if isPrimaryCalendar(ctx, srv, calendarID) {
+		return mcp.NewToolResultError("The primary calendar cannot be deleted"), nil
+	}
+
+	if err := srv.DeleteCalendar(ctx, calendarID); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+	return common.MarshalToolResult(map[string]any{
+		"success":     true,
+		"calendar_id": calendarID,
+		"message":     "Calendar deleted",
+	})
+}
+
+// TestableCalendarListACL lists who a calendar is shared with.
+func TestableCalendarListACL(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	calendarID := common.ParseStringArg(request.GetArguments(), "calendar_id", common.DefaultCalendarID)
+	rules, err := listACLRules(ctx, srv, calendarID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+
+	formatted := make([]map[string]any, 0, len(rules))
+	for _, rule := range rules {
+		formatted = append(formatted, formatACLRule(rule))
+	}
+	return common.MarshalToolResult(map[string]any{
+		"calendar_id": calendarID,
+		"rules":       formatted,
+		"count":       len(formatted),
+	})
+}
+
+// TestableCalendarShare grants a user, group, domain or the public a role on a
+// calendar. An existing rule for the same scope has its role changed instead.
+func TestableCalendarShare(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	calendarID := common.ParseStringArg(args, "calendar_id", common.DefaultCalendarID)
+	role, errResult := common.RequireStringArg(args, "role")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if !aclRoles[role] {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid role %q: use freeBusyReader, reader, writer or owner", role)), nil
+	}
+	scope, errResult := parseACLScope(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	ruleID := aclRuleID(scope)
+	rules, err := listACLRules(ctx, srv, calendarID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+	for _, existing := range rules {
+		if existing.Id != ruleID {
+			continue
+		}
+		if existing.Role == role {
+			return common.MarshalToolResult(map[string]any{"action": "unchanged", "calendar_id": calendarID, "rule": formatACLRule(existing)})
+		}
+		previousRole := existing.Role
+		updated, err := srv.PatchACL(ctx, calendarID, ruleID, &calendar.AclRule{Role: role})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+		}
+		return common.MarshalToolResult(map[string]any{
+			"action":        "updated",
+			"calendar_id":   calendarID,
+			"previous_role": previousRole,
+			"rule":          formatACLRule(updated),
+		})
+	}
+
+	created, err := srv.InsertACL(ctx, calendarID, &calendar.AclRule{Role: role, Scope: scope}, common.ParseBoolArg(args, "send_notifications", true))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+	return common.MarshalToolResult(map[string]any{"action": "created", "calendar_id": calendarID, "rule": formatACLRule(created)})
+}
+
+// TestableCalendarUnshare removes an ACL rule, identified by rule_id or by scope.
+func TestableCalendarUnshare(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	calendarID := common.ParseStringArg(args, "calendar_id", common.DefaultCalendarID)
+	ruleID := common.ParseStringArg(args, "rule_id", "")
+	if ruleID == "" {
+		scope, errResult := parseACLScope(args)
+		if errResult != nil {
+			return errResult, nil
+		}
+		ruleID = aclRuleID(scope)
+	}
+
+	if err := srv.DeleteACL(ctx, calendarID, ruleID); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+	return common.MarshalToolResult(map[string]any{
+		"success":     true,
+		"calendar_id": calendarID,
+		"rule_id":     ruleID,
+		"message":     "Access removed",
+	})
+}
+
+// TestableCalendarSubscribe adds an existing calendar (a colleague's, a shared
+// team calendar, a public holiday calendar) to the user's calendar list.
+func TestableCalendarSubscribe(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	calendarID, errResult := common.RequireStringArg(args, "calendar_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	entry := &calendar.CalendarListEntry{
+		Id:              calendarID,
+		ColorId:         common.ParseStringArg(args, "color_id", ""),
+		SummaryOverride: common.ParseStringArg(args, "summary_override", ""),
+		Hidden:          common.ParseBoolArg(args, "hidden", false),
+	}
+
+	added, err := srv.InsertCalendarListEntry(ctx, entry)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+	result := map[string]any{
+		"id":          added.Id,
+		"summary":     added.Summary,
+		"access_role": added.AccessRole,
+	}
+	if added.SummaryOverride != "" {
+		result["summary_override"] = added.SummaryOverride
+	}
+	if added.TimeZone != "" {
+		result["timezone"] = added.TimeZone
+	}
+	if added.Hidden {
+		result["hidden"] = true
+	}
+	return common.MarshalToolResult(result)
+}
+
+// TestableCalendarUnsubscribe removes a calendar from the user's calendar list
+// without deleting it.
+func TestableCalendarUnsubscribe(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	calendarID, errResult := common.RequireStringArg(request.GetArguments(), "calendar_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if isPrimaryCalendar(ctx, srv, calendarID) {
+		return mcp.NewToolResultError("Cannot unsubscribe from the primary calendar"), nil
+	}
+
+	if err := srv.DeleteCalendarListEntry(ctx, calendarID); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+	return common.MarshalToolResult(map[string]any{
+		"success":     true,
+		"calendar_id": calendarID,
+		"message":     "Removed from calendar list",
+	})
+}
+
+// applyCalendarMetadata copies description, location and timezone arguments onto cal.
+func applyCalendarMetadata(cal *calendar.Calendar, args map[string]any) *mcp.CallToolResult {
+	cal.Description = common.ParseStringArg(args, "description", "")
+	cal.Location = common.ParseStringArg(args, "location", "")
+	if tz := common.ParseStringArg(args, "timezone", ""); tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid timezone %q: %v", tz, err))
+		}
+		cal.TimeZone = tz
+	}
+	return nil
+}
+
+// isPrimaryCalendar reports whether calendarID is "primary" or the ID of the user's primary calendar.
+func isPrimaryCalendar(ctx context.Context, srv CalendarService, calendarID string) bool {
+	if calendarID == common.DefaultCalendarID {
+		return true
+	}
+	entry := calendarEntry(ctx, srv, calendarID)
+	return entry != nil && entry.Primary
+}
+
+// parseACLScope reads scope_type (default "user") and scope_value.
+func parseACLScope(args map[string]any) (*calendar.AclRuleScope, *mcp.CallToolResult) {
+	scopeType := common.ParseStringArg(args, "scope_type", "user")
+	apiType, ok := aclScopeTypes[scopeType]
+	if !ok {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid scope_type %q: use user, group, domain or public", scopeType))
+	}
+	scope := &calendar.AclRuleScope{Type: apiType}
+	if apiType == "default" {
+		return scope, nil
+	}
+	value := strings.TrimSpace(common.ParseStringArg(args, "scope_value", ""))
+	if value == "" {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("scope_value is required for scope_type %q (an email address or domain name)", scopeType))
+	}
+	scope.Value = value
+	return scope, nil
+}
+
+// aclRuleID returns the API's rule ID for a scope: "<type>:<value>", or "default".
+func aclRuleID(scope *calendar.AclRuleScope) string {
+	if scope.Value == "" {
+		return scope.Type
+	}
+	return scope.Type + ":" + scope.Value
+}
+
+// listACLRules reads every ACL rule of a calendar, following page tokens.
+func listACLRules(ctx context.Context, srv CalendarService, calendarID string) ([]*calendar.AclRule, error) {
+	var rules []*calendar.AclRule
+	pageToken := ""
+	for page := 0; page < aclMaxPages; page++ {
+		resp, err := srv.ListACL(ctx, calendarID, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, resp.Items...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return rules, nil
+}
+
+// formatACLRule converts an ACL rule to a response map.
+func formatACLRule(rule *calendar.AclRule) map[string]any {
+	result := map[string]any{
+		"id":   rule.Id,
+		"role": rule.Role,
+	}
+	if rule.Scope != nil {
+		scopeType := rule.Scope.Type
+		if scopeType == "default" {
+			scopeType = "public"
+		}
+		result["scope_type"] = scopeType
+		if rule.Scope.Value != "" {
+			result["scope_value"] = rule.Scope.Value
+		}
+	}
+	return result
+}
+
+// formatCalendar converts calendar metadata to a response map.
+func formatCalendar(cal *calendar.Calendar) map[string]any {
+	result := map[string]any{
+		"id":      cal.Id,
+		"summary": cal.Summary,
+	}
+	if cal.Description != "" {
+		result["description"] = cal.Description
+	}
+	if cal.Location != "" {
+		result["location"] = cal.Location
+	}
+	if cal.TimeZone != "" {
+		result["timezone"] = cal.TimeZone
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/calendar/v3"
+)
+
+type calendarTool func(context.Context, mcp.CallToolRequest, *CalendarHandlerDeps) (*mcp.CallToolResult, error)
+
+// runCalendarTool calls a handler and decodes its JSON result, failing on tool errors.
+func runCalendarTool(t *testing.T, fixtures *CalendarTestFixtures, tool calendarTool, args map[string]any) map[string]any {
+	t.Helper()
+	result, err := tool(context.Background(), CreateMCPRequest(args), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	return data
+}
+
+func TestCalendarCreateUpdateDeleteCalendar(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+
+	created := runCalendarTool(t, fixtures, TestableCalendarCreateCalendar, map[string]any{
+		"summary":  "Project Atlas",
+		"timezone": "Europe/Berlin",
+	})
+	id, _ := created["id"].(string)
+	if id == "" || created["timezone"] != "Europe/Berlin" {
+		t.Fatalf("unexpected create result: %v", created)
+	}
+	if entry := fixtures.MockService.Calendars[id]; entry == nil || entry.Summary != "Project Atlas" {
+		t.Fatalf("calendar not stored: %+v", entry)
+	}
+
+	updated := runCalendarTool(t, fixtures, TestableCalendarUpdateCalendar, map[string]any{"calendar_id": id, "description": "Atlas team"})
+	if updated["summary"] != "Project Atlas" || updated["description"] != "Atlas team" {
+		t.Errorf("unexpected update result: %v", updated)
+	}
+
+	runCalendarTool(t, fixtures, TestableCalendarDeleteCalendar, map[string]any{"calendar_id": id})
+	if _, ok := fixtures.MockService.Calendars[id]; ok {
+		t.Error("calendar should have been deleted")
+	}
+}
+
+func TestCalendarManagement_Errors(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	tests := []struct {
+		name       string
+		tool       calendarTool
+		args       map[string]any
+		errContain string
+	}{
+		{"bad timezone", TestableCalendarCreateCalendar, map[string]any{"summary": "X", "timezone": "Mars/Olympus"}, "Invalid timezone"},
+		{"empty update", TestableCalendarUpdateCalendar, map[string]any{"calendar_id": "work-calendar"}, "Nothing to update"},
+		{"delete primary alias", TestableCalendarDeleteCalendar, map[string]any{"calendar_id": "primary"}, "cannot be deleted"},
+		{"unsubscribe primary", TestableCalendarUnsubscribe, map[string]any{"calendar_id": "primary"}, "primary calendar"},
+		{"bad role", TestableCalendarShare, map[string]any{"role": "admin", "scope_value": "a@example.com"}, "Invalid role"},
+		{"bad scope", TestableCalendarShare, map[string]any{"role": "reader", "scope_type": "team"}, "Invalid scope_type"},
+		{"missing scope value", TestableCalendarShare, map[string]any{"role": "reader", "scope_type": "domain"}, "scope_value is required"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.tool(context.Background(), CreateMCPRequest(tc.args), fixtures.Deps)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError || !strings.Contains(getCalendarTextContent(result), tc.errContain) {
+				t.Errorf("expected error containing %q, got %s", tc.errContain, getCalendarTextContent(result))
+			}
+		})
+	}
+}
+
+func TestCalendarShareAndUnshare(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	fixtures.MockService.ACL["work-calendar"] = map[string]*calendar.AclRule{}
+
+	share := func(args map[string]any) map[string]any {
+		args["calendar_id"] = "work-calendar"
+		return runCalendarTool(t, fixtures, TestableCalendarShare, args)
+	}
+
+	if got := share(map[string]any{"role": "reader", "scope_value": "bob@example.com"}); got["action"] != "created" {
+		t.Errorf("first share = %v", got)
+	}
+	got := share(map[string]any{"role": "writer", "scope_value": "bob@example.com"})
+	if got["action"] != "updated" || got["previous_role"] != "reader" {
+		t.Errorf("role change = %v", got)
+	}
+	if got := share(map[string]any{"role": "writer", "scope_value": "bob@example.com"}); got["action"] != "unchanged" {
+		t.Errorf("repeat share = %v", got)
+	}
+	share(map[string]any{"role": "freeBusyReader", "scope_type": "public"})
+	share(map[string]any{"role": "reader", "scope_type": "domain", "scope_value": "example.com"})
+
+	// Sharing with a user notifies by default.
+	for _, call := range fixtures.MockService.Calls() {
+		if call.Method == "InsertACL" && call.Args[1].(*calendar.AclRule).Scope.Type == "user" && call.Args[2] != true {
+			t.Error("expected send_notifications to default to true")
+		}
+	}
+
+	list := runCalendarTool(t, fixtures, TestableCalendarListACL, map[string]any{"calendar_id": "work-calendar"})
+	if list["count"] != float64(3) {
+		t.Fatalf("expected 3 rules, got %v", list)
+	}
+	roles := map[string]string{}
+	for _, r := range list["rules"].([]any) {
+		rule := r.(map[string]any)
+		roles[rule["id"].(string)] = rule["role"].(string)
+		if rule["id"] == "default" && rule["scope_type"] != "public" {
+			t.Errorf("default scope should be reported as public: %v", rule)
+		}
+	}
+	if roles["user:bob@example.com"] != "writer" || roles["default"] != "freeBusyReader" || roles["domain:example.com"] != "reader" {
+		t.Errorf("roles = %v", roles)
+	}
+
+	runCalendarTool(t, fixtures, TestableCalendarUnshare, map[string]any{"calendar_id": "work-calendar", "scope_value": "bob@example.com"})
+	runCalendarTool(t, fixtures, TestableCalendarUnshare, map[string]any{"calendar_id": "work-calendar", "rule_id": "default"})
+	if rules := fixtures.MockService.ACL["work-calendar"]; len(rules) != 1 || rules["domain:example.com"] == nil {
+		t.Errorf("remaining rules = %v", rules)
+	}
+}
+
+func TestCalendarSubscribeUnsubscribe(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+
+	got := runCalendarTool(t, fixtures, TestableCalendarSubscribe, map[string]any{
+		"calendar_id":      "en.usa#holiday@group.v.calendar.google.com",
+		"summary_override": "US holidays",
+	})
+	if got["id"] != "en.usa#holiday@group.v.calendar.google.com" || got["summary_override"] != "US holidays" {
+		t.Errorf("unexpected subscribe result: %v", got)
+	}
+	if _, ok := fixtures.MockService.Calendars["en.usa#holiday@group.v.calendar.google.com"]; !ok {
+		t.Fatal("calendar not added to the list")
+	}
+
+	runCalendarTool(t, fixtures, TestableCalendarUnsubscribe, map[string]any{"calendar_id": "en.usa#holiday@group.v.calendar.google.com"})
+	if _, ok := fixtures.MockService.Calendars["en.usa#holiday@group.v.calendar.google.com"]; ok {
+		t.Error("calendar should have been removed from the list")
+	}
+}
@@ -23,15 +23,20 @@ func TestableCalendarListEvents(ctx context.Context, request mcp.CallToolRequest
 		Fields: CalendarEventListFields,
 	}
 
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, calendarID, request.GetArguments(), "time_min", "time_max", true)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// Default to showing future events if no time range specified
-	if timeMin := common.ParseStringArg(request.GetArguments(), "time_min", ""); timeMin != "" {
+	if timeMin := common.ParseStringArg(timeArgs, "time_min", ""); timeMin != "" {
 		opts.TimeMin = timeMin
 	} else {
 		// Default to now
 		opts.TimeMin = time.Now().Format(time.RFC3339)
 	}
 
-	if timeMax := common.ParseStringArg(request.GetArguments(), "time_max", ""); timeMax != "" {
+	if timeMax := common.ParseStringArg(timeArgs, "time_max", ""); timeMax != "" {
 		opts.TimeMax = timeMax
 	}
 
@@ -79,6 +84,9 @@ func TestableCalendarListEvents(ctx context.Context, request mcp.CallToolRequest
 		"count":           len(events),
 		"next_page_token": resp.NextPageToken,
 	}
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
 
 	return common.MarshalToolResult(result)
 }
@@ -138,7 +146,11 @@ func TestableCalendarCreateEvent(ctx context.Context, request mcp.CallToolReques
 	}
 
 	// Set start/end times (required start_time, optional end_time/all_day/timezone)
-	if errResult := setNewEventTimes(event, request.GetArguments()); errResult != nil {
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, calendarID, request.GetArguments(), "start_time", "end_time", common.ParseBoolArg(request.GetArguments(), "all_day", false))
+	if errResult != nil {
+		return errResult, nil
+	}
+	if errResult := setNewEventTimes(event, timeArgs); errResult != nil {
 		return errResult, nil
 	}
 
@@ -152,11 +164,20 @@ func TestableCalendarCreateEvent(ctx context.Context, request mcp.CallToolReques
 		event.Reminders = reminders
 	}
 
+	// Drive attachments
+	attachments, errResult := resolveEventAttachments(ctx, request)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if attachments != nil {
+		event.Attachments = attachments.attachments
+	}
+
 	// Google Meet conferencing
 	addConferencing := common.ParseBoolArg(request.GetArguments(), "add_conferencing", false)
 
 	if addConferencing {
-		startTime := common.ParseStringArg(request.GetArguments(), "start_time", "")
+		startTime := common.ParseStringArg(timeArgs, "start_time", "")
 		event.ConferenceData = buildConferenceData(calendarID, startTime, summary)
 	}
 
@@ -177,6 +198,10 @@ func TestableCalendarCreateEvent(ctx context.Context, request mcp.CallToolReques
 	if created.HangoutLink != "" {
 		result["meet_link"] = created.HangoutLink
 	}
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
+	addAttachmentResults(ctx, result, created, attachments)
 
 	return common.MarshalToolResult(result)
 }
@@ -216,7 +241,12 @@ func TestableCalendarUpdateEvent(ctx context.Context, request mcp.CallToolReques
 	}
 
 	// Update times if provided
-	if errResult := updateEventTimes(event, request.GetArguments()); errResult != nil {
+	allDay := event.Start != nil && event.Start.Date != ""
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, calendarID, request.GetArguments(), "start_time", "end_time", allDay)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if errResult := updateEventTimes(event, timeArgs); errResult != nil {
 		return errResult, nil
 	}
 
@@ -225,6 +255,15 @@ func TestableCalendarUpdateEvent(ctx context.Context, request mcp.CallToolReques
 		event.Attendees = attendees
 	}
 
+	// Replace attachments if provided
+	attachments, errResult := resolveEventAttachments(ctx, request)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if attachments != nil {
+		event.Attachments = attachments.attachments
+	}
+
 	updated, err := srv.UpdateEvent(ctx, calendarID, eventID, event)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
@@ -232,6 +271,10 @@ func TestableCalendarUpdateEvent(ctx context.Context, request mcp.CallToolReques
 
 	result := formatEvent(updated)
 	result["html_link"] = updated.HtmlLink
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
+	addAttachmentResults(ctx, result, updated, attachments)
 
 	return common.MarshalToolResult(result)
 }
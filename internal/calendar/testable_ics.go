@@ -0,0 +1,294 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	// icsExportPageSize and icsExportMaxPages bound how many events an export reads.
+	icsExportPageSize = 2500
+	icsExportMaxPages = 20
+
+	// icsImportMaxBytes caps the size of a file calendar_import_ics will read.
+	icsImportMaxBytes = 10 << 20
+)
+
+// icsDirs limits the .ics files calendar_export_ics and calendar_import_ics
+// read and write to the configured allowed directories. Nil allows any path.
+// Set by InitDefaultCalendarHandlerDeps.
+var icsDirs *common.LocalDirPolicy
+
+// TestableCalendarExportICS writes the events of a calendar, optionally limited
+// to a time range, to an RFC 5545 .ics file. Recurring series are exported
+// with their rules; modified occurrences become RECURRENCE-ID overrides and
+// cancelled occurrences become EXDATEs on their series.
+func TestableCalendarExportICS(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	calendarID := common.ParseStringArg(args, "calendar_id", common.DefaultCalendarID)
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, calendarID, args, "time_min", "time_max", true)
+	if errResult != nil {
+		return errResult, nil
+	}
+	opts := &ListEventsOptions{MaxResults: icsExportPageSize, ShowDeleted: true}
+	var until time.Time
+	for _, key := range []string{"time_min", "time_max"} {
+		value := common.ParseStringArg(timeArgs, key, "")
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid %s (RFC3339 required): %v", key, err)), nil
+		}
+		if key == "time_min" {
+			opts.TimeMin = value
+		} else {
+			opts.TimeMax, until = value, t
+		}
+	}
+
+	var events []*calendar.Event
+	truncated := false
+	for page := 0; ; page++ {
+		resp, err := srv.ListEvents(ctx, calendarID, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+		}
+		events = append(events, resp.Items...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		if page == icsExportMaxPages-1 {
+			truncated = true
+			break
+		}
+		opts.PageToken = resp.NextPageToken
+	}
+
+	cal := calendarEntry(ctx, srv, calendarID)
+	timeZone := "UTC"
+	if cal != nil && cal.TimeZone != "" {
+		timeZone = cal.TimeZone
+	}
+	export := newICSExport(timeZone, time.Now())
+
+	// Series first so cancelled occurrences can be attached as EXDATEs.
+	masters := make(map[string]*ICSComponent)
+	var vevents []*ICSComponent
+	var cancelled []*calendar.Event
+	exceptions, exdates, skipped := 0, 0, 0
+	for _, ev := range events {
+		switch {
+		case ev.Status == "cancelled" && ev.RecurringEventId != "":
+			cancelled = append(cancelled, ev)
+			continue
+		case ev.Status == "cancelled":
+			continue // deleted event
+		case ev.RecurringEventId != "":
+			exceptions++
+		}
+		vev, err := export.vevent(ev)
+		if err != nil {
+			skipped++
+			continue
+		}
+		if len(ev.Recurrence) > 0 {
+			masters[ev.Id] = vev
+		}
+		vevents = append(vevents, vev)
+	}
+	for _, ev := range cancelled {
+		master := masters[ev.RecurringEventId]
+		exdate, err := export.exdate(ev.OriginalStartTime)
+		if master == nil || err != nil {
+			skipped++
+			continue
+		}
+		master.Properties = append(master.Properties, exdate)
+		exdates++
+	}
+
+	root := &ICSComponent{Name: "VCALENDAR"}
+	root.Add("PRODID", "-//gsuite-mcp//Calendar Export//EN", nil)
+	root.Add("VERSION", "2.0", nil)
+	root.Add("CALSCALE", "GREGORIAN", nil)
+	root.Add("METHOD", "PUBLISH", nil)
+	if cal != nil {
+		root.AddText("X-WR-CALNAME", cal.Summary)
+		root.AddText("X-WR-CALDESC", cal.Description)
+	}
+	root.Add("X-WR-TIMEZONE", timeZone, nil)
+	root.Components = append(export.vtimezones(until), vevents...)
+	data := []byte(root.String())
+
+	path := common.ParseStringArg(args, "output_path", "")
+	dirs := icsDirs
+	if path == "" {
+		// The default location is chosen here, not by the caller.
+		path = filepath.Join(os.TempDir(), "gsuite-mcp-exports", icsFilename(calendarID))
+		dirs = nil
+	}
+	path, err := common.ResolveAllowedLocalPath(path, dirs)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid output_path: %v", err)), nil
+	}
+	if err := common.WriteLocalFile(path, data, common.ParseBoolArg(args, "overwrite", false)); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	tzids := make([]string, 0)
+	for _, vtz := range root.Children("VTIMEZONE") {
+		tzids = append(tzids, vtz.Get("TZID").Value)
+	}
+	result := map[string]any{
+		"path":            path,
+		"calendar_id":     calendarID,
+		"time_zone":       timeZone,
+		"event_count":     len(vevents),
+		"exception_count": exceptions,
+		"cancelled_count": exdates,
+		"timezones":       tzids,
+		"bytes":           len(data),
+	}
+	if skipped > 0 {
+		result["skipped"] = skipped
+	}
+	if truncated {
+		result["truncated"] = true
+		result["note"] = fmt.Sprintf("Stopped after %d events; export a shorter range with time_min and time_max.", len(events))
+	}
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
+	return common.MarshalToolResult(result)
+}
+
+// icsFilename turns a calendar ID into a safe default export file name.
+func icsFilename(calendarID string) string {
+	var b strings.Builder
+	for _, r := range calendarID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String() + ".ics"
+}
+
+// TestableCalendarImportICS imports the VEVENTs of a local .ics file through
+// events.import. Events are keyed by UID (and RECURRENCE-ID for overrides),
+// so importing the same file again updates the existing events in place.
+func TestableCalendarImportICS(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	path, errResult := common.RequireStringArg(args, "path")
+	if errResult != nil {
+		return errResult, nil
+	}
+	calendarID := common.ParseStringArg(args, "calendar_id", common.DefaultCalendarID)
+
+	path, err := common.ResolveAllowedLocalPath(path, icsDirs)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid path: %v", err)), nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Cannot read %s: %v", path, err)), nil
+	}
+	if info.IsDir() || info.Size() > icsImportMaxBytes {
+		return mcp.NewToolResultError(fmt.Sprintf("%s is not a regular file of at most %d bytes", path, icsImportMaxBytes)), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Cannot read %s: %v", path, err)), nil
+	}
+	root, err := ParseICS(string(data))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid iCalendar file: %v", err)), nil
+	}
+
+	timeZone := calendarTimeZone(ctx, srv, calendarID)
+	imp := newICSImport(root, timeZone)
+
+	// Series before their overrides so the overrides have something to attach to.
+	var ordered []*ICSComponent
+	for _, pass := range []bool{false, true} {
+		for _, vev := range root.Children("VEVENT") {
+			if (vev.Get("RECURRENCE-ID") != nil) == pass {
+				ordered = append(ordered, vev)
+			}
+		}
+	}
+
+	// existed records, per UID, whether the calendar had the event before this import.
+	existed := make(map[string]bool)
+	imported := []map[string]any{}
+	var failures []map[string]any
+	created, updated := 0, 0
+	for _, vev := range ordered {
+		ev, err := imp.event(vev)
+		if err != nil {
+			failures = append(failures, map[string]any{"uid": valueOf(vev.Get("UID")), "summary": vev.Get("SUMMARY").Text(), "error": err.Error()})
+			continue
+		}
+
+		exists, seen := existed[ev.ICalUID]
+		if !seen {
+			resp, err := srv.ListEvents(ctx, calendarID, &ListEventsOptions{ICalUID: ev.ICalUID, ShowDeleted: true, MaxResults: 1})
+			exists = err == nil && len(resp.Items) > 0
+			existed[ev.ICalUID] = exists
+		}
+
+		saved, err := srv.ImportEvent(ctx, calendarID, ev)
+		if err != nil {
+			failures = append(failures, map[string]any{"uid": ev.ICalUID, "summary": ev.Summary, "error": fmt.Sprintf("Calendar API error: %v", err)})
+			continue
+		}
+		action := "created"
+		if exists {
+			action = "updated"
+			updated++
+		} else {
+			created++
+		}
+		item := map[string]any{"uid": ev.ICalUID, "id": saved.Id, "summary": saved.Summary, "action": action}
+		if ev.OriginalStartTime != nil {
+			item["recurrence_id"] = eventDateTimeString(ev.OriginalStartTime)
+		}
+		imported = append(imported, item)
+	}
+
+	result := map[string]any{
+		"path":        path,
+		"calendar_id": calendarID,
+		"time_zone":   timeZone,
+		"created":     created,
+		"updated":     updated,
+		"failed":      len(failures),
+		"events":      imported,
+	}
+	if len(failures) > 0 {
+		result["errors"] = failures
+	}
+	return common.MarshalToolResult(result)
+}
@@ -0,0 +1,232 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestCalendarExportImportICS(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	fixtures.MockService.Calendars["primary"].TimeZone = "America/New_York"
+	series, moved := addWeeklySeries(fixtures.MockService)
+	series.Organizer = &calendar.EventOrganizer{Email: "boss@example.com", DisplayName: "The Boss"}
+	moved.Start.DateTime = "2024-03-11T11:00:00-04:00"
+	moved.End.DateTime = "2024-03-11T11:30:00-04:00"
+	moved.Start.TimeZone, moved.End.TimeZone = "America/New_York", "America/New_York"
+	cancelled := createTestEvent("weekly_20240318T140000Z", "", "", "2024-03-18T10:00:00-04:00", "2024-03-18T10:30:00-04:00", false)
+	cancelled.RecurringEventId = "weekly"
+	cancelled.Status = "cancelled"
+	cancelled.OriginalStartTime = &calendar.EventDateTime{DateTime: "2024-03-18T10:00:00-04:00", TimeZone: "America/New_York"}
+	fixtures.MockService.Events["primary"][cancelled.Id] = cancelled
+	for id, ev := range fixtures.MockService.Events["primary"] {
+		if !strings.HasPrefix(id, "weekly") {
+			delete(fixtures.MockService.Events["primary"], ev.Id)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "out", "cal.ics")
+	result, err := TestableCalendarExportICS(context.Background(), CreateMCPRequest(map[string]any{"output_path": path}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if data["event_count"] != float64(2) || data["exception_count"] != float64(1) || data["cancelled_count"] != float64(1) {
+		t.Errorf("unexpected counts: %v", data)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("export not written: %v", err)
+	}
+	ics := string(raw)
+	for _, want := range []string{
+		"BEGIN:VTIMEZONE\r\nTZID:America/New_York",
+		"X-WR-CALNAME:Primary Calendar",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO",
+		"EXDATE;TZID=America/New_York:20240318T100000",
+		"RECURRENCE-ID;TZID=America/New_York:20240311T100000",
+		"DTSTART;TZID=America/New_York:20240311T110000",
+		"ORGANIZER;CN=The Boss:mailto:boss@example.com",
+		"ATTENDEE;PARTSTAT=ACCEPTED;ROLE=REQ-PARTICIPANT:mailto:team@example.com",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("export missing %q:\n%s", want, ics)
+		}
+	}
+
+	// Exporting again without overwrite refuses to clobber the file.
+	result, _ = TestableCalendarExportICS(context.Background(), CreateMCPRequest(map[string]any{"output_path": path}), fixtures.Deps)
+	if !result.IsError || !strings.Contains(getCalendarTextContent(result), "already exists") {
+		t.Errorf("expected already-exists error, got %s", getCalendarTextContent(result))
+	}
+
+	// Import the file into another calendar, twice.
+	target := NewCalendarTestFixtures()
+	target.MockService.Events["work-calendar"] = map[string]*calendar.Event{}
+	importICS := func() map[string]any {
+		t.Helper()
+		result, err := TestableCalendarImportICS(context.Background(), CreateMCPRequest(map[string]any{"path": path, "calendar_id": "work-calendar"}), target.Deps)
+		if err != nil || result.IsError {
+			t.Fatalf("import failed: %v %s", err, getCalendarTextContent(result))
+		}
+		var data map[string]any
+		if err := json.Unmarshal([]byte(getCalendarTextContent(result)), &data); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		return data
+	}
+
+	first := importICS()
+	if first["created"] != float64(2) || first["updated"] != float64(0) || first["failed"] != float64(0) {
+		t.Errorf("first import = %v", first)
+	}
+	stored := target.MockService.Events["work-calendar"]
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 imported events, got %d", len(stored))
+	}
+	for _, ev := range stored {
+		if ev.ICalUID != "weekly@google.com" {
+			t.Errorf("iCalUID = %q", ev.ICalUID)
+		}
+		if ev.OriginalStartTime == nil {
+			wantRecurrence := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO", "EXDATE;TZID=America/New_York:20240318T100000"}
+			if strings.Join(ev.Recurrence, "|") != strings.Join(wantRecurrence, "|") {
+				t.Errorf("series recurrence = %q", ev.Recurrence)
+			}
+			if ev.Start.DateTime != "2024-03-04T10:00:00-05:00" || ev.Start.TimeZone != "America/New_York" {
+				t.Errorf("series start = %+v", ev.Start)
+			}
+			if ev.Organizer == nil || ev.Organizer.Email != "boss@example.com" || len(ev.Attendees) != 1 || ev.Attendees[0].ResponseStatus != "accepted" {
+				t.Errorf("people not imported: %+v %+v", ev.Organizer, ev.Attendees)
+			}
+		} else if ev.OriginalStartTime.DateTime != "2024-03-11T10:00:00-04:00" || ev.Start.DateTime != "2024-03-11T11:00:00-04:00" {
+			t.Errorf("override = %+v -> %+v", ev.OriginalStartTime, ev.Start)
+		}
+	}
+
+	second := importICS()
+	if second["created"] != float64(0) || second["updated"] != float64(2) || len(target.MockService.Events["work-calendar"]) != 2 {
+		t.Errorf("re-import should update in place: %v (%d events)", second, len(target.MockService.Events["work-calendar"]))
+	}
+}
+
+func TestCalendarExportICS_Truncated(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	fixtures.MockService.EventsNextPageToken = "more"
+	path := filepath.Join(t.TempDir(), "cal.ics")
+	result, _ := TestableCalendarExportICS(context.Background(), CreateMCPRequest(map[string]any{"output_path": path}), fixtures.Deps)
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	if !strings.Contains(text, `"truncated":true`) || !strings.Contains(text, "time_min") {
+		t.Errorf("expected a truncated export with a note, got %s", text)
+	}
+	calls := 0
+	for _, c := range fixtures.MockService.Calls() {
+		if c.Method == "ListEvents" {
+			calls++
+		}
+	}
+	if calls != icsExportMaxPages {
+		t.Errorf("expected %d pages read, got %d", icsExportMaxPages, calls)
+	}
+}
+
+func TestCalendarImportICS_Errors(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.ics")
+	if err := os.WriteFile(bad, []byte("not a calendar"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	partial := filepath.Join(dir, "partial.ics")
+	if err := os.WriteFile(partial, []byte("BEGIN:VCALENDAR\r\n"+
+		"BEGIN:VEVENT\r\nSUMMARY:No start\r\nEND:VEVENT\r\n"+
+		"BEGIN:VEVENT\r\nSUMMARY:Lunch\r\nDTSTART;VALUE=DATE:20260105\r\nEND:VEVENT\r\n"+
+		"END:VCALENDAR\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		path, errContain string
+	}{
+		{filepath.Join(dir, "missing.ics"), "Cannot read"},
+		{bad, "Invalid iCalendar"},
+		{dir, "not a regular file"},
+	} {
+		result, _ := TestableCalendarImportICS(context.Background(), CreateMCPRequest(map[string]any{"path": tc.path}), fixtures.Deps)
+		if !result.IsError || !strings.Contains(getCalendarTextContent(result), tc.errContain) {
+			t.Errorf("%s: expected %q, got %s", tc.path, tc.errContain, getCalendarTextContent(result))
+		}
+	}
+
+	// Bad events are reported individually; the rest are imported, and events
+	// without a UID get a stable generated one.
+	result, _ := TestableCalendarImportICS(context.Background(), CreateMCPRequest(map[string]any{"path": partial}), fixtures.Deps)
+	text := getCalendarTextContent(result)
+	if result.IsError || !strings.Contains(text, `"failed":1`) || !strings.Contains(text, "no DTSTART") || !strings.Contains(text, `"created":1`) {
+		t.Fatalf("unexpected result: %s", text)
+	}
+	var lunch *calendar.Event
+	for _, ev := range fixtures.MockService.Events["primary"] {
+		if ev.Summary == "Lunch" {
+			lunch = ev
+		}
+	}
+	if lunch == nil || !strings.HasSuffix(lunch.ICalUID, "@gsuite-mcp") || lunch.End.Date != "2026-01-06" {
+		t.Errorf("lunch = %+v", lunch)
+	}
+}
+
+func TestCalendarICS_AllowedDirs(t *testing.T) {
+	allowed, outside := t.TempDir(), t.TempDir()
+	policy, err := common.NewLocalDirPolicy([]string{allowed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := icsDirs
+	icsDirs = policy
+	t.Cleanup(func() { icsDirs = prev })
+
+	fixtures := NewCalendarTestFixtures()
+	for _, tc := range []struct {
+		path    string
+		wantErr bool
+	}{
+		{filepath.Join(allowed, "cal.ics"), false},
+		{filepath.Join(outside, "cal.ics"), true},
+	} {
+		result, _ := TestableCalendarExportICS(context.Background(), CreateMCPRequest(map[string]any{"output_path": tc.path}), fixtures.Deps)
+		if result.IsError != tc.wantErr {
+			t.Errorf("export to %s: expected error %v, got %s", tc.path, tc.wantErr, getCalendarTextContent(result))
+		}
+	}
+
+	// The default export location is not a caller-supplied path.
+	if result, _ := TestableCalendarExportICS(context.Background(), CreateMCPRequest(map[string]any{"overwrite": true}), fixtures.Deps); result.IsError {
+		t.Errorf("expected the default export path to be accepted, got %s", getCalendarTextContent(result))
+	}
+
+	if err := os.Rename(filepath.Join(allowed, "cal.ics"), filepath.Join(outside, "cal.ics")); err != nil {
+		t.Fatal(err)
+	}
+	result, _ := TestableCalendarImportICS(context.Background(), CreateMCPRequest(map[string]any{"path": filepath.Join(outside, "cal.ics")}), fixtures.Deps)
+	if !result.IsError || !strings.Contains(getCalendarTextContent(result), "outside the allowed directories") {
+		t.Errorf("expected an import outside the allowed directories to be refused, got %s", getCalendarTextContent(result))
+	}
+}
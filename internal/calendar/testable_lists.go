@@ -59,12 +59,17 @@ func TestableCalendarFreeBusy(ctx context.Context, request mcp.CallToolRequest,
 		return errResult, nil
 	}
 
-	timeMin := common.ParseStringArg(request.GetArguments(), "time_min", "")
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, common.DefaultCalendarID, request.GetArguments(), "time_min", "time_max", true)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	timeMin := common.ParseStringArg(timeArgs, "time_min", "")
 	if timeMin == "" {
 		return mcp.NewToolResultError("time_min parameter is required (RFC3339 format)"), nil
 	}
 
-	timeMax := common.ParseStringArg(request.GetArguments(), "time_max", "")
+	timeMax := common.ParseStringArg(timeArgs, "time_max", "")
 	if timeMax == "" {
 		return mcp.NewToolResultError("time_max parameter is required (RFC3339 format)"), nil
 	}
@@ -115,6 +120,9 @@ func TestableCalendarFreeBusy(ctx context.Context, request mcp.CallToolRequest,
 		"time_max":  timeMax,
 		"calendars": calendars,
 	}
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
 
 	return common.MarshalToolResult(result)
 }
@@ -137,11 +145,16 @@ func TestableCalendarListInstances(ctx context.Context, request mcp.CallToolRequ
 		Fields: CalendarEventListFields,
 	}
 
-	if timeMin := common.ParseStringArg(request.GetArguments(), "time_min", ""); timeMin != "" {
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, calendarID, request.GetArguments(), "time_min", "time_max", true)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	if timeMin := common.ParseStringArg(timeArgs, "time_min", ""); timeMin != "" {
 		opts.TimeMin = timeMin
 	}
 
-	if timeMax := common.ParseStringArg(request.GetArguments(), "time_max", ""); timeMax != "" {
+	if timeMax := common.ParseStringArg(timeArgs, "time_max", ""); timeMax != "" {
 		opts.TimeMax = timeMax
 	}
 
@@ -163,6 +176,9 @@ func TestableCalendarListInstances(ctx context.Context, request mcp.CallToolRequ
 		"count":           len(instances),
 		"next_page_token": resp.NextPageToken,
 	}
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
 
 	return common.MarshalToolResult(result)
 }
@@ -181,12 +197,34 @@ func TestableCalendarUpdateInstance(ctx context.Context, request mcp.CallToolReq
 
 	calendarID := common.ParseStringArg(request.GetArguments(), "calendar_id", common.DefaultCalendarID)
 
+	scope := common.ParseStringArg(request.GetArguments(), "scope", "this")
+	if scope != "this" && scope != "following" {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid scope %q: must be 'this' or 'following'", scope)), nil
+	}
+
 	// First, get the existing instance
 	event, err := srv.GetEvent(ctx, calendarID, instanceID, "")
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get instance: %v", err)), nil
 	}
 
+	allDay := event.Start != nil && event.Start.Date != ""
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, calendarID, request.GetArguments(), "start_time", "end_time", allDay)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	if scope == "following" {
+		result, errResult := updateThisAndFollowing(ctx, srv, timeArgs, calendarID, event)
+		if errResult != nil {
+			return errResult, nil
+		}
+		if resolvedTimes != nil {
+			result["resolved_times"] = resolvedTimes
+		}
+		return common.MarshalToolResult(result)
+	}
+
 	// Update fields that are provided
 	if summary := common.ParseStringArg(request.GetArguments(), "summary", ""); summary != "" {
 		event.Summary = summary
@@ -199,7 +237,7 @@ func TestableCalendarUpdateInstance(ctx context.Context, request mcp.CallToolReq
 	}
 
 	// Update times if provided
-	if errResult := updateEventTimes(event, request.GetArguments()); errResult != nil {
+	if errResult := updateEventTimes(event, timeArgs); errResult != nil {
 		return errResult, nil
 	}
 
@@ -213,6 +251,9 @@ func TestableCalendarUpdateInstance(ctx context.Context, request mcp.CallToolReq
 	if updated.RecurringEventId != "" {
 		result["recurring_event_id"] = updated.RecurringEventId
 	}
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
 
 	return common.MarshalToolResult(result)
 }
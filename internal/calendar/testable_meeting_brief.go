@@ -0,0 +1,700 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/contacts"
+	"github.com/aliwatters/gsuite-mcp/internal/drive"
+	"github.com/aliwatters/gsuite-mcp/internal/meet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+	gmeet "google.golang.org/api/meet/v2"
+	"google.golang.org/api/people/v1"
+)
+
+const (
+	// briefMaxAttendees bounds how many attendees are looked up and searched for.
+	briefMaxAttendees = 25
+	// briefDefaultThreads and briefMaxThreads bound the Gmail threads returned.
+	briefDefaultThreads = 5
+	briefMaxThreads     = 20
+	// briefDefaultLookbackDays and briefMaxLookbackDays bound how far back
+	// mail and shared files are searched.
+	briefDefaultLookbackDays = 30
+	briefMaxLookbackDays     = 365
+	// briefMaxSharedFiles bounds the files listed as recently shared by attendees.
+	briefMaxSharedFiles = 10
+	// briefInstanceLookbackDays is how far back the previous occurrence is searched.
+	briefInstanceLookbackDays = 90
+	// briefConferenceSlack widens the window matched against conference start times.
+	briefConferenceSlack = time.Hour
+	// briefSnippetChars and briefEntryChars truncate long mail snippets and
+	// transcript entries.
+	briefSnippetChars = 300
+	briefEntryChars   = 500
+	// briefMinSectionBytes is the smallest max_section_bytes accepted.
+	briefMinSectionBytes = 500
+	// briefLookupConcurrency bounds the contact and thread lookups in flight.
+	briefLookupConcurrency = 5
+)
+
+// briefSectionNames lists the sections of a meeting brief in output order.
+var briefSectionNames = []string{"attendees", "emails", "files", "transcript"}
+
+// briefSectionBudgets is the default size budget of each section, in bytes of
+// JSON-encoded items.
+var briefSectionBudgets = map[string]int{
+	"attendees":  6000,
+	"emails":     8000,
+	"files":      6000,
+	"transcript": 16000,
+}
+
+// meetCodePattern extracts the meeting code from a Meet link.
+var meetCodePattern = regexp.MustCompile(`meet\.google\.com/([a-z]{3}-[a-z]{4}-[a-z]{3})`)
+
+// briefSection collects one section of a meeting brief within its size budget.
+type briefSection struct {
+	budget    int
+	used      int
+	items     []map[string]any
+	truncated bool
+	note      string // why the section is empty, when that is not an error
+	extra     map[string]any
+	err       error
+}
+
+// add appends item if it fits in the remaining budget. Once an item does not
+// fit the section is marked truncated and add reports false.
+func (s *briefSection) add(item map[string]any) bool {
+	if s.truncated {
+		return false
+	}
+	data, err := json.Marshal(item)
+	if err != nil || s.used+len(data) > s.budget {
+		s.truncated = true
+		return false
+	}
+	s.used += len(data)
+	s.items = append(s.items, item)
+	return true
+}
+
+// format renders the section with its status. Items gathered before a
+// failure are still returned.
+func (s *briefSection) format() map[string]any {
+	out := map[string]any{"budget_bytes": s.budget}
+	for k, v := range s.extra {
+		out[k] = v
+	}
+	items := s.items
+	if items == nil {
+		items = []map[string]any{}
+	}
+	out["items"] = items
+	out["count"] = len(items)
+	out["status"] = "ok"
+	if s.err != nil {
+		out["status"] = "error"
+		out["error"] = s.err.Error()
+	}
+	if s.truncated {
+		out["truncated"] = true
+	}
+	if s.note != "" {
+		out["note"] = s.note
+	}
+	return out
+}
+
+// meetingBrief holds what the section fetchers share.
+type meetingBrief struct {
+	request    mcp.CallToolRequest
+	srv        CalendarService
+	calendarID string
+	event      *calendar.Event
+	emails     []string // other attendees, lower-cased
+	since      time.Time
+	maxThreads int
+}
+
+// TestableCalendarMeetingBrief gathers what is needed to prepare for an event:
+// the event itself, who the attendees are, recent mail with them, related
+// Drive files and the transcript of the previous occurrence. Sections are
+// fetched in parallel, each within a size budget, and a failing section is
+// reported in place without failing the others.
+func TestableCalendarMeetingBrief(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	eventID, errResult := common.RequireStringArg(args, "event_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	calendarID := common.ParseStringArg(args, "calendar_id", common.DefaultCalendarID)
+
+	sections := briefSectionNames
+	if requested := parseUniqueStrings(args, "sections"); len(requested) > 0 {
+		sections = nil
+		for _, name := range requested {
+			name = strings.ToLower(name)
+			if _, ok := briefSectionBudgets[name]; !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid section %q: must be one of %s", name, strings.Join(briefSectionNames, ", "))), nil
+			}
+			sections = append(sections, name)
+		}
+	}
+	maxBytes := common.ParseIntArg(args, "max_section_bytes", 0)
+	if maxBytes > 0 && maxBytes < briefMinSectionBytes {
+		return mcp.NewToolResultError(fmt.Sprintf("max_section_bytes must be at least %d", briefMinSectionBytes)), nil
+	}
+	lookback := min(common.ParseIntArg(args, "lookback_days", briefDefaultLookbackDays), briefMaxLookbackDays)
+
+	event, err := srv.GetEvent(ctx, calendarID, eventID, CalendarEventGetFields)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+
+	b := &meetingBrief{
+		request:    request,
+		srv:        srv,
+		calendarID: calendarID,
+		event:      event,
+		emails:     briefAttendeeEmails(event, common.ResolveAccountEmail(request, deps, DefaultCalendarHandlerDeps)),
+		since:      time.Now().AddDate(0, 0, -lookback),
+		maxThreads: min(common.ParseIntArg(args, "max_threads", briefDefaultThreads), briefMaxThreads),
+	}
+	fetchers := map[string]func(context.Context, *briefSection) error{
+		"attendees":  b.fetchAttendees,
+		"emails":     b.fetchEmails,
+		"files":      b.fetchFiles,
+		"transcript": b.fetchTranscript,
+	}
+
+	// Each section runs in its own goroutine and keeps its own error, so a
+	// failing service does not cancel or hide the others.
+	results := make([]*briefSection, len(sections))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, name := range sections {
+		budget := briefSectionBudgets[name]
+		if maxBytes > 0 {
+			budget = maxBytes
+		}
+		section := &briefSection{budget: budget, extra: map[string]any{}}
+		results[i] = section
+		fetch := fetchers[name]
+		g.Go(func() error {
+			section.err = fetch(gCtx, section)
+			return nil
+		})
+	}
+	g.Wait() //nolint:errcheck // goroutines return nil unconditionally; failures stored in sections
+
+	out := make(map[string]any, len(sections))
+	var failed []string
+	for i, name := range sections {
+		out[name] = results[i].format()
+		if results[i].err != nil {
+			failed = append(failed, name)
+		}
+	}
+
+	result := map[string]any{
+		"event":           formatEventFull(event),
+		"attendee_emails": b.emails,
+		"sections":        out,
+		"lookback_days":   lookback,
+	}
+	if len(failed) > 0 {
+		result["failed_sections"] = failed
+	}
+	return common.MarshalToolResult(result)
+}
+
+// briefAttendeeEmails returns the lower-cased emails of the event's other
+// participants: attendees and organizer, without rooms or the caller.
+func briefAttendeeEmails(event *calendar.Event, self string) []string {
+	seen := map[string]bool{"": true, strings.ToLower(self): true}
+	var emails []string
+	addEmail := func(email string) {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if seen[email] || len(emails) >= briefMaxAttendees {
+			return
+		}
+		seen[email] = true
+		emails = append(emails, email)
+	}
+	for _, a := range event.Attendees {
+		if !a.Self && !a.Resource {
+			addEmail(a.Email)
+		}
+	}
+	if event.Organizer != nil && !event.Organizer.Self {
+		addEmail(event.Organizer.Email)
+	}
+	return emails
+}
+
+// fetchAttendees describes each attendee, enriched from the caller's contacts.
+func (b *meetingBrief) fetchAttendees(ctx context.Context, section *briefSection) error {
+	if len(b.emails) == 0 {
+		section.note = "no other attendees"
+		return nil
+	}
+	srv, errResult, ok := contacts.ResolveContactsServiceOrError(ctx, b.request, MeetingBriefHandlerDeps.Contacts)
+	if !ok {
+		return errors.New(getResultText(errResult))
+	}
+
+	attendees := make(map[string]*calendar.EventAttendee, len(b.event.Attendees))
+	for _, a := range b.event.Attendees {
+		attendees[strings.ToLower(a.Email)] = a
+	}
+
+	// Look attendees up concurrently; items keep the attendee order.
+	items := make([]map[string]any, len(b.emails))
+	lookupErrs := make([]error, len(b.emails))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(briefLookupConcurrency)
+	for i, email := range b.emails {
+		item := map[string]any{"email": email, "in_contacts": false}
+		if a := attendees[email]; a != nil {
+			item["response_status"] = a.ResponseStatus
+			if a.DisplayName != "" {
+				item["display_name"] = a.DisplayName
+			}
+			if a.Optional {
+				item["optional"] = true
+			}
+		}
+		if b.event.Organizer != nil && strings.EqualFold(b.event.Organizer.Email, email) {
+			item["organizer"] = true
+		}
+		items[i] = item
+
+		g.Go(func() error {
+			resp, err := srv.SearchContacts(gCtx, email, &contacts.SearchContactsOptions{
+				PageSize: 5,
+				ReadMask: "names,emailAddresses,organizations,phoneNumbers",
+			})
+			if err != nil {
+				lookupErrs[i] = err
+				item["lookup_error"] = err.Error()
+				return nil
+			}
+			if resp != nil {
+				for _, r := range resp.Results {
+					if r.Person != nil && personHasEmail(r.Person.EmailAddresses, email) {
+						addContactDetails(item, r.Person.Names, r.Person.Organizations, r.Person.PhoneNumbers)
+						break
+					}
+				}
+			}
+			return nil
+		})
+	}
+	g.Wait() //nolint:errcheck // goroutines return nil unconditionally; failures stored in lookupErrs
+
+	var lastErr error
+	failures := 0
+	for i, item := range items {
+		if !section.add(item) {
+			break
+		}
+		if lookupErrs[i] != nil {
+			failures++
+			lastErr = lookupErrs[i]
+		}
+	}
+	if failures > 0 && failures == len(section.items) {
+		return fmt.Errorf("People API error: %w", lastErr)
+	}
+	return nil
+}
+
+// fetchEmails lists recent Gmail threads with any of the attendees.
+func (b *meetingBrief) fetchEmails(ctx context.Context, section *briefSection) error {
+	if len(b.emails) == 0 {
+		section.note = "no other attendees"
+		return nil
+	}
+	if MeetingBriefHandlerDeps.Gmail == nil && DefaultBriefGmailHandlerDeps == nil {
+		return errors.New("Gmail is not configured for the meeting brief")
+	}
+	srv, errResult, ok := common.ResolveServiceOrError(ctx, b.request, MeetingBriefHandlerDeps.Gmail, DefaultBriefGmailHandlerDeps)
+	if !ok {
+		return errors.New(getResultText(errResult))
+	}
+
+	terms := make([]string, 0, 2*len(b.emails))
+	for _, email := range b.emails {
+		terms = append(terms, "from:"+email, "to:"+email)
+	}
+	days := max(1, int(time.Since(b.since).Hours()/24+0.5))
+	query := fmt.Sprintf("{%s} newer_than:%dd", strings.Join(terms, " "), days)
+	section.extra["query"] = query
+
+	resp, err := srv.ListThreads(ctx, query, int64(b.maxThreads))
+	if err != nil {
+		return fmt.Errorf("Gmail API error: %w", err)
+	}
+	// Fetch threads concurrently; items keep the search order.
+	items := make([]map[string]any, len(resp.Threads))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(briefLookupConcurrency)
+	for i, t := range resp.Threads {
+		item := map[string]any{"thread_id": t.Id}
+		items[i] = item
+		g.Go(func() error {
+			thread, err := srv.GetThread(gCtx, t.Id, "metadata")
+			if err != nil {
+				item["error"] = err.Error()
+			} else if n := len(thread.Messages); n > 0 {
+				first, last := thread.Messages[0], thread.Messages[n-1]
+				item["subject"] = briefHeader(first.Payload, "Subject")
+				item["from"] = briefHeader(last.Payload, "From")
+				item["date"] = briefHeader(last.Payload, "Date")
+				item["message_count"] = n
+				item["snippet"] = truncateRunes(last.Snippet, briefSnippetChars)
+			}
+			return nil
+		})
+	}
+	g.Wait() //nolint:errcheck // goroutines return nil unconditionally; failures stored per item
+
+	for _, item := range items {
+		if !section.add(item) {
+			break
+		}
+	}
+	return nil
+}
+
+// fetchFiles lists the event's Drive attachments and files the attendees
+// own that were shared with the caller and modified recently.
+func (b *meetingBrief) fetchFiles(ctx context.Context, section *briefSection) error {
+	if len(b.event.Attachments) == 0 && len(b.emails) == 0 {
+		section.note = "no attachments or other attendees"
+		return nil
+	}
+	srv, errResult, ok := drive.ResolveDriveServiceOrError(ctx, b.request, MeetingBriefHandlerDeps.Drive)
+	if !ok {
+		return errors.New(getResultText(errResult))
+	}
+
+	seen := make(map[string]bool)
+	for _, att := range b.event.Attachments {
+		item := map[string]any{
+			"source":    "attachment",
+			"file_id":   att.FileId,
+			"name":      att.Title,
+			"mime_type": att.MimeType,
+			"url":       att.FileUrl,
+		}
+		if att.FileId != "" {
+			seen[att.FileId] = true
+			file, err := srv.GetFile(ctx, att.FileId, "id,name,mimeType,webViewLink,modifiedTime")
+			if err != nil {
+				item["error"] = err.Error()
+			} else {
+				item["name"] = file.Name
+				item["modified_time"] = file.ModifiedTime
+			}
+		}
+		if !section.add(item) {
+			return nil
+		}
+	}
+
+	if len(b.emails) == 0 {
+		return nil
+	}
+	owners := make([]string, 0, len(b.emails))
+	for _, email := range b.emails {
+		owners = append(owners, fmt.Sprintf("'%s' in owners", strings.ReplaceAll(email, "'", `\'`)))
+	}
+	query := fmt.Sprintf("(%s) and modifiedTime > '%s' and trashed = false",
+		strings.Join(owners, " or "), b.since.UTC().Format(time.RFC3339))
+	list, err := srv.ListFiles(ctx, &drive.ListFilesOptions{
+		Query:    query,
+		PageSize: briefMaxSharedFiles,
+		OrderBy:  "modifiedTime desc",
+		Fields:   "files(id,name,mimeType,webViewLink,modifiedTime,owners(emailAddress))",
+	})
+	if err != nil {
+		return fmt.Errorf("Drive API error: %w", err)
+	}
+	for _, f := range list.Files {
+		if seen[f.Id] {
+			continue
+		}
+		item := map[string]any{
+			"source":        "shared_by_attendee",
+			"file_id":       f.Id,
+			"name":          f.Name,
+			"mime_type":     f.MimeType,
+			"url":           f.WebViewLink,
+			"modified_time": f.ModifiedTime,
+		}
+		if len(f.Owners) > 0 {
+			item["owner"] = f.Owners[0].EmailAddress
+		}
+		if !section.add(item) {
+			break
+		}
+	}
+	return nil
+}
+
+// fetchTranscript returns the transcript of the previous occurrence of a
+// recurring event, matched to its Meet conference by meeting code and time.
+func (b *meetingBrief) fetchTranscript(ctx context.Context, section *briefSection) error {
+	if b.event.RecurringEventId == "" {
+		section.note = "not an occurrence of a recurring event"
+		if len(b.event.Recurrence) > 0 {
+			section.note = "event is a recurring series; pass the id of an occurrence"
+		}
+		return nil
+	}
+	prev, err := b.previousInstance(ctx)
+	if err != nil {
+		return err
+	}
+	if prev == nil {
+		section.note = fmt.Sprintf("no previous occurrence in the last %d days", briefInstanceLookbackDays)
+		return nil
+	}
+	section.extra["previous_event"] = map[string]any{
+		"id":    prev.Id,
+		"start": eventDateTimeString(prev.Start),
+		"end":   eventDateTimeString(prev.End),
+	}
+
+	code := meetingCode(prev)
+	if code == "" {
+		section.note = "previous occurrence has no Google Meet conference"
+		return nil
+	}
+	start, err := parseEventDateTime(prev.Start)
+	if err != nil {
+		return fmt.Errorf("previous occurrence has an invalid start: %w", err)
+	}
+	end, err := parseEventDateTime(prev.End)
+	if err != nil {
+		end = start
+	}
+	windowStart, windowEnd := start.Add(-briefConferenceSlack), end.Add(briefConferenceSlack)
+
+	srv, errResult, ok := meet.ResolveMeetServiceOrError(ctx, b.request, MeetingBriefHandlerDeps.Meet)
+	if !ok {
+		return errors.New(getResultText(errResult))
+	}
+	filter := fmt.Sprintf(`space.meeting_code = "%s" AND start_time >= "%s" AND start_time <= "%s"`,
+		code, windowStart.UTC().Format(time.RFC3339), windowEnd.UTC().Format(time.RFC3339))
+	records, _, err := srv.ListConferenceRecords(ctx, filter, "", 10)
+	if err != nil {
+		return fmt.Errorf("Meet API error: %w", err)
+	}
+	record := earliestRecordIn(records, windowStart, windowEnd)
+	if record == nil {
+		section.note = "no Meet conference record found for the previous occurrence"
+		return nil
+	}
+	section.extra["conference_record"] = record.Name
+
+	transcripts, _, err := srv.ListTranscripts(ctx, record.Name, "")
+	if err != nil {
+		return fmt.Errorf("Meet API error: %w", err)
+	}
+	if len(transcripts) == 0 {
+		section.note = "previous occurrence was not transcribed"
+		return nil
+	}
+	transcript := transcripts[0]
+	for _, t := range transcripts {
+		if t.State == "FILE_GENERATED" {
+			transcript = t
+			break
+		}
+	}
+	section.extra["transcript"] = transcript.Name
+	if transcript.DocsDestination != nil && transcript.DocsDestination.ExportUri != "" {
+		section.extra["document_url"] = transcript.DocsDestination.ExportUri
+	}
+
+	// Speaker names are best effort; entries are still useful without them.
+	speakers := make(map[string]string)
+	if participants, _, _, err := srv.ListParticipants(ctx, record.Name, "", 100); err == nil {
+		for _, p := range participants {
+			speakers[p.Name] = participantName(p)
+		}
+	}
+
+	pageToken := ""
+	for {
+		entries, next, err := srv.ListTranscriptEntries(ctx, transcript.Name, pageToken, 100)
+		if err != nil {
+			return fmt.Errorf("Meet API error: %w", err)
+		}
+		for _, e := range entries {
+			item := map[string]any{
+				"speaker":    speakers[e.Participant],
+				"text":       truncateRunes(e.Text, briefEntryChars),
+				"start_time": e.StartTime,
+			}
+			if !section.add(item) {
+				return nil
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		pageToken = next
+	}
+}
+
+// previousInstance returns the latest non-cancelled occurrence of the event's
+// series that starts before the event, or nil if there is none.
+func (b *meetingBrief) previousInstance(ctx context.Context) (*calendar.Event, error) {
+	current, err := parseEventDateTime(b.event.Start)
+	if err != nil {
+		return nil, fmt.Errorf("event has an invalid start: %w", err)
+	}
+	opts := &ListInstancesOptions{
+		TimeMin:    current.AddDate(0, 0, -briefInstanceLookbackDays).Format(time.RFC3339),
+		TimeMax:    current.Format(time.RFC3339),
+		MaxResults: 250,
+	}
+
+	var prev *calendar.Event
+	var prevStart time.Time
+	for {
+		resp, err := b.srv.ListInstances(ctx, b.calendarID, b.event.RecurringEventId, opts)
+		if err != nil {
+			return nil, fmt.Errorf("Calendar API error: %w", err)
+		}
+		for _, ev := range resp.Items {
+			if ev.Id == b.event.Id || ev.Status == "cancelled" || ev.Start == nil {
+				continue
+			}
+			start, err := parseEventDateTime(ev.Start)
+			if err != nil || !start.Before(current) {
+				continue
+			}
+			if prev == nil || start.After(prevStart) {
+				prev, prevStart = ev, start
+			}
+		}
+		if resp.NextPageToken == "" {
+			return prev, nil
+		}
+		opts.PageToken = resp.NextPageToken
+	}
+}
+
+// meetingCode returns the Google Meet code of an event's conference, or "".
+func meetingCode(event *calendar.Event) string {
+	if cd := event.ConferenceData; cd != nil && cd.ConferenceSolution != nil &&
+		cd.ConferenceSolution.Key != nil && cd.ConferenceSolution.Key.Type == "hangoutsMeet" && cd.ConferenceId != "" {
+		return cd.ConferenceId
+	}
+	if m := meetCodePattern.FindStringSubmatch(event.HangoutLink); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// earliestRecordIn returns the earliest conference record starting within
+// [from, to]. The Meet filter already restricts this; the check guards
+// against records whose start time cannot be matched server-side.
+func earliestRecordIn(records []*gmeet.ConferenceRecord, from, to time.Time) *gmeet.ConferenceRecord {
+	var inWindow []*gmeet.ConferenceRecord
+	for _, r := range records {
+		t, err := time.Parse(time.RFC3339, r.StartTime)
+		if err == nil && !t.Before(from) && !t.After(to) {
+			inWindow = append(inWindow, r)
+		}
+	}
+	if len(inWindow) == 0 {
+		return nil
+	}
+	sort.Slice(inWindow, func(i, j int) bool { return inWindow[i].StartTime < inWindow[j].StartTime })
+	return inWindow[0]
+}
+
+// personHasEmail reports whether a contact lists email among its addresses.
+func personHasEmail(addresses []*people.EmailAddress, email string) bool {
+	for _, a := range addresses {
+		if strings.EqualFold(a.Value, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// addContactDetails adds a contact's name, organization and phone to item.
+func addContactDetails(item map[string]any, names []*people.Name, orgs []*people.Organization, phones []*people.PhoneNumber) {
+	item["in_contacts"] = true
+	if len(names) > 0 && names[0].DisplayName != "" {
+		item["contact_name"] = names[0].DisplayName
+	}
+	if len(orgs) > 0 {
+		if orgs[0].Name != "" {
+			item["organization"] = orgs[0].Name
+		}
+		if orgs[0].Title != "" {
+			item["title"] = orgs[0].Title
+		}
+	}
+	if len(phones) > 0 && phones[0].Value != "" {
+		item["phone"] = phones[0].Value
+	}
+}
+
+// briefHeader returns the value of a message header, or "".
+func briefHeader(payload *gmail.MessagePart, name string) string {
+	if payload == nil {
+		return ""
+	}
+	for _, h := range payload.Headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// participantName returns a Meet participant's display name.
+func participantName(p *gmeet.Participant) string {
+	switch {
+	case p.SignedinUser != nil:
+		return p.SignedinUser.DisplayName
+	case p.AnonymousUser != nil:
+		return p.AnonymousUser.DisplayName
+	case p.PhoneUser != nil:
+		return p.PhoneUser.DisplayName
+	}
+	return ""
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with "…".
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
@@ -0,0 +1,342 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/contacts"
+	"github.com/aliwatters/gsuite-mcp/internal/drive"
+	"github.com/aliwatters/gsuite-mcp/internal/meet"
+	"google.golang.org/api/calendar/v3"
+	gdrive "google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/people/v1"
+)
+
+// fakeBriefGmail is a common.GmailThreadReader serving canned threads.
+type fakeBriefGmail struct {
+	threads map[string]*gmail.Thread
+	err     error
+	query   string
+}
+
+func (f *fakeBriefGmail) ListThreads(_ context.Context, query string, maxResults int64) (*gmail.ListThreadsResponse, error) {
+	f.query = query
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := &gmail.ListThreadsResponse{}
+	for i := 0; i < len(f.threads) && int64(i) < maxResults; i++ {
+		resp.Threads = append(resp.Threads, &gmail.Thread{Id: fmt.Sprintf("t%d", i)})
+	}
+	return resp, nil
+}
+
+func (f *fakeBriefGmail) GetThread(_ context.Context, threadID, _ string) (*gmail.Thread, error) {
+	if t, ok := f.threads[threadID]; ok {
+		return t, nil
+	}
+	return nil, errors.New("thread not found")
+}
+
+func briefMessage(subject, from, snippet string) *gmail.Message {
+	return &gmail.Message{
+		Snippet: snippet,
+		Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+			{Name: "Subject", Value: subject},
+			{Name: "From", Value: from},
+			{Name: "Date", Value: "Mon, 18 Mar 2024 09:00:00 +0000"},
+		}},
+	}
+}
+
+// briefMocks are the non-calendar services behind a meeting brief.
+type briefMocks struct {
+	gmail    *fakeBriefGmail
+	contacts *contacts.MockContactsService
+	drive    *drive.MockDriveService
+	meet     *meet.MockMeetService
+}
+
+// useMeetingBriefMocks routes the brief's Gmail, Contacts, Drive and Meet
+// calls to mocks with data about the weekly sync set up by addWeeklySync.
+func useMeetingBriefMocks(t *testing.T) *briefMocks {
+	t.Helper()
+	m := &briefMocks{gmail: &fakeBriefGmail{threads: map[string]*gmail.Thread{
+		"t0": {Id: "t0", Messages: []*gmail.Message{
+			briefMessage("Sync agenda", "alice@example.com", "first"),
+			briefMessage("Re: Sync agenda", "bob@partner.com", "Added the budget item"),
+		}},
+	}}}
+
+	contactsFixtures := contacts.NewContactsTestFixtures()
+	contactsFixtures.MockService.SearchContactsFunc = func(_ context.Context, query string, _ *contacts.SearchContactsOptions) (*people.SearchResponse, error) {
+		if query != "alice@example.com" {
+			return &people.SearchResponse{}, nil
+		}
+		return &people.SearchResponse{Results: []*people.SearchResult{{Person: &people.Person{
+			Names:          []*people.Name{{DisplayName: "Alice Smith"}},
+			EmailAddresses: []*people.EmailAddress{{Value: "Alice@example.com"}},
+			Organizations:  []*people.Organization{{Name: "Example Corp", Title: "PM"}},
+			PhoneNumbers:   []*people.PhoneNumber{{Value: "+1 555 0100"}},
+		}}}}, nil
+	}
+	m.contacts = contactsFixtures.MockService
+
+	driveFixtures := drive.NewDriveTestFixtures()
+	driveFixtures.MockService.GetFileFunc = func(_ context.Context, fileID string, _ string) (*gdrive.File, error) {
+		if fileID == "doc-1" {
+			return &gdrive.File{Id: "doc-1", Name: "Sync notes", ModifiedTime: "2024-03-20T08:00:00Z"}, nil
+		}
+		return nil, errors.New("file not found")
+	}
+	driveFixtures.MockService.ListFilesFunc = func(_ context.Context, opts *drive.ListFilesOptions) (*gdrive.FileList, error) {
+		if !strings.Contains(opts.Query, "'bob@partner.com' in owners") {
+			t.Errorf("unexpected Drive query: %s", opts.Query)
+		}
+		return &gdrive.FileList{Files: []*gdrive.File{
+			{Id: "doc-1", Name: "Sync notes"},
+			{Id: "sheet-2", Name: "Budget", ModifiedTime: "2024-03-19T12:00:00Z", Owners: []*gdrive.User{{EmailAddress: "bob@partner.com"}}},
+		}}, nil
+	}
+	m.drive = driveFixtures.MockService
+
+	meetFixtures := meet.NewMeetTestFixtures()
+	m.meet = meetFixtures.MockService
+
+	MeetingBriefHandlerDeps = MeetingBriefDeps{
+		Gmail:    common.NewTestFixtures[common.GmailThreadReader](m.gmail).Deps,
+		Contacts: contactsFixtures.Deps,
+		Drive:    driveFixtures.Deps,
+		Meet:     meetFixtures.Deps,
+	}
+	t.Cleanup(func() { MeetingBriefHandlerDeps = MeetingBriefDeps{} })
+	return m
+}
+
+// addWeeklySync adds a weekly series with a past occurrence on 2024-03-15,
+// matching the Meet mock's conference record, and the next on 2024-03-22.
+func addWeeklySync(fixtures *CalendarTestFixtures) {
+	attendees := []*calendar.EventAttendee{
+		{Email: "test@example.com", Self: true, ResponseStatus: "accepted"},
+		{Email: "alice@example.com", ResponseStatus: "accepted"},
+		{Email: "bob@partner.com", DisplayName: "Bob", ResponseStatus: "needsAction", Optional: true},
+		{Email: "room-1@resource.calendar.google.com", Resource: true},
+	}
+	events := fixtures.MockService.Events["primary"]
+	events["weekly"] = &calendar.Event{
+		Id: "weekly", Summary: "Weekly sync", Recurrence: []string{"RRULE:FREQ=WEEKLY"},
+		Start: &calendar.EventDateTime{DateTime: "2024-03-08T10:00:00Z"},
+		End:   &calendar.EventDateTime{DateTime: "2024-03-08T11:00:00Z"},
+	}
+	for _, day := range []string{"08", "15", "22"} {
+		events["weekly_202403"+day] = &calendar.Event{
+			Id: "weekly_202403" + day, Summary: "Weekly sync", RecurringEventId: "weekly",
+			Start:       &calendar.EventDateTime{DateTime: "2024-03-" + day + "T10:00:00Z"},
+			End:         &calendar.EventDateTime{DateTime: "2024-03-" + day + "T11:00:00Z"},
+			HangoutLink: "https://meet.google.com/abc-defg-hij",
+			Attendees:   attendees,
+			Organizer:   &calendar.EventOrganizer{Email: "test@example.com", Self: true},
+			Attachments: []*calendar.EventAttachment{{FileId: "doc-1", Title: "Sync notes", FileUrl: "https://docs.google.com/document/d/doc-1/edit"}},
+		}
+	}
+}
+
+func briefSectionData(t *testing.T, data map[string]any, name string) map[string]any {
+	t.Helper()
+	sections, _ := data["sections"].(map[string]any)
+	section, ok := sections[name].(map[string]any)
+	if !ok {
+		t.Fatalf("missing section %q in %v", name, data["sections"])
+	}
+	return section
+}
+
+func briefItems(section map[string]any) []map[string]any {
+	raw, _ := section["items"].([]any)
+	items := make([]map[string]any, 0, len(raw))
+	for _, v := range raw {
+		items = append(items, v.(map[string]any))
+	}
+	return items
+}
+
+func TestCalendarMeetingBrief(t *testing.T) {
+	mocks := useMeetingBriefMocks(t)
+	fixtures := NewCalendarTestFixtures()
+	addWeeklySync(fixtures)
+
+	data := runCalendarTool(t, fixtures, TestableCalendarMeetingBrief, map[string]any{"event_id": "weekly_20240322"})
+
+	if event, _ := data["event"].(map[string]any); event["id"] != "weekly_20240322" {
+		t.Errorf("unexpected event: %v", data["event"])
+	}
+	if got := fmt.Sprint(data["attendee_emails"]); got != "[alice@example.com bob@partner.com]" {
+		t.Errorf("attendee_emails = %s, want the other people only", got)
+	}
+	if _, ok := data["failed_sections"]; ok {
+		t.Errorf("unexpected failed sections: %v", data["failed_sections"])
+	}
+
+	attendees := briefItems(briefSectionData(t, data, "attendees"))
+	if len(attendees) != 2 {
+		t.Fatalf("expected 2 attendees, got %v", attendees)
+	}
+	alice, bob := attendees[0], attendees[1]
+	if alice["in_contacts"] != true || alice["contact_name"] != "Alice Smith" || alice["organization"] != "Example Corp" || alice["title"] != "PM" || alice["phone"] != "+1 555 0100" {
+		t.Errorf("unexpected contact details: %v", alice)
+	}
+	if bob["in_contacts"] != false || bob["display_name"] != "Bob" || bob["optional"] != true || bob["response_status"] != "needsAction" {
+		t.Errorf("unexpected attendee: %v", bob)
+	}
+
+	emails := briefSectionData(t, data, "emails")
+	if !strings.Contains(mocks.gmail.query, "from:alice@example.com to:alice@example.com") || !strings.Contains(mocks.gmail.query, "newer_than:30d") {
+		t.Errorf("unexpected Gmail query: %s", mocks.gmail.query)
+	}
+	threads := briefItems(emails)
+	if len(threads) != 1 || threads[0]["subject"] != "Sync agenda" || threads[0]["from"] != "bob@partner.com" ||
+		threads[0]["snippet"] != "Added the budget item" || threads[0]["message_count"] != float64(2) {
+		t.Errorf("unexpected threads: %v", threads)
+	}
+
+	files := briefItems(briefSectionData(t, data, "files"))
+	if len(files) != 2 {
+		t.Fatalf("expected attachment and shared file without duplicates, got %v", files)
+	}
+	if files[0]["source"] != "attachment" || files[0]["modified_time"] != "2024-03-20T08:00:00Z" {
+		t.Errorf("unexpected attachment: %v", files[0])
+	}
+	if files[1]["source"] != "shared_by_attendee" || files[1]["file_id"] != "sheet-2" || files[1]["owner"] != "bob@partner.com" {
+		t.Errorf("unexpected shared file: %v", files[1])
+	}
+
+	transcript := briefSectionData(t, data, "transcript")
+	if prev, _ := transcript["previous_event"].(map[string]any); prev["id"] != "weekly_20240315" {
+		t.Errorf("expected the 2024-03-15 occurrence, got %v", transcript["previous_event"])
+	}
+	if transcript["conference_record"] != "conferenceRecords/abc-123" || transcript["document_url"] == nil {
+		t.Errorf("unexpected transcript source: %v", transcript)
+	}
+	entries := briefItems(transcript)
+	if len(entries) != 2 || entries[0]["speaker"] != "Alice Smith" || entries[1]["speaker"] != "Guest User" {
+		t.Errorf("unexpected transcript entries: %v", entries)
+	}
+}
+
+func TestCalendarMeetingBrief_PartialFailures(t *testing.T) {
+	mocks := useMeetingBriefMocks(t)
+	mocks.gmail.err = errors.New("quota exceeded")
+	mocks.meet.Errors.ListConferenceRecords = errors.New("permission denied")
+	fixtures := NewCalendarTestFixtures()
+	addWeeklySync(fixtures)
+
+	data := runCalendarTool(t, fixtures, TestableCalendarMeetingBrief, map[string]any{"event_id": "weekly_20240322"})
+
+	if got := fmt.Sprint(data["failed_sections"]); got != "[emails transcript]" {
+		t.Errorf("failed_sections = %s", got)
+	}
+	emails := briefSectionData(t, data, "emails")
+	if emails["status"] != "error" || !strings.Contains(emails["error"].(string), "quota exceeded") {
+		t.Errorf("unexpected emails section: %v", emails)
+	}
+	transcript := briefSectionData(t, data, "transcript")
+	if transcript["status"] != "error" || !strings.Contains(transcript["error"].(string), "permission denied") {
+		t.Errorf("unexpected transcript section: %v", transcript)
+	}
+	for _, name := range []string{"attendees", "files"} {
+		if s := briefSectionData(t, data, name); s["status"] != "ok" || s["count"] == float64(0) {
+			t.Errorf("section %s should be unaffected: %v", name, s)
+		}
+	}
+}
+
+func TestCalendarMeetingBrief_Budget(t *testing.T) {
+	mocks := useMeetingBriefMocks(t)
+	long := strings.Repeat("word ", 100)
+	for i := range 10 {
+		id := fmt.Sprintf("t%d", i)
+		mocks.gmail.threads[id] = &gmail.Thread{Id: id, Messages: []*gmail.Message{briefMessage("Thread "+id, "alice@example.com", long)}}
+	}
+	fixtures := NewCalendarTestFixtures()
+	addWeeklySync(fixtures)
+
+	data := runCalendarTool(t, fixtures, TestableCalendarMeetingBrief, map[string]any{
+		"event_id":          "weekly_20240322",
+		"sections":          []any{"emails"},
+		"max_threads":       float64(10),
+		"max_section_bytes": float64(1000),
+	})
+
+	sections := data["sections"].(map[string]any)
+	if len(sections) != 1 {
+		t.Errorf("expected only the emails section, got %v", sections)
+	}
+	emails := briefSectionData(t, data, "emails")
+	threads := briefItems(emails)
+	if emails["truncated"] != true || emails["budget_bytes"] != float64(1000) || len(threads) == 0 || len(threads) >= 10 {
+		t.Errorf("expected a truncated section, got %d threads: %v", len(threads), emails)
+	}
+	if snippet := threads[0]["snippet"].(string); len([]rune(snippet)) != briefSnippetChars+1 || !strings.HasSuffix(snippet, "…") {
+		t.Errorf("snippet not truncated: %q", snippet)
+	}
+}
+
+func TestCalendarMeetingBrief_NoPreviousInstance(t *testing.T) {
+	useMeetingBriefMocks(t)
+	fixtures := NewCalendarTestFixtures()
+	addWeeklySync(fixtures)
+
+	tests := []struct {
+		eventID string
+		note    string
+	}{
+		{"weekly_20240308", "no previous occurrence"},
+		{"weekly", "recurring series"},
+		{"event001", "not an occurrence"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.eventID, func(t *testing.T) {
+			data := runCalendarTool(t, fixtures, TestableCalendarMeetingBrief, map[string]any{
+				"event_id": tt.eventID,
+				"sections": []any{"transcript"},
+			})
+			transcript := briefSectionData(t, data, "transcript")
+			note, _ := transcript["note"].(string)
+			if transcript["status"] != "ok" || !strings.Contains(note, tt.note) {
+				t.Errorf("expected note %q, got %v", tt.note, transcript)
+			}
+		})
+	}
+}
+
+func TestCalendarMeetingBrief_Errors(t *testing.T) {
+	useMeetingBriefMocks(t)
+	fixtures := NewCalendarTestFixtures()
+
+	tests := []struct {
+		name       string
+		args       map[string]any
+		errContain string
+	}{
+		{"missing event_id", map[string]any{}, "event_id"},
+		{"unknown event", map[string]any{"event_id": "nope"}, "Calendar API error"},
+		{"invalid section", map[string]any{"event_id": "event001", "sections": []any{"slides"}}, "Invalid section"},
+		{"tiny budget", map[string]any{"event_id": "event001", "max_section_bytes": float64(10)}, "at least"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := TestableCalendarMeetingBrief(context.Background(), CreateMCPRequest(tt.args), fixtures.Deps)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError || !strings.Contains(getCalendarTextContent(result), tt.errContain) {
+				t.Errorf("expected error containing %q, got %s", tt.errContain, getCalendarTextContent(result))
+			}
+		})
+	}
+}
@@ -0,0 +1,457 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/calendar/v3"
+)
+
+// updateThisAndFollowing applies the requested edits to an occurrence and every
+// later one. The series is split: the original RRULE is truncated with UNTIL just
+// before the occurrence, and a new series carrying the edits starts at it. The
+// new series is created first so a failure never leaves the calendar with the
+// tail of the series missing. Cancelled occurrences after the split carry over
+// to the new series as EXDATEs, and excluded dates move with the series when
+// the edit changes its start time; modified occurrences cannot be moved and are
+// reported as orphaned on the original series. The new series keeps the
+// original's conference and attachments. It returns the result to report, or
+// a tool error.
+func updateThisAndFollowing(ctx context.Context, srv CalendarService, args map[string]any, calendarID string, instance *calendar.Event) (map[string]any, *mcp.CallToolResult) {
+	if instance.RecurringEventId == "" || instance.OriginalStartTime == nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("event %s is not an occurrence of a recurring event", instance.Id))
+	}
+
+	master, err := srv.GetEvent(ctx, calendarID, instance.RecurringEventId, "")
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Failed to get recurring series: %v", err))
+	}
+	if len(master.Recurrence) == 0 || master.Start == nil || master.End == nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("series %s has no recurrence rule", master.Id))
+	}
+
+	allDay := instance.OriginalStartTime.Date != ""
+	at, err := parseEventDateTime(instance.OriginalStartTime)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid occurrence start: %v", err))
+	}
+	seriesStart, err := parseEventDateTime(master.Start)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid series start: %v", err))
+	}
+
+	startTZ, endTZ := master.Start.TimeZone, master.End.TimeZone
+
+	// Editing from the first occurrence onwards is an edit of the whole series.
+	if !at.After(seriesStart) {
+		if errResult := applyEventEdits(master, args); errResult != nil {
+			return nil, errResult
+		}
+		keepSeriesTimeZone(master, startTZ, endTZ)
+		updated, err := srv.UpdateEvent(ctx, calendarID, master.Id, master)
+		if err != nil {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err))
+		}
+		result := formatEvent(updated)
+		result["html_link"] = updated.HtmlLink
+		result["recurrence"] = updated.Recurrence
+		result["scope"] = "all"
+		return result, nil
+	}
+
+	loc := time.UTC
+	if startTZ != "" {
+		if l, err := time.LoadLocation(startTZ); err == nil {
+			loc = l
+		}
+	}
+
+	occurrencesBefore := 0
+	if recurrenceHasCount(master.Recurrence) {
+		occurrencesBefore, err = countInstancesBefore(ctx, srv, calendarID, master.Id, seriesStart, at)
+		if err != nil {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err))
+		}
+	}
+	before, after, err := SplitRecurrence(master.Recurrence, at, allDay, loc, occurrencesBefore)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Cannot split recurrence: %v", err))
+	}
+
+	exceptions, err := listSeriesExceptions(ctx, srv, calendarID, master)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Calendar API error: listing exceptions: %v", err))
+	}
+	var carried []string
+	var orphaned []map[string]any
+	for _, ev := range exceptions {
+		// The occurrence being edited becomes the new series' first one.
+		if ev.Id == instance.Id || ev.OriginalStartTime == nil {
+			continue
+		}
+		orig, err := parseEventDateTime(ev.OriginalStartTime)
+		if err != nil || orig.Before(at) {
+			continue
+		}
+		if ev.Status == "cancelled" {
+			after = append(after, exdateLine(orig, allDay))
+			carried = append(carried, eventDateTimeString(ev.OriginalStartTime))
+			continue
+		}
+		orphaned = append(orphaned, formatException(master, ev))
+	}
+
+	next, err := seriesTail(master, at, allDay, loc)
+	if err != nil {
+		return nil, mcp.NewToolResultError(err.Error())
+	}
+	if errResult := applyEventEdits(next, args); errResult != nil {
+		return nil, errResult
+	}
+	keepSeriesTimeZone(next, startTZ, endTZ)
+
+	// Excluded and added dates follow the new series when the edit moves it.
+	if (next.Start.Date != "") != allDay {
+		return nil, mcp.NewToolResultError("cannot switch between all-day and timed occurrences when editing this and following occurrences")
+	}
+	nextStart, err := parseEventDateTime(next.Start)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid start: %v", err))
+	}
+	next.Recurrence, err = shiftRecurrenceDates(after, at, nextStart, allDay, loc)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Cannot move recurrence dates: %v", err))
+	}
+
+	confVersion := 0
+	if next.ConferenceData != nil {
+		confVersion = 1
+	}
+	created, err := srv.CreateEvent(ctx, calendarID, next, confVersion)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Calendar API error: creating new series: %v", err))
+	}
+	if _, err := srv.PatchEvent(ctx, calendarID, master.Id, &calendar.Event{Recurrence: before}); err != nil {
+		// Roll back so the occurrences are not duplicated.
+		if delErr := srv.DeleteEvent(ctx, calendarID, created.Id); delErr != nil {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("Calendar API error: truncating original series: %v; new series %s could not be removed: %v", err, created.Id, delErr))
+		}
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Calendar API error: truncating original series: %v", err))
+	}
+
+	newSeries := formatEvent(created)
+	newSeries["html_link"] = created.HtmlLink
+	newSeries["recurrence"] = created.Recurrence
+
+	result := map[string]any{
+		"scope":    "following",
+		"split_at": eventDateTimeString(instance.OriginalStartTime),
+		"original_series": map[string]any{
+			"id":         master.Id,
+			"recurrence": before,
+		},
+		"new_series": newSeries,
+	}
+	if len(carried) > 0 {
+		result["cancelled_carried_over"] = carried
+	}
+	if len(orphaned) > 0 {
+		result["orphaned_exceptions"] = orphaned
+		result["orphaned_note"] = "These modified occurrences stay attached to the original series, which no longer covers their dates; re-apply their changes to the new series and delete them if they are still wanted."
+	}
+	return result, nil
+}
+
+// exdateLine returns an EXDATE recurrence line excluding the occurrence
+// originally starting at orig.
+func exdateLine(orig time.Time, allDay bool) string {
+	if allDay {
+		return "EXDATE;VALUE=DATE:" + orig.Format("20060102")
+	}
+	return "EXDATE:" + orig.UTC().Format("20060102T150405Z")
+}
+
+// listSeriesExceptions returns the modified and cancelled occurrences of a
+// recurring series. Listing by iCalUID without expanding returns the series
+// and its exceptions; showDeleted adds cancelled occurrences.
+func listSeriesExceptions(ctx context.Context, srv CalendarService, calendarID string, master *calendar.Event) ([]*calendar.Event, error) {
+	opts := &ListEventsOptions{
+		ICalUID:     master.ICalUID,
+		ShowDeleted: true,
+		MaxResults:  common.CalendarMaxResultsLimit,
+	}
+	var exceptions []*calendar.Event
+	for {
+		resp, err := srv.ListEvents(ctx, calendarID, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range resp.Items {
+			if ev.RecurringEventId == master.Id {
+				exceptions = append(exceptions, ev)
+			}
+		}
+		if resp.NextPageToken == "" {
+			return exceptions, nil
+		}
+		opts.PageToken = resp.NextPageToken
+	}
+}
+
+// seriesTail returns a copy of master's content timed to start at the given
+// occurrence, ready to be created as a new series.
+func seriesTail(master *calendar.Event, at time.Time, allDay bool, loc *time.Location) (*calendar.Event, error) {
+	next := &calendar.Event{
+		Summary:                 master.Summary,
+		Description:             master.Description,
+		Location:                master.Location,
+		ColorId:                 master.ColorId,
+		Reminders:               master.Reminders,
+		Transparency:            master.Transparency,
+		Visibility:              master.Visibility,
+		GuestsCanInviteOthers:   master.GuestsCanInviteOthers,
+		GuestsCanModify:         master.GuestsCanModify,
+		GuestsCanSeeOtherGuests: master.GuestsCanSeeOtherGuests,
+		Attachments:             master.Attachments,
+	}
+	// The new series joins the same conference rather than requesting one.
+	if master.ConferenceData != nil {
+		conference := *master.ConferenceData
+		conference.CreateRequest = nil
+		next.ConferenceData = &conference
+	}
+	for _, a := range master.Attendees {
+		next.Attendees = append(next.Attendees, &calendar.EventAttendee{
+			Email:       a.Email,
+			DisplayName: a.DisplayName,
+			Optional:    a.Optional,
+			Resource:    a.Resource,
+		})
+	}
+
+	if allDay {
+		startDate, err := time.Parse("2006-01-02", master.Start.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid series start date: %w", err)
+		}
+		endDate, err := time.Parse("2006-01-02", master.End.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid series end date: %w", err)
+		}
+		days := int(endDate.Sub(startDate).Hours() / 24)
+		next.Start = &calendar.EventDateTime{Date: at.Format("2006-01-02")}
+		next.End = &calendar.EventDateTime{Date: at.AddDate(0, 0, days).Format("2006-01-02")}
+		return next, nil
+	}
+
+	start, err := parseEventDateTime(master.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid series start: %w", err)
+	}
+	end, err := parseEventDateTime(master.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid series end: %w", err)
+	}
+	next.Start = &calendar.EventDateTime{DateTime: at.In(loc).Format(time.RFC3339), TimeZone: master.Start.TimeZone}
+	next.End = &calendar.EventDateTime{DateTime: at.Add(end.Sub(start)).In(loc).Format(time.RFC3339), TimeZone: master.End.TimeZone}
+	return next, nil
+}
+
+// applyEventEdits applies the summary, description, location, color, time and
+// attendee arguments shared by the event update tools.
+func applyEventEdits(event *calendar.Event, args map[string]any) *mcp.CallToolResult {
+	if summary := common.ParseStringArg(args, "summary", ""); summary != "" {
+		event.Summary = summary
+	}
+	if val, ok := args["description"].(string); ok {
+		event.Description = val
+	}
+	if val, ok := args["location"].(string); ok {
+		event.Location = val
+	}
+	if colorID := common.ParseStringArg(args, "color_id", ""); colorID != "" {
+		event.ColorId = colorID
+	}
+	if errResult := updateEventTimes(event, args); errResult != nil {
+		return errResult
+	}
+	if attendees := parseAttendees(args); attendees != nil {
+		event.Attendees = attendees
+	}
+	return nil
+}
+
+// keepSeriesTimeZone restores the series time zones after edited start/end
+// times replaced them; the API requires a time zone to expand a timed series.
+func keepSeriesTimeZone(event *calendar.Event, startTZ, endTZ string) {
+	if event.Start != nil && event.Start.DateTime != "" && event.Start.TimeZone == "" {
+		event.Start.TimeZone = startTZ
+	}
+	if event.End != nil && event.End.DateTime != "" && event.End.TimeZone == "" {
+		event.End.TimeZone = endTZ
+	}
+}
+
+// countInstancesBefore counts the occurrences of a series (including cancelled
+// ones, which still consume a COUNT) that start before at.
+func countInstancesBefore(ctx context.Context, srv CalendarService, calendarID, eventID string, seriesStart, at time.Time) (int, error) {
+	count := 0
+	opts := &ListInstancesOptions{
+		TimeMin:     seriesStart.Format(time.RFC3339),
+		TimeMax:     at.Format(time.RFC3339),
+		ShowDeleted: true,
+		MaxResults:  common.CalendarMaxResultsLimit,
+		Fields:      "nextPageToken,items(id,originalStartTime)",
+	}
+	for {
+		resp, err := srv.ListInstances(ctx, calendarID, eventID, opts)
+		if err != nil {
+			return 0, err
+		}
+		for _, inst := range resp.Items {
+			if inst.OriginalStartTime == nil {
+				continue
+			}
+			if t, err := parseEventDateTime(inst.OriginalStartTime); err == nil && t.Before(at) {
+				count++
+			}
+		}
+		if resp.NextPageToken == "" {
+			return count, nil
+		}
+		opts.PageToken = resp.NextPageToken
+	}
+}
+
+// parseEventDateTime parses an EventDateTime; all-day dates resolve to UTC midnight.
+func parseEventDateTime(dt *calendar.EventDateTime) (time.Time, error) {
+	if dt.DateTime != "" {
+		return time.Parse(time.RFC3339, dt.DateTime)
+	}
+	return time.Parse("2006-01-02", dt.Date)
+}
+
+// TestableCalendarListExceptions lists the occurrences of a recurring event that
+// differ from the series: modified instances, cancelled instances, and dates
+// excluded by EXDATE.
+func TestableCalendarListExceptions(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	eventID, errResult := common.RequireStringArg(request.GetArguments(), "event_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	calendarID := common.ParseStringArg(request.GetArguments(), "calendar_id", common.DefaultCalendarID)
+
+	master, err := srv.GetEvent(ctx, calendarID, eventID, "")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+	if master.RecurringEventId != "" {
+		// An instance ID was given; report on its series.
+		if master, err = srv.GetEvent(ctx, calendarID, master.RecurringEventId, ""); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+		}
+	}
+	if len(master.Recurrence) == 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("event %s is not a recurring event", master.Id)), nil
+	}
+
+	events, err := listSeriesExceptions(ctx, srv, calendarID, master)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+	exceptions := make([]map[string]any, 0, len(events))
+	modified, cancelled := 0, 0
+	for _, ev := range events {
+		if ev.Status == "cancelled" {
+			cancelled++
+		} else {
+			modified++
+		}
+		exceptions = append(exceptions, formatException(master, ev))
+	}
+
+	loc := time.UTC
+	if master.Start != nil && master.Start.TimeZone != "" {
+		if l, err := time.LoadLocation(master.Start.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	excluded := []string{}
+	for _, line := range master.Recurrence {
+		prop, values, err := parseRecurrenceDates(line, loc)
+		if err != nil || prop.Name != "EXDATE" {
+			continue
+		}
+		for _, v := range values {
+			excluded = append(excluded, v.Format())
+		}
+	}
+
+	result := map[string]any{
+		"event_id":        master.Id,
+		"summary":         master.Summary,
+		"recurrence":      master.Recurrence,
+		"exceptions":      exceptions,
+		"modified_count":  modified,
+		"cancelled_count": cancelled,
+		"excluded_dates":  excluded,
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// formatException describes how an exception instance differs from its series.
+func formatException(master, ev *calendar.Event) map[string]any {
+	result := map[string]any{
+		"id": ev.Id,
+	}
+	if ev.OriginalStartTime != nil {
+		result["original_start"] = eventDateTimeString(ev.OriginalStartTime)
+	}
+	if ev.Status == "cancelled" {
+		result["type"] = "cancelled"
+		return result
+	}
+
+	result["type"] = "modified"
+	if ev.Start != nil {
+		result["start"] = eventDateTimeString(ev.Start)
+	}
+	if ev.End != nil {
+		result["end"] = eventDateTimeString(ev.End)
+	}
+	result["summary"] = ev.Summary
+
+	var changes []string
+	if ev.Start != nil && ev.OriginalStartTime != nil {
+		start, errStart := parseEventDateTime(ev.Start)
+		orig, errOrig := parseEventDateTime(ev.OriginalStartTime)
+		if errStart == nil && errOrig == nil && !start.Equal(orig) {
+			changes = append(changes, "time")
+		}
+	}
+	if ev.Summary != master.Summary {
+		changes = append(changes, "summary")
+	}
+	if ev.Description != master.Description {
+		changes = append(changes, "description")
+	}
+	if ev.Location != master.Location {
+		changes = append(changes, "location")
+	}
+	if len(ev.Attendees) != len(master.Attendees) {
+		changes = append(changes, "attendees")
+	}
+	if len(changes) > 0 {
+		result["changes"] = changes
+	}
+	return result
+}
@@ -0,0 +1,265 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// addWeeklySeries stores a weekly Monday 10:00 New York series starting 4 March
+// 2024, plus an unmodified instance on 11 March.
+func addWeeklySeries(mock *MockCalendarService) (series, instance *calendar.Event) {
+	series = createTestEvent("weekly", "Standup", "Daily sync", "2024-03-04T10:00:00-05:00", "2024-03-04T10:30:00-05:00", false)
+	series.ICalUID = "weekly@google.com"
+	series.Start.TimeZone = "America/New_York"
+	series.End.TimeZone = "America/New_York"
+	series.Recurrence = []string{"RRULE:FREQ=WEEKLY;BYDAY=MO"}
+	series.Attendees = []*calendar.EventAttendee{{Email: "team@example.com", ResponseStatus: "accepted"}}
+	mock.Events["primary"]["weekly"] = series
+
+	instance = createTestEvent("weekly_20240311T140000Z", "Standup", "Daily sync", "2024-03-11T10:00:00-04:00", "2024-03-11T10:30:00-04:00", false)
+	instance.ICalUID = series.ICalUID
+	instance.RecurringEventId = "weekly"
+	instance.OriginalStartTime = &calendar.EventDateTime{DateTime: "2024-03-11T10:00:00-04:00", TimeZone: "America/New_York"}
+	instance.Attendees = []*calendar.EventAttendee{{Email: "team@example.com", ResponseStatus: "accepted"}}
+	mock.Events["primary"][instance.Id] = instance
+	return series, instance
+}
+
+func TestCalendarUpdateInstance_Following(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	series, _ := addWeeklySeries(fixtures.MockService)
+
+	req := CreateMCPRequest(map[string]any{
+		"instance_id": "weekly_20240311T140000Z",
+		"scope":       "following",
+		"summary":     "Standup (new room)",
+		"location":    "Room 4",
+	})
+	result, err := TestableCalendarUpdateInstance(context.Background(), req, fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	// The original series now stops before 11 March 10:00 EDT (14:00 UTC).
+	wantBefore := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20240311T135959Z"}
+	if !reflect.DeepEqual(series.Recurrence, wantBefore) {
+		t.Errorf("original recurrence = %q, want %q", series.Recurrence, wantBefore)
+	}
+	if series.Summary != "Standup" {
+		t.Errorf("original summary changed to %q", series.Summary)
+	}
+
+	newSeries := data["new_series"].(map[string]any)
+	created := fixtures.MockService.Events["primary"][newSeries["id"].(string)]
+	if created == nil {
+		t.Fatalf("new series %v not stored", newSeries["id"])
+	}
+	if created.Summary != "Standup (new room)" || created.Location != "Room 4" {
+		t.Errorf("new series edits not applied: %q / %q", created.Summary, created.Location)
+	}
+	if created.Start.DateTime != "2024-03-11T10:00:00-04:00" || created.End.DateTime != "2024-03-11T10:30:00-04:00" {
+		t.Errorf("new series times = %s - %s", created.Start.DateTime, created.End.DateTime)
+	}
+	if created.Start.TimeZone != "America/New_York" {
+		t.Errorf("new series time zone = %q", created.Start.TimeZone)
+	}
+	if !reflect.DeepEqual(created.Recurrence, []string{"RRULE:FREQ=WEEKLY;BYDAY=MO"}) {
+		t.Errorf("new series recurrence = %q", created.Recurrence)
+	}
+	if len(created.Attendees) != 1 || created.Attendees[0].Email != "team@example.com" || created.Attendees[0].ResponseStatus != "" {
+		t.Errorf("new series attendees = %+v", created.Attendees)
+	}
+}
+
+func TestCalendarUpdateInstance_FollowingExceptions(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	series, _ := addWeeklySeries(fixtures.MockService)
+	events := fixtures.MockService.Events["primary"]
+
+	// Before the split: left alone.
+	early := createTestEvent("weekly_20240304T150000Z", "Standup", "", "2024-03-04T10:00:00-05:00", "2024-03-04T10:30:00-05:00", false)
+	early.Status = "cancelled"
+	// After the split: one moved to the afternoon, one cancelled.
+	moved := createTestEvent("weekly_20240318T140000Z", "Standup", "Daily sync", "2024-03-18T15:00:00-04:00", "2024-03-18T15:30:00-04:00", false)
+	cancelled := createTestEvent("weekly_20240325T140000Z", "Standup", "", "2024-03-25T10:00:00-04:00", "2024-03-25T10:30:00-04:00", false)
+	cancelled.Status = "cancelled"
+	for ev, orig := range map[*calendar.Event]string{early: "2024-03-04T10:00:00-05:00", moved: "2024-03-18T10:00:00-04:00", cancelled: "2024-03-25T10:00:00-04:00"} {
+		ev.ICalUID = series.ICalUID
+		ev.RecurringEventId = "weekly"
+		ev.OriginalStartTime = &calendar.EventDateTime{DateTime: orig, TimeZone: "America/New_York"}
+		events[ev.Id] = ev
+	}
+
+	data := runCalendarTool(t, fixtures, TestableCalendarUpdateInstance, map[string]any{
+		"instance_id": "weekly_20240311T140000Z",
+		"scope":       "following",
+		"summary":     "Standup (new room)",
+	})
+
+	created := events[data["new_series"].(map[string]any)["id"].(string)]
+	wantAfter := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO", "EXDATE:20240325T140000Z"}
+	if !reflect.DeepEqual(created.Recurrence, wantAfter) {
+		t.Errorf("new series recurrence = %q, want %q", created.Recurrence, wantAfter)
+	}
+	if carried, _ := data["cancelled_carried_over"].([]any); len(carried) != 1 || carried[0] != "2024-03-25T10:00:00-04:00" {
+		t.Errorf("cancelled_carried_over = %v", data["cancelled_carried_over"])
+	}
+	orphaned, _ := data["orphaned_exceptions"].([]any)
+	if len(orphaned) != 1 || orphaned[0].(map[string]any)["id"] != moved.Id || data["orphaned_note"] == nil {
+		t.Fatalf("expected the moved occurrence reported as orphaned, got %v", data["orphaned_exceptions"])
+	}
+	if changes := orphaned[0].(map[string]any)["changes"].([]any); changes[0] != "time" {
+		t.Errorf("orphaned changes = %v", changes)
+	}
+}
+
+func TestCalendarUpdateInstance_FollowingMovesDates(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	series, _ := addWeeklySeries(fixtures.MockService)
+	series.Recurrence = append(series.Recurrence, "EXDATE;TZID=America/New_York:20240401T100000")
+	series.ConferenceData = &calendar.ConferenceData{
+		ConferenceId:  "abc-defg-hij",
+		EntryPoints:   []*calendar.EntryPoint{{EntryPointType: "video", Uri: "https://meet.google.com/abc-defg-hij"}},
+		CreateRequest: &calendar.CreateConferenceRequest{RequestId: "old"},
+	}
+	series.Attachments = []*calendar.EventAttachment{{FileId: "doc1", FileUrl: "https://drive.google.com/open?id=doc1", Title: "Agenda"}}
+	events := fixtures.MockService.Events["primary"]
+	cancelled := createTestEvent("weekly_20240325T140000Z", "Standup", "", "2024-03-25T10:00:00-04:00", "2024-03-25T10:30:00-04:00", false)
+	cancelled.ICalUID = series.ICalUID
+	cancelled.RecurringEventId = "weekly"
+	cancelled.Status = "cancelled"
+	cancelled.OriginalStartTime = &calendar.EventDateTime{DateTime: "2024-03-25T10:00:00-04:00", TimeZone: "America/New_York"}
+	events[cancelled.Id] = cancelled
+
+	// Moving the series an hour later moves its excluded dates with it.
+	data := runCalendarTool(t, fixtures, TestableCalendarUpdateInstance, map[string]any{
+		"instance_id": "weekly_20240311T140000Z",
+		"scope":       "following",
+		"start_time":  "2024-03-11T11:00:00-04:00",
+		"end_time":    "2024-03-11T11:30:00-04:00",
+	})
+
+	created := events[data["new_series"].(map[string]any)["id"].(string)]
+	wantAfter := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO", "EXDATE:20240401T150000Z", "EXDATE:20240325T150000Z"}
+	if !reflect.DeepEqual(created.Recurrence, wantAfter) {
+		t.Errorf("new series recurrence = %q, want %q", created.Recurrence, wantAfter)
+	}
+	if created.ConferenceData == nil || created.ConferenceData.ConferenceId != "abc-defg-hij" || created.ConferenceData.CreateRequest != nil {
+		t.Errorf("expected the new series to join the existing conference, got %+v", created.ConferenceData)
+	}
+	if len(created.Attachments) != 1 || created.Attachments[0].FileId != "doc1" {
+		t.Errorf("expected the attachments carried over, got %+v", created.Attachments)
+	}
+	if calls := fixtures.MockService.Calls(); !slices.ContainsFunc(calls, func(c MethodCall) bool {
+		return c.Method == "CreateEvent" && c.Args[len(c.Args)-1] == 1
+	}) {
+		t.Errorf("expected the new series created with conferenceDataVersion=1, got %+v", calls)
+	}
+}
+
+func TestCalendarUpdateInstance_FollowingFromFirst(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	series, first := addWeeklySeries(fixtures.MockService)
+	first.OriginalStartTime = &calendar.EventDateTime{DateTime: "2024-03-04T10:00:00-05:00"}
+
+	req := CreateMCPRequest(map[string]any{"instance_id": first.Id, "scope": "following", "summary": "Renamed"})
+	result, _ := TestableCalendarUpdateInstance(context.Background(), req, fixtures.Deps)
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	if !strings.Contains(text, `"scope":"all"`) || series.Summary != "Renamed" {
+		t.Errorf("expected whole-series edit, got %s", text)
+	}
+	if !reflect.DeepEqual(series.Recurrence, []string{"RRULE:FREQ=WEEKLY;BYDAY=MO"}) {
+		t.Errorf("recurrence should be untouched, got %q", series.Recurrence)
+	}
+}
+
+func TestCalendarUpdateInstance_InvalidScope(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	addWeeklySeries(fixtures.MockService)
+	req := CreateMCPRequest(map[string]any{"instance_id": "weekly_20240311T140000Z", "scope": "all"})
+	result, _ := TestableCalendarUpdateInstance(context.Background(), req, fixtures.Deps)
+	if !result.IsError || !strings.Contains(getCalendarTextContent(result), "invalid scope") {
+		t.Errorf("expected invalid scope error, got %s", getCalendarTextContent(result))
+	}
+
+	// A non-recurring event cannot be split.
+	req = CreateMCPRequest(map[string]any{"instance_id": "event001", "scope": "following"})
+	result, _ = TestableCalendarUpdateInstance(context.Background(), req, fixtures.Deps)
+	if !result.IsError || !strings.Contains(getCalendarTextContent(result), "not an occurrence") {
+		t.Errorf("expected not-an-occurrence error, got %s", getCalendarTextContent(result))
+	}
+}
+
+func TestCalendarListExceptions(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	series, moved := addWeeklySeries(fixtures.MockService)
+	series.Recurrence = append(series.Recurrence, "EXDATE;TZID=America/New_York:20240325T100000")
+	moved.Start.DateTime = "2024-03-11T11:00:00-04:00"
+	moved.End.DateTime = "2024-03-11T11:30:00-04:00"
+	moved.Location = "Cafe"
+
+	cancelled := createTestEvent("weekly_20240318T140000Z", "Standup", "Daily sync", "2024-03-18T10:00:00-04:00", "2024-03-18T10:30:00-04:00", false)
+	cancelled.ICalUID = series.ICalUID
+	cancelled.RecurringEventId = "weekly"
+	cancelled.Status = "cancelled"
+	cancelled.OriginalStartTime = &calendar.EventDateTime{DateTime: "2024-03-18T10:00:00-04:00"}
+	fixtures.MockService.Events["primary"][cancelled.Id] = cancelled
+
+	// Looking up by an instance ID resolves to the series.
+	result, err := TestableCalendarListExceptions(context.Background(), CreateMCPRequest(map[string]any{"event_id": moved.Id}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if data["event_id"] != "weekly" || data["modified_count"] != float64(1) || data["cancelled_count"] != float64(1) {
+		t.Errorf("unexpected counts: %v", data)
+	}
+	for _, e := range data["exceptions"].([]any) {
+		ex := e.(map[string]any)
+		switch ex["id"] {
+		case moved.Id:
+			changes := ex["changes"].([]any)
+			if ex["type"] != "modified" || len(changes) != 2 || changes[0] != "time" || changes[1] != "location" {
+				t.Errorf("moved exception = %v", ex)
+			}
+		case cancelled.Id:
+			if ex["type"] != "cancelled" || ex["original_start"] != "2024-03-18T10:00:00-04:00" {
+				t.Errorf("cancelled exception = %v", ex)
+			}
+		default:
+			t.Errorf("unexpected exception %v", ex)
+		}
+	}
+	if excluded := data["excluded_dates"].([]any); len(excluded) != 1 || excluded[0] != "2024-03-25T10:00:00-04:00" {
+		t.Errorf("excluded_dates = %v", excluded)
+	}
+
+	result, _ = TestableCalendarListExceptions(context.Background(), CreateMCPRequest(map[string]any{"event_id": "event001"}), fixtures.Deps)
+	if !result.IsError {
+		t.Error("expected error for a non-recurring event")
+	}
+}
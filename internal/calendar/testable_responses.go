@@ -0,0 +1,326 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	// CalendarPendingInviteFields contains the fields needed to find invitations awaiting a response.
+	CalendarPendingInviteFields = "nextPageToken,items(id,summary,status,start,end,location,htmlLink,hangoutLink,eventType,organizer,attendees,recurrence,recurringEventId,originalStartTime)"
+
+	pendingInvitesDefaultDays = 30
+)
+
+// respondStatuses maps accepted response values to Calendar responseStatus values.
+var respondStatuses = map[string]string{
+	"accepted":  "accepted",
+	"accept":    "accepted",
+	"yes":       "accepted",
+	"declined":  "declined",
+	"decline":   "declined",
+	"no":        "declined",
+	"tentative": "tentative",
+	"maybe":     "tentative",
+}
+
+// ParseResponseStatus maps a response such as "yes", "decline" or "maybe" to
+// a Calendar responseStatus.
+func ParseResponseStatus(value string) (string, error) {
+	if status, ok := respondStatuses[strings.ToLower(strings.TrimSpace(value))]; ok {
+		return status, nil
+	}
+	return "", fmt.Errorf("invalid response %q: must be accepted, declined, or tentative", value)
+}
+
+// EventResponse is the outcome of RespondToEvent.
+type EventResponse struct {
+	Event    *calendar.Event // the event as patched
+	Previous string          // the user's responseStatus before the change
+	Comment  string          // the user's response comment after the change
+}
+
+// RespondToEvent sets the user's own response to event, patching only their
+// attendee entry so the rest of the event is left untouched. email finds the
+// user when no attendee is marked as self. A nil comment keeps the current one.
+func RespondToEvent(ctx context.Context, srv CalendarService, calendarID string, event *calendar.Event, email, response string, comment *string) (*EventResponse, error) {
+	self := selfAttendee(event, email)
+	if self == nil {
+		return nil, fmt.Errorf("you are not listed as an attendee of event %s", event.Id)
+	}
+	if self.Organizer {
+		return nil, fmt.Errorf("you are the organizer of event %s; there is no invitation to respond to", event.Id)
+	}
+
+	previous := self.ResponseStatus
+	patchAttendee := &calendar.EventAttendee{Email: self.Email, ResponseStatus: response, Comment: self.Comment}
+	if comment != nil {
+		patchAttendee.Comment = *comment
+	}
+
+	// AttendeesOmitted tells the API the list is partial, so only our own
+	// entry is changed and other guests are never rewritten.
+	patched, err := srv.PatchEvent(ctx, calendarID, event.Id, &calendar.Event{
+		Attendees:        []*calendar.EventAttendee{patchAttendee},
+		AttendeesOmitted: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Calendar API error: %w", err)
+	}
+	return &EventResponse{Event: patched, Previous: previous, Comment: patchAttendee.Comment}, nil
+}
+
+// TestableCalendarRespond sets the user's own response to an event.
+func TestableCalendarRespond(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	eventID, errResult := common.RequireStringArg(args, "event_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	responseArg, errResult := common.RequireStringArg(args, "response")
+	if errResult != nil {
+		return errResult, nil
+	}
+	response, err := ParseResponseStatus(responseArg)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	calendarID := common.ParseStringArg(args, "calendar_id", common.DefaultCalendarID)
+	instanceStart := common.ParseStringArg(args, "instance_start", "")
+
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	var event *calendar.Event
+	if instanceStart != "" {
+		event, err = findInstance(ctx, srv, calendarID, eventID, instanceStart)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	} else {
+		event, err = srv.GetEvent(ctx, calendarID, eventID, CalendarEventGetFields+",originalStartTime")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+		}
+	}
+
+	var comment *string
+	if c, ok := args["comment"].(string); ok {
+		comment = &c
+	}
+	resp, err := RespondToEvent(ctx, srv, calendarID, event, common.ResolveAccountEmail(request, deps, DefaultCalendarHandlerDeps), response, comment)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	patched := resp.Event
+
+	result := map[string]any{
+		"success":           true,
+		"event_id":          patched.Id,
+		"calendar_id":       calendarID,
+		"summary":           patched.Summary,
+		"response_status":   response,
+		"previous_response": resp.Previous,
+		"html_link":         patched.HtmlLink,
+	}
+	if resp.Comment != "" {
+		result["comment"] = resp.Comment
+	}
+	if event.RecurringEventId != "" {
+		result["recurring_event_id"] = event.RecurringEventId
+	}
+	if event.OriginalStartTime != nil {
+		result["original_start"] = eventDateTimeString(event.OriginalStartTime)
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// TestableCalendarListPendingInvites lists events in a time range where the
+// user's response is still needsAction. Recurring series can optionally be
+// expanded so each occurrence awaiting a response is listed.
+func TestableCalendarListPendingInvites(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	calendarID := common.ParseStringArg(args, "calendar_id", common.DefaultCalendarID)
+	expand := common.ParseBoolArg(args, "expand_recurring", false)
+
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, calendarID, args, "time_min", "time_max", true)
+	if errResult != nil {
+		return errResult, nil
+	}
+	now := time.Now()
+	timeMin := common.ParseStringArg(timeArgs, "time_min", now.Format(time.RFC3339))
+	timeMax := common.ParseStringArg(timeArgs, "time_max", now.AddDate(0, 0, pendingInvitesDefaultDays).Format(time.RFC3339))
+
+	// singleEvents=false keeps each series as one entry; modified occurrences
+	// (exceptions) are still returned on their own.
+	resp, err := srv.ListEvents(ctx, calendarID, &ListEventsOptions{
+		TimeMin:    timeMin,
+		TimeMax:    timeMax,
+		MaxResults: common.ParseMaxResults(args, common.CalendarDefaultMaxResults, common.CalendarMaxResultsLimit),
+		PageToken:  common.ParseStringArg(args, "page_token", ""),
+		Fields:     CalendarPendingInviteFields,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+
+	email := common.ResolveAccountEmail(request, deps, DefaultCalendarHandlerDeps)
+	invites := make([]map[string]any, 0)
+	var errors []string
+	// An exception can appear both on its own and among its series' instances.
+	seen := make(map[string]bool)
+	add := func(event *calendar.Event) {
+		if !seen[event.Id] {
+			seen[event.Id] = true
+			invites = append(invites, formatPendingInvite(event))
+		}
+	}
+	for _, event := range resp.Items {
+		if event.Status == "cancelled" {
+			continue
+		}
+		self := selfAttendee(event, email)
+		if self == nil || self.Organizer {
+			continue
+		}
+
+		if len(event.Recurrence) > 0 && expand {
+			instances, err := srv.ListInstances(ctx, calendarID, event.Id, &ListInstancesOptions{
+				TimeMin: timeMin,
+				TimeMax: timeMax,
+				Fields:  CalendarPendingInviteFields,
+			})
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %v", event.Id, err))
+				continue
+			}
+			for _, inst := range instances.Items {
+				if s := selfAttendee(inst, email); s != nil && s.ResponseStatus == "needsAction" && inst.Status != "cancelled" {
+					add(inst)
+				}
+			}
+			continue
+		}
+
+		if self.ResponseStatus == "needsAction" {
+			add(event)
+		}
+	}
+
+	result := map[string]any{
+		"calendar_id":     calendarID,
+		"time_min":        timeMin,
+		"time_max":        timeMax,
+		"invites":         invites,
+		"count":           len(invites),
+		"next_page_token": resp.NextPageToken,
+	}
+	if len(errors) > 0 {
+		result["errors"] = errors
+	}
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// formatPendingInvite formats an event awaiting the user's response.
+func formatPendingInvite(event *calendar.Event) map[string]any {
+	result := formatEvent(event)
+	result["html_link"] = event.HtmlLink
+	if event.Organizer != nil {
+		result["organizer"] = event.Organizer.Email
+	}
+	if len(event.Recurrence) > 0 {
+		result["recurring"] = true
+		result["recurrence"] = event.Recurrence
+	}
+	if event.RecurringEventId != "" {
+		result["recurring_event_id"] = event.RecurringEventId
+	}
+	if event.OriginalStartTime != nil {
+		result["original_start"] = eventDateTimeString(event.OriginalStartTime)
+	}
+	result["attendee_count"] = len(event.Attendees)
+	return result
+}
+
+// findInstance returns the occurrence of a recurring event whose original start
+// is start (RFC3339 date-time, or YYYY-MM-DD for all-day series).
+func findInstance(ctx context.Context, srv CalendarService, calendarID, eventID, start string) (*calendar.Event, error) {
+	var at time.Time
+	allDay := len(start) == len("2006-01-02")
+	var err error
+	if allDay {
+		at, err = time.Parse("2006-01-02", start)
+	} else {
+		at, err = time.Parse(time.RFC3339, start)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid instance_start %q: use RFC3339 or YYYY-MM-DD", start)
+	}
+
+	resp, err := srv.ListInstances(ctx, calendarID, eventID, &ListInstancesOptions{
+		TimeMin: at.Add(-time.Minute).Format(time.RFC3339),
+		TimeMax: at.Add(48 * time.Hour).Format(time.RFC3339),
+		Fields:  "items(" + CalendarEventGetFields + ",originalStartTime)",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calendar API error: %w", err)
+	}
+	for _, inst := range resp.Items {
+		orig := inst.OriginalStartTime
+		if orig == nil {
+			continue
+		}
+		if allDay && orig.Date == start {
+			return inst, nil
+		}
+		if t, err := time.Parse(time.RFC3339, orig.DateTime); err == nil && t.Equal(at) {
+			return inst, nil
+		}
+	}
+	return nil, fmt.Errorf("no instance of event %s starts at %s", eventID, start)
+}
+
+// selfAttendee returns the authenticated user's attendee entry. The API marks
+// it with Self; email is the fallback for events not read back from the API.
+func selfAttendee(event *calendar.Event, email string) *calendar.EventAttendee {
+	for _, a := range event.Attendees {
+		if a.Self {
+			return a
+		}
+	}
+	if email == "" {
+		return nil
+	}
+	for _, a := range event.Attendees {
+		if strings.EqualFold(a.Email, email) {
+			return a
+		}
+	}
+	return nil
+}
+
+// eventDateTimeString returns the date-time of an EventDateTime, or its date for all-day values.
+func eventDateTimeString(dt *calendar.EventDateTime) string {
+	if dt.DateTime != "" {
+		return dt.DateTime
+	}
+	return dt.Date
+}
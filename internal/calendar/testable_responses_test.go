@@ -0,0 +1,171 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"google.golang.org/api/calendar/v3"
+)
+
+// addInvite stores an event organised by someone else with the test user invited.
+func addInvite(mock *MockCalendarService, id, status string) *calendar.Event {
+	event := createTestEvent(id, "Invite "+id, "", "2024-02-05T10:00:00-08:00", "2024-02-05T11:00:00-08:00", false)
+	event.Organizer = &calendar.EventOrganizer{Email: "boss@example.com"}
+	event.Attendees = []*calendar.EventAttendee{
+		{Email: "boss@example.com", Organizer: true, ResponseStatus: "accepted"},
+		{Email: "peer@example.com", ResponseStatus: "tentative", Comment: "maybe late"},
+		{Email: common.TestEmail, Self: true, ResponseStatus: status},
+	}
+	mock.Events["primary"][id] = event
+	return event
+}
+
+func TestCalendarRespond(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	addInvite(fixtures.MockService, "inv1", "needsAction")
+
+	req := CreateMCPRequest(map[string]any{"event_id": "inv1", "response": "decline", "comment": "conflict"})
+	result, err := TestableCalendarRespond(context.Background(), req, fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if data["response_status"] != "declined" || data["previous_response"] != "needsAction" {
+		t.Errorf("unexpected result: %v", data)
+	}
+
+	// Only the self attendee was sent, with AttendeesOmitted set.
+	var patch *calendar.Event
+	for _, call := range fixtures.MockService.Calls() {
+		switch call.Method {
+		case "PatchEvent":
+			patch = call.Args[2].(*calendar.Event)
+		case "UpdateEvent":
+			t.Error("calendar_respond must not overwrite the whole event")
+		}
+	}
+	if patch == nil || !patch.AttendeesOmitted || len(patch.Attendees) != 1 || patch.Attendees[0].Email != common.TestEmail {
+		t.Fatalf("expected a self-only attendee patch, got %+v", patch)
+	}
+
+	stored := fixtures.MockService.Events["primary"]["inv1"]
+	if stored.Attendees[2].ResponseStatus != "declined" || stored.Attendees[2].Comment != "conflict" {
+		t.Errorf("self attendee not updated: %+v", stored.Attendees[2])
+	}
+	if stored.Attendees[1].ResponseStatus != "tentative" || stored.Attendees[1].Comment != "maybe late" {
+		t.Errorf("other attendee changed: %+v", stored.Attendees[1])
+	}
+}
+
+func TestCalendarRespond_Instance(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	series := addInvite(fixtures.MockService, "series1", "accepted")
+	series.Recurrence = []string{"RRULE:FREQ=WEEKLY;BYDAY=MO"}
+	inst := addInvite(fixtures.MockService, "series1_20240212T180000Z", "accepted")
+	inst.RecurringEventId = "series1"
+	inst.OriginalStartTime = &calendar.EventDateTime{DateTime: "2024-02-12T10:00:00-08:00"}
+
+	req := CreateMCPRequest(map[string]any{
+		"event_id":       "series1",
+		"response":       "tentative",
+		"instance_start": "2024-02-12T18:00:00Z",
+	})
+	result, _ := TestableCalendarRespond(context.Background(), req, fixtures.Deps)
+	text := getCalendarTextContent(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	if !strings.Contains(text, `"recurring_event_id":"series1"`) {
+		t.Errorf("expected recurring_event_id in %s", text)
+	}
+	if got := inst.Attendees[2].ResponseStatus; got != "tentative" {
+		t.Errorf("instance response = %q, want tentative", got)
+	}
+	if got := series.Attendees[2].ResponseStatus; got != "accepted" {
+		t.Errorf("series response = %q, want unchanged accepted", got)
+	}
+
+	req = CreateMCPRequest(map[string]any{"event_id": "series1", "response": "accepted", "instance_start": "2024-02-19T18:00:00Z"})
+	result, _ = TestableCalendarRespond(context.Background(), req, fixtures.Deps)
+	if !result.IsError || !strings.Contains(getCalendarTextContent(result), "no instance") {
+		t.Errorf("expected missing-instance error, got %s", getCalendarTextContent(result))
+	}
+}
+
+func TestCalendarRespond_Errors(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	addInvite(fixtures.MockService, "inv1", "needsAction")
+
+	tests := []struct {
+		name       string
+		args       map[string]any
+		errContain string
+	}{
+		{"invalid response", map[string]any{"event_id": "inv1", "response": "later"}, "invalid response"},
+		{"not an attendee", map[string]any{"event_id": "event001", "response": "accepted"}, "not listed"},
+		{"missing event", map[string]any{"event_id": "nope", "response": "accepted"}, "Calendar API error"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := TestableCalendarRespond(context.Background(), CreateMCPRequest(tc.args), fixtures.Deps)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError || !strings.Contains(getCalendarTextContent(result), tc.errContain) {
+				t.Errorf("expected error containing %q, got %s", tc.errContain, getCalendarTextContent(result))
+			}
+		})
+	}
+}
+
+func TestCalendarListPendingInvites(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	addInvite(fixtures.MockService, "pending", "needsAction")
+	addInvite(fixtures.MockService, "answered", "accepted")
+	series := addInvite(fixtures.MockService, "series1", "needsAction")
+	series.Recurrence = []string{"RRULE:FREQ=WEEKLY;BYDAY=MO"}
+	for id, status := range map[string]string{"series1_a": "accepted", "series1_b": "needsAction"} {
+		inst := addInvite(fixtures.MockService, id, status)
+		inst.RecurringEventId = "series1"
+		inst.OriginalStartTime = &calendar.EventDateTime{DateTime: "2024-02-12T10:00:00-08:00"}
+	}
+
+	list := func(args map[string]any) map[string]int {
+		t.Helper()
+		result, err := TestableCalendarListPendingInvites(context.Background(), CreateMCPRequest(args), fixtures.Deps)
+		if err != nil || result.IsError {
+			t.Fatalf("unexpected error: %v %s", err, getCalendarTextContent(result))
+		}
+		var data map[string]any
+		if err := json.Unmarshal([]byte(getCalendarTextContent(result)), &data); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		ids := make(map[string]int)
+		for _, inv := range data["invites"].([]any) {
+			ids[inv.(map[string]any)["id"].(string)]++
+		}
+		return ids
+	}
+
+	ids := list(map[string]any{})
+	if ids["pending"] != 1 || ids["series1"] != 1 || ids["answered"] != 0 || ids["event001"] != 0 {
+		t.Errorf("invites = %v, want pending and series1", ids)
+	}
+
+	// Expanding lists the occurrences awaiting a reply instead of the series,
+	// without duplicating exceptions that were also returned on their own.
+	ids = list(map[string]any{"expand_recurring": true})
+	if ids["series1"] != 0 || ids["series1_a"] != 0 || ids["series1_b"] != 1 || ids["pending"] != 1 {
+		t.Errorf("expanded invites = %v", ids)
+	}
+}
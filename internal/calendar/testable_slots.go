@@ -0,0 +1,310 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/calendar/v3"
+)
+
+// TestableCalendarFindSlots finds meeting times when every attendee is free and
+// inside their working hours, optionally booking the chosen slot.
+func TestableCalendarFindSlots(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	durationMin := common.ParseIntArg(args, "duration_minutes", 0)
+	if durationMin == 0 {
+		return mcp.NewToolResultError("duration_minutes parameter is required (positive number of minutes)"), nil
+	}
+	bufferMin := 0
+	if v, ok := args["buffer_minutes"].(float64); ok && v > 0 {
+		bufferMin = int(v)
+	}
+	granularityMin := common.ParseIntArg(args, "granularity_minutes", slotDefaultGranularity)
+	maxResults := common.ParseIntArg(args, "max_results", slotDefaultMaxResults)
+	if maxResults > slotMaxResultsLimit {
+		maxResults = slotMaxResultsLimit
+	}
+	includeSelf := common.ParseBoolArg(args, "include_self", true)
+	includeWeekends := common.ParseBoolArg(args, "include_weekends", false)
+	calendarID := common.ParseStringArg(args, "calendar_id", common.DefaultCalendarID)
+
+	defaultHours, err := parseWorkingHours(common.ParseStringArg(args, "working_hours", slotDefaultWorkingHours))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	tzName := common.ParseStringArg(args, "timezone", "")
+	if tzName == "" {
+		tzName = calendarTimeZone(ctx, srv, calendarID)
+	}
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid timezone %q: %v", tzName, err)), nil
+	}
+
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, calendarID, args, "window_start", "window_end", true)
+	if errResult != nil {
+		return errResult, nil
+	}
+	window, errResult := parseSlotWindow(timeArgs, time.Now())
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	attendees, errResult := parseSlotAttendees(args, loc, defaultHours)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if includeSelf {
+		attendees = append([]slotAttendee{{ID: calendarID, Location: loc, Hours: defaultHours}}, attendees...)
+	}
+	if len(attendees) == 0 {
+		return mcp.NewToolResultError("attendees parameter is required (list of email addresses or calendar IDs)"), nil
+	}
+
+	buffer := time.Duration(bufferMin) * time.Minute
+	items := make([]*calendar.FreeBusyRequestItem, 0, len(attendees))
+	for _, a := range attendees {
+		items = append(items, &calendar.FreeBusyRequestItem{Id: a.ID})
+	}
+	resp, err := srv.GetFreeBusy(ctx, &calendar.FreeBusyRequest{
+		TimeMin: window.Start.Add(-buffer).Format(time.RFC3339),
+		TimeMax: window.End.Add(buffer).Format(time.RFC3339),
+		Items:   items,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+
+	// Intersect each attendee's free working time. An attendee whose calendar
+	// cannot be read (e.g. external, not shared) is reported, and only their
+	// working hours constrain the search.
+	free := []timeSpan{window}
+	readable := 0
+	attendeeInfo := make([]map[string]any, 0, len(attendees))
+	for _, a := range attendees {
+		info := map[string]any{
+			"id":            a.ID,
+			"timezone":      a.Location.String(),
+			"working_hours": a.Hours.String(),
+		}
+		attendeeInfo = append(attendeeInfo, info)
+		free = intersectSpanSets(free, a.workingSpans(window, includeWeekends))
+
+		fb, ok := resp.Calendars[a.ID]
+		if !ok {
+			info["error"] = "no free/busy data returned"
+			continue
+		}
+		if len(fb.Errors) > 0 {
+			info["error"] = fb.Errors[0].Reason
+			continue
+		}
+		busy, err := busySpans(fb.Busy, buffer)
+		if err != nil {
+			info["error"] = err.Error()
+			continue
+		}
+		info["busy_count"] = len(fb.Busy)
+		free = subtractSpans(free, busy)
+		readable++
+	}
+	// With no readable calendar every working hour in the window would look
+	// free.
+	if readable == 0 {
+		reasons := make([]string, 0, len(attendeeInfo))
+		for _, info := range attendeeInfo {
+			reasons = append(reasons, fmt.Sprintf("%s: %v", info["id"], info["error"]))
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("No attendee's free/busy information could be read (%s); include your own calendar with include_self or ask attendees to share their calendars", strings.Join(reasons, "; "))), nil
+	}
+
+	duration := time.Duration(durationMin) * time.Minute
+	candidates := candidateSlots(free, duration, time.Duration(granularityMin)*time.Minute, attendees, window.Start, loc)
+	total := len(candidates)
+	if len(candidates) > maxResults {
+		candidates = candidates[:maxResults]
+	}
+
+	slots := make([]map[string]any, 0, len(candidates))
+	for _, c := range candidates {
+		local := make(map[string]string, len(attendees))
+		for _, a := range attendees {
+			local[a.ID] = formatLocalSlot(c.timeSpan, a.Location)
+		}
+		slots = append(slots, map[string]any{
+			"start": c.Start.In(loc).Format(time.RFC3339),
+			"end":   c.End.In(loc).Format(time.RFC3339),
+			"score": c.Score,
+			"local": local,
+		})
+	}
+
+	result := map[string]any{
+		"timezone":         loc.String(),
+		"window_start":     window.Start.In(loc).Format(time.RFC3339),
+		"window_end":       window.End.In(loc).Format(time.RFC3339),
+		"duration_minutes": durationMin,
+		"buffer_minutes":   bufferMin,
+		"attendees":        attendeeInfo,
+		"slots":            slots,
+		"count":            len(slots),
+		"total_candidates": total,
+	}
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
+
+	if common.ParseBoolArg(args, "create_event", false) {
+		index := 0
+		if v, ok := args["slot_index"].(float64); ok {
+			index = int(v)
+		}
+		if index < 0 || index >= len(candidates) {
+			return mcp.NewToolResultError(fmt.Sprintf("slot_index %d out of range: %d slot(s) found", index, len(candidates))), nil
+		}
+		created, err := createEventFromSlot(ctx, srv, args, calendarID, candidates[index].timeSpan, loc, attendees)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+		}
+		event := formatEvent(created)
+		event["html_link"] = created.HtmlLink
+		if created.HangoutLink != "" {
+			event["meet_link"] = created.HangoutLink
+		}
+		result["created_event"] = event
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// calendarTimeZone returns the time zone configured on calendarID (the primary
+// calendar for "primary"), or "UTC" when it cannot be determined.
+func calendarTimeZone(ctx context.Context, srv CalendarService, calendarID string) string {
+	if cal := calendarEntry(ctx, srv, calendarID); cal != nil && cal.TimeZone != "" {
+		return cal.TimeZone
+	}
+	return "UTC"
+}
+
+// calendarEntry returns the calendar list entry for calendarID (the primary
+// calendar for "primary"), or nil when it is not in the user's list.
+func calendarEntry(ctx context.Context, srv CalendarService, calendarID string) *calendar.CalendarListEntry {
+	list, err := srv.ListCalendars(ctx, CalendarListFields)
+	if err != nil {
+		return nil
+	}
+	for _, cal := range list.Items {
+		if cal.Id == calendarID || (calendarID == common.DefaultCalendarID && cal.Primary) {
+			return cal
+		}
+	}
+	return nil
+}
+
+// parseSlotWindow reads window_start/window_end (RFC3339, after
+// resolveTimeArgs), defaulting to the
+// next slotDefaultWindowDays days from now.
+func parseSlotWindow(args map[string]any, now time.Time) (timeSpan, *mcp.CallToolResult) {
+	window := timeSpan{Start: now, End: now.AddDate(0, 0, slotDefaultWindowDays)}
+	if s := common.ParseStringArg(args, "window_start", ""); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return timeSpan{}, mcp.NewToolResultError(fmt.Sprintf("Invalid window_start (RFC3339 required): %v", err))
+		}
+		window.Start = t
+		window.End = t.AddDate(0, 0, slotDefaultWindowDays)
+	}
+	if s := common.ParseStringArg(args, "window_end", ""); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return timeSpan{}, mcp.NewToolResultError(fmt.Sprintf("Invalid window_end (RFC3339 required): %v", err))
+		}
+		window.End = t
+	}
+	if !window.End.After(window.Start) {
+		return timeSpan{}, mcp.NewToolResultError("window_end must be after window_start")
+	}
+	if window.End.Sub(window.Start) > slotMaxWindowDays*24*time.Hour {
+		return timeSpan{}, mcp.NewToolResultError(fmt.Sprintf("search window cannot exceed %d days", slotMaxWindowDays))
+	}
+	return window, nil
+}
+
+// parseSlotAttendees reads the attendees array. Each entry is either an email
+// address or an object with email, and optional timezone and working_hours
+// overriding the request defaults.
+func parseSlotAttendees(args map[string]any, defaultLoc *time.Location, defaultHours workingHours) ([]slotAttendee, *mcp.CallToolResult) {
+	raw, _ := args["attendees"].([]any)
+	attendees := make([]slotAttendee, 0, len(raw))
+	for _, entry := range raw {
+		a := slotAttendee{Location: defaultLoc, Hours: defaultHours}
+		switch v := entry.(type) {
+		case string:
+			a.ID = v
+		case map[string]any:
+			a.ID = common.ParseStringArg(v, "email", "")
+			if tz := common.ParseStringArg(v, "timezone", ""); tz != "" {
+				loc, err := time.LoadLocation(tz)
+				if err != nil {
+					return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid timezone %q for attendee %s: %v", tz, a.ID, err))
+				}
+				a.Location = loc
+			}
+			if wh := common.ParseStringArg(v, "working_hours", ""); wh != "" {
+				hours, err := parseWorkingHours(wh)
+				if err != nil {
+					return nil, mcp.NewToolResultError(fmt.Sprintf("attendee %s: %v", a.ID, err))
+				}
+				a.Hours = hours
+			}
+		}
+		if a.ID != "" {
+			attendees = append(attendees, a)
+		}
+	}
+	return attendees, nil
+}
+
+// formatLocalSlot renders a slot in an attendee's local time, e.g.
+// "Tue 2024-03-05 09:00-09:30 America/New_York".
+func formatLocalSlot(span timeSpan, loc *time.Location) string {
+	start, end := span.Start.In(loc), span.End.In(loc)
+	return fmt.Sprintf("%s-%s %s", start.Format("Mon 2006-01-02 15:04"), end.Format("15:04"), loc.String())
+}
+
+// createEventFromSlot books span on calendarID, inviting every attendee other
+// than the organizer's own calendar.
+func createEventFromSlot(ctx context.Context, srv CalendarService, args map[string]any, calendarID string, span timeSpan, loc *time.Location, attendees []slotAttendee) (*calendar.Event, error) {
+	summary := common.ParseStringArg(args, "summary", "Meeting")
+	start := span.Start.In(loc).Format(time.RFC3339)
+	event := &calendar.Event{
+		Summary:     summary,
+		Description: common.ParseStringArg(args, "description", ""),
+		Start:       &calendar.EventDateTime{DateTime: start, TimeZone: loc.String()},
+		End:         &calendar.EventDateTime{DateTime: span.End.In(loc).Format(time.RFC3339), TimeZone: loc.String()},
+	}
+	for _, a := range attendees {
+		if a.ID == calendarID {
+			continue
+		}
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{Email: a.ID})
+	}
+
+	confVersion := 0
+	if common.ParseBoolArg(args, "add_conferencing", false) {
+		event.ConferenceData = buildConferenceData(calendarID, start, summary)
+		confVersion = 1
+	}
+	return srv.CreateEvent(ctx, calendarID, event, confVersion)
+}
@@ -3,9 +3,11 @@ package calendar
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/calendar/v3"
 )
 
@@ -29,7 +31,11 @@ func TestableCalendarCreateFocusTime(ctx context.Context, request mcp.CallToolRe
 	}
 
 	// Set start/end times
-	if errResult := setNewEventTimes(event, request.GetArguments()); errResult != nil {
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, calendarID, request.GetArguments(), "start_time", "end_time", false)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if errResult := setNewEventTimes(event, timeArgs); errResult != nil {
 		return errResult, nil
 	}
 
@@ -57,6 +63,9 @@ func TestableCalendarCreateFocusTime(ctx context.Context, request mcp.CallToolRe
 	result := formatEvent(created)
 	result["html_link"] = created.HtmlLink
 	result["event_type"] = "focusTime"
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
 
 	return common.MarshalToolResult(result)
 }
@@ -81,7 +90,11 @@ func TestableCalendarCreateOutOfOffice(ctx context.Context, request mcp.CallTool
 	}
 
 	// Set start/end times
-	if errResult := setNewEventTimes(event, request.GetArguments()); errResult != nil {
+	timeArgs, resolvedTimes, errResult := resolveTimeArgs(ctx, srv, calendarID, request.GetArguments(), "start_time", "end_time", common.ParseBoolArg(request.GetArguments(), "all_day", false))
+	if errResult != nil {
+		return errResult, nil
+	}
+	if errResult := setNewEventTimes(event, timeArgs); errResult != nil {
 		return errResult, nil
 	}
 
@@ -107,6 +120,9 @@ func TestableCalendarCreateOutOfOffice(ctx context.Context, request mcp.CallTool
 	result := formatEvent(created)
 	result["html_link"] = created.HtmlLink
 	result["event_type"] = "outOfOffice"
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
 
 	return common.MarshalToolResult(result)
 }
@@ -118,3 +134,385 @@ func boolToDeclineMode(autoDecline bool) string {
 	}
 	return "declineNone"
 }
+
+const (
+	// workingLocationDefaultDays is the range used when time_max is not given.
+	workingLocationDefaultDays = 7
+	// workingLocationMaxDays bounds the range of calendar_get_working_locations.
+	workingLocationMaxDays = 62
+)
+
+// workingLocationTypes maps the tool's location types to the API's.
+var workingLocationTypes = map[string]string{
+	"home":   "homeOffice",
+	"office": "officeLocation",
+	"custom": "customLocation",
+}
+
+// TestableCalendarSetWorkingLocation creates a working location event (home,
+// office or a custom place) for a day, a date range or part of a day.
+func TestableCalendarSetWorkingLocation(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+	args := request.GetArguments()
+
+	locationType, errResult := common.RequireStringArg(args, "location_type")
+	if errResult != nil {
+		return errResult, nil
+	}
+	apiType, ok := workingLocationTypes[locationType]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid location_type %q: must be 'home', 'office' or 'custom'", locationType)), nil
+	}
+
+	label := common.ParseStringArg(args, "label", "")
+	props := &calendar.EventWorkingLocationProperties{Type: apiType}
+	switch apiType {
+	case "homeOffice":
+		props.HomeOffice = map[string]any{}
+	case "officeLocation":
+		props.OfficeLocation = &calendar.EventWorkingLocationPropertiesOfficeLocation{
+			Label:          label,
+			BuildingId:     common.ParseStringArg(args, "building_id", ""),
+			FloorId:        common.ParseStringArg(args, "floor_id", ""),
+			FloorSectionId: common.ParseStringArg(args, "floor_section_id", ""),
+			DeskId:         common.ParseStringArg(args, "desk_id", ""),
+		}
+	case "customLocation":
+		if label == "" {
+			return mcp.NewToolResultError("label parameter is required for a custom location"), nil
+		}
+		props.CustomLocation = &calendar.EventWorkingLocationPropertiesCustomLocation{Label: label}
+	}
+
+	event := &calendar.Event{
+		Summary:                   workingLocationSummary(apiType, label),
+		EventType:                 "workingLocation",
+		Visibility:                "public",
+		Transparency:              "transparent",
+		WorkingLocationProperties: props,
+	}
+
+	calendarID := common.ParseStringArg(args, "calendar_id", common.DefaultCalendarID)
+
+	// A whole day (or range of days) by date, or part of a day by start/end time.
+	var resolvedTimes map[string]any
+	if common.ParseStringArg(args, "start_time", "") != "" {
+		timeArgs, resolved, errResult := resolveTimeArgs(ctx, srv, calendarID, args, "start_time", "end_time", false)
+		if errResult != nil {
+			return errResult, nil
+		}
+		if errResult := setNewEventTimes(event, timeArgs); errResult != nil {
+			return errResult, nil
+		}
+		resolvedTimes = resolved
+	} else {
+		date := common.ParseStringArg(args, "date", "")
+		if date == "" {
+			return mcp.NewToolResultError("date (YYYY-MM-DD) or start_time parameter is required"), nil
+		}
+		start, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid date (YYYY-MM-DD required): %v", err)), nil
+		}
+		last := start
+		if endDate := common.ParseStringArg(args, "end_date", ""); endDate != "" {
+			if last, err = time.Parse(time.DateOnly, endDate); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid end_date (YYYY-MM-DD required): %v", err)), nil
+			}
+			if last.Before(start) {
+				return mcp.NewToolResultError("end_date must not be before date"), nil
+			}
+		}
+		event.Start = &calendar.EventDateTime{Date: start.Format(time.DateOnly)}
+		event.End = &calendar.EventDateTime{Date: last.AddDate(0, 0, 1).Format(time.DateOnly)}
+	}
+
+	if rrule := common.ParseStringArg(args, "recurrence", ""); rrule != "" {
+		event.Recurrence = []string{rrule}
+	}
+
+	created, err := srv.CreateEvent(ctx, calendarID, event, 0)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Calendar API error: %v", err)), nil
+	}
+
+	result := formatEvent(created)
+	result["html_link"] = created.HtmlLink
+	result["event_type"] = "workingLocation"
+	for k, v := range formatWorkingLocation(created.WorkingLocationProperties) {
+		result[k] = v
+	}
+	if len(created.Recurrence) > 0 {
+		result["recurrence"] = created.Recurrence
+	}
+	if resolvedTimes != nil {
+		result["resolved_times"] = resolvedTimes
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// workingLocationSummary is the event title Calendar shows for a location.
+func workingLocationSummary(apiType, label string) string {
+	switch {
+	case apiType == "homeOffice":
+		return "Home"
+	case label != "":
+		return label
+	default:
+		return "Office"
+	}
+}
+
+// formatWorkingLocation flattens working location properties into
+// location_type and the details of the place.
+func formatWorkingLocation(props *calendar.EventWorkingLocationProperties) map[string]any {
+	result := map[string]any{}
+	if props == nil {
+		return result
+	}
+	for name, apiType := range workingLocationTypes {
+		if apiType == props.Type {
+			result["location_type"] = name
+		}
+	}
+	switch {
+	case props.OfficeLocation != nil:
+		office := props.OfficeLocation
+		if office.Label != "" {
+			result["label"] = office.Label
+		}
+		if office.BuildingId != "" {
+			result["building_id"] = office.BuildingId
+		}
+		if office.FloorId != "" {
+			result["floor_id"] = office.FloorId
+		}
+		if office.FloorSectionId != "" {
+			result["floor_section_id"] = office.FloorSectionId
+		}
+		if office.DeskId != "" {
+			result["desk_id"] = office.DeskId
+		}
+	case props.CustomLocation != nil && props.CustomLocation.Label != "":
+		result["label"] = props.CustomLocation.Label
+	}
+	return result
+}
+
+// workingLocationCalendar is the working location events read from one calendar.
+type workingLocationCalendar struct {
+	calendarID string
+	events     []*calendar.Event
+	err        error
+}
+
+// TestableCalendarGetWorkingLocations reports where the owners of one or more
+// calendars are working on each day of a range, so questions like "who is in
+// the office on Thursday" can be answered from the calendars the account can read.
+func TestableCalendarGetWorkingLocations(ctx context.Context, request mcp.CallToolRequest, deps *CalendarHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveCalendarServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+	args := request.GetArguments()
+
+	calendarIDs := parseUniqueStrings(args, "calendar_ids")
+	if len(calendarIDs) == 0 {
+		calendarIDs = []string{common.DefaultCalendarID}
+	}
+
+	var filter string
+	if s := common.ParseStringArg(args, "location_type", ""); s != "" {
+		if _, ok := workingLocationTypes[s]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid location_type %q: must be 'home', 'office' or 'custom'", s)), nil
+		}
+		filter = s
+	}
+
+	loc := time.UTC
+	tz := common.ParseStringArg(args, "timezone", "")
+	if tz == "" {
+		tz = calendarTimeZone(ctx, srv, common.DefaultCalendarID)
+	}
+	if tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid timezone %q: %v", tz, err)), nil
+		}
+		loc = l
+	}
+
+	// The range is whole days in loc: date_min through date_max inclusive.
+	now := time.Now().In(loc)
+	first := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if s := common.ParseStringArg(args, "date_min", ""); s != "" {
+		t, err := time.ParseInLocation(time.DateOnly, s, loc)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid date_min (YYYY-MM-DD required): %v", err)), nil
+		}
+		first = t
+	}
+	last := first.AddDate(0, 0, workingLocationDefaultDays-1)
+	if s := common.ParseStringArg(args, "date_max", ""); s != "" {
+		t, err := time.ParseInLocation(time.DateOnly, s, loc)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid date_max (YYYY-MM-DD required): %v", err)), nil
+		}
+		last = t
+	}
+	if last.Before(first) {
+		return mcp.NewToolResultError("date_max must not be before date_min"), nil
+	}
+	if last.After(first.AddDate(0, 0, workingLocationMaxDays-1)) {
+		return mcp.NewToolResultError(fmt.Sprintf("Date range is limited to %d days", workingLocationMaxDays)), nil
+	}
+	timeMin, timeMax := first, last.AddDate(0, 0, 1)
+
+	results := make([]workingLocationCalendar, len(calendarIDs))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(agendaConcurrency)
+	for i, calendarID := range calendarIDs {
+		i, calendarID := i, calendarID
+		g.Go(func() error {
+			results[i] = fetchWorkingLocations(gCtx, srv, calendarID, timeMin, timeMax)
+			return nil
+		})
+	}
+	g.Wait() //nolint:errcheck // goroutines return nil unconditionally; failures stored in results
+
+	calendars := make([]map[string]any, 0, len(results))
+	for _, r := range results {
+		info := map[string]any{"calendar_id": r.calendarID, "event_count": len(r.events)}
+		if r.err != nil {
+			info["error"] = r.err.Error()
+		}
+		calendars = append(calendars, info)
+	}
+
+	days := make([]map[string]any, 0)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		dayEnd := day.AddDate(0, 0, 1)
+		byType := map[string][]string{}
+		var entries []map[string]any
+		var unknown []string
+		for _, r := range results {
+			if r.err != nil {
+				continue
+			}
+			found := false
+			for _, ev := range r.events {
+				entry := workingLocationOnDay(ev, day, dayEnd, loc)
+				if entry == nil {
+					continue
+				}
+				found = true
+				locationType, _ := entry["location_type"].(string)
+				if filter != "" && locationType != filter {
+					continue
+				}
+				entry["calendar_id"] = r.calendarID
+				entries = append(entries, entry)
+				if n := len(byType[locationType]); n == 0 || byType[locationType][n-1] != r.calendarID {
+					byType[locationType] = append(byType[locationType], r.calendarID)
+				}
+			}
+			if !found && filter == "" {
+				unknown = append(unknown, r.calendarID)
+			}
+		}
+		if filter != "" && len(entries) == 0 {
+			continue
+		}
+		dayResult := map[string]any{
+			"date":      day.Format(time.DateOnly),
+			"weekday":   day.Weekday().String(),
+			"locations": entries,
+		}
+		for locationType, ids := range byType {
+			dayResult[locationType] = ids
+		}
+		if len(unknown) > 0 {
+			dayResult["not_set"] = unknown
+		}
+		days = append(days, dayResult)
+	}
+
+	result := map[string]any{
+		"date_min":  first.Format(time.DateOnly),
+		"date_max":  last.Format(time.DateOnly),
+		"timezone":  loc.String(),
+		"calendars": calendars,
+		"days":      days,
+	}
+	if filter != "" {
+		result["location_type"] = filter
+	}
+	return common.MarshalToolResult(result)
+}
+
+// fetchWorkingLocations reads the expanded working location events of one calendar.
+func fetchWorkingLocations(ctx context.Context, srv CalendarService, calendarID string, timeMin, timeMax time.Time) workingLocationCalendar {
+	result := workingLocationCalendar{calendarID: calendarID}
+	opts := &ListEventsOptions{
+		TimeMin:      timeMin.Format(time.RFC3339),
+		TimeMax:      timeMax.Format(time.RFC3339),
+		SingleEvents: true,
+		OrderBy:      "startTime",
+		EventTypes:   []string{"workingLocation"},
+		MaxResults:   common.CalendarMaxResultsLimit,
+	}
+	for {
+		resp, err := srv.ListEvents(ctx, calendarID, opts)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		for _, ev := range resp.Items {
+			if ev.Status != "cancelled" && ev.WorkingLocationProperties != nil {
+				result.events = append(result.events, ev)
+			}
+		}
+		if resp.NextPageToken == "" {
+			return result
+		}
+		opts.PageToken = resp.NextPageToken
+	}
+}
+
+// workingLocationOnDay describes ev if it covers any part of [dayStart, dayEnd),
+// or returns nil. All-day events cover the dates from start up to their
+// exclusive end date, and always their start date.
+func workingLocationOnDay(ev *calendar.Event, dayStart, dayEnd time.Time, loc *time.Location) map[string]any {
+	if ev.Start == nil || ev.End == nil {
+		return nil
+	}
+	entry := formatWorkingLocation(ev.WorkingLocationProperties)
+	entry["event_id"] = ev.Id
+	entry["summary"] = ev.Summary
+
+	if ev.Start.Date != "" {
+		// Dates in YYYY-MM-DD form compare correctly as strings.
+		date := dayStart.Format(time.DateOnly)
+		if date < ev.Start.Date || (date >= ev.End.Date && date != ev.Start.Date) {
+			return nil
+		}
+		entry["all_day"] = true
+		return entry
+	}
+
+	start, err := parseEventDateTime(ev.Start)
+	if err != nil {
+		return nil
+	}
+	end, err := parseEventDateTime(ev.End)
+	if err != nil || !end.After(dayStart) || !start.Before(dayEnd) {
+		return nil
+	}
+	entry["start"] = start.In(loc).Format(time.RFC3339)
+	entry["end"] = end.In(loc).Format(time.RFC3339)
+	return entry
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -372,3 +373,145 @@ func TestFormatEventFullWithConferenceData(t *testing.T) {
 		}
 	})
 }
+
+// ============================================================================
+// calendar_set_working_location / calendar_get_working_locations tests
+// ============================================================================
+
+func TestCalendarSetWorkingLocation(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+
+	data := runCalendarTool(t, fixtures, TestableCalendarSetWorkingLocation, map[string]any{
+		"location_type": "office",
+		"label":         "London HQ",
+		"building_id":   "LON-1",
+		"date":          "2026-03-02",
+		"recurrence":    "RRULE:FREQ=WEEKLY;BYDAY=TU,TH",
+	})
+	if data["location_type"] != "office" || data["label"] != "London HQ" || data["building_id"] != "LON-1" {
+		t.Errorf("unexpected result: %v", data)
+	}
+	if data["start"] != "2026-03-02" || data["end"] != "2026-03-03" || data["summary"] != "London HQ" {
+		t.Errorf("expected a one-day all-day event: %v", data)
+	}
+
+	var created *calendar.Event
+	for _, ev := range fixtures.MockService.Events["primary"] {
+		if ev.EventType == "workingLocation" {
+			created = ev
+		}
+	}
+	if created == nil {
+		t.Fatal("working location event not created")
+	}
+	if created.Transparency != "transparent" || created.Visibility != "public" || len(created.Recurrence) != 1 {
+		t.Errorf("unexpected event: %+v", created)
+	}
+
+	home := runCalendarTool(t, fixtures, TestableCalendarSetWorkingLocation, map[string]any{
+		"location_type": "home",
+		"start_time":    "2026-03-04T13:00:00Z",
+		"end_time":      "2026-03-04T17:00:00Z",
+	})
+	if home["location_type"] != "home" || home["summary"] != "Home" || home["start"] != "2026-03-04T13:00:00Z" {
+		t.Errorf("unexpected part-day result: %v", home)
+	}
+
+	for _, tc := range []struct {
+		args       map[string]any
+		errContain string
+	}{
+		{map[string]any{"location_type": "cafe", "date": "2026-03-02"}, "Invalid location_type"},
+		{map[string]any{"location_type": "custom", "date": "2026-03-02"}, "label parameter is required"},
+		{map[string]any{"location_type": "home"}, "date (YYYY-MM-DD) or start_time"},
+		{map[string]any{"location_type": "home", "date": "2026-03-02", "end_date": "2026-03-01"}, "end_date must not be before"},
+	} {
+		result, _ := TestableCalendarSetWorkingLocation(context.Background(), CreateMCPRequest(tc.args), fixtures.Deps)
+		if !result.IsError || !strings.Contains(getCalendarTextContent(result), tc.errContain) {
+			t.Errorf("args %v: expected error containing %q, got %s", tc.args, tc.errContain, getCalendarTextContent(result))
+		}
+	}
+}
+
+func addWorkingLocation(mock *MockCalendarService, calendarID, id string, start, end *calendar.EventDateTime, props *calendar.EventWorkingLocationProperties) {
+	if mock.Events[calendarID] == nil {
+		mock.Events[calendarID] = make(map[string]*calendar.Event)
+	}
+	mock.Events[calendarID][id] = &calendar.Event{
+		Id: id, EventType: "workingLocation", Start: start, End: end, WorkingLocationProperties: props,
+	}
+}
+
+func TestCalendarGetWorkingLocations(t *testing.T) {
+	fixtures := NewCalendarTestFixtures()
+	mock := fixtures.MockService
+	mock.Calendars["primary"].TimeZone = "Europe/London"
+
+	office := &calendar.EventWorkingLocationProperties{Type: "officeLocation", OfficeLocation: &calendar.EventWorkingLocationPropertiesOfficeLocation{Label: "London HQ"}}
+	home := &calendar.EventWorkingLocationProperties{Type: "homeOffice", HomeOffice: map[string]any{}}
+
+	// Alice is in the office Wednesday and Thursday; Bob is home Thursday
+	// morning and in the office in the afternoon; Carol has not set anything.
+	addWorkingLocation(mock, "alice@example.com", "a1", &calendar.EventDateTime{Date: "2026-03-04"}, &calendar.EventDateTime{Date: "2026-03-06"}, office)
+	addWorkingLocation(mock, "bob@example.com", "b1", &calendar.EventDateTime{DateTime: "2026-03-05T08:00:00Z"}, &calendar.EventDateTime{DateTime: "2026-03-05T12:00:00Z"}, home)
+	addWorkingLocation(mock, "bob@example.com", "b2", &calendar.EventDateTime{DateTime: "2026-03-05T12:00:00Z"}, &calendar.EventDateTime{DateTime: "2026-03-05T17:00:00Z"}, office)
+	// Ordinary events on the same calendar are ignored.
+	mock.Events["bob@example.com"]["b3"] = createTestEvent("b3", "Standup", "", "2026-03-05T09:00:00Z", "2026-03-05T09:15:00Z", false)
+
+	data := runCalendarTool(t, fixtures, TestableCalendarGetWorkingLocations, map[string]any{
+		"calendar_ids": []any{"alice@example.com", "bob@example.com", "carol@example.com"},
+		"date_min":     "2026-03-04",
+		"date_max":     "2026-03-05",
+	})
+	if data["timezone"] != "Europe/London" {
+		t.Errorf("expected the primary calendar's time zone, got %v", data["timezone"])
+	}
+	days := data["days"].([]any)
+	if len(days) != 2 {
+		t.Fatalf("expected 2 days, got %v", days)
+	}
+	thursday := days[1].(map[string]any)
+	if thursday["weekday"] != "Thursday" {
+		t.Fatalf("unexpected day: %v", thursday)
+	}
+	if got := fmt.Sprint(thursday["office"]); got != "[alice@example.com bob@example.com]" {
+		t.Errorf("office on Thursday = %s", got)
+	}
+	if got := fmt.Sprint(thursday["home"]); got != "[bob@example.com]" {
+		t.Errorf("home on Thursday = %s", got)
+	}
+	if got := fmt.Sprint(thursday["not_set"]); got != "[carol@example.com]" {
+		t.Errorf("not_set on Thursday = %s", got)
+	}
+	if locations := thursday["locations"].([]any); len(locations) != 3 {
+		t.Errorf("expected 3 locations on Thursday, got %v", locations)
+	}
+	if wednesday := days[0].(map[string]any); fmt.Sprint(wednesday["office"]) != "[alice@example.com]" {
+		t.Errorf("office on Wednesday = %v", wednesday["office"])
+	}
+
+	// Filtering by type drops days and entries that do not match.
+	homeOnly := runCalendarTool(t, fixtures, TestableCalendarGetWorkingLocations, map[string]any{
+		"calendar_ids":  []any{"alice@example.com", "bob@example.com"},
+		"date_min":      "2026-03-04",
+		"date_max":      "2026-03-05",
+		"location_type": "home",
+	})
+	if days := homeOnly["days"].([]any); len(days) != 1 || len(days[0].(map[string]any)["locations"].([]any)) != 1 {
+		t.Errorf("expected only Bob's home morning: %v", days)
+	}
+
+	for _, tc := range []struct {
+		args       map[string]any
+		errContain string
+	}{
+		{map[string]any{"date_min": "2026-03-05", "date_max": "2026-03-04"}, "date_max must not be before"},
+		{map[string]any{"date_min": "2026-01-01", "date_max": "2026-06-01"}, "limited to 62 days"},
+		{map[string]any{"location_type": "moon"}, "Invalid location_type"},
+	} {
+		result, _ := TestableCalendarGetWorkingLocations(context.Background(), CreateMCPRequest(tc.args), fixtures.Deps)
+		if !result.IsError || !strings.Contains(getCalendarTextContent(result), tc.errContain) {
+			t.Errorf("args %v: expected error containing %q, got %s", tc.args, tc.errContain, getCalendarTextContent(result))
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// resolvedTime is the result of parsing a time expression.
+type resolvedTime struct {
+	Start time.Time
+	// End is set when the expression names a range ("10:00-11:30") or a
+	// whole day ("tomorrow"), and is zero otherwise.
+	End time.Time
+	// DateOnly reports that the expression named a day but no time of day;
+	// Start is then midnight and End the following midnight.
+	DateOnly bool
+}
+
+var (
+	// clockPattern matches a time of day: "9", "9am", "9:30", "09:30 pm", "1530", "09:00:00".
+	clockPattern = regexp.MustCompile(`^(\d{1,2})(?::?(\d{2})(?::(\d{2}))?)?\s*(am|pm|a\.m\.|p\.m\.)?$`)
+	// rangeSeparator splits "10:00-11:30" or "3pm to 4pm" into start and end.
+	rangeSeparator = regexp.MustCompile(`\s*(?:-|\bto\b|\buntil\b|\btill\b)\s*`)
+	// isoDateTimePattern matches the date part of "2026-11-02T09:00".
+	isoDateTimePattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})t(.+)$`)
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var monthNames = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// parseTimeExpression parses an RFC3339 timestamp or a natural time
+// expression such as "tomorrow 3pm", "next Tuesday 10:00-11:30",
+// "Nov 2 9am", "in 2 hours" or "2026-11-02 09:00 Europe/Berlin".
+//
+// Relative parts are resolved against ref, and wall-clock times are placed in
+// ref's location unless the expression ends with an IANA zone name. A bare
+// weekday (or "this <weekday>") is today or the next such day; "next
+// <weekday>" is the first such day after today. A time of day without a date
+// is on ref's day, and a range that ends before it starts ends the next day.
+// Wall-clock times are built with time.Date, so they take the UTC offset in
+// force on that date rather than ref's.
+func parseTimeExpression(expr string, ref time.Time) (resolvedTime, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return resolvedTime{}, errors.New("empty time expression")
+	}
+	if t, err := time.Parse(time.RFC3339, expr); err == nil {
+		return resolvedTime{Start: t}, nil
+	}
+
+	fields := strings.Fields(expr)
+	if zone := fields[len(fields)-1]; strings.Contains(zone, "/") || strings.EqualFold(zone, "UTC") {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return resolvedTime{}, fmt.Errorf("unknown time zone %q", zone)
+		}
+		ref = ref.In(loc)
+		fields = fields[:len(fields)-1]
+		if len(fields) == 0 {
+			return resolvedTime{}, fmt.Errorf("no date or time before time zone %q", zone)
+		}
+	}
+
+	lower := strings.NewReplacer("–", "-", "—", "-", ",", " ").Replace(strings.ToLower(strings.Join(fields, " ")))
+	var tokens []string
+	for _, tok := range strings.Fields(lower) {
+		if m := isoDateTimePattern.FindStringSubmatch(tok); m != nil {
+			tokens = append(tokens, m[1], m[2])
+		} else {
+			tokens = append(tokens, tok)
+		}
+	}
+
+	today := midnight(ref)
+	var (
+		day         time.Time // the named day at midnight, if any
+		instant     time.Time // "now" or "in N hours"
+		modifier    string    // "this" or "next" before a weekday
+		clockTokens []string
+	)
+	setDay := func(d time.Time) error {
+		if !day.IsZero() {
+			return fmt.Errorf("more than one date in %q", expr)
+		}
+		day = d
+		return nil
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		var err error
+		switch {
+		case tok == "on" || tok == "at":
+		case tok == "this" || tok == "next":
+			modifier = tok
+			continue
+		case tok == "today":
+			err = setDay(today)
+		case tok == "tomorrow":
+			err = setDay(today.AddDate(0, 0, 1))
+		case tok == "yesterday":
+			err = setDay(today.AddDate(0, 0, -1))
+		case tok == "now":
+			instant = ref
+		case tok == "in" && i+2 < len(tokens):
+			var n int
+			if n, err = strconv.Atoi(tokens[i+1]); err != nil {
+				if tokens[i+1] != "a" && tokens[i+1] != "an" {
+					return resolvedTime{}, fmt.Errorf("cannot parse %q in %q", "in "+tokens[i+1], expr)
+				}
+				n, err = 1, nil
+			}
+			switch strings.TrimSuffix(tokens[i+2], "s") {
+			case "minute", "min":
+				instant = ref.Add(time.Duration(n) * time.Minute)
+			case "hour", "hr":
+				instant = ref.Add(time.Duration(n) * time.Hour)
+			case "day":
+				err = setDay(today.AddDate(0, 0, n))
+			case "week":
+				err = setDay(today.AddDate(0, 0, 7*n))
+			default:
+				return resolvedTime{}, fmt.Errorf("unknown unit %q in %q", tokens[i+2], expr)
+			}
+			i += 2
+		default:
+			if wd, ok := weekdayNames[tok]; ok {
+				ahead := (int(wd) - int(ref.Weekday()) + 7) % 7
+				if modifier == "next" && ahead == 0 {
+					ahead = 7
+				}
+				err = setDay(today.AddDate(0, 0, ahead))
+			} else if d, perr := time.ParseInLocation(time.DateOnly, tok, ref.Location()); perr == nil {
+				err = setDay(d)
+			} else if d, n, ok := parseMonthDay(tokens[i:], today); ok {
+				err = setDay(d)
+				i += n - 1
+			} else {
+				clockTokens = append(clockTokens, tok)
+			}
+		}
+		if err != nil {
+			return resolvedTime{}, err
+		}
+		modifier = ""
+	}
+
+	clock := strings.Join(clockTokens, " ")
+	if !instant.IsZero() {
+		if clock != "" || !day.IsZero() {
+			return resolvedTime{}, fmt.Errorf("cannot combine a relative time with a date or time of day in %q", expr)
+		}
+		return resolvedTime{Start: instant}, nil
+	}
+	if clock == "" {
+		if day.IsZero() {
+			return resolvedTime{}, fmt.Errorf("cannot parse time expression %q", expr)
+		}
+		return resolvedTime{Start: day, End: day.AddDate(0, 0, 1), DateOnly: true}, nil
+	}
+	if day.IsZero() {
+		day = today
+	}
+
+	parts := rangeSeparator.Split(clock, -1)
+	if len(parts) > 2 {
+		return resolvedTime{}, fmt.Errorf("cannot parse time expression %q", expr)
+	}
+	start, err := parseClockTime(parts[0])
+	if err != nil {
+		return resolvedTime{}, fmt.Errorf("cannot parse time expression %q: %w", expr, err)
+	}
+	if len(parts) == 1 {
+		return resolvedTime{Start: start.on(day)}, nil
+	}
+	end, err := parseClockTime(parts[1])
+	if err != nil {
+		return resolvedTime{}, fmt.Errorf("cannot parse time expression %q: %w", expr, err)
+	}
+	start.inferMeridiem(end)
+	r := resolvedTime{Start: start.on(day), End: end.on(day)}
+	if !r.End.After(r.Start) {
+		r.End = end.on(day.AddDate(0, 0, 1))
+	}
+	return r, nil
+}
+
+// midnight returns the start of t's day in t's location.
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// parseMonthDay parses "nov 2", "2 nov" or "november 2nd 2026" at the start
+// of tokens, returning the date and the number of tokens used. Without a
+// year, the next such date on or after today is used.
+func parseMonthDay(tokens []string, today time.Time) (time.Time, int, bool) {
+	if len(tokens) < 2 {
+		return time.Time{}, 0, false
+	}
+	month, ok := monthNames[tokens[0]]
+	dayTok := tokens[1]
+	if !ok {
+		if month, ok = monthNames[tokens[1]]; !ok {
+			return time.Time{}, 0, false
+		}
+		dayTok = tokens[0]
+	}
+	dayNum, err := strconv.Atoi(strings.TrimRight(dayTok, "stndrh"))
+	if err != nil || dayNum < 1 || dayNum > 31 {
+		return time.Time{}, 0, false
+	}
+
+	used, year := 2, today.Year()
+	if len(tokens) > 2 && len(tokens[2]) == 4 {
+		if y, err := strconv.Atoi(tokens[2]); err == nil {
+			used, year = 3, y
+		}
+	}
+	d := time.Date(year, month, dayNum, 0, 0, 0, 0, today.Location())
+	if used == 2 && d.Before(today) {
+		d = d.AddDate(1, 0, 0)
+	}
+	return d, used, true
+}
+
+// clockTime is a parsed time of day.
+type clockTime struct {
+	hour, minute, second int
+	meridiem             string // "am", "pm" or "" for a 24-hour time
+}
+
+// parseClockTime parses a time of day such as "3pm", "15:30", "09:00:00", "noon" or "midnight".
+func parseClockTime(s string) (clockTime, error) {
+	switch s = strings.TrimSpace(s); s {
+	case "noon":
+		return clockTime{hour: 12}, nil
+	case "midnight":
+		return clockTime{}, nil
+	}
+	m := clockPattern.FindStringSubmatch(s)
+	if m == nil {
+		return clockTime{}, fmt.Errorf("unrecognised time of day %q", s)
+	}
+	c := clockTime{meridiem: strings.ReplaceAll(m[4], ".", "")}
+	c.hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		c.minute, _ = strconv.Atoi(m[2])
+	}
+	if m[3] != "" {
+		c.second, _ = strconv.Atoi(m[3])
+	}
+	if c.second > 59 || c.minute > 59 || c.hour > 23 || (c.meridiem != "" && (c.hour == 0 || c.hour > 12)) {
+		return clockTime{}, fmt.Errorf("invalid time of day %q", s)
+	}
+	return c, nil
+}
+
+// hour24 returns the hour on a 24-hour clock.
+func (c clockTime) hour24() int {
+	switch c.meridiem {
+	case "am":
+		return c.hour % 12
+	case "pm":
+		return c.hour%12 + 12
+	}
+	return c.hour
+}
+
+// inferMeridiem gives a range start without am/pm the end's ("2-3pm"), unless
+// that would put the start after the end ("11-1pm" starts at 11am).
+func (c *clockTime) inferMeridiem(end clockTime) {
+	if c.meridiem != "" || end.meridiem == "" || c.hour > 12 {
+		return
+	}
+	c.meridiem = end.meridiem
+	if c.hour24()*60+c.minute > end.hour24()*60+end.minute {
+		c.meridiem = "am"
+	}
+}
+
+// on returns the time of day on day, in day's location.
+func (c clockTime) on(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), c.hour24(), c.minute, c.second, 0, day.Location())
+}
@@ -0,0 +1,94 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimeExpression(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Friday, two days before US daylight saving time ends.
+	ref := time.Date(2026, 10, 30, 16, 45, 0, 0, ny)
+
+	tests := []struct {
+		expr      string
+		wantStart string
+		wantEnd   string
+		dateOnly  bool
+	}{
+		{"2026-11-02T09:00:00Z", "2026-11-02T09:00:00Z", "", false},
+		{"tomorrow 3pm", "2026-10-31T15:00:00-04:00", "", false},
+		{"Tomorrow at 3:30 PM", "2026-10-31T15:30:00-04:00", "", false},
+		{"9:15", "2026-10-30T09:15:00-04:00", "", false},
+		{"noon", "2026-10-30T12:00:00-04:00", "", false},
+		// The offset is the one in force on the resolved day, not today's.
+		{"next Tuesday 10:00-11:30", "2026-11-03T10:00:00-05:00", "2026-11-03T11:30:00-05:00", false},
+		{"monday 2-3pm", "2026-11-02T14:00:00-05:00", "2026-11-02T15:00:00-05:00", false},
+		{"mon 11-1pm", "2026-11-02T11:00:00-05:00", "2026-11-02T13:00:00-05:00", false},
+		{"friday 10pm to 1am", "2026-10-30T22:00:00-04:00", "2026-10-31T01:00:00-04:00", false},
+		{"friday", "2026-10-30T00:00:00-04:00", "2026-10-31T00:00:00-04:00", true},
+		{"next friday", "2026-11-06T00:00:00-05:00", "2026-11-07T00:00:00-05:00", true},
+		{"2026-11-02 09:00 Europe/Berlin", "2026-11-02T09:00:00+01:00", "", false},
+		{"2026-11-02T09:00", "2026-11-02T09:00:00-05:00", "", false},
+		{"2026-11-02T09:00:00", "2026-11-02T09:00:00-05:00", "", false},
+		{"2026-11-02T09:00:30 Europe/Berlin", "2026-11-02T09:00:30+01:00", "", false},
+		{"Nov 2 9am", "2026-11-02T09:00:00-05:00", "", false},
+		{"3rd March, 10:00", "2027-03-03T10:00:00-05:00", "", false},
+		{"october 29 2026", "2026-10-29T00:00:00-04:00", "2026-10-30T00:00:00-04:00", true},
+		{"in 2 hours", "2026-10-30T18:45:00-04:00", "", false},
+		{"in 3 days 8am", "2026-11-02T08:00:00-05:00", "", false},
+		{"now UTC", "2026-10-30T20:45:00Z", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			got, err := parseTimeExpression(tc.expr, ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := got.Start.Format(time.RFC3339); s != tc.wantStart {
+				t.Errorf("start = %s, want %s", s, tc.wantStart)
+			}
+			end := ""
+			if !got.End.IsZero() {
+				end = got.End.Format(time.RFC3339)
+			}
+			if end != tc.wantEnd {
+				t.Errorf("end = %q, want %q", end, tc.wantEnd)
+			}
+			if got.DateOnly != tc.dateOnly {
+				t.Errorf("date only = %v, want %v", got.DateOnly, tc.dateOnly)
+			}
+		})
+	}
+}
+
+func TestParseTimeExpression_Errors(t *testing.T) {
+	ref := time.Date(2026, 10, 30, 16, 45, 0, 0, time.UTC)
+	tests := []struct {
+		expr       string
+		errContain string
+	}{
+		{"", "empty"},
+		{"whenever", "unrecognised time of day"},
+		{"tomorrow 25:00", "invalid time of day"},
+		{"13pm", "invalid time of day"},
+		{"09:00:75", "invalid time of day"},
+		{"today tomorrow", "more than one date"},
+		{"in 2 fortnights", "unknown unit"},
+		{"tomorrow in 2 hours", "cannot combine"},
+		{"9am Mars/Olympus", "unknown time zone"},
+		{"1-2-3pm", "cannot parse"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := parseTimeExpression(tc.expr, ref)
+			if err == nil || !strings.Contains(err.Error(), tc.errContain) {
+				t.Errorf("expected error containing %q, got %v", tc.errContain, err)
+			}
+		})
+	}
+}
@@ -26,6 +26,12 @@ type CitationDriveService interface {
 	// AddParentFolder adds a parent folder to a file (Drive AddParents API).
 	// This does not remove existing parents; use the Drive package's MoveFile for a full move.
 	AddParentFolder(ctx context.Context, fileID string, parentFolderID string) (*drive.File, error)
+
+	// GetStartPageToken returns the change feed token for changes made from now on.
+	GetStartPageToken(ctx context.Context) (string, error)
+
+	// ListChanges lists a page of changes made since pageToken.
+	ListChanges(ctx context.Context, pageToken string) (*drive.ChangeList, error)
 }
 
 // CitationSheetsService defines the Sheets operations used by the citation package.
@@ -91,6 +97,24 @@ func (r *realCitationDriveService) AddParentFolder(ctx context.Context, fileID s
 		Context(ctx).Do()
 }
 
+func (r *realCitationDriveService) GetStartPageToken(ctx context.Context) (string, error) {
+	token, err := r.svc.Changes.GetStartPageToken().SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	return token.StartPageToken, nil
+}
+
+func (r *realCitationDriveService) ListChanges(ctx context.Context, pageToken string) (*drive.ChangeList, error) {
+	return r.svc.Changes.List(pageToken).
+		IncludeItemsFromAllDrives(true).
+		SupportsAllDrives(true).
+		IncludeRemoved(true).
+		PageSize(1000).
+		Fields("nextPageToken,newStartPageToken,changes(fileId,removed)").
+		Context(ctx).Do()
+}
+
 // realCitationSheetsService wraps *sheets.Service to implement CitationSheetsService.
 type realCitationSheetsService struct {
 	svc *sheets.Service
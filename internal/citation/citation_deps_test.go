@@ -13,7 +13,8 @@ import (
 
 // mockCitationDrive implements CitationDriveService for tests.
 type mockCitationDrive struct {
-	files map[string]*drive.File
+	files   map[string]*drive.File
+	changes map[string]*drive.ChangeList // page token → page of the change feed
 }
 
 func (m *mockCitationDrive) GetFile(_ context.Context, fileID string, _ string) (*drive.File, error) {
@@ -44,6 +45,17 @@ func (m *mockCitationDrive) AddParentFolder(_ context.Context, fileID string, _
 	return nil, io.ErrUnexpectedEOF
 }
 
+func (m *mockCitationDrive) GetStartPageToken(_ context.Context) (string, error) {
+	return "start", nil
+}
+
+func (m *mockCitationDrive) ListChanges(_ context.Context, pageToken string) (*drive.ChangeList, error) {
+	if list, ok := m.changes[pageToken]; ok {
+		return list, nil
+	}
+	return &drive.ChangeList{NewStartPageToken: pageToken}, nil
+}
+
 // mockCitationSheets implements CitationSheetsService for tests.
 type mockCitationSheets struct {
 	created *sheets.Spreadsheet
@@ -176,3 +188,61 @@ func TestNewRealCitationServiceWithDeps_ChunkSlides(t *testing.T) {
 		t.Error("expected at least 1 chunk from slides presentation")
 	}
 }
+
+func TestRefreshIndex_UsesChangeFeed(t *testing.T) {
+	ctx := context.Background()
+
+	mockDrive := &mockCitationDrive{
+		files: map[string]*drive.File{
+			"f1": {Id: "f1", Name: "one.txt", MimeType: "text/plain", ModifiedTime: "2026-01-01T00:00:00Z"},
+			"f2": {Id: "f2", Name: "two.txt", MimeType: "text/plain", ModifiedTime: "2026-01-01T00:00:00Z"},
+			"f3": {Id: "f3", Name: "three.txt", MimeType: "text/plain", ModifiedTime: "2026-01-01T00:00:00Z"},
+		},
+	}
+	mockSheets := &mockCitationSheets{}
+	svc := NewRealCitationServiceWithDeps(mockDrive, mockSheets, &mockCitationSlides{}, &CitationConfig{
+		Indexes: map[string]IndexEntry{"idx1": {SheetID: "sheet1"}},
+	})
+	sqlite, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer sqlite.Close()
+	svc.stores["idx1"] = &DualStore{indexID: "idx1", sheets: NewSheetsStore(mockSheets, "sheet1"), sqlite: sqlite}
+	if _, err := svc.AddDocuments(ctx, "idx1", []string{"f1", "f2", "f3"}); err != nil {
+		t.Fatalf("AddDocuments: %v", err)
+	}
+
+	// Without a saved position every file is checked and the position is recorded.
+	result, err := svc.RefreshIndex(ctx, "idx1")
+	if err != nil {
+		t.Fatalf("RefreshIndex: %v", err)
+	}
+	if result.Checked != 3 {
+		t.Errorf("expected a full check of 3 files, got %d", result.Checked)
+	}
+	meta, _ := sqlite.GetMetadata(ctx)
+	if meta.ChangesPageToken != "start" {
+		t.Fatalf("expected the start token to be saved, got %q", meta.ChangesPageToken)
+	}
+
+	// f1 and f2 both changed, but the feed only reports f2 (and f3 as removed),
+	// so f1 is left alone.
+	mockDrive.files["f1"].ModifiedTime = "2026-02-01T00:00:00Z"
+	mockDrive.files["f2"].ModifiedTime = "2026-02-01T00:00:00Z"
+	mockDrive.changes = map[string]*drive.ChangeList{
+		"start": {NextPageToken: "p2", Changes: []*drive.Change{{FileId: "f2"}, {FileId: "unindexed"}}},
+		"p2":    {NewStartPageToken: "next", Changes: []*drive.Change{{FileId: "f3", Removed: true}}},
+	}
+	result, err = svc.RefreshIndex(ctx, "idx1")
+	if err != nil {
+		t.Fatalf("RefreshIndex: %v", err)
+	}
+	if result.Checked != 2 || len(result.Updated) != 1 || result.Updated[0] != "two.txt" || len(result.Removed) != 1 || result.Removed[0] != "three.txt" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	meta, _ = sqlite.GetMetadata(ctx)
+	if meta.ChangesPageToken != "next" {
+		t.Errorf("expected the feed position to advance, got %q", meta.ChangesPageToken)
+	}
+}
@@ -16,9 +16,10 @@ type CitationHandlerDeps = common.HandlerDeps[CitationService]
 var DefaultCitationHandlerDeps *CitationHandlerDeps
 
 // InitDefaultDeps initializes the default handler deps with the given config.
-func InitDefaultDeps(cfg *CitationConfig) {
+// Source documents are read through filter, which may be nil.
+func InitDefaultDeps(cfg *CitationConfig, filter *common.DriveAccessFilter) {
 	constructor := func(ctx context.Context, client *http.Client) (CitationService, error) {
-		return NewRealCitationService(ctx, client, cfg)
+		return NewRealCitationService(ctx, client, cfg, filter)
 	}
 	DefaultCitationHandlerDeps = common.NewDefaultHandlerDeps(constructor)
 }
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/option"
@@ -78,7 +79,7 @@ type IndexEntry struct {
 }
 
 // NewRealCitationService creates a new citation service from an HTTP client.
-func NewRealCitationService(ctx context.Context, client *http.Client, cfg *CitationConfig) (*RealCitationService, error) {
+func NewRealCitationService(ctx context.Context, client *http.Client, cfg *CitationConfig, filter *common.DriveAccessFilter) (*RealCitationService, error) {
 	driveSrv, err := drive.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
 		return nil, fmt.Errorf("creating drive service: %w", err)
@@ -96,8 +97,17 @@ func NewRealCitationService(ctx context.Context, client *http.Client, cfg *Citat
 		cfg = &CitationConfig{Indexes: make(map[string]IndexEntry)}
 	}
 
+	var driveSvc CitationDriveService = &realCitationDriveService{svc: driveSrv}
+	if filter.IsActive() {
+		lookup, err := filter.NewLookup(ctx, client)
+		if err != nil {
+			return nil, fmt.Errorf("creating drive access lookup: %w", err)
+		}
+		driveSvc = newFilteredCitationDriveService(driveSvc, filter, lookup)
+	}
+
 	return &RealCitationService{
-		drive:  &realCitationDriveService{svc: driveSrv},
+		drive:  driveSvc,
 		sheets: &realCitationSheetsService{svc: sheetsSrv},
 		slides: &realCitationSlidesService{svc: slidesSrv},
 		stores: make(map[string]*DualStore),
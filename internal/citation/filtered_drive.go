@@ -0,0 +1,54 @@
+package citation
+
+import (
+	"context"
+	"io"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"google.golang.org/api/drive/v3"
+)
+
+// filteredCitationDriveService wraps a CitationDriveService and refuses files
+// outside the drives and folders allowed by the drive access filter, so
+// documents cannot be indexed around it.
+type filteredCitationDriveService struct {
+	CitationDriveService
+	filter *common.DriveAccessFilter
+	lookup common.DriveLookup
+}
+
+// newFilteredCitationDriveService returns inner unchanged when the filter is inactive.
+func newFilteredCitationDriveService(inner CitationDriveService, filter *common.DriveAccessFilter, lookup common.DriveLookup) CitationDriveService {
+	if !filter.IsActive() || lookup == nil {
+		return inner
+	}
+	return &filteredCitationDriveService{CitationDriveService: inner, filter: filter, lookup: lookup}
+}
+
+func (f *filteredCitationDriveService) GetFile(ctx context.Context, fileID string, fields string) (*drive.File, error) {
+	if err := f.filter.CheckFile(ctx, f.lookup, fileID); err != nil {
+		return nil, err
+	}
+	return f.CitationDriveService.GetFile(ctx, fileID, fields)
+}
+
+func (f *filteredCitationDriveService) DownloadFile(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	if err := f.filter.CheckFile(ctx, f.lookup, fileID); err != nil {
+		return nil, err
+	}
+	return f.CitationDriveService.DownloadFile(ctx, fileID)
+}
+
+func (f *filteredCitationDriveService) ExportFile(ctx context.Context, fileID string, mimeType string) (io.ReadCloser, error) {
+	if err := f.filter.CheckFile(ctx, f.lookup, fileID); err != nil {
+		return nil, err
+	}
+	return f.CitationDriveService.ExportFile(ctx, fileID, mimeType)
+}
+
+func (f *filteredCitationDriveService) AddParentFolder(ctx context.Context, fileID string, parentFolderID string) (*drive.File, error) {
+	if err := f.filter.CheckFile(ctx, f.lookup, parentFolderID); err != nil {
+		return nil, err
+	}
+	return f.CitationDriveService.AddParentFolder(ctx, fileID, parentFolderID)
+}
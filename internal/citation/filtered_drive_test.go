@@ -0,0 +1,58 @@
+package citation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/config"
+	"google.golang.org/api/drive/v3"
+)
+
+// citationDriveLookup answers the drive access filter from a mockCitationDrive.
+type citationDriveLookup struct {
+	files map[string]*drive.File
+}
+
+func (l *citationDriveLookup) ListDrives(context.Context) ([]common.DriveInfo, error) {
+	return []common.DriveInfo{{ID: "drive-sensitive", Name: "SENSITIVE"}}, nil
+}
+
+func (l *citationDriveLookup) GetFileLocation(_ context.Context, fileID string) (*common.FileLocation, error) {
+	f := l.files[fileID]
+	if f == nil {
+		return &common.FileLocation{ID: fileID}, nil
+	}
+	return &common.FileLocation{ID: f.Id, DriveID: f.DriveId, Parents: f.Parents}, nil
+}
+
+func (l *citationDriveLookup) LookupRule(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
+func TestFilteredCitationDriveService(t *testing.T) {
+	ctx := context.Background()
+	mockDrive := &mockCitationDrive{files: map[string]*drive.File{
+		"open":   {Id: "open", Name: "open.txt", MimeType: "text/plain"},
+		"secret": {Id: "secret", Name: "secret.txt", MimeType: "text/plain", DriveId: "drive-sensitive"},
+	}}
+	filter := common.NewDriveAccessFilter(&config.DriveAccess{Blocked: []string{"SENSITIVE"}})
+	srv := newFilteredCitationDriveService(mockDrive, filter, &citationDriveLookup{files: mockDrive.files})
+
+	if _, err := srv.GetFile(ctx, "open", ""); err != nil {
+		t.Errorf("expected an allowed file to be readable: %v", err)
+	}
+	if _, err := srv.GetFile(ctx, "secret", ""); err == nil {
+		t.Error("expected GetFile on a blocked drive to be refused")
+	}
+	if _, err := srv.ExportFile(ctx, "secret", "text/plain"); err == nil {
+		t.Error("expected ExportFile on a blocked drive to be refused")
+	}
+	if _, err := srv.AddParentFolder(ctx, "open", "secret"); err == nil {
+		t.Error("expected adding to a folder in a blocked drive to be refused")
+	}
+
+	if got := newFilteredCitationDriveService(mockDrive, nil, nil); got != mockDrive {
+		t.Error("expected no wrapper without an active filter")
+	}
+}
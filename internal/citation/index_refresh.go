@@ -8,8 +8,13 @@ import (
 	"google.golang.org/api/drive/v3"
 )
 
-// RefreshIndex checks all indexed files against Drive for modifications, renames, or deletions,
-// and re-indexes any files that have changed.
+// changesPageTokenKey is the index metadata key holding the change feed position.
+const changesPageTokenKey = "changes_page_token"
+
+// RefreshIndex checks indexed files against Drive for modifications, renames, or deletions,
+// and re-indexes any files that have changed. When the index holds a change feed position
+// from an earlier refresh, only the files the feed reports as changed are checked; otherwise
+// every file is, and the feed position is recorded for next time.
 func (s *RealCitationService) RefreshIndex(ctx context.Context, indexID string) (*RefreshResult, error) {
 	store, err := s.getStore(ctx, indexID)
 	if err != nil {
@@ -24,6 +29,41 @@ func (s *RealCitationService) RefreshIndex(ctx context.Context, indexID string)
 
 	result := &RefreshResult{}
 
+	// Narrow the check to changed files when the feed can tell us which they are.
+	// The start token is taken before a full check so changes made during it are
+	// seen by the next refresh.
+	var nextToken string
+	removed := map[string]bool{}
+	meta, err := store.GetMetadata(ctx)
+	if err == nil && meta.ChangesPageToken != "" {
+		changed, gone, token, feedErr := s.changedFileIDs(ctx, meta.ChangesPageToken)
+		if feedErr == nil {
+			nextToken, removed = token, gone
+			indexed = filterIndexedFiles(indexed, func(id string) bool { return changed[id] || gone[id] })
+		}
+	}
+	if nextToken == "" {
+		if token, tokenErr := s.drive.GetStartPageToken(ctx); tokenErr == nil {
+			nextToken = token
+		}
+	}
+
+	// Files the feed reports as removed (deleted or no longer accessible) leave the index.
+	result.Checked = len(indexed)
+	remaining := make([]IndexedFile, 0, len(indexed))
+	for _, prev := range indexed {
+		if !removed[prev.FileID] {
+			remaining = append(remaining, prev)
+			continue
+		}
+		if delErr := s.removeFileFromIndex(ctx, store, prev.FileID); delErr != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("removing %s: %v", prev.FileName, delErr))
+		} else {
+			result.Removed = append(result.Removed, prev.FileName)
+		}
+	}
+	indexed = remaining
+
 	// refreshFileResult holds the outcome of checking a single indexed file.
 	type refreshFileResult struct {
 		prev    IndexedFile
@@ -31,7 +71,7 @@ func (s *RealCitationService) RefreshIndex(ctx context.Context, indexID string)
 		err     error
 	}
 
-	// Fetch current Drive metadata for all indexed files concurrently (up to 5 at a time).
+	// Fetch current Drive metadata for the files to check concurrently (up to 5 at a time).
 	fileResults := make([]refreshFileResult, len(indexed))
 	g, gCtx := errgroup.WithContext(ctx)
 	g.SetLimit(5)
@@ -81,9 +121,55 @@ func (s *RealCitationService) RefreshIndex(ctx context.Context, indexID string)
 		}
 	}
 
+	// Keep the old position after failures so the failed files are checked again.
+	if nextToken != "" && len(result.Errors) == 0 {
+		if err := store.SetMetadata(ctx, changesPageTokenKey, nextToken); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("saving change feed position: %v", err))
+		}
+	}
+
 	return result, nil
 }
 
+// changedFileIDs reads the change feed from pageToken to its end and returns the IDs of
+// changed and removed files, and the position to read from next time.
+func (s *RealCitationService) changedFileIDs(ctx context.Context, pageToken string) (changed, removed map[string]bool, next string, err error) {
+	changed, removed = map[string]bool{}, map[string]bool{}
+	for {
+		list, err := s.drive.ListChanges(ctx, pageToken)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("listing changes: %w", err)
+		}
+		for _, c := range list.Changes {
+			if c.Removed {
+				removed[c.FileId] = true
+				delete(changed, c.FileId)
+			} else {
+				changed[c.FileId] = true
+				delete(removed, c.FileId)
+			}
+		}
+		if list.NewStartPageToken != "" {
+			return changed, removed, list.NewStartPageToken, nil
+		}
+		if list.NextPageToken == "" {
+			return nil, nil, "", fmt.Errorf("change feed ended without a new start page token")
+		}
+		pageToken = list.NextPageToken
+	}
+}
+
+// filterIndexedFiles returns the files whose IDs keep accepts.
+func filterIndexedFiles(files []IndexedFile, keep func(fileID string) bool) []IndexedFile {
+	kept := make([]IndexedFile, 0, len(files))
+	for _, f := range files {
+		if keep(f.FileID) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
 // removeFileFromIndex deletes a file's chunks and its tracking record.
 func (s *RealCitationService) removeFileFromIndex(ctx context.Context, store *DualStore, fileID string) error {
 	if err := store.DeleteChunksByFileID(ctx, fileID); err != nil {
@@ -18,7 +18,7 @@ func RegisterTools(s *server.MCPServer) {
 		mcp.WithString("name", mcp.Required(), mcp.Description("Index name")),
 		mcp.WithString("folder_id", mcp.Description("Drive folder ID for source docs")),
 		common.WithAccountParam(),
-	), HandleCitationCreateIndex)
+	), common.WithDriveAccessCheck(HandleCitationCreateIndex, "folder_id"))
 
 	s.AddTool(mcp.NewTool("citation_add_documents",
 		mcp.WithDescription(experimentalPrefix+"Extract and chunk documents into a citation index."),
@@ -85,7 +85,7 @@ func RegisterTools(s *server.MCPServer) {
 	), HandleCitationFormatCitation)
 
 	s.AddTool(mcp.NewTool("citation_refresh",
-		mcp.WithDescription(experimentalPrefix+"Refresh index: detect updated/removed/renamed files and re-index."),
+		mcp.WithDescription(experimentalPrefix+"Refresh index: detect updated/removed/renamed files and re-index. After the first refresh, only files reported by Drive's change feed are checked."),
 		mcp.WithString("index_id", mcp.Required(), mcp.Description("Index name")),
 		common.WithAccountParam(),
 	), HandleCitationRefresh)
@@ -365,6 +365,8 @@ func (s *SQLiteStore) GetMetadata(_ context.Context) (*IndexInfo, error) {
 			info.SourceFolderID = value
 		case "created_at":
 			info.CreatedAt = value
+		case "changes_page_token":
+			info.ChangesPageToken = value
 		case "doc_count":
 			n, err := strconv.Atoi(value)
 			if err != nil {
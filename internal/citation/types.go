@@ -50,6 +50,8 @@ type IndexInfo struct {
 	CreatedAt      string `json:"created_at,omitempty"`
 	DocCount       int    `json:"doc_count,omitempty"`
 	ChunkCount     int    `json:"chunk_count,omitempty"`
+	// ChangesPageToken is the Drive change feed position of the last refresh.
+	ChangesPageToken string `json:"-"`
 }
 
 // RefreshResult summarizes what changed during a refresh.
@@ -58,4 +60,7 @@ type RefreshResult struct {
 	Removed []string `json:"removed,omitempty"`
 	Renamed []string `json:"renamed,omitempty"`
 	Errors  []string `json:"errors,omitempty"`
+	// Checked is how many indexed files were compared with Drive; with a
+	// saved change feed position only the files the feed reports are.
+	Checked int `json:"checked"`
 }
@@ -29,6 +29,15 @@ func ParseBoolArg(args map[string]any, key string, defaultVal bool) bool {
 	return defaultVal
 }
 
+// ParseIntArg extracts a positive integer argument (JSON numbers arrive as float64).
+// Returns defaultVal if the argument is missing, invalid, or not positive.
+func ParseIntArg(args map[string]any, key string, defaultVal int) int {
+	if val, ok := args[key].(float64); ok && val > 0 {
+		return int(val)
+	}
+	return defaultVal
+}
+
 // ParseMaxResults extracts 'max_results' argument and enforces limits.
 func ParseMaxResults(args map[string]any, defaultVal, maxLimit int64) int64 {
 	maxResults := defaultVal
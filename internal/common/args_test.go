@@ -89,6 +89,19 @@ func TestParseBoolArg(t *testing.T) {
 	}
 }
 
+func TestParseIntArg(t *testing.T) {
+	args := map[string]any{"days": float64(7), "zero": float64(0), "text": "7"}
+	if got := ParseIntArg(args, "days", 3); got != 7 {
+		t.Errorf("got %d, want 7", got)
+	}
+	if got := ParseIntArg(args, "zero", 3); got != 3 {
+		t.Errorf("got %d, want 3 (non-positive uses default)", got)
+	}
+	if got := ParseIntArg(args, "text", 3); got != 3 {
+		t.Errorf("got %d, want 3 (wrong type uses default)", got)
+	}
+}
+
 func TestParseMaxResults(t *testing.T) {
 	args := map[string]any{"max_results": float64(50)}
 	if got := ParseMaxResults(args, 10, 100); got != 50 {
@@ -13,7 +13,8 @@ import (
 type Deps struct {
 	AuthManager       *auth.Manager
 	DriveAccessFilter *DriveAccessFilter
-	CitationEnabled   bool // true when large_doc_indexing feature is on
+	CitationEnabled   bool            // true when large_doc_indexing feature is on
+	LocalDirs         *LocalDirPolicy // directories open to local file tools; nil allows any
 }
 
 // Global instance set during initialization.
@@ -2,14 +2,17 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aliwatters/gsuite-mcp/internal/config"
 	"github.com/mark3labs/mcp-go/mcp"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 )
 
@@ -19,7 +22,55 @@ type DriveInfo struct {
 	Name string
 }
 
-// DriveAccessFilter enforces allowlist/blocklist access control on shared drives.
+// FileLocation is where a file sits in Drive: its shared drive (empty for
+// My Drive) and its parent folders.
+type FileLocation struct {
+	ID      string
+	DriveID string
+	Parents []string
+}
+
+// DriveLookup is the Drive access a DriveAccessFilter needs to resolve its
+// rules and walk parent folders. An implementation acts as one account.
+type DriveLookup interface {
+	// ListDrives returns the shared drives the account can see.
+	ListDrives(ctx context.Context) ([]DriveInfo, error)
+	// GetFileLocation returns a file's location. The ID "root" is the
+	// account's My Drive.
+	GetFileLocation(ctx context.Context, fileID string) (*FileLocation, error)
+	// LookupRule returns the IDs of the files or folders a rule names: a
+	// Drive path such as "/My Drive/HR" or a file ID. It returns no IDs and
+	// no error when nothing matches.
+	LookupRule(ctx context.Context, rule string) ([]string, error)
+}
+
+// DriveLookupFactory creates a DriveLookup acting as the account client
+// authenticates.
+type DriveLookupFactory func(ctx context.Context, client *http.Client) (DriveLookup, error)
+
+const (
+	// locationCacheTTL bounds how long a folder's parents are trusted, so a
+	// moved folder is seen within a few minutes.
+	locationCacheTTL = 5 * time.Minute
+	locationCacheMax = 10000
+	// maxAncestors stops a parent walk on unexpectedly deep or cyclic trees.
+	maxAncestors = 100
+)
+
+// folderRules are the folder and file rules resolved for one account. Paths
+// such as "/My Drive/HR" name different folders for each account.
+type folderRules struct {
+	ids     map[string]bool
+	pending []string // rules whose lookup failed, retried on the next check
+}
+
+type cachedLocation struct {
+	loc     *FileLocation
+	expires time.Time
+}
+
+// DriveAccessFilter enforces allowlist/blocklist access control on shared
+// drives, and on folder subtrees named by path or folder ID.
 // It is safe for concurrent use.
 type DriveAccessFilter struct {
 	config     *config.DriveAccess
@@ -27,6 +78,12 @@ type DriveAccessFilter struct {
 	blockedIDs map[string]bool
 	resolved   bool
 	mu         sync.RWMutex
+
+	pathRules   []string // configured entries that are Drive paths
+	unresolved  []string // other entries that named no shared drive; maybe folder IDs
+	folderRules map[string]*folderRules
+	locations   map[string]cachedLocation
+	newLookup   DriveLookupFactory
 }
 
 // NewDriveAccessFilter creates a filter from configuration.
@@ -38,7 +95,53 @@ func NewDriveAccessFilter(cfg *config.DriveAccess) *DriveAccessFilter {
 	if len(cfg.Allowed) == 0 && len(cfg.Blocked) == 0 {
 		return nil
 	}
-	return &DriveAccessFilter{config: cfg}
+	f := &DriveAccessFilter{
+		config:      cfg,
+		folderRules: make(map[string]*folderRules),
+		locations:   make(map[string]cachedLocation),
+	}
+	for _, entry := range f.entries() {
+		if IsDrivePathRule(entry) {
+			f.pathRules = append(f.pathRules, normalizePathRule(entry))
+		}
+	}
+	return f
+}
+
+// IsDrivePathRule reports whether a drive_access entry is a Drive path
+// ("/My Drive/HR/**", "shared:Engineering/Specs") rather than a shared drive
+// name or ID.
+func IsDrivePathRule(entry string) bool {
+	return strings.HasPrefix(entry, "/") || strings.HasPrefix(entry, "shared:")
+}
+
+// normalizePathRule drops a trailing "/**" or "/": a path rule always covers
+// everything below it.
+func normalizePathRule(entry string) string {
+	entry = strings.TrimSuffix(entry, "/**")
+	if trimmed := strings.TrimRight(entry, "/"); trimmed != "" {
+		return trimmed
+	}
+	return entry
+}
+
+// entries returns the configured allowed or blocked entries.
+func (f *DriveAccessFilter) entries() []string {
+	if len(f.config.Allowed) > 0 {
+		return f.config.Allowed
+	}
+	return f.config.Blocked
+}
+
+// SetLookupFactory sets how CheckFileAccess looks up files for an account.
+// Without one, path rules cannot be resolved there.
+func (f *DriveAccessFilter) SetLookupFactory(factory DriveLookupFactory) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.newLookup = factory
 }
 
 // IsActive returns true if the filter has any restrictions configured.
@@ -73,15 +176,20 @@ func (f *DriveAccessFilter) ResolveDriveNames(drives []DriveInfo) {
 	resolve := func(names []string) map[string]bool {
 		ids := make(map[string]bool)
 		for _, name := range names {
+			if IsDrivePathRule(name) {
+				continue
+			}
 			lower := strings.ToLower(name)
 			if id, ok := nameToID[lower]; ok {
 				ids[id] = true
 			} else if idSet[name] {
 				// Config value is already an ID
 				ids[name] = true
+			} else {
+				// Either a folder ID or a drive the user doesn't have
+				// access to with this account; folder rules tell which.
+				f.unresolved = append(f.unresolved, name)
 			}
-			// Unresolved names are silently ignored — may be drives
-			// the user doesn't have access to with this account.
 		}
 		return ids
 	}
@@ -142,61 +250,264 @@ func (f *DriveAccessFilter) Check(driveID string) error {
 	return nil
 }
 
-// ensureResolved resolves drive names to IDs if not already done.
-func (f *DriveAccessFilter) ensureResolved(ctx context.Context, driveSrv *drive.Service) {
-	f.mu.RLock()
-	if f.resolved {
-		f.mu.RUnlock()
+// Resolve resolves drive names to IDs with lookup if not already done.
+func (f *DriveAccessFilter) Resolve(ctx context.Context, lookup DriveLookup) {
+	if f == nil || !f.IsActive() || f.IsResolved() {
 		return
 	}
+	drives, err := lookup.ListDrives(ctx)
+	if err != nil {
+		return // fail open
+	}
+	f.ResolveDriveNames(drives)
+}
+
+// HasFolderRules reports whether any configured entry may name a folder or
+// file rather than a shared drive. Only then do checks walk parent folders.
+func (f *DriveAccessFilter) HasFolderRules() bool {
+	if f == nil || !f.IsActive() {
+		return false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.pathRules) > 0 || len(f.unresolved) > 0
+}
+
+// CheckFile returns an error if the file, or a folder above it, is not
+// permitted. Fails open when the file cannot be looked up.
+func (f *DriveAccessFilter) CheckFile(ctx context.Context, lookup DriveLookup, fileID string) error {
+	if f == nil || !f.IsActive() {
+		return nil
+	}
+	f.Resolve(ctx, lookup)
+	loc, err := lookup.GetFileLocation(ctx, fileID)
+	if err != nil {
+		return nil // fail open
+	}
+	return f.CheckLocation(ctx, lookup, loc)
+}
+
+// CheckLocation is CheckFile for a file whose location is already known,
+// such as a search result.
+//
+// With folder rules, an allowlist admits files in an allowed shared drive or
+// below an allowed folder; My Drive is no longer allowed as a whole. A
+// blocklist refuses files in a blocked shared drive or below a blocked folder.
+func (f *DriveAccessFilter) CheckLocation(ctx context.Context, lookup DriveLookup, loc *FileLocation) error {
+	if f == nil || !f.IsActive() {
+		return nil
+	}
+	f.Resolve(ctx, lookup)
+	if !f.HasFolderRules() {
+		return f.Check(loc.DriveID)
+	}
+	rules, ok := f.rulesFor(ctx, lookup)
+	f.mu.RLock()
+	pathRules := len(f.pathRules)
 	f.mu.RUnlock()
+	if !ok || (len(rules) == 0 && pathRules == 0) {
+		// Only drive rules after all, or the account is unknown: fail open
+		// to the drive check.
+		return f.Check(loc.DriveID)
+	}
 
-	var allDrives []DriveInfo
-	pageToken := ""
-	for {
-		call := driveSrv.Drives.List().PageSize(100).Context(ctx)
-		if pageToken != "" {
-			call = call.PageToken(pageToken)
+	if len(f.config.Allowed) > 0 {
+		if loc.DriveID != "" && f.Check(loc.DriveID) == nil {
+			return nil
 		}
-		result, err := call.Do()
+		for _, id := range f.ancestors(ctx, lookup, loc) {
+			if rules[id] {
+				return nil
+			}
+		}
+		return fmt.Errorf("access denied: file is outside the allowed drives and folders; check drive_access.allowed in config.json")
+	}
+
+	if err := f.Check(loc.DriveID); err != nil {
+		return err
+	}
+	for _, id := range f.ancestors(ctx, lookup, loc) {
+		if rules[id] {
+			return fmt.Errorf("access denied: file is in a blocked folder; check drive_access.blocked in config.json")
+		}
+	}
+	return nil
+}
+
+// rulesFor returns the IDs the folder rules name for the account lookup acts
+// as, resolving them on first use. It reports false when the account's My
+// Drive cannot be found.
+func (f *DriveAccessFilter) rulesFor(ctx context.Context, lookup DriveLookup) (map[string]bool, bool) {
+	root, err := lookup.GetFileLocation(ctx, "root")
+	if err != nil || root.ID == "" {
+		return nil, false
+	}
+
+	f.mu.Lock()
+	rules, ok := f.folderRules[root.ID]
+	if !ok {
+		rules = &folderRules{ids: make(map[string]bool)}
+		rules.pending = append(append(rules.pending, f.pathRules...), f.unresolved...)
+		f.folderRules[root.ID] = rules
+	}
+	pending := rules.pending
+	rules.pending = nil
+	f.mu.Unlock()
+
+	found := make(map[string]bool)
+	var failed []string
+	for _, rule := range pending {
+		ids, err := lookup.LookupRule(ctx, rule)
 		if err != nil {
-			return // fail open
+			failed = append(failed, rule)
+			continue
 		}
-		for _, d := range result.Drives {
-			allDrives = append(allDrives, DriveInfo{ID: d.Id, Name: d.Name})
+		for _, id := range ids {
+			found[id] = true
 		}
-		if result.NextPageToken == "" {
-			break
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id := range found {
+		rules.ids[id] = true
+	}
+	rules.pending = append(rules.pending, failed...)
+	ids := make(map[string]bool, len(rules.ids))
+	for id := range rules.ids {
+		ids[id] = true
+	}
+	return ids, true
+}
+
+// ancestors returns the file's ID followed by the IDs of every folder above
+// it that the account can see. Folder locations are cached for a few minutes.
+func (f *DriveAccessFilter) ancestors(ctx context.Context, lookup DriveLookup, loc *FileLocation) []string {
+	ids := []string{loc.ID}
+	seen := map[string]bool{loc.ID: true}
+	queue := append([]string(nil), loc.Parents...)
+	for len(queue) > 0 && len(ids) < maxAncestors {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
 		}
-		pageToken = result.NextPageToken
+		seen[id] = true
+		ids = append(ids, id)
+
+		parent := f.cachedLocation(id)
+		if parent == nil {
+			var err error
+			if parent, err = lookup.GetFileLocation(ctx, id); err != nil {
+				continue // a folder the account cannot see ends this branch
+			}
+			f.cacheLocation(id, parent)
+		}
+		queue = append(queue, parent.Parents...)
+	}
+	return ids
+}
+
+func (f *DriveAccessFilter) cachedLocation(id string) *FileLocation {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if c, ok := f.locations[id]; ok && time.Now().Before(c.expires) {
+		return c.loc
 	}
+	return nil
+}
 
-	f.ResolveDriveNames(allDrives)
+func (f *DriveAccessFilter) cacheLocation(id string, loc *FileLocation) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.locations) >= locationCacheMax {
+		f.locations = make(map[string]cachedLocation)
+	}
+	f.locations[id] = cachedLocation{loc: loc, expires: time.Now().Add(locationCacheTTL)}
 }
 
-// CheckFileAccess checks whether a file is in an allowed drive using the Drive API.
-// Fails open on API errors (does not block if check cannot be performed).
+// CheckFileAccess checks whether a file is in an allowed drive and folder
+// using the Drive API. Fails open on API errors (does not block if check
+// cannot be performed).
 func (f *DriveAccessFilter) CheckFileAccess(ctx context.Context, client *http.Client, fileID string) error {
 	if f == nil || !f.IsActive() {
 		return nil
 	}
+	lookup, err := f.NewLookup(ctx, client)
+	if err != nil {
+		return nil // fail open
+	}
+	return f.CheckFile(ctx, lookup, fileID)
+}
 
+// NewLookup returns a DriveLookup acting as the account client
+// authenticates, from the factory set with SetLookupFactory.
+func (f *DriveAccessFilter) NewLookup(ctx context.Context, client *http.Client) (DriveLookup, error) {
+	f.mu.RLock()
+	factory := f.newLookup
+	f.mu.RUnlock()
+	if factory != nil {
+		return factory(ctx, client)
+	}
 	driveSrv, err := drive.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
-		return nil // fail open
+		return nil, err
 	}
+	return &apiDriveLookup{srv: driveSrv}, nil
+}
 
-	f.ensureResolved(ctx, driveSrv)
+// apiDriveLookup is the DriveLookup used without a factory. It resolves
+// shared drives and folder IDs but not paths.
+type apiDriveLookup struct {
+	srv *drive.Service
+}
+
+func (l *apiDriveLookup) ListDrives(ctx context.Context) ([]DriveInfo, error) {
+	var drives []DriveInfo
+	pageToken := ""
+	for {
+		call := l.srv.Drives.List().PageSize(100).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		result, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range result.Drives {
+			drives = append(drives, DriveInfo{ID: d.Id, Name: d.Name})
+		}
+		if result.NextPageToken == "" {
+			return drives, nil
+		}
+		pageToken = result.NextPageToken
+	}
+}
 
-	file, err := driveSrv.Files.Get(fileID).
-		Fields("driveId").
+func (l *apiDriveLookup) GetFileLocation(ctx context.Context, fileID string) (*FileLocation, error) {
+	file, err := l.srv.Files.Get(fileID).
+		Fields("id,driveId,parents").
 		SupportsAllDrives(true).
 		Context(ctx).Do()
 	if err != nil {
-		return nil // fail open
+		return nil, err
 	}
+	return &FileLocation{ID: file.Id, DriveID: file.DriveId, Parents: file.Parents}, nil
+}
 
-	return f.Check(file.DriveId)
+func (l *apiDriveLookup) LookupRule(ctx context.Context, rule string) ([]string, error) {
+	if IsDrivePathRule(rule) {
+		return nil, fmt.Errorf("cannot resolve path %q without a path lookup", rule)
+	}
+	loc, err := l.GetFileLocation(ctx, rule)
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []string{loc.ID}, nil
 }
 
 // WithDriveAccessCheck wraps an MCP handler to check drive access before execution.
@@ -242,3 +553,36 @@ func WithDriveAccessCheck(handler func(context.Context, mcp.CallToolRequest) (*m
 		return handler(ctx, request)
 	}
 }
+
+// WithDriveRootCreateCheck wraps the handler of a tool that creates a file in
+// My Drive's root, such as docs_create, and refuses it when folder rules do
+// not allow the root. Pass explicit appDeps as for WithDriveAccessCheck.
+func WithDriveRootCreateCheck(handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), appDeps ...*Deps) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var injected *Deps
+	if len(appDeps) > 0 {
+		injected = appDeps[0]
+	}
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		d := injected
+		if d == nil {
+			d = GetDeps()
+		}
+		if d == nil || !d.DriveAccessFilter.HasFolderRules() {
+			return handler(ctx, request)
+		}
+
+		email, err := ResolveAccountFromRequest(request)
+		if err != nil {
+			return handler(ctx, request)
+		}
+		client, err := d.AuthManager.GetClientOrAuthenticate(ctx, email, false)
+		if err != nil {
+			return handler(ctx, request)
+		}
+
+		if err := d.DriveAccessFilter.CheckFileAccess(ctx, client, "root"); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return handler(ctx, request)
+	}
+}
@@ -1,6 +1,8 @@
 package common
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/aliwatters/gsuite-mcp/internal/config"
@@ -136,3 +138,149 @@ func TestDriveAccessFilter_NilSafe(t *testing.T) {
 		t.Errorf("nil filter should always allow: %v", err)
 	}
 }
+
+// fakeDriveLookup serves a fixed tree of files. Rules resolve through the
+// paths map or, for IDs, through files.
+type fakeDriveLookup struct {
+	drives  []DriveInfo
+	files   map[string]*FileLocation
+	paths   map[string][]string
+	fail    map[string]bool // rules whose lookup errors
+	lookups int
+}
+
+func (l *fakeDriveLookup) ListDrives(context.Context) ([]DriveInfo, error) {
+	return l.drives, nil
+}
+
+func (l *fakeDriveLookup) GetFileLocation(_ context.Context, fileID string) (*FileLocation, error) {
+	l.lookups++
+	if loc, ok := l.files[fileID]; ok {
+		return loc, nil
+	}
+	return nil, fmt.Errorf("file %s not found", fileID)
+}
+
+func (l *fakeDriveLookup) LookupRule(_ context.Context, rule string) ([]string, error) {
+	if l.fail[rule] {
+		return nil, fmt.Errorf("lookup of %s failed", rule)
+	}
+	if IsDrivePathRule(rule) {
+		return l.paths[rule], nil
+	}
+	if _, ok := l.files[rule]; ok {
+		return []string{rule}, nil
+	}
+	return nil, nil
+}
+
+// newFakeDriveLookup builds My Drive/{Workspace/draft.doc, HR/salaries.xlsx,
+// notes.txt} and a Marketing shared drive holding plan.doc.
+func newFakeDriveLookup() *fakeDriveLookup {
+	return &fakeDriveLookup{
+		drives: []DriveInfo{{ID: "drive-marketing", Name: "Marketing"}},
+		files: map[string]*FileLocation{
+			"root":            {ID: "my-root"},
+			"my-root":         {ID: "my-root"},
+			"workspace":       {ID: "workspace", Parents: []string{"my-root"}},
+			"draft":           {ID: "draft", Parents: []string{"workspace"}},
+			"hr":              {ID: "hr", Parents: []string{"my-root"}},
+			"salaries":        {ID: "salaries", Parents: []string{"hr"}},
+			"notes":           {ID: "notes", Parents: []string{"my-root"}},
+			"drive-marketing": {ID: "drive-marketing", DriveID: "drive-marketing"},
+			"plan":            {ID: "plan", DriveID: "drive-marketing", Parents: []string{"drive-marketing"}},
+		},
+		paths: map[string][]string{
+			"/My Drive/Agent Workspace": {"workspace"},
+			"/My Drive/HR":              {"hr"},
+		},
+		fail: map[string]bool{},
+	}
+}
+
+func TestNormalizePathRule(t *testing.T) {
+	for in, want := range map[string]string{
+		"/My Drive/HR/**":  "/My Drive/HR",
+		"/My Drive/HR/":    "/My Drive/HR",
+		"/":                "/",
+		"shared:Eng/Specs": "shared:Eng/Specs",
+	} {
+		if got := normalizePathRule(in); got != want {
+			t.Errorf("normalizePathRule(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDriveAccessFilter_CheckFile_FolderAllowlist(t *testing.T) {
+	f := NewDriveAccessFilter(&config.DriveAccess{Allowed: []string{"/My Drive/Agent Workspace/**", "Marketing"}})
+	lookup := newFakeDriveLookup()
+	ctx := context.Background()
+
+	for _, id := range []string{"draft", "workspace", "plan"} {
+		if err := f.CheckFile(ctx, lookup, id); err != nil {
+			t.Errorf("expected %s to be allowed: %v", id, err)
+		}
+	}
+	for _, id := range []string{"notes", "salaries", "my-root"} {
+		if err := f.CheckFile(ctx, lookup, id); err == nil {
+			t.Errorf("expected %s outside the allowed folder to be refused", id)
+		}
+	}
+	if err := f.CheckFile(ctx, lookup, "missing"); err != nil {
+		t.Errorf("expected an unknown file to fail open: %v", err)
+	}
+}
+
+func TestDriveAccessFilter_CheckFile_FolderBlocklist(t *testing.T) {
+	f := NewDriveAccessFilter(&config.DriveAccess{Blocked: []string{"/My Drive/HR/**", "workspace"}})
+	lookup := newFakeDriveLookup()
+	ctx := context.Background()
+
+	for _, id := range []string{"hr", "salaries", "draft"} {
+		if err := f.CheckFile(ctx, lookup, id); err == nil {
+			t.Errorf("expected %s to be refused", id)
+		}
+	}
+	for _, id := range []string{"notes", "plan"} {
+		if err := f.CheckFile(ctx, lookup, id); err != nil {
+			t.Errorf("expected %s to be allowed: %v", id, err)
+		}
+	}
+
+	// Folder locations are cached: checking again looks up only the file.
+	before := lookup.lookups
+	if err := f.CheckFile(ctx, lookup, "salaries"); err == nil {
+		t.Error("expected salaries to stay refused")
+	}
+	if got := lookup.lookups - before; got != 2 { // the file and My Drive
+		t.Errorf("expected 2 lookups with a warm cache, got %d", got)
+	}
+}
+
+func TestDriveAccessFilter_CheckFile_RetriesFailedRules(t *testing.T) {
+	f := NewDriveAccessFilter(&config.DriveAccess{Blocked: []string{"/My Drive/HR"}})
+	lookup := newFakeDriveLookup()
+	lookup.fail["/My Drive/HR"] = true
+	ctx := context.Background()
+
+	if err := f.CheckFile(ctx, lookup, "salaries"); err != nil {
+		t.Errorf("expected an unresolvable rule to fail open: %v", err)
+	}
+	delete(lookup.fail, "/My Drive/HR")
+	if err := f.CheckFile(ctx, lookup, "salaries"); err == nil {
+		t.Error("expected the rule to apply once it resolves")
+	}
+}
+
+func TestDriveAccessFilter_CheckFile_DriveRulesOnly(t *testing.T) {
+	f := NewDriveAccessFilter(&config.DriveAccess{Allowed: []string{"Marketing"}})
+	lookup := newFakeDriveLookup()
+	ctx := context.Background()
+
+	if f.CheckFile(ctx, lookup, "notes") != nil || f.CheckFile(ctx, lookup, "plan") != nil {
+		t.Error("expected My Drive and the allowed drive to be allowed")
+	}
+	if f.HasFolderRules() {
+		t.Error("expected drive names alone not to make folder rules")
+	}
+}
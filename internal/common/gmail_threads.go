@@ -0,0 +1,18 @@
+package common
+
+import (
+	"context"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// GmailThreadReader is the read-only view of Gmail threads shared with
+// packages the gmail package imports (such as calendar), so they can read
+// mail without an import cycle. gmail.GmailService satisfies it.
+type GmailThreadReader interface {
+	// ListThreads lists threads matching a Gmail search query.
+	ListThreads(ctx context.Context, query string, maxResults int64) (*gmail.ListThreadsResponse, error)
+
+	// GetThread retrieves a thread in the given format ("metadata", "full", ...).
+	GetThread(ctx context.Context, threadID, format string) (*gmail.Thread, error)
+}
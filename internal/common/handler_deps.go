@@ -36,6 +36,23 @@ func getServiceWithDeps[S any](ctx context.Context, request mcp.CallToolRequest,
 	return deps.ServiceFactory.CreateService(ctx, email)
 }
 
+// ResolveAccountEmail returns the account email a request resolves to, or ""
+// if it cannot be resolved. It suits tools that only use the address as a
+// hint, such as web links or matching the user among attendees.
+func ResolveAccountEmail[S any](request mcp.CallToolRequest, deps *HandlerDeps[S], defaultDeps *HandlerDeps[S]) string {
+	if deps == nil {
+		deps = defaultDeps
+	}
+	if deps == nil || deps.EmailResolver == nil {
+		return ""
+	}
+	email, err := deps.EmailResolver(request)
+	if err != nil {
+		return ""
+	}
+	return email
+}
+
 // MarshalToolResult marshals the result to JSON and returns an MCP tool result.
 func MarshalToolResult(result any) (*mcp.CallToolResult, error) {
 	jsonData, err := json.Marshal(result)
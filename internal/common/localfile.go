@@ -0,0 +1,160 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandUserPath expands a leading "~" or "~/" to the user's home directory.
+func ExpandUserPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("resolve home directory: %w", err)
+		}
+		if path == "~" {
+			return home, nil
+		}
+		return filepath.Join(home, strings.TrimPrefix(path, "~/")), nil
+	}
+	return path, nil
+}
+
+// ResolveLocalPath expands "~", rejects NUL bytes and returns a clean absolute path.
+func ResolveLocalPath(path string) (string, error) {
+	expanded, err := ExpandUserPath(path)
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsRune(expanded, 0) {
+		return "", fmt.Errorf("path contains an invalid NUL byte")
+	}
+	if expanded == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	if !filepath.IsAbs(expanded) {
+		expanded, err = filepath.Abs(expanded)
+		if err != nil {
+			return "", fmt.Errorf("resolve path: %w", err)
+		}
+	}
+	return filepath.Clean(expanded), nil
+}
+
+// LocalDirPolicy restricts local file access to a set of directories and
+// their subdirectories. A nil policy allows any path.
+type LocalDirPolicy struct {
+	dirs []string
+}
+
+// NewLocalDirPolicy builds a policy from directory paths, expanding "~" and
+// resolving symlinks. It returns nil when dirs is empty.
+func NewLocalDirPolicy(dirs []string) (*LocalDirPolicy, error) {
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+	p := &LocalDirPolicy{dirs: make([]string, 0, len(dirs))}
+	for _, dir := range dirs {
+		resolved, err := ResolveLocalPath(strings.TrimSpace(dir))
+		if err != nil {
+			return nil, fmt.Errorf("allowed directory %q: %w", dir, err)
+		}
+		p.dirs = append(p.dirs, resolveExistingPrefix(resolved))
+	}
+	return p, nil
+}
+
+// Dirs returns the allowed directories.
+func (p *LocalDirPolicy) Dirs() []string {
+	if p == nil {
+		return nil
+	}
+	return p.dirs
+}
+
+// Check returns an error unless path, as returned by ResolveLocalPath, lies
+// within an allowed directory. Symlinks in the existing part of the path are
+// resolved first, so a link cannot lead outside the allowed directories.
+func (p *LocalDirPolicy) Check(path string) error {
+	if p == nil {
+		return nil
+	}
+	real := resolveExistingPrefix(path)
+	for _, dir := range p.dirs {
+		if rel, err := filepath.Rel(dir, real); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("path %q is outside the allowed directories (%s)", path, strings.Join(p.dirs, ", "))
+}
+
+// ResolveAllowedLocalPath resolves path with ResolveLocalPath and checks it
+// against the allowed directories. A nil policy allows any path.
+func ResolveAllowedLocalPath(path string, dirs *LocalDirPolicy) (string, error) {
+	resolved, err := ResolveLocalPath(path)
+	if err != nil {
+		return "", err
+	}
+	if err := dirs.Check(resolved); err != nil {
+		return "", err
+	}
+	return resolved, nil
+}
+
+// resolveExistingPrefix resolves symlinks in the longest existing prefix of
+// an absolute path and appends the remaining, not yet created, elements.
+func resolveExistingPrefix(path string) string {
+	var missing []string
+	for cur := path; ; {
+		if real, err := filepath.EvalSymlinks(cur); err == nil {
+			return filepath.Join(append([]string{real}, missing...)...)
+		}
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return path
+		}
+		missing = append([]string{filepath.Base(cur)}, missing...)
+		cur = parent
+	}
+}
+
+// WriteLocalFile writes data to path with owner-only permissions, creating
+// parent directories as needed. An existing file is only replaced when
+// overwrite is true.
+func WriteLocalFile(path string, data []byte, overwrite bool) error {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return fmt.Errorf("output path %q is a directory", path)
+	} else if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("check output path %q: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if overwrite {
+		flags |= os.O_TRUNC
+	} else {
+		flags |= os.O_EXCL
+	}
+
+	file, err := os.OpenFile(path, flags, 0o600)
+	if os.IsExist(err) {
+		return fmt.Errorf("output file %q already exists; pass overwrite=true to replace it", path)
+	}
+	if err != nil {
+		return fmt.Errorf("open output file %q: %w", path, err)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("write output file %q: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close output file %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalDirPolicy(t *testing.T) {
+	root := t.TempDir()
+	allowed := filepath.Join(root, "allowed")
+	outside := filepath.Join(root, "outside")
+	for _, dir := range []string{allowed, outside} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(allowed, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := NewLocalDirPolicy([]string{allowed})
+	if err != nil {
+		t.Fatalf("NewLocalDirPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		allowed bool
+	}{
+		{"directory itself", allowed, true},
+		{"new file inside", filepath.Join(allowed, "sub", "new.bin"), true},
+		{"sibling with common prefix", allowed + "-other/file", false},
+		{"outside", filepath.Join(outside, "file"), false},
+		{"parent traversal", filepath.Clean(filepath.Join(allowed, "..", "outside", "file")), false},
+		{"symlink leading outside", filepath.Join(allowed, "escape", "file"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.path)
+			if tt.allowed && err != nil {
+				t.Errorf("Check(%q) = %v, want nil", tt.path, err)
+			}
+			if !tt.allowed && (err == nil || !strings.Contains(err.Error(), "outside the allowed directories")) {
+				t.Errorf("Check(%q) = %v, want outside error", tt.path, err)
+			}
+		})
+	}
+}
+
+func TestLocalDirPolicy_Nil(t *testing.T) {
+	policy, err := NewLocalDirPolicy(nil)
+	if err != nil || policy != nil {
+		t.Fatalf("NewLocalDirPolicy(nil) = %v, %v; want nil, nil", policy, err)
+	}
+	if err := policy.Check("/anywhere/at/all"); err != nil {
+		t.Errorf("nil policy should allow any path, got %v", err)
+	}
+}
@@ -22,12 +22,14 @@ const configFileMode = 0600
 
 var accountEmailPattern = regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9](?:[A-Za-z0-9\-]{0,61}[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9\-]{0,61}[A-Za-z0-9])?)+$`)
 
-// DriveAccess configures which shared drives are accessible via MCP tools.
-// Set either Allowed (allowlist) or Blocked (blocklist), not both.
-// My Drive is always accessible. If neither is set, all drives are accessible.
+// DriveAccess configures which shared drives and folders are accessible via
+// MCP tools. Set either Allowed (allowlist) or Blocked (blocklist), not both.
+// Entries are shared drive names or IDs, folder IDs, or Drive paths such as
+// "/My Drive/HR/**". My Drive is accessible unless a folder is allowlisted.
+// If neither is set, all drives are accessible.
 type DriveAccess struct {
-	Allowed []string `json:"allowed,omitempty"` // Only these shared drives + My Drive
-	Blocked []string `json:"blocked,omitempty"` // Everything except these shared drives
+	Allowed []string `json:"allowed,omitempty"` // Only these drives and folders (+ My Drive if no folders)
+	Blocked []string `json:"blocked,omitempty"` // Everything except these drives and folders
 }
 
 // CitationIndex maps an index name to its backing Sheet ID.
@@ -40,6 +42,12 @@ type CitationConfig struct {
 	Indexes map[string]CitationIndex `json:"indexes,omitempty"`
 }
 
+// LocalFiles restricts where tools may read and write local files.
+// If AllowedDirs is empty, any path is accessible.
+type LocalFiles struct {
+	AllowedDirs []string `json:"allowed_dirs,omitempty"` // Directories (and their subdirectories) open to local file tools
+}
+
 // Features holds feature flags.
 type Features struct {
 	LargeDocIndexing bool `json:"large_doc_indexing,omitempty"`
@@ -51,6 +59,7 @@ type Config struct {
 	DriveAccess *DriveAccess    `json:"drive_access,omitempty"`
 	Features    *Features       `json:"features,omitempty"`
 	Citation    *CitationConfig `json:"citation,omitempty"`
+	LocalFiles  *LocalFiles     `json:"local_files,omitempty"`
 }
 
 // Validate checks the configuration for errors.
@@ -58,6 +67,13 @@ func (c Config) Validate() error {
 	if c.DriveAccess != nil && len(c.DriveAccess.Allowed) > 0 && len(c.DriveAccess.Blocked) > 0 {
 		return fmt.Errorf("drive_access: cannot set both 'allowed' and 'blocked' — choose one mode")
 	}
+	if c.LocalFiles != nil {
+		for _, dir := range c.LocalFiles.AllowedDirs {
+			if strings.TrimSpace(dir) == "" {
+				return fmt.Errorf("local_files: allowed_dirs cannot contain an empty path")
+			}
+		}
+	}
 	return nil
 }
 
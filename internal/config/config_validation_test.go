@@ -51,3 +51,17 @@ func TestConfig_Validate_EmptyDriveAccess(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestConfig_Validate_LocalFilesEmptyDir(t *testing.T) {
+	cfg := Config{LocalFiles: &LocalFiles{AllowedDirs: []string{"~/Downloads", " "}}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for an empty allowed directory")
+	}
+}
+
+func TestConfig_Validate_LocalFiles(t *testing.T) {
+	cfg := Config{LocalFiles: &LocalFiles{AllowedDirs: []string{"~/Downloads", "/srv/shared"}}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -21,7 +21,7 @@ func registerDocsCoreTools(s *server.MCPServer) {
 		mcp.WithDescription("Create a new Google Doc with the given title."),
 		mcp.WithString("title", mcp.Required(), mcp.Description("Document title")),
 		common.WithAccountParam(),
-	), HandleDocsCreate)
+	), common.WithDriveRootCreateCheck(HandleDocsCreate))
 
 	// docs_get - Get document content
 	s.AddTool(mcp.NewTool("docs_get",
@@ -270,7 +270,7 @@ func registerDocsEnhancedTools(s *server.MCPServer) {
 		mcp.WithString("content_type", mcp.Description("MIME type of content: text/plain (default), text/html, text/markdown")),
 		mcp.WithString("parent_id", mcp.Description("Parent folder ID or URL to place the new document in")),
 		common.WithAccountParam(),
-	), HandleDocsImportToGoogleDoc)
+	), common.WithDriveAccessCheck(HandleDocsImportToGoogleDoc, "parent_id"))
 
 }
 
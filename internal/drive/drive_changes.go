@@ -0,0 +1,234 @@
+package drive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/drive/v3"
+)
+
+// DriveChangeListFields contains fields for change feed responses.
+const DriveChangeListFields = "nextPageToken,newStartPageToken,changes(changeType,removed,fileId,time,driveId,file(id,name,mimeType,size,createdTime,modifiedTime,parents,driveId,trashed,webViewLink))"
+
+// changeTokenPath returns the file holding saved change feed positions. It
+// is resolved on use so --config-dir applies; tests replace it.
+var changeTokenPath = func() string {
+	return filepath.Join(config.DefaultConfigDir(), "drive_change_tokens.json")
+}
+
+// changeTokenMu serializes reads and writes of the token file.
+var changeTokenMu sync.Mutex
+
+// changeToken is a saved position in the change feed.
+type changeToken struct {
+	PageToken string    `json:"page_token"`
+	Since     time.Time `json:"since"` // when the feed was last read to its end
+}
+
+// changeTokenKey identifies a feed position by account and scope, so
+// watchers of different folders do not consume each other's changes.
+func changeTokenKey(account, driveID, folderID string) string {
+	return strings.Join([]string{account, driveID, folderID}, "|")
+}
+
+func loadChangeTokens() map[string]changeToken {
+	tokens := map[string]changeToken{}
+	if data, err := os.ReadFile(changeTokenPath()); err == nil {
+		_ = json.Unmarshal(data, &tokens)
+	}
+	return tokens
+}
+
+func saveChangeToken(key string, token changeToken) error {
+	changeTokenMu.Lock()
+	defer changeTokenMu.Unlock()
+	tokens := loadChangeTokens()
+	tokens[key] = token
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := changeTokenPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// changeKind classifies a change as created, modified, trashed or removed.
+// Drive does not say which; a file created after the feed was last read
+// counts as created. Without that time, a file never modified since its
+// creation does.
+func changeKind(change *drive.Change, since time.Time) string {
+	switch {
+	case change.Removed || change.File == nil:
+		return "removed"
+	case change.File.Trashed:
+		return "trashed"
+	}
+	created, err := time.Parse(time.RFC3339, change.File.CreatedTime)
+	if err != nil {
+		return "modified"
+	}
+	if since.IsZero() {
+		if change.File.CreatedTime == change.File.ModifiedTime {
+			return "created"
+		}
+		return "modified"
+	}
+	if created.After(since) {
+		return "created"
+	}
+	return "modified"
+}
+
+// TestableDriveListChanges lists files changed since the last call. The
+// feed position is saved per account and scope, so each call returns only
+// what changed after the previous one. The first call records a starting
+// point and returns no changes.
+func TestableDriveListChanges(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	driveID := common.ParseStringArg(args, "drive_id", "")
+	folderID, errResult := resolveOptionalRef(ctx, srv, args, "folder_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	explicitToken := common.ParseStringArg(args, "page_token", "")
+	maxResults := common.ParseMaxResults(args, common.DriveListDefaultMaxResults, common.DriveListMaxResultsLimit)
+
+	if folderID != "" {
+		// Accept aliases such as "root" by asking Drive for the folder's ID.
+		folder, err := srv.GetFile(ctx, folderID, "id,mimeType")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error getting folder: %v", err)), nil
+		}
+		if folder.MimeType != folderMimeType {
+			return mcp.NewToolResultError(fmt.Sprintf("%s is not a folder", folderID)), nil
+		}
+		folderID = folder.Id
+	}
+
+	key := changeTokenKey(common.ResolveAccountEmail(request, deps, DefaultDriveHandlerDeps), driveID, folderID)
+	changeTokenMu.Lock()
+	saved, haveSaved := loadChangeTokens()[key]
+	changeTokenMu.Unlock()
+
+	result := map[string]any{}
+	if driveID != "" {
+		result["drive_id"] = driveID
+	}
+	if folderID != "" {
+		result["folder_id"] = folderID
+	}
+
+	pageToken := explicitToken
+	if pageToken == "" {
+		pageToken = saved.PageToken
+	}
+	if common.ParseBoolArg(args, "reset", false) || pageToken == "" {
+		start, err := srv.GetStartPageToken(ctx, driveID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error getting start page token: %v", err)), nil
+		}
+		if err := saveChangeToken(key, changeToken{PageToken: start, Since: time.Now().UTC()}); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("saving change token: %v", err)), nil
+		}
+		result["changes"] = []map[string]any{}
+		result["count"] = 0
+		result["page_token"] = start
+		result["baseline"] = true
+		result["note"] = "Recorded the current position of the change feed. Call again to list changes made from now on."
+		return common.MarshalToolResult(result)
+	}
+
+	since := time.Time{}
+	if explicitToken == "" && haveSaved {
+		since = saved.Since
+	}
+
+	resolver := NewPathResolver(srv)
+	changes := make([]map[string]any, 0)
+	seen := map[string]int{} // file ID → index in changes; a file's latest change wins
+	next, reachedEnd := pageToken, false
+	for int64(len(changes)) < maxResults {
+		resp, err := srv.ListChanges(ctx, next, &ListChangesOptions{
+			DriveID:  driveID,
+			PageSize: maxResults,
+			Fields:   DriveChangeListFields,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error listing changes: %v (pass reset=true to start over from now)", err)), nil
+		}
+		for _, change := range resp.Changes {
+			if change.ChangeType == "drive" {
+				continue
+			}
+			kind := changeKind(change, since)
+			if folderID != "" && kind != "removed" && !resolver.InFolder(ctx, change.File.Parents, folderID) {
+				continue
+			}
+			entry := map[string]any{
+				"file_id": change.FileId,
+				"change":  kind,
+				"time":    change.Time,
+			}
+			if change.File != nil {
+				for k, v := range formatFile(change.File) {
+					if k != "id" {
+						entry[k] = v
+					}
+				}
+				if path := resolver.ResolvePath(ctx, change.File.Parents); path != "" {
+					entry["path"] = path
+				}
+			}
+			if i, ok := seen[change.FileId]; ok {
+				changes[i] = entry
+				continue
+			}
+			seen[change.FileId] = len(changes)
+			changes = append(changes, entry)
+		}
+		if resp.NewStartPageToken != "" {
+			next, reachedEnd = resp.NewStartPageToken, true
+			break
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		next = resp.NextPageToken
+	}
+
+	if explicitToken == "" {
+		token := changeToken{PageToken: next, Since: saved.Since}
+		if reachedEnd {
+			token.Since = time.Now().UTC()
+		}
+		if err := saveChangeToken(key, token); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("saving change token: %v", err)), nil
+		}
+	}
+
+	result["changes"] = changes
+	result["count"] = len(changes)
+	result["page_token"] = next
+	result["has_more"] = !reachedEnd
+	if folderID != "" {
+		result["note"] = "Removed files have no parents to check, so they are listed for every folder."
+	}
+	return common.MarshalToolResult(result)
+}
@@ -0,0 +1,147 @@
+package drive
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// useChangeTokenFile points saved change tokens at a temp file.
+func useChangeTokenFile(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "drive_change_tokens.json")
+	prev := changeTokenPath
+	changeTokenPath = func() string { return path }
+	t.Cleanup(func() { changeTokenPath = prev })
+}
+
+// changeFeedFixtures serves a folder tree Root/Projects/Sub and a feed with
+// one page of changes after token "5".
+func changeFeedFixtures(t *testing.T) *DriveTestFixtures {
+	t.Helper()
+	fixtures := NewDriveTestFixtures()
+	folders := map[string]*drive.File{
+		"root-id":  {Id: "root-id", Name: "My Drive", MimeType: folderMimeType},
+		"projects": {Id: "projects", Name: "Projects", MimeType: folderMimeType, Parents: []string{"root-id"}},
+		"sub":      {Id: "sub", Name: "Sub", MimeType: folderMimeType, Parents: []string{"projects"}},
+		"other":    {Id: "other", Name: "Other", MimeType: folderMimeType, Parents: []string{"root-id"}},
+	}
+	fixtures.MockService.GetFileFunc = func(_ context.Context, fileID string, _ string) (*drive.File, error) {
+		return folders[fileID], nil
+	}
+	fixtures.MockService.GetStartPageTokenFunc = func(_ context.Context, _ string) (string, error) {
+		return "5", nil
+	}
+	recent := time.Now().UTC().Add(time.Minute).Format(time.RFC3339)
+	old := "2020-01-01T00:00:00Z"
+	fixtures.MockService.ListChangesFunc = func(_ context.Context, pageToken string, _ *ListChangesOptions) (*drive.ChangeList, error) {
+		switch pageToken {
+		case "5":
+			return &drive.ChangeList{NextPageToken: "6", Changes: []*drive.Change{
+				{FileId: "a", File: &drive.File{Id: "a", Name: "a.txt", CreatedTime: recent, ModifiedTime: recent, Parents: []string{"sub"}}},
+				{FileId: "b", File: &drive.File{Id: "b", Name: "b.txt", CreatedTime: old, ModifiedTime: recent, Parents: []string{"projects"}}},
+			}}, nil
+		case "6":
+			return &drive.ChangeList{NewStartPageToken: "9", Changes: []*drive.Change{
+				{FileId: "c", File: &drive.File{Id: "c", Name: "c.txt", CreatedTime: old, ModifiedTime: recent, Trashed: true, Parents: []string{"other"}}},
+				{FileId: "d", Removed: true},
+				{FileId: "b", File: &drive.File{Id: "b", Name: "b-renamed.txt", CreatedTime: old, ModifiedTime: recent, Parents: []string{"projects"}}},
+			}}, nil
+		}
+		return &drive.ChangeList{NewStartPageToken: pageToken}, nil
+	}
+	return fixtures
+}
+
+func TestDriveListChanges_BaselineThenChanges(t *testing.T) {
+	useChangeTokenFile(t)
+	fixtures := changeFeedFixtures(t)
+
+	data, errText := runDriveTransfer(t, TestableDriveListChanges, fixtures, map[string]any{})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["baseline"] != true || data["page_token"] != "5" || data["count"] != float64(0) {
+		t.Fatalf("expected a baseline, got %v", data)
+	}
+
+	data, errText = runDriveTransfer(t, TestableDriveListChanges, fixtures, map[string]any{})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["page_token"] != "9" || data["has_more"] != false {
+		t.Fatalf("expected to reach the end of the feed, got %v", data)
+	}
+	changes := data["changes"].([]any)
+	want := []struct{ id, kind, name, path string }{
+		{"a", "created", "a.txt", "My Drive/Projects/Sub"},
+		{"b", "modified", "b-renamed.txt", "My Drive/Projects"},
+		{"c", "trashed", "c.txt", "My Drive/Other"},
+		{"d", "removed", "", ""},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %v", len(want), changes)
+	}
+	for i, w := range want {
+		c := changes[i].(map[string]any)
+		name, _ := c["name"].(string)
+		path, _ := c["path"].(string)
+		if c["file_id"] != w.id || c["change"] != w.kind || name != w.name || path != w.path {
+			t.Errorf("change %d = %v, want %+v", i, c, w)
+		}
+	}
+
+	// The saved position moved to the end of the feed.
+	data, _ = runDriveTransfer(t, TestableDriveListChanges, fixtures, map[string]any{})
+	if data["count"] != float64(0) || data["page_token"] != "9" {
+		t.Errorf("expected no new changes, got %v", data)
+	}
+}
+
+func TestDriveListChanges_FolderScopeAndLimit(t *testing.T) {
+	useChangeTokenFile(t)
+	fixtures := changeFeedFixtures(t)
+	args := map[string]any{"folder_id": "projects", "max_results": float64(2)}
+
+	if _, errText := runDriveTransfer(t, TestableDriveListChanges, fixtures, args); errText != "" {
+		t.Fatal(errText)
+	}
+	data, errText := runDriveTransfer(t, TestableDriveListChanges, fixtures, args)
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["count"] != float64(2) || data["has_more"] != true || data["page_token"] != "6" {
+		t.Fatalf("expected the first page only, got %v", data)
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveListChanges, fixtures, args)
+	changes := data["changes"].([]any)
+	if len(changes) != 2 || changes[0].(map[string]any)["file_id"] != "d" || changes[1].(map[string]any)["file_id"] != "b" {
+		t.Errorf("expected the removed file and b from the second page, got %v", changes)
+	}
+
+	// Another scope keeps its own position.
+	data, _ = runDriveTransfer(t, TestableDriveListChanges, fixtures, map[string]any{"folder_id": "other"})
+	if data["baseline"] != true {
+		t.Errorf("expected a separate baseline for another folder, got %v", data)
+	}
+}
+
+func TestDriveListChanges_ExplicitTokenLeavesSavedPosition(t *testing.T) {
+	useChangeTokenFile(t)
+	fixtures := changeFeedFixtures(t)
+
+	data, errText := runDriveTransfer(t, TestableDriveListChanges, fixtures, map[string]any{"page_token": "6"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["count"] != float64(3) {
+		t.Fatalf("expected 3 changes from token 6, got %v", data)
+	}
+	if tokens := loadChangeTokens(); len(tokens) != 0 {
+		t.Errorf("explicit page_token should not save a position, got %v", tokens)
+	}
+}
@@ -74,7 +74,7 @@ func TestableDriveListComments(ctx context.Context, request mcp.CallToolRequest,
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -110,7 +110,7 @@ func TestableDriveGetComment(ctx context.Context, request mcp.CallToolRequest, d
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -140,7 +140,7 @@ func TestableDriveCreateComment(ctx context.Context, request mcp.CallToolRequest
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -172,7 +172,7 @@ func TestableDriveUpdateComment(ctx context.Context, request mcp.CallToolRequest
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -209,7 +209,7 @@ func TestableDriveDeleteComment(ctx context.Context, request mcp.CallToolRequest
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -241,7 +241,7 @@ func TestableDriveListReplies(ctx context.Context, request mcp.CallToolRequest,
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -283,7 +283,7 @@ func TestableDriveCreateReply(ctx context.Context, request mcp.CallToolRequest,
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -0,0 +1,166 @@
+package drive
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffEdits caps how many lines may differ between two texts. The diff
+// keeps one row of Myers' search per edit, so memory grows with its square.
+const maxDiffEdits = 3000
+
+// diffOp is one line of a line diff: ' ' kept, '-' removed or '+' added.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// splitLines splits text into lines, ignoring a byte order mark, carriage
+// returns and a final newline, so exports from different revisions compare
+// line by line.
+func splitLines(text string) []string {
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diffLines returns the shortest edit script turning a into b, using Myers'
+// O(ND) algorithm.
+func diffLines(a, b []string) ([]diffOp, error) {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds v[-d-1..d+1] as it was when round d started.
+	var trace [][]int
+
+	for d := 0; ; d++ {
+		if d > maxDiffEdits {
+			return nil, fmt.Errorf("more than %d lines differ; download the revisions to compare them instead", maxDiffEdits)
+		}
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackDiff(a, b, trace), nil
+			}
+		}
+	}
+}
+
+// backtrackDiff walks the saved search rows from the end of both texts back
+// to the start, collecting the edit script in reverse.
+func backtrackDiff(a, b []string, trace [][]int) []diffOp {
+	var ops []diffOp
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		row := trace[d]
+		at := func(k int) int { return row[k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{'+', b[y-1]})
+			} else {
+				ops = append(ops, diffOp{'-', a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// unifiedDiff returns a unified diff of two texts with contextLines lines of
+// context around each change, and how many lines were added and removed. The
+// diff is empty when the texts have the same lines.
+func unifiedDiff(fromName, toName, from, to string, contextLines int) (string, int, int, error) {
+	ops, err := diffLines(splitLines(from), splitLines(to))
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	// Line numbers in each text before each op.
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	var changes []int
+	added, removed := 0, 0
+	for i, op := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		switch op.kind {
+		case ' ':
+			aPos[i+1]++
+			bPos[i+1]++
+		case '-':
+			aPos[i+1]++
+			removed++
+			changes = append(changes, i)
+		case '+':
+			bPos[i+1]++
+			added++
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return "", 0, 0, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	for i := 0; i < len(changes); {
+		start := max(changes[i]-contextLines, 0)
+		end := changes[i] + 1
+		for i < len(changes) && changes[i] <= end+2*contextLines {
+			end = changes[i] + 1
+			i++
+		}
+		end = min(end+contextLines, len(ops))
+
+		aCount, bCount := aPos[end]-aPos[start], bPos[end]-bPos[start]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aPos[start], aCount), hunkRange(bPos[start], bCount))
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String(), added, removed, nil
+}
+
+// hunkRange formats the line range of a hunk. An empty range names the line
+// before it, as diff -u does.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -5,11 +5,15 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"github.com/mark3labs/mcp-go/mcp"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/drivelabels/v2"
 )
 
 // TestableDriveSearch searches files with query syntax.
@@ -19,13 +23,44 @@ func TestableDriveSearch(ctx context.Context, request mcp.CallToolRequest, deps
 		return errResult, nil
 	}
 
-	query, errResult := common.RequireStringArg(request.GetArguments(), "query")
-	if errResult != nil {
-		return errResult, nil
+	args := request.GetArguments()
+	query := common.ParseStringArg(args, "query", "")
+	labelRef := common.ParseStringArg(args, "label", "")
+	labelField := common.ParseStringArg(args, "label_field", "")
+	labelValue := common.ParseStringArg(args, "label_value", "")
+	if query == "" && labelRef == "" {
+		return mcp.NewToolResultError("query parameter is required unless label is given"), nil
+	}
+	if labelField != "" && (labelRef == "" || labelValue == "") {
+		return mcp.NewToolResultError("label_field requires label and label_value"), nil
+	}
+
+	// Restrict to files with a label, or with a label field value
+	var label *drivelabels.GoogleAppsDriveLabelsV2Label
+	if labelRef != "" {
+		var err error
+		if label, err = resolveLabel(ctx, srv, labelRef); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		var field *drivelabels.GoogleAppsDriveLabelsV2Field
+		if labelField != "" {
+			if field, err = resolveLabelField(label, labelField); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		clause, err := labelSearchClause(label, field, labelValue)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if query == "" {
+			query = clause
+		} else {
+			query = fmt.Sprintf("(%s) and %s", query, clause)
+		}
 	}
 
 	// Apply friendly file_type filter if provided
-	fileType := common.ParseStringArg(request.GetArguments(), "file_type", "")
+	fileType := common.ParseStringArg(args, "file_type", "")
 	if fileType != "" {
 		mimeType, ok := friendlyFileTypes[strings.ToLower(fileType)]
 		if !ok {
@@ -43,17 +78,22 @@ func TestableDriveSearch(ctx context.Context, request mcp.CallToolRequest, deps
 		}
 	}
 
-	maxResults := common.ParseMaxResults(request.GetArguments(), common.DriveSearchDefaultMaxResults, common.DriveSearchMaxResultsLimit)
-	pageToken := common.ParseStringArg(request.GetArguments(), "page_token", "")
-	corpora := common.ParseStringArg(request.GetArguments(), "corpora", "allDrives")
+	maxResults := common.ParseMaxResults(args, common.DriveSearchDefaultMaxResults, common.DriveSearchMaxResultsLimit)
+	pageToken := common.ParseStringArg(args, "page_token", "")
+	corpora := common.ParseStringArg(args, "corpora", "allDrives")
 
-	resp, err := srv.ListFiles(ctx, &ListFilesOptions{
+	opts := &ListFilesOptions{
 		Query:     query,
 		PageSize:  maxResults,
 		PageToken: pageToken,
 		Fields:    DriveFileListFields,
 		Corpora:   corpora,
-	})
+	}
+	if label != nil {
+		opts.Fields = DriveFileListLabelFields
+		opts.IncludeLabels = label.Id
+	}
+	resp, err := srv.ListFiles(ctx, opts)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
 	}
@@ -65,6 +105,13 @@ func TestableDriveSearch(ctx context.Context, request mcp.CallToolRequest, deps
 		if path := resolver.ResolvePath(ctx, f.Parents); path != "" {
 			fm["path"] = path
 		}
+		if label != nil && f.LabelInfo != nil {
+			labels := make([]map[string]any, 0, len(f.LabelInfo.Labels))
+			for _, l := range f.LabelInfo.Labels {
+				labels = append(labels, formatAppliedLabel(l, label))
+			}
+			fm["labels"] = labels
+		}
 		files = append(files, fm)
 	}
 
@@ -84,7 +131,7 @@ func TestableDriveGet(ctx context.Context, request mcp.CallToolRequest, deps *Dr
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -110,18 +157,22 @@ func TestableDriveDownload(ctx context.Context, request mcp.CallToolRequest, dep
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
 
+	if outputPath := common.ParseStringArg(request.GetArguments(), "output_path", ""); outputPath != "" {
+		return downloadToOutputPath(ctx, srv, request, fileID, outputPath)
+	}
+
 	file, err := srv.GetFile(ctx, fileID, DriveFileDownloadFields)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Drive API error getting file info: %v", err)), nil
 	}
 
 	if file.Size > common.DriveMaxFileSize {
-		return mcp.NewToolResultError(fmt.Sprintf("File too large (%d bytes). Maximum supported size is %d bytes", file.Size, common.DriveMaxFileSize)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("File too large (%d bytes). Maximum supported size is %d bytes; use output_path to download it to a local file", file.Size, common.DriveMaxFileSize)), nil
 	}
 
 	content, exportMimeType, dlErrResult := downloadFileContent(ctx, srv, fileID, file)
@@ -160,6 +211,13 @@ func TestableDriveUpload(ctx context.Context, request mcp.CallToolRequest, deps
 		return errResult, nil
 	}
 
+	if localPath := common.ParseStringArg(request.GetArguments(), "local_path", ""); localPath != "" {
+		if common.ParseStringArg(request.GetArguments(), "content", "") != "" {
+			return mcp.NewToolResultError("use content or local_path, not both"), nil
+		}
+		return uploadFromLocalPath(ctx, srv, request, localPath)
+	}
+
 	name, errResult := common.RequireStringArg(request.GetArguments(), "name")
 	if errResult != nil {
 		return errResult, nil
@@ -172,7 +230,7 @@ func TestableDriveUpload(ctx context.Context, request mcp.CallToolRequest, deps
 
 	// Reject obviously oversized content before decoding
 	if int64(len(contentStr)) > common.DriveMaxFileSize*2 {
-		return mcp.NewToolResultError(fmt.Sprintf("Content too large. Maximum supported size is %d bytes", common.DriveMaxFileSize)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Content too large. Maximum supported size is %d bytes; use local_path for larger files", common.DriveMaxFileSize)), nil
 	}
 
 	// Decode content if base64 encoded
@@ -189,15 +247,19 @@ func TestableDriveUpload(ctx context.Context, request mcp.CallToolRequest, deps
 	}
 
 	if int64(len(data)) > common.DriveMaxFileSize {
-		return mcp.NewToolResultError(fmt.Sprintf("Content too large (%d bytes). Maximum supported size is %d bytes", len(data), common.DriveMaxFileSize)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Content too large (%d bytes). Maximum supported size is %d bytes; use local_path for larger files", len(data), common.DriveMaxFileSize)), nil
 	}
 
 	file := &drive.File{
 		Name: name,
 	}
 
-	if parentID := common.ParseStringArg(request.GetArguments(), "parent_id", ""); parentID != "" {
-		file.Parents = []string{common.ExtractGoogleResourceID(parentID)}
+	parentID, errResult := resolveOptionalRef(ctx, srv, request.GetArguments(), "parent_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if parentID != "" {
+		file.Parents = []string{parentID}
 	}
 
 	if mimeType := common.ParseStringArg(request.GetArguments(), "mime_type", ""); mimeType != "" {
@@ -225,6 +287,105 @@ func TestableDriveUpload(ctx context.Context, request mcp.CallToolRequest, deps
 	return common.MarshalToolResult(result)
 }
 
+// downloadToOutputPath streams a file to a local path instead of returning
+// its content, so files of any size and type can be downloaded. An existing
+// directory receives the file under its Drive name.
+func downloadToOutputPath(ctx context.Context, srv DriveService, request mcp.CallToolRequest, fileID, outputPath string) (*mcp.CallToolResult, error) {
+	path, err := resolveTransferPath(outputPath)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid output_path: %v", err)), nil
+	}
+
+	file, err := srv.GetFile(ctx, fileID, DriveFileTransferFields)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error getting file info: %v", err)), nil
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, localFileName(file.Name, file.Id))
+	}
+
+	transfer, err := downloadToLocalFile(ctx, srv, file, path, common.ParseBoolArg(request.GetArguments(), "overwrite", false))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := map[string]any{
+		"file_id":   file.Id,
+		"name":      file.Name,
+		"mime_type": file.MimeType,
+		"path":      transfer.path,
+		"size":      transfer.bytes,
+	}
+	if transfer.exportedAs != "" {
+		result["exported_as"] = transfer.exportedAs
+	}
+	if transfer.resumedFrom > 0 {
+		result["resumed_from"] = transfer.resumedFrom
+	}
+	if file.Md5Checksum != "" {
+		result["md5_checksum"] = file.Md5Checksum
+	}
+	return common.MarshalToolResult(result)
+}
+
+// uploadFromLocalPath uploads a local file with a resumable upload, resuming
+// an earlier interrupted upload of the same file to the same destination.
+func uploadFromLocalPath(ctx context.Context, srv DriveService, request mcp.CallToolRequest, localPath string) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	path, err := resolveTransferPath(localPath)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid local_path: %v", err)), nil
+	}
+
+	file := &drive.File{
+		Name:        common.ParseStringArg(args, "name", filepath.Base(path)),
+		MimeType:    common.ParseStringArg(args, "mime_type", mime.TypeByExtension(filepath.Ext(path))),
+		Description: common.ParseStringArg(args, "description", ""),
+	}
+	parentID, errResult := resolveOptionalRef(ctx, srv, args, "parent_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if parentID != "" {
+		file.Parents = []string{parentID}
+	}
+
+	transfer, err := uploadLocalFile(ctx, srv, path, uploadTarget{
+		file:    file,
+		account: common.ParseStringArg(args, "account", ""),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive upload error: %v", err)), nil
+	}
+
+	created := transfer.file
+	result := map[string]any{
+		"file_id":      created.Id,
+		"name":         created.Name,
+		"mime_type":    created.MimeType,
+		"size":         transfer.bytes,
+		"created_time": created.CreatedTime,
+		"url":          created.WebViewLink,
+		"local_path":   path,
+	}
+	if created.Md5Checksum != "" {
+		result["md5_checksum"] = created.Md5Checksum
+	}
+	if transfer.resumedFrom > 0 {
+		result["resumed_from"] = transfer.resumedFrom
+	}
+	return common.MarshalToolResult(result)
+}
+
+// localFileName turns a Drive file name into a safe local file name.
+func localFileName(name, fallback string) string {
+	name = strings.Trim(strings.NewReplacer("/", "_", "\\", "_", "\x00", "_").Replace(name), ". ")
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
 // TestableDriveList lists files in a folder.
 func TestableDriveList(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
 	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
@@ -232,9 +393,12 @@ func TestableDriveList(ctx context.Context, request mcp.CallToolRequest, deps *D
 		return errResult, nil
 	}
 
-	folderID := common.ParseStringArg(request.GetArguments(), "folder_id", "root")
-	if folderID != "root" {
-		folderID = common.ExtractGoogleResourceID(folderID)
+	folderID := "root"
+	if common.ParseStringArg(request.GetArguments(), "folder_id", "") != "" || common.ParseStringArg(request.GetArguments(), "path", "") != "" {
+		folderID, errResult = resolveRequiredRef(ctx, srv, request, "folder_id")
+		if errResult != nil {
+			return errResult, nil
+		}
 	}
 
 	query := fmt.Sprintf("'%s' in parents and trashed = false", folderID)
@@ -281,9 +445,23 @@ func TestableDriveCreateFolder(ctx context.Context, request mcp.CallToolRequest,
 		return errResult, nil
 	}
 
-	name, errResult := common.RequireStringArg(request.GetArguments(), "name")
-	if errResult != nil {
-		return errResult, nil
+	args := request.GetArguments()
+	name := common.ParseStringArg(args, "name", "")
+	path := common.ParseStringArg(args, "path", "")
+	switch {
+	case path != "" && name != "":
+		return mcp.NewToolResultError("use name and parent_id, or path, not both"), nil
+	case path != "" && common.ParseBoolArg(args, "parents", false):
+		return makeFolderPath(ctx, srv, path, common.ParseStringArg(args, "description", ""))
+	case path != "":
+		dir, base, ok := splitFolderPath(path)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("%q is not a folder path such as /My Drive/Reports/2026", path)), nil
+		}
+		name = base
+		args = map[string]any{"parent_id": dir, "description": args["description"]}
+	case name == "":
+		return mcp.NewToolResultError("name or path parameter is required"), nil
 	}
 
 	folder := &drive.File{
@@ -291,11 +469,15 @@ func TestableDriveCreateFolder(ctx context.Context, request mcp.CallToolRequest,
 		MimeType: "application/vnd.google-apps.folder",
 	}
 
-	if parentID := common.ParseStringArg(request.GetArguments(), "parent_id", ""); parentID != "" {
-		folder.Parents = []string{common.ExtractGoogleResourceID(parentID)}
+	parentID, errResult := resolveOptionalRef(ctx, srv, args, "parent_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if parentID != "" {
+		folder.Parents = []string{parentID}
 	}
 
-	if description := common.ParseStringArg(request.GetArguments(), "description", ""); description != "" {
+	if description := common.ParseStringArg(args, "description", ""); description != "" {
 		folder.Description = description
 	}
 
@@ -314,6 +496,48 @@ func TestableDriveCreateFolder(ctx context.Context, request mcp.CallToolRequest,
 	return common.MarshalToolResult(result)
 }
 
+// splitFolderPath splits a folder path into its parent path and name.
+func splitFolderPath(path string) (string, string, bool) {
+	path = strings.TrimRight(path, "/")
+	i := strings.LastIndex(path, "/")
+	if !IsDrivePath(path) || i < 0 || path[i+1:] == "" {
+		return "", "", false
+	}
+	dir := path[:i]
+	if dir == "" {
+		dir = "/"
+	}
+	return dir, path[i+1:], true
+}
+
+// makeFolderPath creates the folder at path and any missing parents, like
+// mkdir -p. An existing folder at path is returned unchanged.
+func makeFolderPath(ctx context.Context, srv DriveService, path, description string) (*mcp.CallToolResult, error) {
+	folder, created, err := NewPathResolver(srv).MakeFolderPath(ctx, path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+	}
+	createdIDs := make([]string, 0, len(created))
+	for _, f := range created {
+		createdIDs = append(createdIDs, f.Id)
+	}
+	if description != "" && len(created) > 0 {
+		if _, err := srv.UpdateFile(ctx, folder.Id, &drive.File{Description: description}); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error setting description: %v", err)), nil
+		}
+	}
+
+	result := map[string]any{
+		"folder_id":       folder.Id,
+		"name":            folder.Name,
+		"path":            path,
+		"created":         len(created) > 0,
+		"created_folders": createdIDs,
+		"url":             folder.WebViewLink,
+	}
+	return common.MarshalToolResult(result)
+}
+
 // TestableDriveMove moves a file to a different folder.
 func TestableDriveMove(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
 	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
@@ -321,16 +545,18 @@ func TestableDriveMove(ctx context.Context, request mcp.CallToolRequest, deps *D
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
 
-	newParentID, errResult := common.RequireStringArg(request.GetArguments(), "new_parent_id")
+	if common.ParseStringArg(request.GetArguments(), "new_parent_id", "") == "" {
+		return mcp.NewToolResultError("new_parent_id parameter is required"), nil
+	}
+	newParentID, errResult := resolveOptionalRef(ctx, srv, request.GetArguments(), "new_parent_id")
 	if errResult != nil {
 		return errResult, nil
 	}
-	newParentID = common.ExtractGoogleResourceID(newParentID)
 
 	// Get current parents to remove
 	file, err := srv.GetFile(ctx, fileID, "id,parents")
@@ -364,7 +590,7 @@ func TestableDriveCopy(ctx context.Context, request mcp.CallToolRequest, deps *D
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -375,8 +601,12 @@ func TestableDriveCopy(ctx context.Context, request mcp.CallToolRequest, deps *D
 		copyFile.Name = name
 	}
 
-	if parentID := common.ParseStringArg(request.GetArguments(), "parent_id", ""); parentID != "" {
-		copyFile.Parents = []string{common.ExtractGoogleResourceID(parentID)}
+	parentID, errResult := resolveOptionalRef(ctx, srv, request.GetArguments(), "parent_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if parentID != "" {
+		copyFile.Parents = []string{parentID}
 	}
 
 	copied, err := srv.CopyFile(ctx, fileID, copyFile)
@@ -404,7 +634,7 @@ func TestableDriveTrash(ctx context.Context, request mcp.CallToolRequest, deps *
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -432,7 +662,7 @@ func TestableDriveDelete(ctx context.Context, request mcp.CallToolRequest, deps
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -0,0 +1,197 @@
+package drive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"google.golang.org/api/drive/v3"
+)
+
+const docMimeType = "application/vnd.google-apps.document"
+
+// folderFixtures builds Projects/{a.txt, Notes (doc), Sub/{b.txt, Deep/c.txt}}
+// under My Drive and returns the Projects folder.
+func folderFixtures(t *testing.T) (*DriveTestFixtures, *fakeDriveTree, *drive.File) {
+	t.Helper()
+	fixtures := NewDriveTestFixtures()
+	tree := newFakeDriveTree(fixtures.MockService)
+	projects := tree.add("Projects", "root", folderMimeType, nil)
+	a := tree.add("a.txt", projects.Id, "text/plain", []byte("hello"))
+	a.Owners = []*drive.User{{EmailAddress: "alice@example.com"}}
+	notes := tree.add("Notes", projects.Id, docMimeType, nil)
+	notes.Owners = []*drive.User{{EmailAddress: "bob@example.com"}}
+	sub := tree.add("Sub", projects.Id, folderMimeType, nil)
+	b := tree.add("b.txt", sub.Id, "text/plain", []byte("0123456789"))
+	b.Owners = []*drive.User{{EmailAddress: "alice@example.com"}}
+	deep := tree.add("Deep", sub.Id, folderMimeType, nil)
+	tree.add("c.txt", deep.Id, "text/plain", []byte("abc"))
+	return fixtures, tree, projects
+}
+
+// treeChild returns the child of a drive_tree node with the given name.
+func treeChild(t *testing.T, node map[string]any, name string) map[string]any {
+	t.Helper()
+	children, _ := node["children"].([]any)
+	for _, c := range children {
+		if m := c.(map[string]any); m["name"] == name {
+			return m
+		}
+	}
+	t.Fatalf("no child %q in %v", name, node)
+	return nil
+}
+
+func TestDriveTree_NestsAndTotals(t *testing.T) {
+	fixtures, _, projects := folderFixtures(t)
+
+	data, errText := runDriveTransfer(t, TestableDriveTree, fixtures, map[string]any{"folder_id": projects.Id})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	root := data["tree"].(map[string]any)
+	if root["size"] != float64(15) || root["file_count"] != float64(3) {
+		t.Errorf("expected Projects to total 15 bytes in 3 files, got %v", root)
+	}
+	if got := treeChild(t, root, "Notes")["mime_type"]; got != docMimeType {
+		t.Errorf("expected Notes to be a doc, got %v", got)
+	}
+	sub := treeChild(t, root, "Sub")
+	deep := treeChild(t, sub, "Deep")
+	if deep["not_listed"] != true || deep["children"] != nil {
+		t.Errorf("expected Deep at the depth limit to be unlisted, got %v", deep)
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveTree, fixtures, map[string]any{"folder_id": projects.Id, "depth": float64(3)})
+	deep = treeChild(t, treeChild(t, data["tree"].(map[string]any), "Sub"), "Deep")
+	if deep["not_listed"] != nil || treeChild(t, deep, "c.txt")["size"] != float64(3) {
+		t.Errorf("expected Deep to be listed at depth 3, got %v", deep)
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveTree, fixtures, map[string]any{"folder_id": projects.Id, "max_items": float64(2)})
+	if data["truncated"] != true || data["items"] != float64(2) {
+		t.Errorf("expected a truncated listing of 2 items, got %v", data)
+	}
+
+	_, errText = runDriveTransfer(t, TestableDriveTree, fixtures, map[string]any{"folder_id": treeChild(t, root, "a.txt")["id"]})
+	if errText == "" {
+		t.Error("expected an error for a file that is not a folder")
+	}
+}
+
+func TestDriveFolderStats_TotalsByTypeAndOwner(t *testing.T) {
+	fixtures, _, projects := folderFixtures(t)
+
+	data, errText := runDriveTransfer(t, TestableDriveFolderStats, fixtures, map[string]any{"folder_id": projects.Id})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["files"] != float64(4) || data["folders"] != float64(2) || data["bytes"] != float64(18) || data["levels"] != float64(3) {
+		t.Fatalf("unexpected totals: %v", data)
+	}
+
+	byType := data["by_type"].([]any)
+	first := byType[0].(map[string]any)
+	if len(byType) != 2 || first["key"] != "text/plain" || first["count"] != float64(3) || first["bytes"] != float64(18) {
+		t.Errorf("unexpected by_type: %v", byType)
+	}
+	owners := map[string]float64{}
+	for _, b := range data["by_owner"].([]any) {
+		m := b.(map[string]any)
+		owners[m["key"].(string)] = m["bytes"].(float64)
+	}
+	if owners["alice@example.com"] != 15 || owners["bob@example.com"] != 0 || owners["(shared drive)"] != 3 {
+		t.Errorf("unexpected by_owner: %v", owners)
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveFolderStats, fixtures, map[string]any{"folder_id": projects.Id, "max_depth": float64(1)})
+	if data["files"] != float64(2) || data["folders"] != float64(1) {
+		t.Errorf("expected only the top level with max_depth=1, got %v", data)
+	}
+}
+
+func TestDriveCopyFolder_CopiesStructure(t *testing.T) {
+	fixtures, tree, projects := folderFixtures(t)
+	tree.add("Link", projects.Id, shortcutMimeType, nil)
+
+	data, errText := runDriveTransfer(t, TestableDriveCopyFolder, fixtures, map[string]any{"folder_id": projects.Id})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["name"] != "Copy of Projects" || data["folders_created"] != float64(2) || data["files_copied"] != float64(4) {
+		t.Fatalf("unexpected result: %v", data)
+	}
+	if skipped := data["skipped"].([]any); len(skipped) != 1 || skipped[0].(map[string]any)["name"] != "Link" {
+		t.Errorf("expected the shortcut to be skipped, got %v", skipped)
+	}
+
+	copyID := data["folder_id"].(string)
+	if got := tree.files[copyID].Parents[0]; got != "root" {
+		t.Errorf("expected the copy next to the source, got parent %s", got)
+	}
+	c := tree.find(copyID, "Sub/Deep/c.txt")
+	if c == nil || string(tree.content[c.Id]) != "abc" {
+		t.Fatalf("expected Sub/Deep/c.txt in the copy, got %v", c)
+	}
+	if notes := tree.find(copyID, "Notes"); notes == nil || notes.MimeType != docMimeType {
+		t.Errorf("expected the Google Doc to be copied, got %v", notes)
+	}
+}
+
+func TestDriveCopyFolder_IntoItself(t *testing.T) {
+	fixtures, tree, projects := folderFixtures(t)
+
+	data, errText := runDriveTransfer(t, TestableDriveCopyFolder, fixtures, map[string]any{
+		"folder_id": projects.Id,
+		"parent_id": projects.Id,
+		"name":      "Backup",
+	})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["folders_created"] != float64(2) || data["files_copied"] != float64(4) {
+		t.Fatalf("expected the copy not to copy itself, got %v", data)
+	}
+	if tree.find(projects.Id, "Backup/Backup") != nil {
+		t.Error("expected no nested Backup folder")
+	}
+}
+
+func TestDriveFolderTools_RespectAccessFilter(t *testing.T) {
+	fixtures, tree, projects := folderFixtures(t)
+	secret := tree.add("Secret", projects.Id, folderMimeType, nil)
+	secret.DriveId = "drive-sensitive"
+	tree.add("plan.txt", secret.Id, "text/plain", []byte("classified")).DriveId = "drive-sensitive"
+
+	mock := fixtures.MockService
+	mock.ListDrivesFunc = func(context.Context, int64, string) (*drive.DriveList, error) {
+		return &drive.DriveList{}, nil
+	}
+	filtered := NewFilteredDriveService(mock, newTestFilter(nil, []string{"SENSITIVE"}))
+	fixtures.Deps.ServiceFactory = &common.MockServiceFactory[DriveService]{MockService: filtered}
+
+	data, errText := runDriveTransfer(t, TestableDriveTree, fixtures, map[string]any{"folder_id": projects.Id, "depth": float64(5)})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	for _, c := range data["tree"].(map[string]any)["children"].([]any) {
+		if c.(map[string]any)["name"] == "Secret" {
+			t.Error("expected the blocked folder to be hidden from the tree")
+		}
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveFolderStats, fixtures, map[string]any{"folder_id": projects.Id})
+	if data["bytes"] != float64(18) {
+		t.Errorf("expected blocked files to be left out of the totals, got %v", data)
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveCopyFolder, fixtures, map[string]any{"folder_id": projects.Id})
+	if copyID := data["folder_id"].(string); tree.find(copyID, "Secret") != nil {
+		t.Error("expected the blocked folder not to be copied")
+	}
+
+	_, errText = runDriveTransfer(t, TestableDriveTree, fixtures, map[string]any{"folder_id": secret.Id})
+	if errText == "" {
+		t.Error("expected an error listing a blocked folder")
+	}
+}
@@ -0,0 +1,439 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/drive/v3"
+)
+
+const (
+	// folderWalkConcurrency bounds concurrent Drive calls in recursive folder tools.
+	folderWalkConcurrency = 5
+	// folderChildFields are the fields listed for folder contents.
+	folderChildFields = "nextPageToken,files(id,name,mimeType,size,modifiedTime,owners(emailAddress),driveId)"
+	// shortcutMimeType is the MIME type of Drive shortcuts.
+	shortcutMimeType = "application/vnd.google-apps.shortcut"
+
+	treeDefaultDepth    = 2
+	treeMaxDepth        = 10
+	treeDefaultMaxItems = 500
+	treeMaxItemsLimit   = 5000
+	statsMaxItems       = 50000
+)
+
+// listFolderChildren lists every non-trashed item directly in a folder,
+// folders first, with the given list fields.
+func listFolderChildren(ctx context.Context, srv DriveService, folderID, fields string) ([]*drive.File, error) {
+	var files []*drive.File
+	pageToken := ""
+	for {
+		resp, err := srv.ListFiles(ctx, &ListFilesOptions{
+			Query:     fmt.Sprintf("'%s' in parents and trashed = false", folderID),
+			PageSize:  1000,
+			PageToken: pageToken,
+			OrderBy:   "folder,name",
+			Fields:    fields,
+			Corpora:   "allDrives",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing folder %s: %w", folderID, err)
+		}
+		files = append(files, resp.Files...)
+		if resp.NextPageToken == "" {
+			return files, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// walkFolder visits the contents of folderID breadth first, down to maxDepth
+// levels (0 for no limit). The folders of each level are listed concurrently,
+// at most folderWalkConcurrency at a time. visit runs on the calling
+// goroutine, in listing order, with depth 1 for direct children; it returns
+// whether to descend into the item if it is a folder. Items are listed with
+// fields, such as folderChildFields.
+func walkFolder(ctx context.Context, srv DriveService, folderID, fields string, maxDepth int, visit func(parentID string, depth int, f *drive.File) bool) error {
+	level := []string{folderID}
+	for depth := 1; len(level) > 0 && (maxDepth == 0 || depth <= maxDepth); depth++ {
+		children := make([][]*drive.File, len(level))
+		g, gCtx := errgroup.WithContext(ctx)
+		g.SetLimit(folderWalkConcurrency)
+		for i, id := range level {
+			g.Go(func() error {
+				files, err := listFolderChildren(gCtx, srv, id, fields)
+				children[i] = files
+				return err
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return err
+		}
+
+		var next []string
+		for i, files := range children {
+			for _, f := range files {
+				if visit(level[i], depth, f) && f.MimeType == folderMimeType {
+					next = append(next, f.Id)
+				}
+			}
+		}
+		level = next
+	}
+	return nil
+}
+
+// getFolder fetches a folder's metadata, rejecting files that are not folders.
+func getFolder(ctx context.Context, srv DriveService, folderID string) (*drive.File, *mcp.CallToolResult) {
+	folder, err := srv.GetFile(ctx, folderID, "id,name,mimeType,modifiedTime,parents,driveId,webViewLink")
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Drive API error getting folder: %v", err))
+	}
+	if folder.MimeType != folderMimeType {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("%s is not a folder (%s)", folderID, folder.MimeType))
+	}
+	return folder, nil
+}
+
+// treeNode is one item in a drive_tree result. Folder sizes and file counts
+// cover what was listed below them.
+type treeNode struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	MimeType     string      `json:"mime_type"`
+	Size         int64       `json:"size,omitempty"`
+	FileCount    int         `json:"file_count,omitempty"`
+	ModifiedTime string      `json:"modified_time,omitempty"`
+	Children     []*treeNode `json:"children,omitempty"`
+	NotListed    bool        `json:"not_listed,omitempty"` // folder below the depth limit
+}
+
+// total fills in folder sizes and file counts from their children.
+func (n *treeNode) total() {
+	for _, c := range n.Children {
+		if c.MimeType == folderMimeType {
+			c.total()
+			n.FileCount += c.FileCount
+		} else {
+			n.FileCount++
+		}
+		n.Size += c.Size
+	}
+}
+
+// TestableDriveTree returns a folder's contents as a nested tree.
+func TestableDriveTree(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	folderID, errResult := resolveRequiredRef(ctx, srv, request, "folder_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	depth := min(max(common.ParseIntArg(args, "depth", treeDefaultDepth), 1), treeMaxDepth)
+	maxItems := min(max(common.ParseIntArg(args, "max_items", treeDefaultMaxItems), 1), treeMaxItemsLimit)
+
+	folder, errResult := getFolder(ctx, srv, folderID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	root := &treeNode{ID: folder.Id, Name: folder.Name, MimeType: folder.MimeType, ModifiedTime: folder.ModifiedTime}
+	nodes := map[string]*treeNode{folder.Id: root}
+	items, truncated := 0, false
+	err := walkFolder(ctx, srv, folder.Id, folderChildFields, depth, func(parentID string, d int, f *drive.File) bool {
+		if items >= maxItems {
+			truncated = true
+			return false
+		}
+		items++
+		node := &treeNode{ID: f.Id, Name: f.Name, MimeType: f.MimeType, Size: f.Size, ModifiedTime: f.ModifiedTime}
+		nodes[parentID].Children = append(nodes[parentID].Children, node)
+		if f.MimeType == folderMimeType {
+			nodes[f.Id] = node
+			node.NotListed = d == depth
+		}
+		return true
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+	}
+	root.total()
+
+	result := map[string]any{
+		"tree":  root,
+		"depth": depth,
+		"items": items,
+	}
+	if path := NewPathResolver(srv).ResolvePath(ctx, folder.Parents); path != "" {
+		result["path"] = path
+	}
+	if truncated {
+		result["truncated"] = true
+		result["note"] = fmt.Sprintf("Stopped after %d items; raise max_items or list a subfolder.", maxItems)
+	}
+	return common.MarshalToolResult(result)
+}
+
+// statsBucket totals files of one type or owner.
+type statsBucket struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+	Bytes int64  `json:"bytes"`
+}
+
+// sortedBuckets returns buckets by bytes, then count, descending.
+func sortedBuckets(m map[string]*statsBucket) []*statsBucket {
+	list := make([]*statsBucket, 0, len(m))
+	for _, b := range m {
+		list = append(list, b)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Bytes != list[j].Bytes {
+			return list[i].Bytes > list[j].Bytes
+		}
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+// TestableDriveFolderStats totals a folder's files by type and owner.
+func TestableDriveFolderStats(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	folderID, errResult := resolveRequiredRef(ctx, srv, request, "folder_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	maxDepth := max(common.ParseIntArg(args, "max_depth", 0), 0)
+
+	folder, errResult := getFolder(ctx, srv, folderID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	byType := map[string]*statsBucket{}
+	byOwner := map[string]*statsBucket{}
+	add := func(m map[string]*statsBucket, key string, size int64) {
+		b, ok := m[key]
+		if !ok {
+			b = &statsBucket{Key: key}
+			m[key] = b
+		}
+		b.Count++
+		b.Bytes += size
+	}
+
+	var files, folders, items int
+	var bytes int64
+	deepest, truncated := 0, false
+	err := walkFolder(ctx, srv, folder.Id, folderChildFields, maxDepth, func(_ string, depth int, f *drive.File) bool {
+		if items >= statsMaxItems {
+			truncated = true
+			return false
+		}
+		items++
+		deepest = max(deepest, depth)
+		if f.MimeType == folderMimeType {
+			folders++
+			return true
+		}
+		owner := "(shared drive)"
+		if len(f.Owners) > 0 {
+			owner = f.Owners[0].EmailAddress
+		}
+		files++
+		bytes += f.Size
+		add(byType, f.MimeType, f.Size)
+		add(byOwner, owner, f.Size)
+		return true
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+	}
+
+	result := map[string]any{
+		"folder_id": folder.Id,
+		"name":      folder.Name,
+		"files":     files,
+		"folders":   folders,
+		"bytes":     bytes,
+		"levels":    deepest,
+		"by_type":   sortedBuckets(byType),
+		"by_owner":  sortedBuckets(byOwner),
+	}
+	if truncated {
+		result["truncated"] = true
+		result["note"] = fmt.Sprintf("Stopped after %d items; totals cover only those.", statsMaxItems)
+	}
+	return common.MarshalToolResult(result)
+}
+
+// folderCopy tracks a recursive folder copy. Item copies run concurrently,
+// so results are recorded under a lock.
+type folderCopy struct {
+	srv     DriveService
+	mu      sync.Mutex
+	created map[string]bool // IDs of folders made by this copy, never copied again
+	folders int
+	files   int
+	skipped []map[string]any
+	errors  []string
+}
+
+func (c *folderCopy) fail(format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errors = append(c.errors, fmt.Sprintf(format, args...))
+}
+
+// isCopy reports whether id is a folder created by this copy, as happens
+// when the destination is inside the source.
+func (c *folderCopy) isCopy(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.created[id]
+}
+
+// copyItem copies one item into dstID and returns the new folder's ID when
+// the item is a folder whose contents still need copying.
+func (c *folderCopy) copyItem(ctx context.Context, f *drive.File, dstID string) string {
+	switch f.MimeType {
+	case folderMimeType:
+		created, err := c.srv.CreateFile(ctx, &drive.File{Name: f.Name, MimeType: folderMimeType, Parents: []string{dstID}}, nil)
+		if err != nil {
+			c.fail("creating folder %q: %v", f.Name, err)
+			return ""
+		}
+		c.mu.Lock()
+		c.created[created.Id] = true
+		c.folders++
+		c.mu.Unlock()
+		return created.Id
+	case shortcutMimeType:
+		c.mu.Lock()
+		c.skipped = append(c.skipped, map[string]any{"id": f.Id, "name": f.Name, "reason": "shortcut"})
+		c.mu.Unlock()
+		return ""
+	}
+	if _, err := c.srv.CopyFile(ctx, f.Id, &drive.File{Name: f.Name, Parents: []string{dstID}}); err != nil {
+		c.fail("copying %q: %v", f.Name, err)
+		return ""
+	}
+	c.mu.Lock()
+	c.files++
+	c.mu.Unlock()
+	return ""
+}
+
+// TestableDriveCopyFolder copies a folder and everything in it.
+func TestableDriveCopyFolder(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	folderID, errResult := resolveRequiredRef(ctx, srv, request, "folder_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	source, errResult := getFolder(ctx, srv, folderID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	parentID, errResult := resolveOptionalRef(ctx, srv, args, "parent_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if parentID == "" && len(source.Parents) > 0 {
+		parentID = source.Parents[0]
+	}
+	name := common.ParseStringArg(args, "name", "")
+	if name == "" {
+		name = source.Name
+		if len(source.Parents) > 0 && parentID == source.Parents[0] {
+			name = "Copy of " + source.Name
+		}
+	}
+
+	rootCopy := &drive.File{Name: name, MimeType: folderMimeType}
+	if parentID != "" {
+		rootCopy.Parents = []string{parentID}
+	}
+	root, err := srv.CreateFile(ctx, rootCopy, nil)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error creating folder: %v", err)), nil
+	}
+
+	c := &folderCopy{srv: srv, created: map[string]bool{root.Id: true}, skipped: []map[string]any{}, errors: []string{}}
+	type copyJob struct{ src, dst string }
+	level := []copyJob{{source.Id, root.Id}}
+	for len(level) > 0 {
+		// List this level's source folders, then copy their contents.
+		children := make([][]*drive.File, len(level))
+		g, gCtx := errgroup.WithContext(ctx)
+		g.SetLimit(folderWalkConcurrency)
+		for i, job := range level {
+			g.Go(func() error {
+				files, err := listFolderChildren(gCtx, srv, job.src, folderChildFields)
+				if err != nil {
+					c.fail("%v", err)
+				}
+				children[i] = files
+				return nil
+			})
+		}
+		_ = g.Wait()
+
+		var next []copyJob
+		var nextMu sync.Mutex
+		g, gCtx = errgroup.WithContext(ctx)
+		g.SetLimit(folderWalkConcurrency)
+		for i, files := range children {
+			for _, f := range files {
+				if c.isCopy(f.Id) {
+					continue
+				}
+				g.Go(func() error {
+					if dst := c.copyItem(gCtx, f, level[i].dst); dst != "" {
+						nextMu.Lock()
+						next = append(next, copyJob{f.Id, dst})
+						nextMu.Unlock()
+					}
+					return nil
+				})
+			}
+		}
+		_ = g.Wait()
+		level = next
+	}
+
+	result := map[string]any{
+		"folder_id":        root.Id,
+		"name":             root.Name,
+		"url":              root.WebViewLink,
+		"source_folder_id": source.Id,
+		"folders_created":  c.folders,
+		"files_copied":     c.files,
+		"skipped":          c.skipped,
+		"errors":           c.errors,
+	}
+	return common.MarshalToolResult(result)
+}
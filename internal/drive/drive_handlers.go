@@ -8,6 +8,7 @@ import (
 	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"github.com/mark3labs/mcp-go/mcp"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/drivelabels/v2"
 	"google.golang.org/api/option"
 )
 
@@ -24,7 +25,13 @@ func NewDriveServiceConstructor(filter *common.DriveAccessFilter) common.Service
 		if err != nil {
 			return nil, fmt.Errorf("creating drive service: %w", err)
 		}
+		labels, err := drivelabels.NewService(ctx, option.WithHTTPClient(client))
+		if err != nil {
+			return nil, fmt.Errorf("creating drive labels service: %w", err)
+		}
 		real := NewRealDriveService(srv)
+		real.client = client
+		real.labels = labels
 		if filter != nil && filter.IsActive() {
 			return NewFilteredDriveService(real, filter), nil
 		}
@@ -51,6 +58,7 @@ func InitDefaultDriveHandlerDeps(appDeps *common.Deps) {
 	var filter *common.DriveAccessFilter
 	if appDeps != nil {
 		filter = appDeps.DriveAccessFilter
+		transferDirs = appDeps.LocalDirs
 	}
 	DefaultDriveHandlerDeps = common.NewDefaultHandlerDeps(NewDriveServiceConstructor(filter), appDeps)
 }
@@ -0,0 +1,209 @@
+package drive
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/drivelabels/v2"
+)
+
+// labelFixtures serves a "Classification" label with a selection, a date
+// and a multi-value user field, and records label modifications.
+func labelFixtures() (*DriveTestFixtures, *[]*drive.ModifyLabelsRequest) {
+	fixtures := NewDriveTestFixtures()
+	var requests []*drive.ModifyLabelsRequest
+
+	classification := &drivelabels.GoogleAppsDriveLabelsV2Label{
+		Id:         "lbl1",
+		Name:       "labels/lbl1",
+		LabelType:  "ADMIN",
+		Properties: &drivelabels.GoogleAppsDriveLabelsV2LabelProperties{Title: "Classification"},
+		Fields: []*drivelabels.GoogleAppsDriveLabelsV2Field{
+			{
+				Id:         "conf",
+				QueryKey:   "labels/lbl1.conf",
+				Properties: &drivelabels.GoogleAppsDriveLabelsV2FieldProperties{DisplayName: "Confidentiality", Required: true},
+				SelectionOptions: &drivelabels.GoogleAppsDriveLabelsV2FieldSelectionOptions{
+					Choices: []*drivelabels.GoogleAppsDriveLabelsV2FieldSelectionOptionsChoice{
+						{Id: "c1", Properties: &drivelabels.GoogleAppsDriveLabelsV2FieldSelectionOptionsChoiceProperties{DisplayName: "Internal"}},
+						{Id: "c2", Properties: &drivelabels.GoogleAppsDriveLabelsV2FieldSelectionOptionsChoiceProperties{DisplayName: "Public"}},
+					},
+				},
+			},
+			{
+				Id:          "review",
+				Properties:  &drivelabels.GoogleAppsDriveLabelsV2FieldProperties{DisplayName: "Review date"},
+				DateOptions: &drivelabels.GoogleAppsDriveLabelsV2FieldDateOptions{},
+			},
+			{
+				Id:          "owners",
+				Properties:  &drivelabels.GoogleAppsDriveLabelsV2FieldProperties{DisplayName: "Owners"},
+				UserOptions: &drivelabels.GoogleAppsDriveLabelsV2FieldUserOptions{ListOptions: &drivelabels.GoogleAppsDriveLabelsV2FieldListOptions{}},
+			},
+		},
+	}
+	project := &drivelabels.GoogleAppsDriveLabelsV2Label{
+		Id:         "lbl2",
+		Properties: &drivelabels.GoogleAppsDriveLabelsV2LabelProperties{Title: "Project"},
+		Fields: []*drivelabels.GoogleAppsDriveLabelsV2Field{
+			{Id: "num", Properties: &drivelabels.GoogleAppsDriveLabelsV2FieldProperties{DisplayName: "Number"}, IntegerOptions: &drivelabels.GoogleAppsDriveLabelsV2FieldIntegerOptions{}},
+		},
+	}
+
+	mock := fixtures.MockService
+	mock.ListLabelsFunc = func(context.Context, string, int64, string) (*drivelabels.GoogleAppsDriveLabelsV2ListLabelsResponse, error) {
+		return &drivelabels.GoogleAppsDriveLabelsV2ListLabelsResponse{Labels: []*drivelabels.GoogleAppsDriveLabelsV2Label{classification, project}}, nil
+	}
+	mock.ListFileLabelsFunc = func(context.Context, string, string) (*drive.LabelList, error) {
+		return &drive.LabelList{Labels: []*drive.Label{{
+			Id: "lbl1",
+			Fields: map[string]drive.LabelField{
+				"owners": {ValueType: "user", User: []*drive.User{{EmailAddress: "a@example.com"}}},
+				"conf":   {ValueType: "selection", Selection: []string{"c1"}},
+			},
+		}}}, nil
+	}
+	mock.ModifyFileLabelsFunc = func(_ context.Context, _ string, req *drive.ModifyLabelsRequest) (*drive.ModifyLabelsResponse, error) {
+		requests = append(requests, req)
+		return &drive.ModifyLabelsResponse{ModifiedLabels: []*drive.Label{{Id: req.LabelModifications[0].LabelId}}}, nil
+	}
+	return fixtures, &requests
+}
+
+func TestDriveListLabels(t *testing.T) {
+	fixtures, _ := labelFixtures()
+	var role string
+	inner := fixtures.MockService.ListLabelsFunc
+	fixtures.MockService.ListLabelsFunc = func(ctx context.Context, minimumRole string, pageSize int64, pageToken string) (*drivelabels.GoogleAppsDriveLabelsV2ListLabelsResponse, error) {
+		role = minimumRole
+		return inner(ctx, minimumRole, pageSize, pageToken)
+	}
+
+	data, errText := runDriveTransfer(t, TestableDriveListLabels, fixtures, map[string]any{"minimum_role": "applier"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if role != "APPLIER" || data["count"] != float64(2) {
+		t.Fatalf("expected both labels for the APPLIER role, got %q %v", role, data)
+	}
+	field := data["labels"].([]any)[0].(map[string]any)["fields"].([]any)[0].(map[string]any)
+	if field["type"] != "selection" || field["query_key"] != "labels/lbl1.conf" || len(field["choices"].([]any)) != 2 {
+		t.Errorf("unexpected field definition: %v", field)
+	}
+
+	if _, errText = runDriveTransfer(t, TestableDriveListLabels, fixtures, map[string]any{"minimum_role": "owner"}); !strings.Contains(errText, "minimum_role") {
+		t.Errorf("expected an invalid role to be refused, got %q", errText)
+	}
+}
+
+func TestDriveGetLabels(t *testing.T) {
+	fixtures, _ := labelFixtures()
+	data, errText := runDriveTransfer(t, TestableDriveGetLabels, fixtures, map[string]any{"file_id": "f1"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	label := data["labels"].([]any)[0].(map[string]any)
+	fields := label["fields"].([]any)
+	first, second := fields[0].(map[string]any), fields[1].(map[string]any)
+	if label["title"] != "Classification" || first["name"] != "Confidentiality" || first["values"].([]any)[0] != "Internal" || second["values"].([]any)[0] != "a@example.com" {
+		t.Errorf("expected fields named and ordered by the definition, got %v", label)
+	}
+}
+
+func TestDriveModifyLabels(t *testing.T) {
+	fixtures, requests := labelFixtures()
+	data, errText := runDriveTransfer(t, TestableDriveModifyLabels, fixtures, map[string]any{
+		"file_id": "f1",
+		"label":   "classification",
+		"fields": map[string]any{
+			"Confidentiality": "public",
+			"review":          nil,
+			"Owners":          []any{"a@example.com", "b@example.com"},
+		},
+	})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	mod := (*requests)[0].LabelModifications[0]
+	if data["label_id"] != "lbl1" || mod.LabelId != "lbl1" || len(mod.FieldModifications) != 3 {
+		t.Fatalf("unexpected modification: %v %+v", data, mod)
+	}
+	byID := map[string]*drive.LabelFieldModification{}
+	for _, m := range mod.FieldModifications {
+		byID[m.FieldId] = m
+	}
+	if byID["conf"].SetSelectionValues[0] != "c2" || !byID["review"].UnsetValues || len(byID["owners"].SetUserValues) != 2 {
+		t.Errorf("expected names resolved to IDs, got %+v %+v %+v", byID["conf"], byID["review"], byID["owners"])
+	}
+
+	data, errText = runDriveTransfer(t, TestableDriveModifyLabels, fixtures, map[string]any{"file_id": "f1", "label": "lbl2", "remove": true})
+	if errText != "" || data["removed"] != true || !(*requests)[1].LabelModifications[0].RemoveLabel {
+		t.Errorf("expected the label to be removed, got %v %q", data, errText)
+	}
+
+	for _, tc := range []struct {
+		fields map[string]any
+		want   string
+	}{
+		{map[string]any{"Confidentiality": "Secret"}, "no choice"},
+		{map[string]any{"Review date": "next week"}, "YYYY-MM-DD"},
+		{map[string]any{"Confidentiality": []any{"Internal", "Public"}}, "single value"},
+		{map[string]any{"Colour": "red"}, "no field"},
+	} {
+		if _, errText = runDriveTransfer(t, TestableDriveModifyLabels, fixtures, map[string]any{"file_id": "f1", "label": "Classification", "fields": tc.fields}); !strings.Contains(errText, tc.want) {
+			t.Errorf("fields %v: expected an error containing %q, got %q", tc.fields, tc.want, errText)
+		}
+	}
+}
+
+func TestDriveSearchByLabel(t *testing.T) {
+	fixtures, _ := labelFixtures()
+	var opts *ListFilesOptions
+	fixtures.MockService.ListFilesFunc = func(_ context.Context, o *ListFilesOptions) (*drive.FileList, error) {
+		opts = o
+		return &drive.FileList{Files: []*drive.File{{
+			Id:   "f1",
+			Name: "plan.doc",
+			LabelInfo: &drive.FileLabelInfo{Labels: []*drive.Label{{
+				Id:     "lbl1",
+				Fields: map[string]drive.LabelField{"conf": {ValueType: "selection", Selection: []string{"c1"}}},
+			}}},
+		}}}, nil
+	}
+
+	data, errText := runDriveTransfer(t, TestableDriveSearch, fixtures, map[string]any{
+		"query":       "name contains 'plan'",
+		"label":       "Classification",
+		"label_field": "Confidentiality",
+		"label_value": "Internal",
+	})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if opts.Query != "(name contains 'plan') and labels/lbl1.conf = 'c1'" || opts.IncludeLabels != "lbl1" || opts.Fields != DriveFileListLabelFields {
+		t.Errorf("unexpected list options: %+v", opts)
+	}
+	file := data["files"].([]any)[0].(map[string]any)
+	if file["labels"].([]any)[0].(map[string]any)["fields"].([]any)[0].(map[string]any)["values"].([]any)[0] != "Internal" {
+		t.Errorf("expected the label's values in the results, got %v", file)
+	}
+
+	for _, tc := range []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"label": "Classification"}, "'labels/lbl1' in labels"},
+		{map[string]any{"label": "Project", "label_field": "Number", "label_value": "42"}, "labels/lbl2.num = 42"},
+		{map[string]any{"label": "lbl1", "label_field": "owners", "label_value": "a@example.com"}, "'a@example.com' in labels/lbl1.owners"},
+	} {
+		if _, errText = runDriveTransfer(t, TestableDriveSearch, fixtures, tc.args); errText != "" || opts.Query != tc.want {
+			t.Errorf("%v: expected query %q, got %q %q", tc.args, tc.want, opts.Query, errText)
+		}
+	}
+
+	if _, errText = runDriveTransfer(t, TestableDriveSearch, fixtures, map[string]any{}); !strings.Contains(errText, "query parameter is required") {
+		t.Errorf("expected query to be required without a label, got %q", errText)
+	}
+}
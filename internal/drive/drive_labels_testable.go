@@ -0,0 +1,547 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/drivelabels/v2"
+)
+
+// labelListPageSize is the largest page the Drive Labels API returns.
+const labelListPageSize = 200
+
+// labelRoles maps minimum_role values to Drive Labels API roles.
+var labelRoles = map[string]string{
+	"reader":    "READER",
+	"applier":   "APPLIER",
+	"editor":    "EDITOR",
+	"organizer": "ORGANIZER",
+}
+
+// labelFieldType returns the type of a label field: text, integer, date,
+// selection or user.
+func labelFieldType(f *drivelabels.GoogleAppsDriveLabelsV2Field) string {
+	switch {
+	case f.SelectionOptions != nil:
+		return "selection"
+	case f.IntegerOptions != nil:
+		return "integer"
+	case f.DateOptions != nil:
+		return "date"
+	case f.UserOptions != nil:
+		return "user"
+	default:
+		return "text"
+	}
+}
+
+// labelFieldMultiple reports whether a label field holds a list of values.
+func labelFieldMultiple(f *drivelabels.GoogleAppsDriveLabelsV2Field) bool {
+	return (f.SelectionOptions != nil && f.SelectionOptions.ListOptions != nil) ||
+		(f.UserOptions != nil && f.UserOptions.ListOptions != nil)
+}
+
+func labelTitle(l *drivelabels.GoogleAppsDriveLabelsV2Label) string {
+	if l.Properties == nil {
+		return ""
+	}
+	return l.Properties.Title
+}
+
+func labelFieldName(f *drivelabels.GoogleAppsDriveLabelsV2Field) string {
+	if f.Properties == nil {
+		return ""
+	}
+	return f.Properties.DisplayName
+}
+
+func choiceName(c *drivelabels.GoogleAppsDriveLabelsV2FieldSelectionOptionsChoice) string {
+	if c.Properties == nil {
+		return ""
+	}
+	return c.Properties.DisplayName
+}
+
+// formatLabelDefinition formats a label and its fields for output.
+func formatLabelDefinition(l *drivelabels.GoogleAppsDriveLabelsV2Label) map[string]any {
+	result := map[string]any{
+		"id":    l.Id,
+		"title": labelTitle(l),
+	}
+	if l.Properties != nil && l.Properties.Description != "" {
+		result["description"] = l.Properties.Description
+	}
+	if l.LabelType != "" {
+		result["label_type"] = strings.ToLower(l.LabelType)
+	}
+	if l.Lifecycle != nil && l.Lifecycle.State != "" {
+		result["state"] = strings.ToLower(l.Lifecycle.State)
+	}
+
+	fields := make([]map[string]any, 0, len(l.Fields))
+	for _, f := range l.Fields {
+		field := map[string]any{
+			"id":   f.Id,
+			"name": labelFieldName(f),
+			"type": labelFieldType(f),
+		}
+		if f.QueryKey != "" {
+			field["query_key"] = f.QueryKey
+		}
+		if f.Properties != nil && f.Properties.Required {
+			field["required"] = true
+		}
+		if labelFieldMultiple(f) {
+			field["multiple"] = true
+		}
+		if f.SelectionOptions != nil {
+			choices := make([]map[string]any, 0, len(f.SelectionOptions.Choices))
+			for _, c := range f.SelectionOptions.Choices {
+				choices = append(choices, map[string]any{"id": c.Id, "name": choiceName(c)})
+			}
+			field["choices"] = choices
+		}
+		fields = append(fields, field)
+	}
+	result["fields"] = fields
+	return result
+}
+
+// formatAppliedLabel formats a label applied to a file. With the label's
+// definition, fields and selection choices are shown by name and in the
+// definition's order.
+func formatAppliedLabel(l *drive.Label, def *drivelabels.GoogleAppsDriveLabelsV2Label) map[string]any {
+	result := map[string]any{
+		"id": l.Id,
+	}
+	if def != nil {
+		result["title"] = labelTitle(def)
+	}
+	if l.RevisionId != "" {
+		result["revision_id"] = l.RevisionId
+	}
+
+	var defs map[string]*drivelabels.GoogleAppsDriveLabelsV2Field
+	ids := make([]string, 0, len(l.Fields))
+	if def != nil {
+		defs = make(map[string]*drivelabels.GoogleAppsDriveLabelsV2Field, len(def.Fields))
+		for _, f := range def.Fields {
+			defs[f.Id] = f
+			if _, ok := l.Fields[f.Id]; ok {
+				ids = append(ids, f.Id)
+			}
+		}
+	}
+	if len(ids) != len(l.Fields) {
+		ids = ids[:0]
+		for id := range l.Fields {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+	}
+
+	fields := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		value := l.Fields[id]
+		fieldDef := defs[id]
+		field := map[string]any{"id": id}
+		if fieldDef != nil {
+			field["name"] = labelFieldName(fieldDef)
+		}
+
+		var values []string
+		switch value.ValueType {
+		case "text":
+			field["type"] = "text"
+			values = value.Text
+		case "integer":
+			field["type"] = "integer"
+			for _, n := range value.Integer {
+				values = append(values, strconv.FormatInt(n, 10))
+			}
+		case "dateString":
+			field["type"] = "date"
+			values = value.DateString
+		case "selection":
+			field["type"] = "selection"
+			for _, choiceID := range value.Selection {
+				values = append(values, labelChoiceName(fieldDef, choiceID))
+			}
+		case "user":
+			field["type"] = "user"
+			for _, u := range value.User {
+				values = append(values, u.EmailAddress)
+			}
+		}
+		field["values"] = values
+		fields = append(fields, field)
+	}
+	result["fields"] = fields
+	return result
+}
+
+// labelChoiceName returns the name of a selection choice, or its ID when the
+// field's definition is unknown.
+func labelChoiceName(f *drivelabels.GoogleAppsDriveLabelsV2Field, choiceID string) string {
+	if f != nil && f.SelectionOptions != nil {
+		for _, c := range f.SelectionOptions.Choices {
+			if c.Id == choiceID && choiceName(c) != "" {
+				return choiceName(c)
+			}
+		}
+	}
+	return choiceID
+}
+
+// listAllLabels lists every published label the user can read.
+func listAllLabels(ctx context.Context, srv DriveService) ([]*drivelabels.GoogleAppsDriveLabelsV2Label, error) {
+	var labels []*drivelabels.GoogleAppsDriveLabelsV2Label
+	pageToken := ""
+	for {
+		resp, err := srv.ListLabels(ctx, "", labelListPageSize, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, resp.Labels...)
+		if resp.NextPageToken == "" {
+			return labels, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// resolveLabel finds a label by ID, resource name (labels/ID) or title. A
+// title shared by several labels is an error naming their IDs.
+func resolveLabel(ctx context.Context, srv DriveService, ref string) (*drivelabels.GoogleAppsDriveLabelsV2Label, error) {
+	labels, err := listAllLabels(ctx, srv)
+	if err != nil {
+		return nil, fmt.Errorf("listing labels: %w", err)
+	}
+	id := strings.TrimPrefix(ref, "labels/")
+	var byTitle []*drivelabels.GoogleAppsDriveLabelsV2Label
+	for _, l := range labels {
+		if l.Id == id {
+			return l, nil
+		}
+		if strings.EqualFold(labelTitle(l), ref) {
+			byTitle = append(byTitle, l)
+		}
+	}
+	switch len(byTitle) {
+	case 0:
+		return nil, fmt.Errorf("label %q not found; use drive_list_labels to see the available labels", ref)
+	case 1:
+		return byTitle[0], nil
+	}
+	ids := make([]string, 0, len(byTitle))
+	for _, l := range byTitle {
+		ids = append(ids, l.Id)
+	}
+	return nil, fmt.Errorf("label title %q is ambiguous; use one of the label IDs: %s", ref, strings.Join(ids, ", "))
+}
+
+// resolveLabelField finds a field of a label by ID or name.
+func resolveLabelField(l *drivelabels.GoogleAppsDriveLabelsV2Label, ref string) (*drivelabels.GoogleAppsDriveLabelsV2Field, error) {
+	for _, f := range l.Fields {
+		if f.Id == ref {
+			return f, nil
+		}
+	}
+	for _, f := range l.Fields {
+		if strings.EqualFold(labelFieldName(f), ref) {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("label %q has no field %q", labelTitle(l), ref)
+}
+
+// labelFieldValues converts a field value, or a list of values, to the form
+// the Drive API expects: selection choice names become choice IDs, integers
+// and dates are validated.
+func labelFieldValues(f *drivelabels.GoogleAppsDriveLabelsV2Field, value any) ([]string, error) {
+	items, ok := value.([]any)
+	if !ok {
+		items = []any{value}
+	}
+	if len(items) > 1 && !labelFieldMultiple(f) {
+		return nil, fmt.Errorf("field %q takes a single value", labelFieldName(f))
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		var s string
+		switch v := item.(type) {
+		case string:
+			s = strings.TrimSpace(v)
+		case float64:
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("field %q: %v is not a whole number", labelFieldName(f), v)
+			}
+			s = strconv.FormatInt(int64(v), 10)
+		default:
+			return nil, fmt.Errorf("field %q: values must be strings or numbers", labelFieldName(f))
+		}
+
+		switch labelFieldType(f) {
+		case "integer":
+			if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+				return nil, fmt.Errorf("field %q: %q is not a whole number", labelFieldName(f), s)
+			}
+		case "date":
+			if _, err := time.Parse(time.DateOnly, s); err != nil {
+				return nil, fmt.Errorf("field %q: %q is not a date in YYYY-MM-DD form", labelFieldName(f), s)
+			}
+		case "selection":
+			choiceID, err := labelChoiceID(f, s)
+			if err != nil {
+				return nil, err
+			}
+			s = choiceID
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+// labelChoiceID finds a selection choice by ID or name.
+func labelChoiceID(f *drivelabels.GoogleAppsDriveLabelsV2Field, ref string) (string, error) {
+	names := make([]string, 0, len(f.SelectionOptions.Choices))
+	for _, c := range f.SelectionOptions.Choices {
+		if c.Id == ref || strings.EqualFold(choiceName(c), ref) {
+			return c.Id, nil
+		}
+		names = append(names, choiceName(c))
+	}
+	return "", fmt.Errorf("field %q has no choice %q; choices are: %s", labelFieldName(f), ref, strings.Join(names, ", "))
+}
+
+// labelFieldModification builds the change that sets a field to value. A
+// null value, empty string or empty list clears the field.
+func labelFieldModification(f *drivelabels.GoogleAppsDriveLabelsV2Field, value any) (*drive.LabelFieldModification, error) {
+	mod := &drive.LabelFieldModification{FieldId: f.Id}
+	if value == nil || value == "" {
+		mod.UnsetValues = true
+		return mod, nil
+	}
+	if items, ok := value.([]any); ok && len(items) == 0 {
+		mod.UnsetValues = true
+		return mod, nil
+	}
+
+	values, err := labelFieldValues(f, value)
+	if err != nil {
+		return nil, err
+	}
+	switch labelFieldType(f) {
+	case "text":
+		mod.SetTextValues = values
+	case "integer":
+		for _, v := range values {
+			n, _ := strconv.ParseInt(v, 10, 64)
+			mod.SetIntegerValues = append(mod.SetIntegerValues, n)
+		}
+	case "date":
+		mod.SetDateValues = values
+	case "selection":
+		mod.SetSelectionValues = values
+	case "user":
+		mod.SetUserValues = values
+	}
+	return mod, nil
+}
+
+// labelSearchClause builds the Drive query clause matching files with a label
+// or, with a field, files whose field has the given value.
+func labelSearchClause(l *drivelabels.GoogleAppsDriveLabelsV2Label, f *drivelabels.GoogleAppsDriveLabelsV2Field, value string) (string, error) {
+	if f == nil {
+		return fmt.Sprintf("'labels/%s' in labels", l.Id), nil
+	}
+	values, err := labelFieldValues(f, value)
+	if err != nil {
+		return "", err
+	}
+	key := f.QueryKey
+	if key == "" {
+		key = fmt.Sprintf("labels/%s.%s", l.Id, f.Id)
+	}
+	v := values[0]
+	if labelFieldType(f) != "integer" {
+		v = quoteQueryValue(v)
+	}
+	if labelFieldMultiple(f) {
+		return fmt.Sprintf("%s in %s", v, key), nil
+	}
+	return fmt.Sprintf("%s = %s", key, v), nil
+}
+
+// TestableDriveListLabels lists the Drive labels available to the user.
+func TestableDriveListLabels(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	role := ""
+	if r := common.ParseStringArg(args, "minimum_role", ""); r != "" {
+		if role, ok = labelRoles[strings.ToLower(r)]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid minimum_role %q: use reader, applier, editor or organizer", r)), nil
+		}
+	}
+	maxResults := common.ParseMaxResults(args, common.DriveSearchDefaultMaxResults, common.DriveSearchMaxResultsLimit)
+	pageToken := common.ParseStringArg(args, "page_token", "")
+
+	resp, err := srv.ListLabels(ctx, role, maxResults, pageToken)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive Labels API error: %v", err)), nil
+	}
+
+	labels := make([]map[string]any, 0, len(resp.Labels))
+	for _, l := range resp.Labels {
+		labels = append(labels, formatLabelDefinition(l))
+	}
+
+	result := map[string]any{
+		"labels":          labels,
+		"count":           len(labels),
+		"next_page_token": resp.NextPageToken,
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// TestableDriveGetLabels lists the labels applied to a file and their field
+// values. Fields are named from the label definitions when they can be read.
+func TestableDriveGetLabels(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
+	if idErrResult != nil {
+		return idErrResult, nil
+	}
+
+	var applied []*drive.Label
+	pageToken := ""
+	for {
+		resp, err := srv.ListFileLabels(ctx, fileID, pageToken)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+		}
+		applied = append(applied, resp.Labels...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	defs := map[string]*drivelabels.GoogleAppsDriveLabelsV2Label{}
+	if len(applied) > 0 {
+		if all, err := listAllLabels(ctx, srv); err == nil {
+			for _, l := range all {
+				defs[l.Id] = l
+			}
+		}
+	}
+
+	labels := make([]map[string]any, 0, len(applied))
+	for _, l := range applied {
+		labels = append(labels, formatAppliedLabel(l, defs[l.Id]))
+	}
+
+	result := map[string]any{
+		"file_id": fileID,
+		"labels":  labels,
+		"count":   len(labels),
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// TestableDriveModifyLabels applies a label to a file and sets its field
+// values, or removes the label.
+func TestableDriveModifyLabels(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
+	if idErrResult != nil {
+		return idErrResult, nil
+	}
+
+	args := request.GetArguments()
+	labelRef, errResult := common.RequireStringArg(args, "label")
+	if errResult != nil {
+		return errResult, nil
+	}
+	remove := common.ParseBoolArg(args, "remove", false)
+
+	var fieldArgs map[string]any
+	if raw, present := args["fields"]; present && raw != nil {
+		if fieldArgs, ok = raw.(map[string]any); !ok {
+			return mcp.NewToolResultError("fields must be an object mapping field names or IDs to values"), nil
+		}
+	}
+	if remove && len(fieldArgs) > 0 {
+		return mcp.NewToolResultError("fields cannot be set when removing a label"), nil
+	}
+
+	label, err := resolveLabel(ctx, srv, labelRef)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	mod := &drive.LabelModification{LabelId: label.Id, RemoveLabel: remove}
+	refs := make([]string, 0, len(fieldArgs))
+	for ref := range fieldArgs {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		field, err := resolveLabelField(label, ref)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		fieldMod, err := labelFieldModification(field, fieldArgs[ref])
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		mod.FieldModifications = append(mod.FieldModifications, fieldMod)
+	}
+
+	resp, err := srv.ModifyFileLabels(ctx, fileID, &drive.ModifyLabelsRequest{
+		LabelModifications: []*drive.LabelModification{mod},
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+	}
+
+	result := map[string]any{
+		"file_id":  fileID,
+		"label_id": label.Id,
+		"title":    labelTitle(label),
+	}
+	if remove {
+		result["removed"] = true
+	} else {
+		for _, l := range resp.ModifiedLabels {
+			if l.Id == label.Id {
+				result["label"] = formatAppliedLabel(l, label)
+			}
+		}
+	}
+
+	return common.MarshalToolResult(result)
+}
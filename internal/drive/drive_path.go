@@ -2,17 +2,53 @@ package drive
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
+
+	"google.golang.org/api/drive/v3"
 )
 
 const maxPathDepth = 20
 
+// pathLookupFields contains fields for files found while looking up a path.
+const pathLookupFields = "id,name,mimeType,modifiedTime,parents,driveId,webViewLink"
+
+// IsDrivePath reports whether ref is a path such as "/My Drive/Reports" or
+// "shared:Engineering/Specs" rather than a file ID or URL.
+func IsDrivePath(ref string) bool {
+	return strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "shared:")
+}
+
+// Errors wrapped by LookupPath when a path names nothing.
+var (
+	ErrPathNotFound = errors.New("not found")
+	ErrNotAFolder   = errors.New("is not a folder")
+)
+
+// AmbiguousPathError is returned when a path segment matches more than one
+// file. Drive allows duplicate names in a folder.
+type AmbiguousPathError struct {
+	Path       string // the path up to and including the ambiguous segment
+	Candidates []*drive.File
+}
+
+func (e *AmbiguousPathError) Error() string {
+	list := make([]string, 0, len(e.Candidates))
+	for _, f := range e.Candidates {
+		list = append(list, fmt.Sprintf("%s (%s, modified %s)", f.Id, f.MimeType, f.ModifiedTime))
+	}
+	return fmt.Sprintf("path %s is ambiguous: %d items have that name: %s; pass one of these IDs instead",
+		e.Path, len(e.Candidates), strings.Join(list, ", "))
+}
+
 // PathResolver resolves the folder path for Drive files.
 // It caches folder lookups to avoid redundant API calls when
 // multiple files share the same parent chain.
 type PathResolver struct {
 	srv        DriveService
 	folderPath map[string]string // folderID → full path
+	parentOf   map[string]string // folderID → parent folder ID ("" for a root)
 	driveName  map[string]string // driveID → drive name
 }
 
@@ -21,6 +57,7 @@ func NewPathResolver(srv DriveService) *PathResolver {
 	return &PathResolver{
 		srv:        srv,
 		folderPath: make(map[string]string),
+		parentOf:   make(map[string]string),
 		driveName:  make(map[string]string),
 	}
 }
@@ -58,6 +95,7 @@ func (r *PathResolver) resolveParentPath(ctx context.Context, folderID string, d
 			name = parent.Name
 		}
 		r.folderPath[folderID] = name
+		r.parentOf[folderID] = ""
 		return name
 	}
 
@@ -71,9 +109,33 @@ func (r *PathResolver) resolveParentPath(ctx context.Context, folderID string, d
 	}
 
 	r.folderPath[folderID] = path
+	r.parentOf[folderID] = parent.Parents[0]
 	return path
 }
 
+// InFolder reports whether a file with the given parents lies in folderID or
+// one of its subfolders. It walks the same cached parent chain as ResolvePath.
+func (r *PathResolver) InFolder(ctx context.Context, parents []string, folderID string) bool {
+	if len(parents) == 0 {
+		return false
+	}
+	id := parents[0]
+	for depth := 0; depth < maxPathDepth && id != ""; depth++ {
+		if id == folderID {
+			return true
+		}
+		if _, ok := r.parentOf[id]; !ok {
+			r.resolveParentPath(ctx, id, 0)
+		}
+		next, ok := r.parentOf[id]
+		if !ok {
+			return false
+		}
+		id = next
+	}
+	return false
+}
+
 // resolveDriveName returns the display name for a shared drive, falling back to the raw ID.
 func (r *PathResolver) resolveDriveName(ctx context.Context, driveID string) string {
 	if driveID == "" {
@@ -98,3 +160,175 @@ func (r *PathResolver) resolveDriveName(ctx context.Context, driveID string) str
 	r.driveName[driveID] = name
 	return name
 }
+
+// LookupPath returns the file at a path, resolving one segment at a time.
+// Paths take the forms "/My Drive/Reports/Q3.xlsx",
+// "/Shared drives/Engineering/Specs" and "shared:Engineering/Specs"; other
+// paths starting with "/" are taken to be in My Drive. Every segment but
+// the last must name a folder. A segment matching several items returns an
+// *AmbiguousPathError.
+func (r *PathResolver) LookupPath(ctx context.Context, path string) (*drive.File, error) {
+	file, _, err := r.walkPath(ctx, path, false)
+	return file, err
+}
+
+// MakeFolderPath returns the folder at a path, creating it and any missing
+// parent folders like mkdir -p. It also returns the folders it created,
+// outermost first.
+func (r *PathResolver) MakeFolderPath(ctx context.Context, path string) (*drive.File, []*drive.File, error) {
+	return r.walkPath(ctx, path, true)
+}
+
+func (r *PathResolver) walkPath(ctx context.Context, path string, create bool) (*drive.File, []*drive.File, error) {
+	rootID, where, segments, err := r.splitPath(ctx, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	current, err := r.srv.GetFile(ctx, rootID, pathLookupFields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting %s: %w", where, err)
+	}
+
+	var created []*drive.File
+	for i, name := range segments {
+		if current.MimeType != folderMimeType {
+			return nil, nil, fmt.Errorf("%s %w", where, ErrNotAFolder)
+		}
+		foldersOnly := create || i < len(segments)-1
+		var matches []*drive.File
+		if len(created) == 0 { // a folder just created has no children
+			matches, err = r.findChildren(ctx, current.Id, name)
+			if err != nil {
+				return nil, nil, fmt.Errorf("looking up %s/%s: %w", where, name, err)
+			}
+		}
+		where += "/" + name
+		if foldersOnly && len(matches) > 0 {
+			var folders []*drive.File
+			for _, f := range matches {
+				if f.MimeType == folderMimeType {
+					folders = append(folders, f)
+				}
+			}
+			if len(folders) == 0 {
+				return nil, nil, fmt.Errorf("%s %w", where, ErrNotAFolder)
+			}
+			matches = folders
+		}
+
+		switch {
+		case len(matches) == 1:
+			current = matches[0]
+		case len(matches) > 1:
+			return nil, nil, &AmbiguousPathError{Path: where, Candidates: matches}
+		case create:
+			current, err = r.srv.CreateFile(ctx, &drive.File{Name: name, MimeType: folderMimeType, Parents: []string{current.Id}}, nil)
+			if err != nil {
+				return nil, nil, fmt.Errorf("creating %s: %w", where, err)
+			}
+			created = append(created, current)
+		case foldersOnly:
+			return nil, nil, fmt.Errorf("folder %s %w", where, ErrPathNotFound)
+		default:
+			return nil, nil, fmt.Errorf("%s %w", where, ErrPathNotFound)
+		}
+	}
+	return current, created, nil
+}
+
+// splitPath returns the ID and display path of the folder a path starts
+// from, and the names below it.
+func (r *PathResolver) splitPath(ctx context.Context, path string) (string, string, []string, error) {
+	var segments []string
+	if rest, ok := strings.CutPrefix(path, "shared:"); ok {
+		segments = pathSegments(rest)
+	} else if strings.HasPrefix(path, "/") {
+		segments = pathSegments(path)
+		switch {
+		case len(segments) > 0 && segments[0] == "My Drive":
+			return "root", "/My Drive", segments[1:], nil
+		case len(segments) > 1 && segments[0] == "Shared drives":
+			segments = segments[1:]
+		default:
+			return "root", "/My Drive", segments, nil
+		}
+	} else {
+		return "", "", nil, fmt.Errorf("%q is not a path; paths start with / or shared:", path)
+	}
+
+	if len(segments) == 0 {
+		return "", "", nil, fmt.Errorf("%q does not name a shared drive", path)
+	}
+	driveID, err := r.findSharedDrive(ctx, segments[0])
+	if err != nil {
+		return "", "", nil, err
+	}
+	return driveID, "/Shared drives/" + segments[0], segments[1:], nil
+}
+
+// pathSegments splits a path into its non-empty names.
+func pathSegments(path string) []string {
+	var segments []string
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// findSharedDrive returns the ID of the shared drive with the given name.
+func (r *PathResolver) findSharedDrive(ctx context.Context, name string) (string, error) {
+	var ids []string
+	pageToken := ""
+	for {
+		resp, err := r.srv.ListDrives(ctx, 100, pageToken)
+		if err != nil {
+			return "", fmt.Errorf("listing shared drives: %w", err)
+		}
+		for _, d := range resp.Drives {
+			if strings.TrimSpace(d.Name) == name {
+				ids = append(ids, d.Id)
+				r.driveName[d.Id] = name
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("shared drive %q %w", name, ErrPathNotFound)
+	case 1:
+		return ids[0], nil
+	}
+	return "", fmt.Errorf("shared drive name %q is ambiguous: %s; pass a drive ID instead", name, strings.Join(ids, ", "))
+}
+
+// findChildren returns the non-trashed items named name in a folder.
+func (r *PathResolver) findChildren(ctx context.Context, parentID, name string) ([]*drive.File, error) {
+	resp, err := r.srv.ListFiles(ctx, &ListFilesOptions{
+		Query:    fmt.Sprintf("%s in parents and name = %s and trashed = false", quoteQueryValue(parentID), quoteQueryValue(name)),
+		PageSize: 100,
+		OrderBy:  "modifiedTime desc",
+		Fields:   "files(" + pathLookupFields + ")",
+		Corpora:  "allDrives",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var matches []*drive.File
+	for _, f := range resp.Files {
+		// Keep exact matches only; paths are case-sensitive.
+		if f.Name == name {
+			matches = append(matches, f)
+		}
+	}
+	return matches, nil
+}
+
+// quoteQueryValue quotes a string for use in a Drive search query.
+func quoteQueryValue(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
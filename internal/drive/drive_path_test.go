@@ -3,6 +3,7 @@ package drive
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -329,3 +330,163 @@ func TestDriveListIncludesPath(t *testing.T) {
 		t.Errorf("got path %q, want %q", path, "My Drive/Projects")
 	}
 }
+
+// pathFixtures builds My Drive/Reports/2026/{Q3.xlsx, Q4.xlsx (twice)} and a
+// shared drive Engineering/Specs/design.md on a fake tree.
+func pathFixtures(t *testing.T) (*DriveTestFixtures, *fakeDriveTree) {
+	t.Helper()
+	fixtures := NewDriveTestFixtures()
+	tree := newFakeDriveTree(fixtures.MockService)
+	tree.files["root"] = &drive.File{Id: "root", Name: "My Drive", MimeType: folderMimeType}
+	tree.files["drive-eng"] = &drive.File{Id: "drive-eng", Name: "Engineering", MimeType: folderMimeType, DriveId: "drive-eng"}
+	fixtures.MockService.ListDrivesFunc = func(context.Context, int64, string) (*drive.DriveList, error) {
+		return &drive.DriveList{Drives: []*drive.Drive{{Id: "drive-eng", Name: "Engineering"}, {Id: "drive-hr", Name: "HR"}}}, nil
+	}
+
+	reports := tree.add("Reports", "root", folderMimeType, nil)
+	year := tree.add("2026", reports.Id, folderMimeType, nil)
+	tree.add("Q3.xlsx", year.Id, "application/vnd.ms-excel", []byte("q3"))
+	tree.add("Q4.xlsx", year.Id, "application/vnd.ms-excel", []byte("q4"))
+	tree.add("Q4.xlsx", year.Id, "application/vnd.ms-excel", []byte("q4 again"))
+	specs := tree.add("Specs", "drive-eng", folderMimeType, nil)
+	tree.add("design.md", specs.Id, "text/markdown", []byte("# Design"))
+	return fixtures, tree
+}
+
+func TestLookupPath(t *testing.T) {
+	fixtures, tree := pathFixtures(t)
+	resolver := NewPathResolver(fixtures.MockService)
+	ctx := context.Background()
+
+	for path, want := range map[string]string{
+		"/My Drive/Reports/2026/Q3.xlsx":          "Q3.xlsx",
+		"/Reports/2026/Q3.xlsx":                   "Q3.xlsx",
+		"/My Drive/Reports/2026/":                 "2026",
+		"/":                                       "My Drive",
+		"shared:Engineering/Specs/design.md":      "design.md",
+		"/Shared drives/Engineering/Specs":        "Specs",
+		"shared:Engineering":                      "Engineering",
+		"/My Drive//Reports/2026/../2026/Q3.xlsx": "",
+	} {
+		file, err := resolver.LookupPath(ctx, path)
+		if want == "" {
+			if err == nil {
+				t.Errorf("%s: expected an error", path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		if file.Name != want {
+			t.Errorf("%s: got %s, want %s", path, file.Name, want)
+		}
+	}
+	if f, _ := resolver.LookupPath(ctx, "shared:Engineering/Specs/design.md"); f == nil || f.Id != tree.find("drive-eng", "Specs/design.md").Id {
+		t.Errorf("expected design.md in the shared drive, got %v", f)
+	}
+
+	for path, want := range map[string]string{
+		"/My Drive/Reports/2025/Q3.xlsx":   "folder /My Drive/Reports/2025 not found",
+		"/My Drive/Reports/2026/Q3.xlsx/x": "/My Drive/Reports/2026/Q3.xlsx is not a folder",
+		"/My Drive/Reports/2026/q3.xlsx":   "/My Drive/Reports/2026/q3.xlsx not found",
+		"shared:Finance/Budget":            `shared drive "Finance" not found`,
+		"/My Drive/Reports/2026/Q4.xlsx":   "is ambiguous",
+		"1AbCdEfGh":                        "is not a path",
+	} {
+		_, err := resolver.LookupPath(ctx, path)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got error %v, want %q", path, err, want)
+		}
+	}
+
+	_, err := resolver.LookupPath(ctx, "/My Drive/Reports/2026/Q4.xlsx")
+	var ambiguous *AmbiguousPathError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+		t.Fatalf("expected two candidates, got %v", err)
+	}
+	for _, c := range ambiguous.Candidates {
+		if !strings.Contains(err.Error(), c.Id) {
+			t.Errorf("expected the error to list %s: %v", c.Id, err)
+		}
+	}
+}
+
+func TestDriveTools_AcceptPaths(t *testing.T) {
+	fixtures, tree := pathFixtures(t)
+
+	data, errText := runDriveTransfer(t, TestableDriveGet, fixtures, map[string]any{"path": "/My Drive/Reports/2026/Q3.xlsx"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	q3 := tree.find("root", "Reports/2026/Q3.xlsx")
+	if data["id"] != q3.Id {
+		t.Errorf("expected %s, got %v", q3.Id, data["id"])
+	}
+
+	_, errText = runDriveTransfer(t, TestableDriveGet, fixtures, map[string]any{"path": "/My Drive/Reports/2026/Q4.xlsx"})
+	if !strings.Contains(errText, "ambiguous") {
+		t.Errorf("expected an ambiguity error, got %q", errText)
+	}
+	_, errText = runDriveTransfer(t, TestableDriveGet, fixtures, map[string]any{})
+	if errText != "file_id or path parameter is required" {
+		t.Errorf("unexpected error %q", errText)
+	}
+
+	data, errText = runDriveTransfer(t, TestableDriveCopy, fixtures, map[string]any{
+		"file_id":   "shared:Engineering/Specs/design.md",
+		"parent_id": "/My Drive/Reports",
+		"name":      "design copy.md",
+	})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if tree.find("root", "Reports/design copy.md") == nil {
+		t.Errorf("expected the copy in Reports, got %v", data)
+	}
+
+	data, errText = runDriveTransfer(t, TestableDriveList, fixtures, map[string]any{"path": "/My Drive/Reports/2026"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["count"] != float64(3) {
+		t.Errorf("expected 3 files in 2026, got %v", data)
+	}
+}
+
+func TestDriveCreateFolder_Paths(t *testing.T) {
+	fixtures, tree := pathFixtures(t)
+
+	data, errText := runDriveTransfer(t, TestableDriveCreateFolder, fixtures, map[string]any{"path": "/My Drive/Reports/2027/Q1"})
+	if !strings.Contains(errText, "/My Drive/Reports/2027 not found") {
+		t.Fatalf("expected a missing parent error, got %q (%v)", errText, data)
+	}
+
+	data, errText = runDriveTransfer(t, TestableDriveCreateFolder, fixtures, map[string]any{"path": "/My Drive/Reports/2027/Q1", "parents": true})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	q1 := tree.find("root", "Reports/2027/Q1")
+	if q1 == nil || data["folder_id"] != q1.Id || data["created"] != true || len(data["created_folders"].([]any)) != 2 {
+		t.Fatalf("expected 2027 and Q1 to be created, got %v", data)
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveCreateFolder, fixtures, map[string]any{"path": "/My Drive/Reports/2027/Q1", "parents": true})
+	if data["folder_id"] != q1.Id || data["created"] != false {
+		t.Errorf("expected the existing folder to be reused, got %v", data)
+	}
+
+	data, errText = runDriveTransfer(t, TestableDriveCreateFolder, fixtures, map[string]any{"path": "shared:Engineering/Specs/Archive"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if f := tree.find("drive-eng", "Specs/Archive"); f == nil || data["folder_id"] != f.Id {
+		t.Errorf("expected Archive in the shared drive, got %v", data)
+	}
+
+	_, errText = runDriveTransfer(t, TestableDriveCreateFolder, fixtures, map[string]any{"path": "/My Drive/X", "name": "X"})
+	if errText == "" {
+		t.Error("expected an error for both name and path")
+	}
+}
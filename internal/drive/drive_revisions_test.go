@@ -0,0 +1,152 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	from := "one\r\ntwo\r\nthree\r\nfour\r\nfive\r\nsix\r\nseven\r\neight\r\nnine\r\nten\r\n"
+	to := "\ufeffone\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	diff, added, removed, err := unifiedDiff("a", "b", from, to, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "--- a\n+++ b\n@@ -2,3 +2,3 @@\n two\n-three\n+THREE\n four\n@@ -10 +10,2 @@\n ten\n+eleven\n"
+	if diff != want || added != 2 || removed != 1 {
+		t.Errorf("unexpected diff (+%d -%d):\n%s", added, removed, diff)
+	}
+
+	if diff, _, _, _ = unifiedDiff("a", "b", "same\n", "same", 3); diff != "" {
+		t.Errorf("expected no diff for the same lines, got %q", diff)
+	}
+	if diff, _, _, _ = unifiedDiff("a", "b", "", "new\n", 3); diff != "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n" {
+		t.Errorf("unexpected diff from an empty text: %q", diff)
+	}
+
+	var many []string
+	for i := 0; i <= maxDiffEdits; i++ {
+		many = append(many, fmt.Sprint(i))
+	}
+	if _, _, _, err = unifiedDiff("a", "b", "", strings.Join(many, "\n"), 3); err == nil {
+		t.Error("expected an error when too many lines differ")
+	}
+}
+
+// revisionFixtures serves a file with two revisions through the mock
+// service and records restored content.
+func revisionFixtures(mimeType string) (*DriveTestFixtures, *[]string) {
+	fixtures := NewDriveTestFixtures()
+	revisions := map[string]string{"r1": "alpha\nbeta\n", "r2": "alpha\nbeta\ngamma\n"}
+	var uploads []string
+
+	mock := fixtures.MockService
+	mock.GetFileFunc = func(_ context.Context, fileID string, _ string) (*drive.File, error) {
+		return &drive.File{Id: fileID, Name: "notes", MimeType: mimeType}, nil
+	}
+	mock.GetRevisionFunc = func(_ context.Context, _ string, revisionID string, _ string) (*drive.Revision, error) {
+		return &drive.Revision{Id: revisionID, MimeType: "text/plain"}, nil
+	}
+	mock.DownloadRevisionFunc = func(_ context.Context, _ string, revisionID string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(revisions[revisionID])), nil
+	}
+	mock.ExportRevisionFunc = func(_ context.Context, _ string, revisionID string, exportMimeType string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(exportMimeType + ":" + revisions[revisionID])), nil
+	}
+	mock.DownloadFileFunc = func(context.Context, string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("alpha\ndelta\n")), nil
+	}
+	mock.ExportFileFunc = func(_ context.Context, _ string, exportMimeType string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(exportMimeType + ":alpha\ndelta\n")), nil
+	}
+	mock.UpdateFileContentFunc = func(_ context.Context, fileID string, uploadMimeType string, content io.Reader) (*drive.File, error) {
+		data, _ := io.ReadAll(content)
+		uploads = append(uploads, uploadMimeType+" "+string(data))
+		return &drive.File{Id: fileID, Name: "notes", MimeType: mimeType, HeadRevisionId: "r3"}, nil
+	}
+	return fixtures, &uploads
+}
+
+func TestDriveRestoreRevision(t *testing.T) {
+	fixtures, uploads := revisionFixtures("text/plain")
+	data, errText := runDriveTransfer(t, TestableDriveRestoreRevision, fixtures, map[string]any{"file_id": "f1", "revision_id": "r1"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["method"] != "upload" || data["head_revision_id"] != "r3" || (*uploads)[0] != "text/plain alpha\nbeta\n" {
+		t.Errorf("expected r1's content to be uploaded, got %v %q", data, *uploads)
+	}
+
+	fixtures, uploads = revisionFixtures("application/vnd.google-apps.document")
+	data, errText = runDriveTransfer(t, TestableDriveRestoreRevision, fixtures, map[string]any{"file_id": "f1", "revision_id": "r2"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	docx := googleWorkspaceImportMIME["application/vnd.google-apps.document"]
+	if data["method"] != "export_import" || (*uploads)[0] != docx+" "+docx+":alpha\nbeta\ngamma\n" {
+		t.Errorf("expected r2 to be exported and imported as docx, got %v %q", data, *uploads)
+	}
+
+	fixtures, _ = revisionFixtures("application/vnd.google-apps.form")
+	if _, errText = runDriveTransfer(t, TestableDriveRestoreRevision, fixtures, map[string]any{"file_id": "f1", "revision_id": "r1"}); !strings.Contains(errText, "Cannot restore") {
+		t.Errorf("expected forms to be refused, got %q", errText)
+	}
+}
+
+func TestDrivePinRevision(t *testing.T) {
+	fixtures, _ := revisionFixtures("application/pdf")
+	var sent *drive.Revision
+	fixtures.MockService.UpdateRevisionFunc = func(_ context.Context, _ string, revisionID string, revision *drive.Revision) (*drive.Revision, error) {
+		sent = revision
+		return &drive.Revision{Id: revisionID, KeepForever: revision.KeepForever}, nil
+	}
+
+	data, errText := runDriveTransfer(t, TestableDrivePinRevision, fixtures, map[string]any{"file_id": "f1", "revision_id": "r1"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["keep_forever"] != true || !sent.KeepForever {
+		t.Errorf("expected the revision to be pinned, got %v", data)
+	}
+
+	data, _ = runDriveTransfer(t, TestableDrivePinRevision, fixtures, map[string]any{"file_id": "f1", "revision_id": "r1", "keep_forever": false})
+	if data["keep_forever"] != false || len(sent.ForceSendFields) != 1 {
+		t.Errorf("expected keepForever=false to be sent, got %v %+v", data, sent)
+	}
+
+	fixtures, _ = revisionFixtures("application/vnd.google-apps.spreadsheet")
+	if _, errText = runDriveTransfer(t, TestableDrivePinRevision, fixtures, map[string]any{"file_id": "f1", "revision_id": "r1"}); !strings.Contains(errText, "binary files") {
+		t.Errorf("expected Google Sheets revisions to be refused, got %q", errText)
+	}
+}
+
+func TestDriveDiffRevisions(t *testing.T) {
+	fixtures, _ := revisionFixtures("text/plain")
+	data, errText := runDriveTransfer(t, TestableDriveDiffRevisions, fixtures, map[string]any{"file_id": "f1", "from_revision_id": "r1", "to_revision_id": "r2"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["diff"] != "--- notes@r1\n+++ notes@r2\n@@ -1,2 +1,3 @@\n alpha\n beta\n+gamma\n" || data["lines_added"] != float64(1) {
+		t.Errorf("unexpected diff: %v", data)
+	}
+
+	fixtures, _ = revisionFixtures("application/vnd.google-apps.document")
+	data, errText = runDriveTransfer(t, TestableDriveDiffRevisions, fixtures, map[string]any{"file_id": "f1", "from_revision_id": "r1", "context_lines": float64(0)})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["to_revision_id"] != "head" || data["diff"] != "--- notes@r1\n+++ notes@head\n@@ -2 +2 @@\n-beta\n+delta\n" {
+		t.Errorf("expected r1 to be compared with the exported current version, got %v", data)
+	}
+
+	fixtures, _ = revisionFixtures("image/png")
+	if _, errText = runDriveTransfer(t, TestableDriveDiffRevisions, fixtures, map[string]any{"file_id": "f1", "from_revision_id": "r1"}); !strings.Contains(errText, "binary") {
+		t.Errorf("expected binary files to be refused, got %q", errText)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -49,7 +51,7 @@ func TestableDriveListRevisions(ctx context.Context, request mcp.CallToolRequest
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -84,7 +86,7 @@ func TestableDriveGetRevision(ctx context.Context, request mcp.CallToolRequest,
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -112,7 +114,7 @@ func TestableDriveDownloadRevision(ctx context.Context, request mcp.CallToolRequ
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -159,3 +161,226 @@ func TestableDriveDownloadRevision(ctx context.Context, request mcp.CallToolRequ
 
 	return common.MarshalToolResult(result)
 }
+
+// googleWorkspaceImportMIME maps Google Workspace MIME types to the Office
+// format a revision is exported to and imported back from when restored.
+var googleWorkspaceImportMIME = map[string]string{
+	"application/vnd.google-apps.document":     "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.google-apps.spreadsheet":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.google-apps.presentation": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
+// isGoogleWorkspaceMimeType reports whether a file is a Google Docs, Sheets,
+// Slides or other Google Workspace file, whose revisions can only be exported.
+func isGoogleWorkspaceMimeType(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "application/vnd.google-apps.")
+}
+
+// TestableDriveRestoreRevision makes an earlier revision the file's current
+// content. Binary files get the revision's content uploaded as a new
+// revision; Google Workspace files are exported to an Office format and
+// imported back.
+func TestableDriveRestoreRevision(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
+	if idErrResult != nil {
+		return idErrResult, nil
+	}
+
+	revisionID, errResult := common.RequireStringArg(request.GetArguments(), "revision_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	file, err := srv.GetFile(ctx, fileID, DriveFileDownloadFields)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error getting file info: %v", err)), nil
+	}
+
+	var body io.ReadCloser
+	var mimeType, method string
+	if isGoogleWorkspaceMimeType(file.MimeType) {
+		var ok bool
+		if mimeType, ok = googleWorkspaceImportMIME[file.MimeType]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Cannot restore revisions of Google Workspace files of type: %s", file.MimeType)), nil
+		}
+		method = "export_import"
+		body, err = srv.ExportRevision(ctx, fileID, revisionID, mimeType)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error exporting revision: %v", err)), nil
+		}
+	} else {
+		revision, err := srv.GetRevision(ctx, fileID, revisionID, "id,mimeType")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error getting revision: %v", err)), nil
+		}
+		mimeType = revision.MimeType
+		if mimeType == "" {
+			mimeType = file.MimeType
+		}
+		method = "upload"
+		body, err = srv.DownloadRevision(ctx, fileID, revisionID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error downloading revision: %v", err)), nil
+		}
+	}
+	defer body.Close()
+
+	updated, err := srv.UpdateFileContent(ctx, fileID, mimeType, body)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error restoring revision: %v", err)), nil
+	}
+
+	result := map[string]any{
+		"file_id":              fileID,
+		"restored_revision_id": revisionID,
+		"method":               method,
+		"file":                 formatFile(updated),
+	}
+	if updated.HeadRevisionId != "" {
+		result["head_revision_id"] = updated.HeadRevisionId
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// TestableDrivePinRevision sets whether a revision is kept forever. Only
+// revisions of binary files can be pinned; Google Workspace files keep
+// their history on their own terms.
+func TestableDrivePinRevision(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
+	if idErrResult != nil {
+		return idErrResult, nil
+	}
+
+	revisionID, errResult := common.RequireStringArg(request.GetArguments(), "revision_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	keepForever := common.ParseBoolArg(request.GetArguments(), "keep_forever", true)
+
+	file, err := srv.GetFile(ctx, fileID, DriveFileDownloadFields)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error getting file info: %v", err)), nil
+	}
+	if isGoogleWorkspaceMimeType(file.MimeType) {
+		return mcp.NewToolResultError("Only revisions of binary files can be pinned; Google Docs, Sheets and Slides revisions cannot be kept forever"), nil
+	}
+
+	revision, err := srv.UpdateRevision(ctx, fileID, revisionID, &drive.Revision{
+		KeepForever:     keepForever,
+		ForceSendFields: []string{"KeepForever"},
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error updating revision: %v", err)), nil
+	}
+
+	result := formatRevision(revision)
+	result["file_id"] = fileID
+	result["keep_forever"] = revision.KeepForever
+
+	return common.MarshalToolResult(result)
+}
+
+// TestableDriveDiffRevisions returns a unified diff between two revisions of
+// a text file or Google Workspace file, or between a revision and the
+// current content.
+func TestableDriveDiffRevisions(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
+	if idErrResult != nil {
+		return idErrResult, nil
+	}
+
+	args := request.GetArguments()
+	fromID, errResult := common.RequireStringArg(args, "from_revision_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	toID := common.ParseStringArg(args, "to_revision_id", "")
+	contextLines := 3
+	if n, ok := args["context_lines"].(float64); ok && n >= 0 {
+		contextLines = int(n) // ParseIntArg would drop 0
+	}
+
+	file, err := srv.GetFile(ctx, fileID, DriveFileDownloadFields)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error getting file info: %v", err)), nil
+	}
+	if !isGoogleWorkspaceMimeType(file.MimeType) && !isTextMimeType(file.MimeType) {
+		return mcp.NewToolResultError(fmt.Sprintf("Cannot diff revisions of a binary file of type: %s", file.MimeType)), nil
+	}
+
+	from, errResult := downloadRevisionContent(ctx, srv, fileID, fromID, file)
+	if errResult != nil {
+		return errResult, nil
+	}
+	var to []byte
+	if toID == "" || toID == "head" {
+		toID = "head"
+		to, _, errResult = downloadFileContent(ctx, srv, fileID, file)
+	} else {
+		to, errResult = downloadRevisionContent(ctx, srv, fileID, toID, file)
+	}
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	diff, added, removed, err := unifiedDiff(file.Name+"@"+fromID, file.Name+"@"+toID, string(from), string(to), contextLines)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Cannot diff revisions: %v", err)), nil
+	}
+
+	result := map[string]any{
+		"file_id":          fileID,
+		"name":             file.Name,
+		"from_revision_id": fromID,
+		"to_revision_id":   toID,
+		"lines_added":      added,
+		"lines_removed":    removed,
+		"identical":        diff == "",
+		"diff":             diff,
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// downloadRevisionContent reads a revision's content the way
+// downloadFileContent reads the current one: Google Workspace files are
+// exported as text, other files are downloaded as they are.
+func downloadRevisionContent(ctx context.Context, srv DriveService, fileID, revisionID string, file *drive.File) ([]byte, *mcp.CallToolResult) {
+	var body io.ReadCloser
+	var err error
+	if isGoogleWorkspaceMimeType(file.MimeType) {
+		exportMimeType, ok := googleWorkspaceExportMIME[file.MimeType]
+		if !ok {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("Cannot export Google Workspace file of type: %s", file.MimeType))
+		}
+		body, err = srv.ExportRevision(ctx, fileID, revisionID, exportMimeType)
+	} else {
+		body, err = srv.DownloadRevision(ctx, fileID, revisionID)
+	}
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Drive API error reading revision %s: %v", revisionID, err))
+	}
+	defer body.Close()
+
+	content, err := readLimited(body)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Error reading revision content: %v", err))
+	}
+	return content, nil
+}
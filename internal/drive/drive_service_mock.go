@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/drivelabels/v2"
 )
 
 // MockDriveService implements DriveService for testing.
@@ -21,13 +22,17 @@ type MockDriveService struct {
 	ExportFileFunc   func(ctx context.Context, fileID string, mimeType string) (io.ReadCloser, error)
 
 	// Drives
-	GetDriveFunc   func(ctx context.Context, driveID string) (*drive.Drive, error)
-	ListDrivesFunc func(ctx context.Context, pageSize int64, pageToken string) (*drive.DriveList, error)
+	GetDriveFunc       func(ctx context.Context, driveID string) (*drive.Drive, error)
+	ListDrivesFunc     func(ctx context.Context, pageSize int64, pageToken string) (*drive.DriveList, error)
+	CreateDriveFunc    func(ctx context.Context, requestID string, d *drive.Drive) (*drive.Drive, error)
+	UpdateDriveFunc    func(ctx context.Context, driveID string, d *drive.Drive) (*drive.Drive, error)
+	SetDriveHiddenFunc func(ctx context.Context, driveID string, hidden bool) (*drive.Drive, error)
 
 	// Permissions
 	ListPermissionsFunc  func(ctx context.Context, fileID string) (*drive.PermissionList, error)
 	CreatePermissionFunc func(ctx context.Context, fileID string, permission *drive.Permission, sendNotification bool) (*drive.Permission, error)
 	DeletePermissionFunc func(ctx context.Context, fileID string, permissionID string) error
+	UpdatePermissionFunc func(ctx context.Context, fileID string, permissionID string, permission *drive.Permission, transferOwnership bool) (*drive.Permission, error)
 
 	// Comments
 	ListCommentsFunc  func(ctx context.Context, fileID string, fields string, pageSize int64, pageToken string, includeDeleted bool) (*drive.CommentList, error)
@@ -44,6 +49,24 @@ type MockDriveService struct {
 	ListRevisionsFunc    func(ctx context.Context, fileID string, fields string, pageSize int64, pageToken string) (*drive.RevisionList, error)
 	GetRevisionFunc      func(ctx context.Context, fileID string, revisionID string, fields string) (*drive.Revision, error)
 	DownloadRevisionFunc func(ctx context.Context, fileID string, revisionID string) (io.ReadCloser, error)
+	ExportRevisionFunc   func(ctx context.Context, fileID string, revisionID string, mimeType string) (io.ReadCloser, error)
+	UpdateRevisionFunc   func(ctx context.Context, fileID string, revisionID string, revision *drive.Revision) (*drive.Revision, error)
+
+	// Transfers
+	DownloadFileRangeFunc func(ctx context.Context, fileID string, offset int64) (io.ReadCloser, error)
+	StartUploadFunc       func(ctx context.Context, fileID string, file *drive.File, size int64) (string, error)
+	UploadStatusFunc      func(ctx context.Context, sessionURI string, size int64) (int64, *drive.File, error)
+	UploadChunkFunc       func(ctx context.Context, sessionURI string, chunk io.Reader, offset, length, size int64) (int64, *drive.File, error)
+	UpdateFileContentFunc func(ctx context.Context, fileID string, mimeType string, content io.Reader) (*drive.File, error)
+
+	// Changes
+	GetStartPageTokenFunc func(ctx context.Context, driveID string) (string, error)
+	ListChangesFunc       func(ctx context.Context, pageToken string, opts *ListChangesOptions) (*drive.ChangeList, error)
+
+	// Labels
+	ListLabelsFunc       func(ctx context.Context, minimumRole string, pageSize int64, pageToken string) (*drivelabels.GoogleAppsDriveLabelsV2ListLabelsResponse, error)
+	ListFileLabelsFunc   func(ctx context.Context, fileID string, pageToken string) (*drive.LabelList, error)
+	ModifyFileLabelsFunc func(ctx context.Context, fileID string, req *drive.ModifyLabelsRequest) (*drive.ModifyLabelsResponse, error)
 }
 
 // File methods
@@ -127,6 +150,31 @@ func (m *MockDriveService) ListDrives(ctx context.Context, pageSize int64, pageT
 	return &drive.DriveList{}, nil
 }
 
+func (m *MockDriveService) CreateDrive(ctx context.Context, requestID string, d *drive.Drive) (*drive.Drive, error) {
+	if m.CreateDriveFunc != nil {
+		return m.CreateDriveFunc(ctx, requestID, d)
+	}
+	created := *d
+	created.Id = "drive-" + requestID
+	return &created, nil
+}
+
+func (m *MockDriveService) UpdateDrive(ctx context.Context, driveID string, d *drive.Drive) (*drive.Drive, error) {
+	if m.UpdateDriveFunc != nil {
+		return m.UpdateDriveFunc(ctx, driveID, d)
+	}
+	updated := *d
+	updated.Id = driveID
+	return &updated, nil
+}
+
+func (m *MockDriveService) SetDriveHidden(ctx context.Context, driveID string, hidden bool) (*drive.Drive, error) {
+	if m.SetDriveHiddenFunc != nil {
+		return m.SetDriveHiddenFunc(ctx, driveID, hidden)
+	}
+	return &drive.Drive{Id: driveID, Hidden: hidden}, nil
+}
+
 // Permission methods
 
 func (m *MockDriveService) ListPermissions(ctx context.Context, fileID string) (*drive.PermissionList, error) {
@@ -150,6 +198,15 @@ func (m *MockDriveService) DeletePermission(ctx context.Context, fileID string,
 	return nil
 }
 
+func (m *MockDriveService) UpdatePermission(ctx context.Context, fileID string, permissionID string, permission *drive.Permission, transferOwnership bool) (*drive.Permission, error) {
+	if m.UpdatePermissionFunc != nil {
+		return m.UpdatePermissionFunc(ctx, fileID, permissionID, permission, transferOwnership)
+	}
+	updated := *permission
+	updated.Id = permissionID
+	return &updated, nil
+}
+
 // Comment methods
 
 func (m *MockDriveService) ListComments(ctx context.Context, fileID string, fields string, pageSize int64, pageToken string, includeDeleted bool) (*drive.CommentList, error) {
@@ -225,3 +282,93 @@ func (m *MockDriveService) DownloadRevision(ctx context.Context, fileID string,
 	}
 	return nil, nil
 }
+
+func (m *MockDriveService) ExportRevision(ctx context.Context, fileID string, revisionID string, mimeType string) (io.ReadCloser, error) {
+	if m.ExportRevisionFunc != nil {
+		return m.ExportRevisionFunc(ctx, fileID, revisionID, mimeType)
+	}
+	return nil, nil
+}
+
+func (m *MockDriveService) UpdateRevision(ctx context.Context, fileID string, revisionID string, revision *drive.Revision) (*drive.Revision, error) {
+	if m.UpdateRevisionFunc != nil {
+		return m.UpdateRevisionFunc(ctx, fileID, revisionID, revision)
+	}
+	return revision, nil
+}
+
+// Transfer methods
+
+func (m *MockDriveService) DownloadFileRange(ctx context.Context, fileID string, offset int64) (io.ReadCloser, error) {
+	if m.DownloadFileRangeFunc != nil {
+		return m.DownloadFileRangeFunc(ctx, fileID, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockDriveService) StartUpload(ctx context.Context, fileID string, file *drive.File, size int64) (string, error) {
+	if m.StartUploadFunc != nil {
+		return m.StartUploadFunc(ctx, fileID, file, size)
+	}
+	return "", nil
+}
+
+func (m *MockDriveService) UploadStatus(ctx context.Context, sessionURI string, size int64) (int64, *drive.File, error) {
+	if m.UploadStatusFunc != nil {
+		return m.UploadStatusFunc(ctx, sessionURI, size)
+	}
+	return 0, nil, nil
+}
+
+func (m *MockDriveService) UploadChunk(ctx context.Context, sessionURI string, chunk io.Reader, offset, length, size int64) (int64, *drive.File, error) {
+	if m.UploadChunkFunc != nil {
+		return m.UploadChunkFunc(ctx, sessionURI, chunk, offset, length, size)
+	}
+	return offset + length, nil, nil
+}
+
+func (m *MockDriveService) UpdateFileContent(ctx context.Context, fileID string, mimeType string, content io.Reader) (*drive.File, error) {
+	if m.UpdateFileContentFunc != nil {
+		return m.UpdateFileContentFunc(ctx, fileID, mimeType, content)
+	}
+	return &drive.File{Id: fileID, MimeType: mimeType}, nil
+}
+
+// Change methods
+
+func (m *MockDriveService) GetStartPageToken(ctx context.Context, driveID string) (string, error) {
+	if m.GetStartPageTokenFunc != nil {
+		return m.GetStartPageTokenFunc(ctx, driveID)
+	}
+	return "1", nil
+}
+
+func (m *MockDriveService) ListChanges(ctx context.Context, pageToken string, opts *ListChangesOptions) (*drive.ChangeList, error) {
+	if m.ListChangesFunc != nil {
+		return m.ListChangesFunc(ctx, pageToken, opts)
+	}
+	return &drive.ChangeList{NewStartPageToken: pageToken}, nil
+}
+
+// Label methods
+
+func (m *MockDriveService) ListLabels(ctx context.Context, minimumRole string, pageSize int64, pageToken string) (*drivelabels.GoogleAppsDriveLabelsV2ListLabelsResponse, error) {
+	if m.ListLabelsFunc != nil {
+		return m.ListLabelsFunc(ctx, minimumRole, pageSize, pageToken)
+	}
+	return &drivelabels.GoogleAppsDriveLabelsV2ListLabelsResponse{}, nil
+}
+
+func (m *MockDriveService) ListFileLabels(ctx context.Context, fileID string, pageToken string) (*drive.LabelList, error) {
+	if m.ListFileLabelsFunc != nil {
+		return m.ListFileLabelsFunc(ctx, fileID, pageToken)
+	}
+	return &drive.LabelList{}, nil
+}
+
+func (m *MockDriveService) ModifyFileLabels(ctx context.Context, fileID string, req *drive.ModifyLabelsRequest) (*drive.ModifyLabelsResponse, error) {
+	if m.ModifyFileLabelsFunc != nil {
+		return m.ModifyFileLabelsFunc(ctx, fileID, req)
+	}
+	return &drive.ModifyLabelsResponse{}, nil
+}
@@ -0,0 +1,167 @@
+package drive
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/drive/v3"
+)
+
+// sharedDriveFixtures serves the three shared drives known to newTestFilter
+// through a filter that blocks SENSITIVE.
+func sharedDriveFixtures(t *testing.T) (*DriveTestFixtures, map[string]*drive.Drive, *[]string) {
+	t.Helper()
+	fixtures := NewDriveTestFixtures()
+	drives := map[string]*drive.Drive{
+		"drive-marketing": {Id: "drive-marketing", Name: "Marketing"},
+		"drive-sensitive": {Id: "drive-sensitive", Name: "SENSITIVE"},
+		"drive-hr":        {Id: "drive-hr", Name: "HR", Hidden: true},
+	}
+	var calls []string
+
+	mock := fixtures.MockService
+	mock.ListDrivesFunc = func(context.Context, int64, string) (*drive.DriveList, error) {
+		list := &drive.DriveList{}
+		for _, id := range []string{"drive-hr", "drive-marketing", "drive-sensitive"} {
+			list.Drives = append(list.Drives, drives[id])
+		}
+		return list, nil
+	}
+	mock.GetDriveFunc = func(_ context.Context, id string) (*drive.Drive, error) {
+		return drives[id], nil
+	}
+	mock.UpdateDriveFunc = func(_ context.Context, id string, d *drive.Drive) (*drive.Drive, error) {
+		calls = append(calls, "update "+id)
+		if d.Name != "" {
+			drives[id].Name = d.Name
+		}
+		if d.Restrictions != nil {
+			drives[id].Restrictions = d.Restrictions
+		}
+		return drives[id], nil
+	}
+	mock.SetDriveHiddenFunc = func(_ context.Context, id string, hidden bool) (*drive.Drive, error) {
+		calls = append(calls, "hide "+id)
+		drives[id].Hidden = hidden
+		return drives[id], nil
+	}
+	filtered := NewFilteredDriveService(mock, newTestFilter(nil, []string{"SENSITIVE"}))
+	fixtures.Deps.ServiceFactory = &common.MockServiceFactory[DriveService]{MockService: filtered}
+	return fixtures, drives, &calls
+}
+
+func TestDriveListSharedDrives_HidesBlockedDrives(t *testing.T) {
+	fixtures, _, _ := sharedDriveFixtures(t)
+
+	data, errText := runDriveTransfer(t, TestableDriveListSharedDrives, fixtures, map[string]any{})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	drives := data["drives"].([]any)
+	if len(drives) != 1 || drives[0].(map[string]any)["name"] != "Marketing" || data["hidden_count"] != float64(1) {
+		t.Errorf("expected only Marketing with HR hidden, got %v", data)
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveListSharedDrives, fixtures, map[string]any{"include_hidden": true})
+	var names []string
+	for _, d := range data["drives"].([]any) {
+		names = append(names, d.(map[string]any)["name"].(string))
+	}
+	if strings.Join(names, ",") != "HR,Marketing" {
+		t.Errorf("expected HR and Marketing, got %v", names)
+	}
+}
+
+func TestDriveUpdateSharedDrive(t *testing.T) {
+	fixtures, drives, calls := sharedDriveFixtures(t)
+
+	data, errText := runDriveTransfer(t, TestableDriveUpdateSharedDrive, fixtures, map[string]any{
+		"drive_name":                      "marketing",
+		"name":                            "Marketing Team",
+		"domain_users_only":               true,
+		"copy_requires_writer_permission": false,
+	})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	r := drives["drive-marketing"].Restrictions
+	if data["name"] != "Marketing Team" || !r.DomainUsersOnly || !slices.Contains(r.ForceSendFields, "CopyRequiresWriterPermission") {
+		t.Errorf("unexpected update: %v %+v", data, r)
+	}
+
+	data, errText = runDriveTransfer(t, TestableDriveUpdateSharedDrive, fixtures, map[string]any{"drive_id": "drive-hr", "hidden": false})
+	if errText != "" || data["hidden"] != nil {
+		t.Errorf("expected HR to be unhidden, got %v %q", data, errText)
+	}
+
+	for _, args := range []map[string]any{
+		{"drive_id": "drive-sensitive", "name": "Leaked"},
+		{"drive_id": "drive-sensitive", "hidden": true},
+		{"drive_name": "SENSITIVE", "name": "Leaked"},
+	} {
+		if _, errText = runDriveTransfer(t, TestableDriveUpdateSharedDrive, fixtures, args); errText == "" {
+			t.Errorf("expected %v to be refused", args)
+		}
+	}
+	if drives["drive-sensitive"].Name != "SENSITIVE" || strings.Join(*calls, ",") != "update drive-marketing,hide drive-hr" {
+		t.Errorf("expected the blocked drive to be untouched, got calls %v", *calls)
+	}
+
+	if _, errText = runDriveTransfer(t, TestableDriveUpdateSharedDrive, fixtures, map[string]any{"drive_id": "drive-hr"}); !strings.Contains(errText, "nothing to update") {
+		t.Errorf("expected a nothing-to-update error, got %q", errText)
+	}
+}
+
+func TestDriveSharedDriveMembers(t *testing.T) {
+	fixtures, _, _ := sharedDriveFixtures(t)
+	fp := newFakePermissions(fixtures.MockService, map[string][]*drive.Permission{
+		"drive-marketing": {
+			{Id: "p-me", Type: "user", Role: "organizer", EmailAddress: "test@example.com"},
+			{Id: "p-bob", Type: "user", Role: "reader", EmailAddress: "bob@example.com"},
+		},
+		"drive-sensitive": {{Id: "p-secret", Type: "user", Role: "organizer", EmailAddress: "ceo@example.com"}},
+	})
+	fixtures.MockService.GetFileFunc = func(_ context.Context, fileID, _ string) (*drive.File, error) {
+		return &drive.File{Id: fileID, DriveId: fileID}, nil
+	}
+
+	data, errText := runDriveTransfer(t, TestableDriveSetSharedDriveMember, fixtures, map[string]any{"drive_id": "drive-marketing", "email": "Bob@example.com", "role": "fileOrganizer"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["action"] != "updated" || data["previous_role"] != "reader" || fp.updates[0] != "drive-marketing/p-bob fileOrganizer false" {
+		t.Errorf("expected bob to be promoted, got %v %v", data, fp.updates)
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveSetSharedDriveMember, fixtures, map[string]any{"drive_id": "drive-marketing", "email": "carol@example.com", "role": "writer"})
+	if data["action"] != "added" {
+		t.Errorf("expected carol to be added, got %v", data)
+	}
+	if _, errText = runDriveTransfer(t, TestableDriveSetSharedDriveMember, fixtures, map[string]any{"drive_id": "drive-marketing", "email": "carol@example.com", "role": "owner"}); !strings.Contains(errText, "invalid role") {
+		t.Errorf("expected an invalid role error, got %q", errText)
+	}
+
+	data, errText = runDriveTransfer(t, TestableDriveRemoveSharedDriveMember, fixtures, map[string]any{"drive_name": "Marketing", "email": "bob@example.com"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	data, _ = runDriveTransfer(t, TestableDriveListSharedDriveMembers, fixtures, map[string]any{"drive_id": "drive-marketing"})
+	if data["count"] != float64(2) {
+		t.Errorf("expected 2 members left, got %v", data)
+	}
+
+	for _, fn := range []func(context.Context, mcp.CallToolRequest, *DriveHandlerDeps) (*mcp.CallToolResult, error){
+		TestableDriveListSharedDriveMembers, TestableDriveSetSharedDriveMember, TestableDriveRemoveSharedDriveMember,
+	} {
+		if _, errText = runDriveTransfer(t, fn, fixtures, map[string]any{"drive_id": "drive-sensitive", "email": "ceo@example.com", "role": "reader"}); errText == "" {
+			t.Error("expected the blocked drive's members to be inaccessible")
+		}
+	}
+	if got := fp.ids("drive-sensitive"); len(got) != 1 {
+		t.Errorf("expected the blocked drive's members to be untouched, got %v", got)
+	}
+}
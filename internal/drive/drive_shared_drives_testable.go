@@ -0,0 +1,386 @@
+package drive
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/drive/v3"
+)
+
+// sharedDriveMemberRoles are the roles a shared drive member can hold.
+// "organizer" is shown as Manager in the Drive UI, "fileOrganizer" as
+// Content manager.
+var sharedDriveMemberRoles = map[string]bool{
+	"organizer":     true,
+	"fileOrganizer": true,
+	"writer":        true,
+	"commenter":     true,
+	"reader":        true,
+}
+
+// listSharedDrives returns every shared drive the service exposes. Through a
+// FilteredDriveService, blocked drives are already left out.
+func listSharedDrives(ctx context.Context, srv DriveService) ([]*drive.Drive, error) {
+	var drives []*drive.Drive
+	pageToken := ""
+	for {
+		resp, err := srv.ListDrives(ctx, 100, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		drives = append(drives, resp.Drives...)
+		if resp.NextPageToken == "" {
+			return drives, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// resolveSharedDrive returns the shared drive named by the drive_id or
+// drive_name parameter. Names are matched case-insensitively against the
+// drives the access filter allows, so a blocked drive is reported as not
+// found rather than revealed.
+func resolveSharedDrive(ctx context.Context, srv DriveService, args map[string]any) (*drive.Drive, *mcp.CallToolResult) {
+	if driveID := common.ParseStringArg(args, "drive_id", ""); driveID != "" {
+		d, err := srv.GetDrive(ctx, driveID)
+		if err != nil {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err))
+		}
+		return d, nil
+	}
+	name := common.ParseStringArg(args, "drive_name", "")
+	if name == "" {
+		return nil, mcp.NewToolResultError("drive_id or drive_name parameter is required")
+	}
+	drives, err := listSharedDrives(ctx, srv)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err))
+	}
+	var matches []*drive.Drive
+	for _, d := range drives {
+		if strings.EqualFold(d.Name, name) {
+			matches = append(matches, d)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, mcp.NewToolResultError(fmt.Sprintf("shared drive %q not found", name))
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, 0, len(matches))
+	for _, d := range matches {
+		ids = append(ids, d.Id)
+	}
+	return nil, mcp.NewToolResultError(fmt.Sprintf("%d shared drives are named %q, use drive_id: %s", len(matches), name, strings.Join(ids, ", ")))
+}
+
+// formatSharedDrive formats a shared drive for output.
+func formatSharedDrive(d *drive.Drive) map[string]any {
+	result := map[string]any{
+		"id":   d.Id,
+		"name": d.Name,
+	}
+	if d.Hidden {
+		result["hidden"] = true
+	}
+	if d.CreatedTime != "" {
+		result["created_time"] = d.CreatedTime
+	}
+	if r := d.Restrictions; r != nil {
+		result["restrictions"] = map[string]any{
+			"admin_managed_restrictions":      r.AdminManagedRestrictions,
+			"copy_requires_writer_permission": r.CopyRequiresWriterPermission,
+			"domain_users_only":               r.DomainUsersOnly,
+			"drive_members_only":              r.DriveMembersOnly,
+		}
+	}
+	if c := d.Capabilities; c != nil {
+		result["can_manage_members"] = c.CanManageMembers
+	}
+	return result
+}
+
+// newDriveRequestID returns a random request ID for drives.create.
+func newDriveRequestID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// TestableDriveListSharedDrives lists the shared drives the account can see.
+func TestableDriveListSharedDrives(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	nameContains := strings.ToLower(common.ParseStringArg(args, "name_contains", ""))
+	includeHidden := common.ParseBoolArg(args, "include_hidden", false)
+
+	drives, err := listSharedDrives(ctx, srv)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+	}
+
+	formatted := make([]map[string]any, 0, len(drives))
+	hidden := 0
+	for _, d := range drives {
+		if nameContains != "" && !strings.Contains(strings.ToLower(d.Name), nameContains) {
+			continue
+		}
+		if d.Hidden && !includeHidden {
+			hidden++
+			continue
+		}
+		formatted = append(formatted, formatSharedDrive(d))
+	}
+	sort.SliceStable(formatted, func(i, j int) bool {
+		return strings.ToLower(formatted[i]["name"].(string)) < strings.ToLower(formatted[j]["name"].(string))
+	})
+
+	result := map[string]any{
+		"drives": formatted,
+		"count":  len(formatted),
+	}
+	if hidden > 0 {
+		result["hidden_count"] = hidden
+	}
+	return common.MarshalToolResult(result)
+}
+
+// TestableDriveCreateSharedDrive creates a shared drive.
+func TestableDriveCreateSharedDrive(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	name, errResult := common.RequireStringArg(args, "name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	requestID := common.ParseStringArg(args, "request_id", "")
+	if requestID == "" {
+		var err error
+		if requestID, err = newDriveRequestID(); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to generate request ID: %v", err)), nil
+		}
+	}
+
+	created, err := srv.CreateDrive(ctx, requestID, &drive.Drive{Name: name})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+	}
+
+	result := formatSharedDrive(created)
+	result["request_id"] = requestID
+	return common.MarshalToolResult(result)
+}
+
+// TestableDriveUpdateSharedDrive renames a shared drive, hides or unhides it,
+// or changes its restrictions.
+func TestableDriveUpdateSharedDrive(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	current, errResult := resolveSharedDrive(ctx, srv, args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	update := &drive.Drive{}
+	changed := false
+	if name := common.ParseStringArg(args, "name", ""); name != "" {
+		update.Name = name
+		changed = true
+	}
+	restrictions := &drive.DriveRestrictions{}
+	for _, r := range []struct {
+		arg, field string
+		set        *bool
+	}{
+		{"admin_managed_restrictions", "AdminManagedRestrictions", &restrictions.AdminManagedRestrictions},
+		{"copy_requires_writer_permission", "CopyRequiresWriterPermission", &restrictions.CopyRequiresWriterPermission},
+		{"domain_users_only", "DomainUsersOnly", &restrictions.DomainUsersOnly},
+		{"drive_members_only", "DriveMembersOnly", &restrictions.DriveMembersOnly},
+	} {
+		if v, ok := args[r.arg].(bool); ok {
+			*r.set = v
+			// false is the zero value, so it must be sent explicitly.
+			restrictions.ForceSendFields = append(restrictions.ForceSendFields, r.field)
+		}
+	}
+	if len(restrictions.ForceSendFields) > 0 {
+		update.Restrictions = restrictions
+		changed = true
+	}
+	hidden, setHidden := args["hidden"].(bool)
+	if !changed && !setHidden {
+		return mcp.NewToolResultError("nothing to update: pass name, hidden or a restriction"), nil
+	}
+
+	updated := current
+	if changed {
+		var err error
+		if updated, err = srv.UpdateDrive(ctx, current.Id, update); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+		}
+	}
+	if setHidden && hidden != current.Hidden {
+		var err error
+		if updated, err = srv.SetDriveHidden(ctx, current.Id, hidden); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+		}
+	}
+	return common.MarshalToolResult(formatSharedDrive(updated))
+}
+
+// TestableDriveListSharedDriveMembers lists the members of a shared drive.
+func TestableDriveListSharedDriveMembers(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	d, errResult := resolveSharedDrive(ctx, srv, request.GetArguments())
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	resp, err := srv.ListPermissions(ctx, d.Id)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+	}
+
+	members := make([]map[string]any, 0, len(resp.Permissions))
+	for _, p := range resp.Permissions {
+		members = append(members, formatPermission(p))
+	}
+
+	result := map[string]any{
+		"drive_id":   d.Id,
+		"drive_name": d.Name,
+		"members":    members,
+		"count":      len(members),
+	}
+	return common.MarshalToolResult(result)
+}
+
+// TestableDriveSetSharedDriveMember adds a member to a shared drive, or
+// changes the role of an existing member.
+func TestableDriveSetSharedDriveMember(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	email, errResult := common.RequireStringArg(args, "email")
+	if errResult != nil {
+		return errResult, nil
+	}
+	role, errResult := common.RequireStringArg(args, "role")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if !sharedDriveMemberRoles[role] {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid role %q: use organizer, fileOrganizer, writer, commenter or reader", role)), nil
+	}
+	permType := common.ParseStringArg(args, "type", "user")
+
+	d, errResult := resolveSharedDrive(ctx, srv, args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	resp, err := srv.ListPermissions(ctx, d.Id)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error listing members: %v", err)), nil
+	}
+	var existing *drive.Permission
+	for _, p := range resp.Permissions {
+		if strings.EqualFold(p.EmailAddress, email) || (permType == "domain" && strings.EqualFold(p.Domain, email)) {
+			existing = p
+			break
+		}
+	}
+
+	result := map[string]any{
+		"drive_id":   d.Id,
+		"drive_name": d.Name,
+	}
+	switch {
+	case existing == nil:
+		permission := &drive.Permission{Type: permType, Role: role}
+		if permType == "domain" {
+			permission.Domain = email
+		} else {
+			permission.EmailAddress = email
+		}
+		created, err := srv.CreatePermission(ctx, d.Id, permission, common.ParseBoolArg(args, "send_notification", true))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+		}
+		result["action"] = "added"
+		result["member"] = formatPermission(created)
+	case existing.Role == role:
+		result["action"] = "unchanged"
+		result["member"] = formatPermission(existing)
+	default:
+		previousRole := existing.Role
+		updated, err := srv.UpdatePermission(ctx, d.Id, existing.Id, &drive.Permission{Role: role}, false)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+		}
+		result["action"] = "updated"
+		result["previous_role"] = previousRole
+		result["member"] = formatPermission(updated)
+	}
+	return common.MarshalToolResult(result)
+}
+
+// TestableDriveRemoveSharedDriveMember removes a member from a shared drive.
+func TestableDriveRemoveSharedDriveMember(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	d, errResult := resolveSharedDrive(ctx, srv, args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	member, errResult := findPermission(ctx, srv, d.Id, args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	if err := srv.DeletePermission(ctx, d.Id, member.Id); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+	}
+
+	result := map[string]any{
+		"success":    true,
+		"drive_id":   d.Id,
+		"drive_name": d.Name,
+		"removed":    formatPermission(member),
+	}
+	return common.MarshalToolResult(result)
+}
@@ -22,14 +22,45 @@ var googleWorkspaceExportMIME = map[string]string{
 	"application/vnd.google-apps.presentation": "text/plain",
 }
 
-// extractRequiredFileID extracts, validates, and normalizes the file_id parameter.
-// Returns the cleaned ID or an error result if missing.
-func extractRequiredFileID(request mcp.CallToolRequest) (string, *mcp.CallToolResult) {
-	fileID := common.ParseStringArg(request.GetArguments(), "file_id", "")
-	if fileID == "" {
-		return "", mcp.NewToolResultError("file_id parameter is required")
+// resolveFileRef turns a file ID, Google Drive URL or path into a file ID.
+func resolveFileRef(ctx context.Context, srv DriveService, ref string) (string, *mcp.CallToolResult) {
+	if !IsDrivePath(ref) {
+		return common.ExtractGoogleResourceID(ref), nil
 	}
-	return common.ExtractGoogleResourceID(fileID), nil
+	file, err := NewPathResolver(srv).LookupPath(ctx, ref)
+	if err != nil {
+		return "", mcp.NewToolResultError(err.Error())
+	}
+	return file.Id, nil
+}
+
+// resolveRequiredRef reads the item a tool acts on from idKey, or from the
+// path parameter when idKey is absent, and returns its ID.
+func resolveRequiredRef(ctx context.Context, srv DriveService, request mcp.CallToolRequest, idKey string) (string, *mcp.CallToolResult) {
+	args := request.GetArguments()
+	ref := common.ParseStringArg(args, idKey, "")
+	if ref == "" {
+		ref = common.ParseStringArg(args, "path", "")
+	}
+	if ref == "" {
+		return "", mcp.NewToolResultError(idKey + " or path parameter is required")
+	}
+	return resolveFileRef(ctx, srv, ref)
+}
+
+// resolveRequiredFileID reads the file a tool acts on from file_id or path.
+func resolveRequiredFileID(ctx context.Context, srv DriveService, request mcp.CallToolRequest) (string, *mcp.CallToolResult) {
+	return resolveRequiredRef(ctx, srv, request, "file_id")
+}
+
+// resolveOptionalRef returns the ID for an optional ID, URL or path
+// parameter, or "" when it is absent.
+func resolveOptionalRef(ctx context.Context, srv DriveService, args map[string]any, key string) (string, *mcp.CallToolResult) {
+	ref := common.ParseStringArg(args, key, "")
+	if ref == "" {
+		return "", nil
+	}
+	return resolveFileRef(ctx, srv, ref)
 }
 
 // readLimited reads up to DriveMaxFileSize+1 bytes to detect oversized responses
@@ -0,0 +1,368 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/drive/v3"
+)
+
+// Sharing audit finding categories.
+const (
+	FindingAnyone   = "anyone"   // anyone with the link, or public on the web
+	FindingExternal = "external" // a user, group or domain outside the internal domains
+	FindingOrphaned = "orphaned" // granted to an account that has been deleted
+)
+
+const (
+	auditDefaultMaxFiles = 500
+	auditMaxFilesLimit   = 5000
+	auditFileFields      = "nextPageToken,files(id,name,mimeType,webViewLink,parents,driveId)"
+)
+
+// sharingFinding is a risky permission found by drive_sharing_audit.
+type sharingFinding struct {
+	FileID       string         `json:"file_id"`
+	Name         string         `json:"name"`
+	URL          string         `json:"url,omitempty"`
+	Category     string         `json:"category"`
+	Permission   map[string]any `json:"permission"`
+	Discoverable bool           `json:"discoverable,omitempty"` // anyone: findable by search, not just by link
+	InheritedBy  int            `json:"inherited_by,omitempty"` // audited files that inherit this permission from outside the audit
+	Revoked      bool           `json:"revoked,omitempty"`
+	Error        string         `json:"error,omitempty"`
+
+	permissionID string
+}
+
+// classifyPermission returns the audit category of a permission, or "" when
+// it is not a finding. Owners are never findings unless their account was
+// deleted. internal is nil when no internal domains are known.
+func classifyPermission(p *drive.Permission, internal map[string]bool) string {
+	switch {
+	case p.Deleted:
+		return FindingOrphaned
+	case p.Role == "owner":
+		return ""
+	case p.Type == "anyone":
+		return FindingAnyone
+	case internal == nil:
+		return ""
+	case p.Type == "domain":
+		if !internal[strings.ToLower(p.Domain)] {
+			return FindingExternal
+		}
+	case p.Type == "user" || p.Type == "group":
+		_, domain, ok := strings.Cut(strings.ToLower(p.EmailAddress), "@")
+		if ok && !internal[domain] {
+			return FindingExternal
+		}
+	}
+	return ""
+}
+
+// permissionSource returns the file or shared drive a permission on f is
+// inherited from, or "" when it is granted on f itself. Outside shared
+// drives Drive may not report inheritance, so a grant that an audited parent
+// carries too is taken to be inherited from that parent.
+func permissionSource(f *drive.File, p *drive.Permission, perms map[string][]*drive.Permission) string {
+	if len(p.PermissionDetails) > 0 {
+		from, _ := permissionInheritedFrom(p)
+		return from
+	}
+	for _, parent := range f.Parents {
+		for _, pp := range perms[parent] {
+			if pp.Id == p.Id && pp.Role == p.Role {
+				return parent
+			}
+		}
+	}
+	return ""
+}
+
+// auditSourceName names a folder or shared drive that audited files inherit
+// a permission from, or returns "" if it cannot be read.
+func auditSourceName(ctx context.Context, srv DriveService, id string, drives map[string]bool) (string, string) {
+	if drives[id] {
+		if d, err := srv.GetDrive(ctx, id); err == nil {
+			return d.Name, ""
+		}
+		return "", ""
+	}
+	if f, err := srv.GetFile(ctx, id, "id,name,webViewLink"); err == nil {
+		return f.Name, f.WebViewLink
+	}
+	return "", ""
+}
+
+// auditFiles returns the files to audit: everything in a folder tree, or
+// the results of a search.
+func auditFiles(ctx context.Context, srv DriveService, folderID, query string, maxFiles int) ([]*drive.File, bool, error) {
+	var files []*drive.File
+	truncated := false
+	if folderID != "" {
+		err := walkFolder(ctx, srv, folderID, auditFileFields, 0, func(parentID string, _ int, f *drive.File) bool {
+			if len(files) >= maxFiles {
+				truncated = true
+				return false
+			}
+			if len(f.Parents) == 0 {
+				f.Parents = []string{parentID}
+			}
+			files = append(files, f)
+			return true
+		})
+		return files, truncated, err
+	}
+
+	pageToken := ""
+	for {
+		resp, err := srv.ListFiles(ctx, &ListFilesOptions{
+			Query:     query,
+			PageSize:  int64(min(maxFiles, 1000)),
+			PageToken: pageToken,
+			Fields:    auditFileFields,
+			Corpora:   "allDrives",
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		for _, f := range resp.Files {
+			if len(files) >= maxFiles {
+				return files, true, nil
+			}
+			files = append(files, f)
+		}
+		if resp.NextPageToken == "" {
+			return files, false, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// internalDomains returns the domains treated as internal: the
+// internal_domains parameter, or the account's own domain.
+func internalDomains(request mcp.CallToolRequest, deps *DriveHandlerDeps) map[string]bool {
+	domains := map[string]bool{}
+	if raw, ok := request.GetArguments()["internal_domains"].([]any); ok {
+		for _, item := range raw {
+			if d, ok := item.(string); ok && d != "" {
+				domains[strings.ToLower(strings.TrimPrefix(d, "@"))] = true
+			}
+		}
+	}
+	if len(domains) == 0 {
+		_, domain, ok := strings.Cut(common.ResolveAccountEmail(request, deps, DefaultDriveHandlerDeps), "@")
+		if !ok {
+			return nil
+		}
+		domains[strings.ToLower(domain)] = true
+	}
+	return domains
+}
+
+// TestableDriveSharingAudit reports risky permissions on the files in a
+// folder tree or matching a search, and optionally revokes them.
+func TestableDriveSharingAudit(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	query := common.ParseStringArg(args, "query", "")
+	var folder *drive.File
+	var folderID string
+	if query == "" {
+		if folderID, errResult = resolveRequiredRef(ctx, srv, request, "folder_id"); errResult != nil {
+			return mcp.NewToolResultError("folder_id, path or query parameter is required"), nil
+		}
+		if folder, errResult = getFolder(ctx, srv, folderID); errResult != nil {
+			return errResult, nil
+		}
+		folderID = folder.Id
+	} else if common.ParseStringArg(args, "folder_id", "") != "" || common.ParseStringArg(args, "path", "") != "" {
+		return mcp.NewToolResultError("use folder_id or path, or query, not both"), nil
+	}
+	maxFiles := min(max(common.ParseIntArg(args, "max_files", auditDefaultMaxFiles), 1), auditMaxFilesLimit)
+
+	revoke := map[string]bool{}
+	if raw, ok := args["revoke"].([]any); ok {
+		for _, item := range raw {
+			category, _ := item.(string)
+			switch category {
+			case FindingAnyone, FindingExternal, FindingOrphaned:
+				revoke[category] = true
+			default:
+				return mcp.NewToolResultError(fmt.Sprintf("invalid revoke category %q: use anyone, external or orphaned", category)), nil
+			}
+		}
+	}
+	confirm := common.ParseBoolArg(args, "confirm", false)
+
+	internal := internalDomains(request, deps)
+	files, truncated, err := auditFiles(ctx, srv, folderID, query, maxFiles)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+	}
+	if folder != nil {
+		files = append([]*drive.File{folder}, files...)
+	}
+
+	// Permissions are listed per file, a few files at a time.
+	var mu sync.Mutex
+	perms := make(map[string][]*drive.Permission, len(files))
+	errors := make([]string, 0)
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(folderWalkConcurrency)
+	for _, f := range files {
+		g.Go(func() error {
+			resp, err := srv.ListPermissions(gCtx, f.Id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s (%s): %v", f.Name, f.Id, err))
+				return nil
+			}
+			perms[f.Id] = resp.Permissions
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	// A permission inherited from a folder is reported once, on the folder
+	// that grants it. One inherited from outside the audited files, such as
+	// shared drive membership, is reported once for its source.
+	audited := make(map[string]bool, len(files))
+	drives := map[string]bool{}
+	for _, f := range files {
+		audited[f.Id] = true
+		if f.DriveId != "" {
+			drives[f.DriveId] = true
+		}
+	}
+	findings := make([]*sharingFinding, 0)
+	inherited := map[string]*sharingFinding{}
+	for _, f := range files {
+		for _, p := range perms[f.Id] {
+			category := classifyPermission(p, internal)
+			if category == "" {
+				continue
+			}
+			finding := &sharingFinding{
+				FileID:       f.Id,
+				Name:         f.Name,
+				URL:          f.WebViewLink,
+				Category:     category,
+				Permission:   formatPermission(p),
+				Discoverable: p.Type == "anyone" && p.AllowFileDiscovery,
+				permissionID: p.Id,
+			}
+			source := permissionSource(f, p, perms)
+			switch {
+			case source == "":
+				findings = append(findings, finding)
+			case audited[source]:
+			case inherited[source+"/"+p.Id] != nil:
+				inherited[source+"/"+p.Id].InheritedBy++
+			default:
+				finding.FileID = source
+				finding.Name, finding.URL = auditSourceName(ctx, srv, source, drives)
+				finding.InheritedBy = 1
+				inherited[source+"/"+p.Id] = finding
+				findings = append(findings, finding)
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Name != findings[j].Name {
+			return findings[i].Name < findings[j].Name
+		}
+		return findings[i].FileID < findings[j].FileID
+	})
+
+	summary := map[string]int{FindingAnyone: 0, FindingExternal: 0, FindingOrphaned: 0}
+	flagged := map[string]bool{}
+	var toRevoke []*sharingFinding
+	keptInherited := 0
+	for _, f := range findings {
+		summary[f.Category]++
+		if f.InheritedBy > 0 {
+			// Granted outside the audited files; revoking it would change
+			// access beyond them.
+			if revoke[f.Category] {
+				keptInherited++
+			}
+			continue
+		}
+		flagged[f.FileID] = true
+		// Owner permissions cannot be deleted, even for deleted accounts.
+		if revoke[f.Category] && f.Permission["role"] != "owner" {
+			toRevoke = append(toRevoke, f)
+		}
+	}
+
+	result := map[string]any{
+		"files_scanned":       len(files),
+		"files_with_findings": len(flagged),
+		"summary":             summary,
+		"findings":            findings,
+		"errors":              errors,
+	}
+	if folderID != "" {
+		result["folder_id"] = folderID
+	} else {
+		result["query"] = query
+	}
+	if internal == nil {
+		result["note"] = "The account's domain is unknown, so external sharing was not checked; pass internal_domains."
+	} else {
+		domains := make([]string, 0, len(internal))
+		for d := range internal {
+			domains = append(domains, d)
+		}
+		sort.Strings(domains)
+		result["internal_domains"] = domains
+	}
+	if truncated {
+		result["truncated"] = true
+	}
+	if keptInherited > 0 {
+		result["inherited_note"] = fmt.Sprintf("%d permissions are inherited from a folder or shared drive outside the audit and will not be revoked; change them at their source.", keptInherited)
+	}
+
+	switch {
+	case len(revoke) == 0:
+	case !confirm:
+		result["revocations_planned"] = len(toRevoke)
+		result["confirm_note"] = fmt.Sprintf("Nothing was changed. Call again with confirm=true to revoke these %d permissions.", len(toRevoke))
+	default:
+		revoked := 0
+		g, gCtx := errgroup.WithContext(ctx)
+		g.SetLimit(folderWalkConcurrency)
+		for _, f := range toRevoke {
+			g.Go(func() error {
+				err := srv.DeletePermission(gCtx, f.FileID, f.permissionID)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					f.Error = err.Error()
+					return nil
+				}
+				f.Revoked = true
+				revoked++
+				return nil
+			})
+		}
+		_ = g.Wait()
+		result["revoked"] = revoked
+		result["revoke_failed"] = len(toRevoke) - revoked
+	}
+	return common.MarshalToolResult(result)
+}
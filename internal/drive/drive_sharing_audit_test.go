@@ -0,0 +1,312 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// fakePermissions keeps permissions per file behind the mock service.
+type fakePermissions struct {
+	mu      sync.Mutex
+	perms   map[string][]*drive.Permission
+	updates []string // "file/permission role transfer"
+}
+
+func newFakePermissions(m *MockDriveService, perms map[string][]*drive.Permission) *fakePermissions {
+	fp := &fakePermissions{perms: perms}
+	m.ListPermissionsFunc = func(_ context.Context, fileID string) (*drive.PermissionList, error) {
+		fp.mu.Lock()
+		defer fp.mu.Unlock()
+		if fileID == "forbidden" {
+			return nil, fmt.Errorf("insufficient permissions")
+		}
+		return &drive.PermissionList{Permissions: append([]*drive.Permission(nil), fp.perms[fileID]...)}, nil
+	}
+	m.DeletePermissionFunc = func(_ context.Context, fileID, permissionID string) error {
+		fp.mu.Lock()
+		defer fp.mu.Unlock()
+		kept := fp.perms[fileID][:0]
+		for _, p := range fp.perms[fileID] {
+			if p.Id != permissionID {
+				kept = append(kept, p)
+			}
+		}
+		fp.perms[fileID] = kept
+		return nil
+	}
+	m.CreatePermissionFunc = func(_ context.Context, fileID string, p *drive.Permission, _ bool) (*drive.Permission, error) {
+		fp.mu.Lock()
+		defer fp.mu.Unlock()
+		created := *p
+		created.Id = fmt.Sprintf("perm-%d", len(fp.perms[fileID]))
+		fp.perms[fileID] = append(fp.perms[fileID], &created)
+		return &created, nil
+	}
+	m.UpdatePermissionFunc = func(_ context.Context, fileID, permissionID string, p *drive.Permission, transfer bool) (*drive.Permission, error) {
+		fp.mu.Lock()
+		defer fp.mu.Unlock()
+		fp.updates = append(fp.updates, fmt.Sprintf("%s/%s %s %v", fileID, permissionID, p.Role, transfer))
+		for _, existing := range fp.perms[fileID] {
+			if existing.Id == permissionID {
+				existing.Role, existing.ExpirationTime, existing.PendingOwner = p.Role, p.ExpirationTime, p.PendingOwner
+				return existing, nil
+			}
+		}
+		return nil, fmt.Errorf("permission %s not found", permissionID)
+	}
+	return fp
+}
+
+func (fp *fakePermissions) ids(fileID string) []string {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	var ids []string
+	for _, p := range fp.perms[fileID] {
+		ids = append(ids, p.Id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestDriveUnshareAndUpdatePermission(t *testing.T) {
+	fixtures := NewDriveTestFixtures()
+	fp := newFakePermissions(fixtures.MockService, map[string][]*drive.Permission{
+		"f1": {
+			{Id: "owner", Type: "user", Role: "owner", EmailAddress: "me@example.com"},
+			{Id: "p-bob", Type: "user", Role: "writer", EmailAddress: "Bob@Partner.com"},
+			{Id: "p-link", Type: "anyone", Role: "reader"},
+		},
+	})
+
+	data, errText := runDriveTransfer(t, TestableDriveUnshare, fixtures, map[string]any{"file_id": "f1", "email": "anyone"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["removed"].(map[string]any)["id"] != "p-link" {
+		t.Errorf("expected the link permission to be removed, got %v", data)
+	}
+	if _, errText = runDriveTransfer(t, TestableDriveUnshare, fixtures, map[string]any{"file_id": "f1", "permission_id": "owner"}); !strings.Contains(errText, "owner") {
+		t.Errorf("expected the owner to be kept, got %q", errText)
+	}
+	if _, errText = runDriveTransfer(t, TestableDriveUnshare, fixtures, map[string]any{"file_id": "f1", "email": "eve@example.com"}); !strings.Contains(errText, "not shared with") {
+		t.Errorf("expected a not-shared error, got %q", errText)
+	}
+
+	data, errText = runDriveTransfer(t, TestableDriveUpdatePermission, fixtures, map[string]any{
+		"file_id":         "f1",
+		"email":           "bob@partner.com",
+		"role":            "reader",
+		"expiration_time": "2026-12-31",
+	})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	perm := data["permission"].(map[string]any)
+	if perm["role"] != "reader" || perm["expiration_time"] != "2026-12-31T23:59:59Z" {
+		t.Errorf("unexpected permission: %v", perm)
+	}
+	if _, errText = runDriveTransfer(t, TestableDriveUpdatePermission, fixtures, map[string]any{"file_id": "f1", "permission_id": "p-bob", "expiration_time": "soon"}); !strings.Contains(errText, "invalid expiration_time") {
+		t.Errorf("expected an invalid time error, got %q", errText)
+	}
+	if got := fp.ids("f1"); strings.Join(got, ",") != "owner,p-bob" {
+		t.Errorf("unexpected permissions left: %v", got)
+	}
+}
+
+func TestDriveTransferOwnership(t *testing.T) {
+	fixtures := NewDriveTestFixtures()
+	fp := newFakePermissions(fixtures.MockService, map[string][]*drive.Permission{
+		"f1": {{Id: "owner", Type: "user", Role: "owner", EmailAddress: "me@example.com"}},
+	})
+
+	data, errText := runDriveTransfer(t, TestableDriveTransferOwnership, fixtures, map[string]any{"file_id": "f1", "email": "carol@example.com"})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if data["permission"].(map[string]any)["role"] != "owner" || len(fp.updates) != 1 || fp.updates[0] != "f1/perm-1 owner true" {
+		t.Errorf("expected carol to be shared with and promoted, got %v %v", data, fp.updates)
+	}
+
+	data, errText = runDriveTransfer(t, TestableDriveTransferOwnership, fixtures, map[string]any{"file_id": "f1", "email": "me@example.com"})
+	if !strings.Contains(errText, "already owns") {
+		t.Errorf("expected an already-owner error, got %q (%v)", errText, data)
+	}
+
+	fp.perms["f2"] = []*drive.Permission{{Id: "p-dan", Type: "user", Role: "reader", EmailAddress: "dan@gmail.com"}}
+	data, errText = runDriveTransfer(t, TestableDriveTransferOwnership, fixtures, map[string]any{"file_id": "f2", "email": "dan@gmail.com", "pending": true})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	if perm := data["permission"].(map[string]any); perm["pending_owner"] != true || perm["role"] != "writer" {
+		t.Errorf("expected a pending transfer, got %v", perm)
+	}
+
+	// A failed permission lookup must not fall through to sharing the file.
+	_, errText = runDriveTransfer(t, TestableDriveTransferOwnership, fixtures, map[string]any{"file_id": "forbidden", "email": "carol@example.com"})
+	if !strings.Contains(errText, "insufficient permissions") || len(fp.perms["forbidden"]) != 0 {
+		t.Errorf("expected the lookup error and no new share, got %q %v", errText, fp.perms["forbidden"])
+	}
+}
+
+func TestDriveSharingAudit(t *testing.T) {
+	fixtures, tree, projects := folderFixtures(t)
+	files := map[string]string{}
+	for id, f := range tree.files {
+		files[f.Name] = id
+	}
+	forbidden := tree.add("locked.txt", projects.Id, "text/plain", nil)
+	delete(tree.files, forbidden.Id)
+	forbidden.Id = "forbidden"
+	tree.files["forbidden"] = forbidden
+
+	owner := &drive.Permission{Id: "owner", Type: "user", Role: "owner", EmailAddress: "test@example.com"}
+	fp := newFakePermissions(fixtures.MockService, map[string][]*drive.Permission{
+		projects.Id: {owner, {Id: "p-dom", Type: "domain", Role: "reader", Domain: "partner.com"}},
+		files["a.txt"]: {
+			owner,
+			{Id: "p-link", Type: "anyone", Role: "reader"},
+			{Id: "p-colleague", Type: "user", Role: "writer", EmailAddress: "colleague@example.com"},
+		},
+		files["b.txt"]: {
+			owner,
+			{Id: "p-web", Type: "anyone", Role: "reader", AllowFileDiscovery: true},
+			{Id: "p-ext", Type: "user", Role: "writer", EmailAddress: "bob@partner.com"},
+			{Id: "p-gone", Type: "user", Role: "reader", EmailAddress: "left@example.com", Deleted: true},
+		},
+		files["c.txt"]: {{Id: "p-old-owner", Type: "user", Role: "owner", Deleted: true}},
+	})
+	var listFields []string
+	listFiles := fixtures.MockService.ListFilesFunc
+	fixtures.MockService.ListFilesFunc = func(ctx context.Context, opts *ListFilesOptions) (*drive.FileList, error) {
+		tree.mu.Lock()
+		listFields = append(listFields, opts.Fields)
+		tree.mu.Unlock()
+		return listFiles(ctx, opts)
+	}
+
+	data, errText := runDriveTransfer(t, TestableDriveSharingAudit, fixtures, map[string]any{"folder_id": projects.Id})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	for _, fields := range listFields {
+		if !strings.Contains(fields, "webViewLink") {
+			t.Errorf("expected the folder walk to request webViewLink for finding URLs, got %q", fields)
+		}
+	}
+	summary := data["summary"].(map[string]any)
+	if summary["anyone"] != float64(2) || summary["external"] != float64(2) || summary["orphaned"] != float64(2) {
+		t.Errorf("unexpected summary: %v", summary)
+	}
+	if data["files_scanned"] != float64(8) || data["files_with_findings"] != float64(4) || len(data["errors"].([]any)) != 1 {
+		t.Errorf("unexpected totals: %v", data)
+	}
+	for _, f := range data["findings"].([]any) {
+		finding := f.(map[string]any)
+		if finding["permission"].(map[string]any)["id"] == "p-web" && finding["discoverable"] != true {
+			t.Errorf("expected the public link to be discoverable: %v", finding)
+		}
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveSharingAudit, fixtures, map[string]any{
+		"folder_id":        projects.Id,
+		"internal_domains": []any{"example.com", "partner.com"},
+		"revoke":           []any{"anyone", "orphaned"},
+	})
+	if data["summary"].(map[string]any)["external"] != float64(0) || data["revocations_planned"] != float64(3) || data["revoked"] != nil {
+		t.Errorf("expected a preview of 3 revocations, got %v", data)
+	}
+	if len(fp.ids(files["b.txt"])) != 4 {
+		t.Fatal("expected nothing to be revoked without confirm")
+	}
+
+	data, _ = runDriveTransfer(t, TestableDriveSharingAudit, fixtures, map[string]any{
+		"folder_id": projects.Id,
+		"revoke":    []any{"anyone", "external", "orphaned"},
+		"confirm":   true,
+	})
+	if data["revoked"] != float64(5) || data["revoke_failed"] != float64(0) {
+		t.Errorf("expected 5 revocations, got %v", data)
+	}
+	if got := strings.Join(fp.ids(files["b.txt"]), ","); got != "owner" {
+		t.Errorf("expected only the owner on b.txt, got %s", got)
+	}
+	if got := strings.Join(fp.ids(files["c.txt"]), ","); got != "p-old-owner" {
+		t.Errorf("expected the deleted owner to be kept, got %s", got)
+	}
+
+	if _, errText = runDriveTransfer(t, TestableDriveSharingAudit, fixtures, map[string]any{"folder_id": projects.Id, "revoke": []any{"everyone"}}); !strings.Contains(errText, "invalid revoke category") {
+		t.Errorf("expected an invalid category error, got %q", errText)
+	}
+}
+
+func TestDriveSharingAudit_InheritedPermissions(t *testing.T) {
+	fixtures, tree, projects := folderFixtures(t)
+	files := map[string]string{}
+	for id, f := range tree.files {
+		files[f.Name] = id
+		f.DriveId = "drive1"
+	}
+	fixtures.MockService.GetDriveFunc = func(_ context.Context, driveID string) (*drive.Drive, error) {
+		return &drive.Drive{Id: driveID, Name: "Engineering"}, nil
+	}
+
+	fromDrive := []*drive.PermissionPermissionDetails{{Inherited: true, InheritedFrom: "drive1"}}
+	fromProjects := []*drive.PermissionPermissionDetails{{Inherited: true, InheritedFrom: projects.Id}}
+	member := func() *drive.Permission {
+		return &drive.Permission{Id: "p-member", Type: "user", Role: "writer", EmailAddress: "x@partner.com", PermissionDetails: fromDrive}
+	}
+	share := func(details []*drive.PermissionPermissionDetails) *drive.Permission {
+		return &drive.Permission{Id: "p-share", Type: "user", Role: "reader", EmailAddress: "y@partner.com", PermissionDetails: details}
+	}
+	link := func() *drive.Permission { return &drive.Permission{Id: "p-link", Type: "anyone", Role: "reader"} }
+	fp := newFakePermissions(fixtures.MockService, map[string][]*drive.Permission{
+		projects.Id:    {member(), share([]*drive.PermissionPermissionDetails{{Inherited: false}})},
+		files["a.txt"]: {member(), share(fromProjects)},
+		files["Sub"]:   {member(), share(fromProjects), link()},
+		files["b.txt"]: {member(), share(fromProjects), link()},
+	})
+	var deleted []string
+	deletePermission := fixtures.MockService.DeletePermissionFunc
+	fixtures.MockService.DeletePermissionFunc = func(ctx context.Context, fileID, permissionID string) error {
+		fp.mu.Lock()
+		deleted = append(deleted, fileID+"/"+permissionID)
+		fp.mu.Unlock()
+		return deletePermission(ctx, fileID, permissionID)
+	}
+
+	data, errText := runDriveTransfer(t, TestableDriveSharingAudit, fixtures, map[string]any{
+		"folder_id":        projects.Id,
+		"internal_domains": []any{"example.com"},
+		"revoke":           []any{"anyone", "external"},
+		"confirm":          true,
+	})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	got := map[string]map[string]any{}
+	for _, f := range data["findings"].([]any) {
+		finding := f.(map[string]any)
+		got[finding["permission"].(map[string]any)["id"].(string)] = finding
+	}
+	if len(got) != 3 || got["p-share"]["file_id"] != projects.Id || got["p-link"]["file_id"] != files["Sub"] {
+		t.Fatalf("expected each permission to be reported once at its source, got %v", data["findings"])
+	}
+	if m := got["p-member"]; m["file_id"] != "drive1" || m["name"] != "Engineering" || m["inherited_by"] != float64(4) || m["revoked"] == true {
+		t.Errorf("expected the drive membership to be reported once and kept, got %v", m)
+	}
+	want := []string{projects.Id + "/p-share", files["Sub"] + "/p-link"}
+	sort.Strings(want)
+	sort.Strings(deleted)
+	if strings.Join(deleted, ",") != strings.Join(want, ",") {
+		t.Errorf("expected only the sources to be revoked, got %v", deleted)
+	}
+	if data["revoked"] != float64(2) || data["inherited_note"] == nil {
+		t.Errorf("unexpected revocation totals: %v", data)
+	}
+}
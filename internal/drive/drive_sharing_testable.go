@@ -3,6 +3,8 @@ package drive
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -16,7 +18,7 @@ func TestableDriveShare(ctx context.Context, request mcp.CallToolRequest, deps *
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -62,7 +64,7 @@ func TestableDriveGetShareableLink(ctx context.Context, request mcp.CallToolRequ
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -86,21 +88,7 @@ func TestableDriveGetShareableLink(ctx context.Context, request mcp.CallToolRequ
 	// Include permissions/sharing status
 	permissions := make([]map[string]any, 0, len(file.Permissions))
 	for _, p := range file.Permissions {
-		perm := map[string]any{
-			"id":   p.Id,
-			"type": p.Type,
-			"role": p.Role,
-		}
-		if p.EmailAddress != "" {
-			perm["email"] = p.EmailAddress
-		}
-		if p.DisplayName != "" {
-			perm["display_name"] = p.DisplayName
-		}
-		if p.Domain != "" {
-			perm["domain"] = p.Domain
-		}
-		permissions = append(permissions, perm)
+		permissions = append(permissions, formatPermission(p))
 	}
 	result["permissions"] = permissions
 	result["permission_count"] = len(permissions)
@@ -115,7 +103,7 @@ func TestableDriveGetPermissions(ctx context.Context, request mcp.CallToolReques
 		return errResult, nil
 	}
 
-	fileID, idErrResult := extractRequiredFileID(request)
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
 	if idErrResult != nil {
 		return idErrResult, nil
 	}
@@ -127,28 +115,264 @@ func TestableDriveGetPermissions(ctx context.Context, request mcp.CallToolReques
 
 	permissions := make([]map[string]any, 0, len(resp.Permissions))
 	for _, p := range resp.Permissions {
-		perm := map[string]any{
-			"id":   p.Id,
-			"type": p.Type,
-			"role": p.Role,
+		permissions = append(permissions, formatPermission(p))
+	}
+
+	result := map[string]any{
+		"file_id":     fileID,
+		"permissions": permissions,
+		"count":       len(permissions),
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// formatPermission converts a permission to a response map.
+func formatPermission(p *drive.Permission) map[string]any {
+	perm := map[string]any{
+		"id":   p.Id,
+		"type": p.Type,
+		"role": p.Role,
+	}
+	if p.EmailAddress != "" {
+		perm["email"] = p.EmailAddress
+	}
+	if p.DisplayName != "" {
+		perm["display_name"] = p.DisplayName
+	}
+	if p.Domain != "" {
+		perm["domain"] = p.Domain
+	}
+	if p.ExpirationTime != "" {
+		perm["expiration_time"] = p.ExpirationTime
+	}
+	if p.Deleted {
+		perm["deleted"] = true
+	}
+	if p.PendingOwner {
+		perm["pending_owner"] = true
+	}
+	if from, ok := permissionInheritedFrom(p); ok {
+		perm["inherited_from"] = from
+	}
+	return perm
+}
+
+// permissionInheritedFrom returns the folder or shared drive a permission is
+// inherited from. Drive reports this in permissionDetails; a permission
+// granted directly on the item as well is not inherited.
+func permissionInheritedFrom(p *drive.Permission) (string, bool) {
+	from := ""
+	for _, d := range p.PermissionDetails {
+		if !d.Inherited {
+			return "", false
 		}
-		if p.EmailAddress != "" {
-			perm["email"] = p.EmailAddress
+		if from == "" {
+			from = d.InheritedFrom
 		}
-		if p.DisplayName != "" {
-			perm["display_name"] = p.DisplayName
+	}
+	return from, from != ""
+}
+
+// findPermission returns the permission named by permission_id, or the one
+// granted to email. The email "anyone" matches a link-sharing permission.
+func findPermission(ctx context.Context, srv DriveService, fileID string, args map[string]any) (*drive.Permission, *mcp.CallToolResult) {
+	permissionID := common.ParseStringArg(args, "permission_id", "")
+	email := strings.ToLower(common.ParseStringArg(args, "email", ""))
+	if permissionID == "" && email == "" {
+		return nil, mcp.NewToolResultError("permission_id or email parameter is required")
+	}
+	p, err := lookupPermission(ctx, srv, fileID, permissionID, email)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Drive API error listing permissions: %v", err))
+	}
+	if p != nil {
+		return p, nil
+	}
+	if permissionID != "" {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("file %s has no permission %s", fileID, permissionID))
+	}
+	return nil, mcp.NewToolResultError(fmt.Sprintf("file %s is not shared with %s", fileID, email))
+}
+
+// lookupPermission returns the permission with permissionID, or else the one
+// granted to email, or nil when the file has neither.
+func lookupPermission(ctx context.Context, srv DriveService, fileID, permissionID, email string) (*drive.Permission, error) {
+	resp, err := srv.ListPermissions(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range resp.Permissions {
+		switch {
+		case permissionID != "" && p.Id == permissionID,
+			permissionID == "" && email == "anyone" && p.Type == "anyone",
+			permissionID == "" && email != "" && strings.EqualFold(p.EmailAddress, email):
+			return p, nil
 		}
-		if p.Domain != "" {
-			perm["domain"] = p.Domain
+	}
+	return nil, nil
+}
+
+// parseExpirationTime accepts an RFC 3339 time or a date, which expires at
+// the end of that day in UTC.
+func parseExpirationTime(value string) (string, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC().Format(time.RFC3339), nil
+	}
+	if d, err := time.Parse("2006-01-02", value); err == nil {
+		return d.Add(24*time.Hour - time.Second).Format(time.RFC3339), nil
+	}
+	return "", fmt.Errorf("invalid expiration_time %q: use RFC 3339 (2026-12-31T17:00:00Z) or a date (2026-12-31)", value)
+}
+
+// TestableDriveUnshare removes a permission from a file.
+func TestableDriveUnshare(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
+	if idErrResult != nil {
+		return idErrResult, nil
+	}
+
+	perm, errResult := findPermission(ctx, srv, fileID, request.GetArguments())
+	if errResult != nil {
+		return errResult, nil
+	}
+	if perm.Role == "owner" {
+		return mcp.NewToolResultError("cannot remove the owner's permission; use drive_transfer_ownership first"), nil
+	}
+
+	if err := srv.DeletePermission(ctx, fileID, perm.Id); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
+	}
+
+	result := map[string]any{
+		"success": true,
+		"file_id": fileID,
+		"removed": formatPermission(perm),
+		"message": "Permission removed",
+	}
+	return common.MarshalToolResult(result)
+}
+
+// TestableDriveUpdatePermission changes a permission's role or expiration.
+func TestableDriveUpdatePermission(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
+	if idErrResult != nil {
+		return idErrResult, nil
+	}
+
+	args := request.GetArguments()
+	role := common.ParseStringArg(args, "role", "")
+	expiration := common.ParseStringArg(args, "expiration_time", "")
+	removeExpiration := common.ParseBoolArg(args, "remove_expiration", false)
+	if role == "" && expiration == "" && !removeExpiration {
+		return mcp.NewToolResultError("nothing to change: pass role, expiration_time or remove_expiration"), nil
+	}
+	if role == "owner" {
+		return mcp.NewToolResultError("use drive_transfer_ownership to change the owner"), nil
+	}
+
+	perm, errResult := findPermission(ctx, srv, fileID, args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	update := &drive.Permission{Role: role}
+	if update.Role == "" {
+		update.Role = perm.Role
+	}
+	switch {
+	case expiration != "" && removeExpiration:
+		return mcp.NewToolResultError("use expiration_time or remove_expiration, not both"), nil
+	case expiration != "":
+		var err error
+		update.ExpirationTime, err = parseExpirationTime(expiration)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		permissions = append(permissions, perm)
+	case removeExpiration:
+		update.NullFields = []string{"ExpirationTime"}
+	}
+
+	updated, err := srv.UpdatePermission(ctx, fileID, perm.Id, update, false)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v", err)), nil
 	}
 
 	result := map[string]any{
-		"file_id":     fileID,
-		"permissions": permissions,
-		"count":       len(permissions),
+		"success":    true,
+		"file_id":    fileID,
+		"permission": formatPermission(updated),
+	}
+	return common.MarshalToolResult(result)
+}
+
+// TestableDriveTransferOwnership makes another user the owner of a file.
+// The previous owner keeps writer access.
+func TestableDriveTransferOwnership(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	fileID, idErrResult := resolveRequiredFileID(ctx, srv, request)
+	if idErrResult != nil {
+		return idErrResult, nil
+	}
+
+	args := request.GetArguments()
+	email, errResult := common.RequireStringArg(args, "email")
+	if errResult != nil {
+		return errResult, nil
+	}
+	pending := common.ParseBoolArg(args, "pending", false)
+
+	// The new owner needs a permission to promote; share first if needed.
+	// Sharing notifies them, so only do it when the file is known not to be
+	// shared with them already.
+	perm, err := lookupPermission(ctx, srv, fileID, "", strings.ToLower(email))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error listing permissions: %v", err)), nil
+	}
+	if perm != nil && perm.Role == "owner" {
+		return mcp.NewToolResultError(fmt.Sprintf("%s already owns this file", email)), nil
+	}
+	if perm == nil {
+		perm, err = srv.CreatePermission(ctx, fileID, &drive.Permission{Type: "user", Role: "writer", EmailAddress: email}, true)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Drive API error sharing with %s: %v", email, err)), nil
+		}
 	}
 
+	var updated *drive.Permission
+	if pending {
+		// Personal accounts cannot receive ownership directly; the new
+		// owner accepts a pending transfer instead.
+		updated, err = srv.UpdatePermission(ctx, fileID, perm.Id, &drive.Permission{Role: "writer", PendingOwner: true}, false)
+	} else {
+		updated, err = srv.UpdatePermission(ctx, fileID, perm.Id, &drive.Permission{Role: "owner"}, true)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive API error: %v (files in shared drives have no owner to transfer; personal accounts need pending=true)", err)), nil
+	}
+
+	result := map[string]any{
+		"success":    true,
+		"file_id":    fileID,
+		"permission": formatPermission(updated),
+		"message":    fmt.Sprintf("%s is now the owner", email),
+	}
+	if pending {
+		result["message"] = fmt.Sprintf("%s has been asked to accept ownership", email)
+	}
 	return common.MarshalToolResult(result)
 }
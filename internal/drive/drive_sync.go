@@ -0,0 +1,619 @@
+package drive
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"google.golang.org/api/drive/v3"
+)
+
+// Sync directions.
+const (
+	SyncPush = "push" // local directory → Drive folder
+	SyncPull = "pull" // Drive folder → local directory
+	SyncBoth = "both" // changes flow both ways
+)
+
+// Conflict policies, applied when both sides changed since the last sync or,
+// in a one-way sync, when the destination changed.
+const (
+	ConflictNewer  = "newer"  // keep the most recently modified side
+	ConflictLocal  = "local"  // keep the local file
+	ConflictRemote = "remote" // keep the Drive file
+	ConflictSkip   = "skip"   // change nothing and report the conflict
+)
+
+// Sync actions reported in a plan.
+const (
+	syncUpload      = "upload"       // create the file in Drive
+	syncUpdate      = "update"       // replace the Drive file's content
+	syncDownload    = "download"     // write the Drive file locally
+	syncTrashRemote = "trash_remote" // move the Drive file to the trash
+	syncDeleteLocal = "delete_local" // delete the local file
+	syncSkip        = "skip"         // leave both sides as they are
+	syncConflict    = "conflict"     // both sides changed; left as they are
+)
+
+// DefaultSyncStateFile is the state file written in the local directory.
+const DefaultSyncStateFile = ".gsuite-mcp-sync.json"
+
+// syncStateVersion is the format version of the state file.
+const syncStateVersion = 1
+
+// folderMimeType is the MIME type of Drive folders.
+const folderMimeType = "application/vnd.google-apps.folder"
+
+// syncListFields are the file fields needed to compare Drive files.
+const syncListFields = "nextPageToken,files(id,name,mimeType,md5Checksum,size,modifiedTime)"
+
+// SyncOptions configures a folder sync.
+type SyncOptions struct {
+	LocalDir  string
+	FolderID  string
+	Direction string   // SyncPush (default), SyncPull or SyncBoth
+	Conflict  string   // ConflictNewer (default), ConflictLocal, ConflictRemote or ConflictSkip
+	Delete    bool     // propagate deletions instead of restoring missing files
+	Exclude   []string // glob patterns matched against relative paths and names
+	DryRun    bool     // plan only
+	StatePath string   // default: <LocalDir>/.gsuite-mcp-sync.json
+	Account   string   // recorded in the state file; upload sessions are per account
+}
+
+// SyncAction is one planned or performed change.
+type SyncAction struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+	FileID string `json:"file_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SyncReport is the result of a sync or, with DryRun, its plan.
+type SyncReport struct {
+	LocalDir  string         `json:"local_dir"`
+	FolderID  string         `json:"folder_id"`
+	Direction string         `json:"direction"`
+	Conflict  string         `json:"conflict"`
+	DryRun    bool           `json:"dry_run"`
+	Actions   []SyncAction   `json:"actions"`
+	Counts    map[string]int `json:"counts"`
+	Unchanged int            `json:"unchanged"`
+	Errors    int            `json:"errors"`
+	StatePath string         `json:"state_path"`
+}
+
+// syncState remembers each file's content when both sides last matched, so
+// later runs can tell which side changed and skip hashing unchanged files.
+type syncState struct {
+	Version  int                        `json:"version"`
+	FolderID string                     `json:"folder_id"`
+	Account  string                     `json:"account,omitempty"`
+	LastSync time.Time                  `json:"last_sync"`
+	Files    map[string]*syncStateEntry `json:"files"`
+}
+
+// syncStateEntry is a file as it was when last in sync.
+type syncStateEntry struct {
+	MD5      string    `json:"md5"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"` // local modification time
+	RemoteID string    `json:"remote_id"`
+}
+
+// localSyncFile is a file in the local directory.
+type localSyncFile struct {
+	path    string // on disk
+	size    int64
+	modTime time.Time
+	md5     string
+}
+
+// syncRun holds the state of one sync.
+type syncRun struct {
+	srv     DriveService
+	opts    SyncOptions
+	state   *syncState
+	local   map[string]*localSyncFile
+	remote  map[string]*drive.File
+	folders map[string]string // relative directory → Drive folder ID
+	dups    map[string]bool   // paths shared by several Drive files
+	report  *SyncReport
+	// root keeps downloads inside LocalDir, so a symlinked subdirectory
+	// cannot lead a pull outside it.
+	root *common.LocalDirPolicy
+}
+
+// ValidateSyncOptions fills in defaults and checks the options.
+func ValidateSyncOptions(opts *SyncOptions) error {
+	if opts.Direction == "" {
+		opts.Direction = SyncPush
+	}
+	if opts.Conflict == "" {
+		opts.Conflict = ConflictNewer
+	}
+	switch opts.Direction {
+	case SyncPush, SyncPull, SyncBoth:
+	default:
+		return fmt.Errorf("invalid direction %q: must be 'push', 'pull' or 'both'", opts.Direction)
+	}
+	switch opts.Conflict {
+	case ConflictNewer, ConflictLocal, ConflictRemote, ConflictSkip:
+	default:
+		return fmt.Errorf("invalid conflict policy %q: must be 'newer', 'local', 'remote' or 'skip'", opts.Conflict)
+	}
+	for _, pattern := range opts.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	if opts.FolderID == "" {
+		return fmt.Errorf("folder ID is required")
+	}
+	if opts.StatePath == "" {
+		opts.StatePath = filepath.Join(opts.LocalDir, DefaultSyncStateFile)
+	}
+	return nil
+}
+
+// RunSync compares a local directory with a Drive folder, recursively, and
+// brings them in line according to opts. Files are compared by MD5; the
+// state file records what each file looked like after the last sync, which
+// tells which side changed and lets unchanged local files skip hashing.
+// Google Workspace files have no content to compare and are skipped.
+// Failures of single files are reported in the plan and do not stop the run.
+func RunSync(ctx context.Context, srv DriveService, opts SyncOptions) (*SyncReport, error) {
+	if err := ValidateSyncOptions(&opts); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(opts.LocalDir); err != nil {
+		if !os.IsNotExist(err) || opts.Direction == SyncPush {
+			return nil, fmt.Errorf("local directory: %w", err)
+		}
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("local path %q is not a directory", opts.LocalDir)
+	}
+
+	root, err := common.NewLocalDirPolicy([]string{opts.LocalDir})
+	if err != nil {
+		return nil, fmt.Errorf("local directory: %w", err)
+	}
+	r := &syncRun{
+		srv:     srv,
+		opts:    opts,
+		state:   loadSyncState(opts.StatePath, opts.FolderID),
+		folders: map[string]string{"": opts.FolderID},
+		dups:    map[string]bool{},
+		report: &SyncReport{
+			LocalDir:  opts.LocalDir,
+			FolderID:  opts.FolderID,
+			Direction: opts.Direction,
+			Conflict:  opts.Conflict,
+			DryRun:    opts.DryRun,
+			Actions:   []SyncAction{},
+			Counts:    map[string]int{},
+			StatePath: opts.StatePath,
+		},
+		root: root,
+	}
+	if err := r.scanLocal(); err != nil {
+		return nil, err
+	}
+	if err := r.scanRemote(ctx, opts.FolderID, ""); err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for p := range r.local {
+		paths[p] = true
+	}
+	for p := range r.remote {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	for _, p := range sorted {
+		if r.dups[p] {
+			continue
+		}
+		action := r.plan(p)
+		if action.Action == "" {
+			r.report.Unchanged++
+			continue
+		}
+		if !opts.DryRun {
+			r.apply(ctx, &action)
+		}
+		r.report.Counts[action.Action]++
+		if action.Error != "" {
+			r.report.Errors++
+		}
+		r.report.Actions = append(r.report.Actions, action)
+	}
+
+	if !opts.DryRun {
+		for p := range r.state.Files {
+			if r.local[p] == nil && r.remote[p] == nil {
+				delete(r.state.Files, p)
+			}
+		}
+		r.state.Account = opts.Account
+		r.state.LastSync = time.Now().UTC()
+		if err := saveSyncState(opts.StatePath, r.state); err != nil {
+			return r.report, fmt.Errorf("saving sync state: %w", err)
+		}
+	}
+	return r.report, nil
+}
+
+// excluded reports whether a relative path matches an exclude pattern, by
+// its full path or by any of its elements.
+func (r *syncRun) excluded(rel string) bool {
+	for _, pattern := range r.opts.Exclude {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		for _, elem := range strings.Split(rel, "/") {
+			if ok, _ := path.Match(pattern, elem); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scanLocal records the regular files under the local directory. MD5s are
+// taken from the state file when size and modification time are unchanged.
+func (r *syncRun) scanLocal() error {
+	r.local = make(map[string]*localSyncFile)
+	if _, err := os.Stat(r.opts.LocalDir); os.IsNotExist(err) {
+		return nil
+	}
+	statePath, _ := filepath.Abs(r.opts.StatePath)
+	return filepath.WalkDir(r.opts.LocalDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relOS, _ := filepath.Rel(r.opts.LocalDir, p)
+		if relOS == "." {
+			return nil
+		}
+		rel := filepath.ToSlash(relOS)
+		if r.excluded(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if abs, _ := filepath.Abs(p); abs == statePath || strings.HasSuffix(p, partialSuffix) || strings.HasSuffix(p, partialSuffix+".json") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		f := &localSyncFile{path: p, size: info.Size(), modTime: info.ModTime()}
+		if s := r.state.Files[rel]; s != nil && s.Size == f.size && s.ModTime.Equal(f.modTime) {
+			f.md5 = s.MD5
+		} else if f.md5, err = fileMD5(p); err != nil {
+			return err
+		}
+		r.local[rel] = f
+		return nil
+	})
+}
+
+// scanRemote records the files under a Drive folder, recursing into
+// subfolders.
+func (r *syncRun) scanRemote(ctx context.Context, folderID, prefix string) error {
+	if r.remote == nil {
+		r.remote = make(map[string]*drive.File)
+	}
+	pageToken := ""
+	for {
+		list, err := r.srv.ListFiles(ctx, &ListFilesOptions{
+			Query:     fmt.Sprintf("'%s' in parents and trashed = false", folderID),
+			PageSize:  1000,
+			PageToken: pageToken,
+			Fields:    syncListFields,
+		})
+		if err != nil {
+			return fmt.Errorf("listing Drive folder %s: %w", folderID, err)
+		}
+		for _, f := range list.Files {
+			rel := f.Name
+			if prefix != "" {
+				rel = prefix + "/" + f.Name
+			}
+			// Names that cannot be local file names are left alone.
+			if f.Name == "." || f.Name == ".." || strings.ContainsAny(f.Name, "/\\\x00") || r.excluded(rel) {
+				continue
+			}
+			if f.MimeType == folderMimeType {
+				r.folders[rel] = f.Id
+				if err := r.scanRemote(ctx, f.Id, rel); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, dup := r.remote[rel]; dup {
+				if !r.dups[rel] {
+					r.dups[rel] = true
+					r.report.Actions = append(r.report.Actions, SyncAction{Path: rel, Action: syncSkip, Reason: "several Drive files have this name"})
+					r.report.Counts[syncSkip]++
+				}
+				continue
+			}
+			r.remote[rel] = f
+		}
+		if list.NextPageToken == "" {
+			return nil
+		}
+		pageToken = list.NextPageToken
+	}
+}
+
+// plan decides what to do with one path. An empty Action means the two
+// sides already match.
+func (r *syncRun) plan(rel string) SyncAction {
+	l, rf, s := r.local[rel], r.remote[rel], r.state.Files[rel]
+	a := SyncAction{Path: rel}
+	if rf != nil {
+		a.FileID = rf.Id
+	}
+	push, pull := r.opts.Direction != SyncPull, r.opts.Direction != SyncPush
+
+	switch {
+	case rf != nil && rf.Md5Checksum == "":
+		a.Action, a.Reason = syncSkip, "Google Workspace file; no content to sync"
+		if l != nil {
+			a.Reason += " (a local file has the same name)"
+		}
+		return a
+
+	case l != nil && rf != nil:
+		if l.md5 == rf.Md5Checksum {
+			r.markSynced(rel, l, rf.Id)
+			return a
+		}
+		localChanged := s == nil || l.md5 != s.MD5
+		remoteChanged := s == nil || rf.Md5Checksum != s.MD5
+		switch {
+		case localChanged && !remoteChanged && push:
+			a.Action, a.Reason, a.Size = syncUpdate, "changed locally", l.size
+			return a
+		case remoteChanged && !localChanged && pull:
+			a.Action, a.Reason, a.Size = syncDownload, "changed in Drive", rf.Size
+			return a
+		}
+		return r.resolveConflict(a, l, rf, localChanged, remoteChanged)
+
+	case l != nil:
+		switch {
+		case s != nil && r.opts.Delete && pull && (!push || l.md5 == s.MD5):
+			a.Action, a.Reason = syncDeleteLocal, "deleted in Drive"
+		case s == nil && r.opts.Delete && !push:
+			a.Action, a.Reason = syncDeleteLocal, "not in Drive"
+		case push:
+			a.Action, a.Reason, a.Size = syncUpload, "new local file", l.size
+			if s != nil {
+				a.Reason = "deleted in Drive; restoring"
+			}
+		default:
+			a.Action, a.Reason = syncSkip, "only exists locally"
+		}
+		return a
+
+	default:
+		switch {
+		case s != nil && r.opts.Delete && push && (!pull || rf.Md5Checksum == s.MD5):
+			a.Action, a.Reason = syncTrashRemote, "deleted locally"
+		case s == nil && r.opts.Delete && !pull:
+			a.Action, a.Reason = syncTrashRemote, "not in the local directory"
+		case pull:
+			a.Action, a.Reason, a.Size = syncDownload, "new in Drive", rf.Size
+			if s != nil {
+				a.Reason = "deleted locally; restoring"
+			}
+		default:
+			a.Action, a.Reason = syncSkip, "only exists in Drive"
+		}
+		return a
+	}
+}
+
+// resolveConflict applies the conflict policy to a file whose two sides
+// differ in a way the direction cannot settle on its own.
+func (r *syncRun) resolveConflict(a SyncAction, l *localSyncFile, rf *drive.File, localChanged, remoteChanged bool) SyncAction {
+	what := "changed on both sides"
+	switch {
+	case !localChanged:
+		what = "changed in Drive"
+	case !remoteChanged:
+		what = "changed locally"
+	}
+
+	winner := r.opts.Conflict
+	if winner == ConflictNewer {
+		winner = ConflictLocal
+		if remoteTime, err := time.Parse(time.RFC3339, rf.ModifiedTime); err == nil && remoteTime.After(l.modTime) {
+			winner = ConflictRemote
+		}
+	}
+	switch {
+	case winner == ConflictLocal && r.opts.Direction != SyncPull:
+		a.Action, a.Reason, a.Size = syncUpdate, what+"; keeping the local file", l.size
+	case winner == ConflictRemote && r.opts.Direction != SyncPush:
+		a.Action, a.Reason, a.Size = syncDownload, what+"; keeping the Drive file", rf.Size
+	default:
+		a.Action, a.Reason = syncConflict, what
+		if winner != ConflictSkip {
+			a.Reason += fmt.Sprintf("; the %s file wins but a %s sync does not write there", winner, r.opts.Direction)
+		}
+	}
+	return a
+}
+
+// apply performs a planned action, recording failures on it.
+func (r *syncRun) apply(ctx context.Context, a *SyncAction) {
+	var err error
+	switch a.Action {
+	case syncUpload, syncUpdate:
+		err = r.upload(ctx, a)
+	case syncDownload:
+		err = r.download(ctx, a)
+	case syncTrashRemote:
+		_, err = r.srv.UpdateFile(ctx, a.FileID, &drive.File{Trashed: true})
+		if err == nil {
+			delete(r.state.Files, a.Path)
+		}
+	case syncDeleteLocal:
+		err = os.Remove(r.local[a.Path].path)
+		if err == nil {
+			delete(r.state.Files, a.Path)
+		}
+	}
+	if err != nil {
+		a.Error = err.Error()
+	}
+}
+
+// upload creates or updates the Drive file for a local file.
+func (r *syncRun) upload(ctx context.Context, a *SyncAction) error {
+	l := r.local[a.Path]
+	target := uploadTarget{account: r.opts.Account, file: &drive.File{MimeType: mime.TypeByExtension(path.Ext(a.Path))}}
+	if rf := r.remote[a.Path]; rf != nil && a.Action == syncUpdate {
+		target.fileID = rf.Id
+	} else {
+		parentID, err := r.ensureFolder(ctx, path.Dir(a.Path))
+		if err != nil {
+			return err
+		}
+		target.file.Name = path.Base(a.Path)
+		target.file.Parents = []string{parentID}
+	}
+	result, err := uploadLocalFile(ctx, r.srv, l.path, target)
+	if err != nil {
+		return err
+	}
+	a.FileID = result.file.Id
+	if result.file.Md5Checksum != "" && result.file.Md5Checksum != l.md5 {
+		return fmt.Errorf("uploaded content does not match the local file's MD5")
+	}
+	r.markSynced(a.Path, l, result.file.Id)
+	return nil
+}
+
+// download writes a Drive file to the local directory and gives it Drive's
+// modification time, so the newer-wins policy compares like with like.
+func (r *syncRun) download(ctx context.Context, a *SyncAction) error {
+	rf := r.remote[a.Path]
+	dest, err := common.ResolveAllowedLocalPath(filepath.Join(r.opts.LocalDir, filepath.FromSlash(a.Path)), r.root)
+	if err != nil {
+		return err
+	}
+	if _, err := downloadToLocalFile(ctx, r.srv, rf, dest, true); err != nil {
+		return err
+	}
+	if t, err := time.Parse(time.RFC3339, rf.ModifiedTime); err == nil {
+		_ = os.Chtimes(dest, t, t)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		return err
+	}
+	r.markSynced(a.Path, &localSyncFile{path: dest, size: info.Size(), modTime: info.ModTime(), md5: rf.Md5Checksum}, rf.Id)
+	return nil
+}
+
+// ensureFolder returns the Drive folder for a relative directory, creating
+// it and any missing parents.
+func (r *syncRun) ensureFolder(ctx context.Context, dir string) (string, error) {
+	if dir == "." {
+		dir = ""
+	}
+	if id, ok := r.folders[dir]; ok {
+		return id, nil
+	}
+	parentID, err := r.ensureFolder(ctx, path.Dir(dir))
+	if err != nil {
+		return "", err
+	}
+	folder, err := r.srv.CreateFile(ctx, &drive.File{Name: path.Base(dir), MimeType: folderMimeType, Parents: []string{parentID}}, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating Drive folder %q: %w", dir, err)
+	}
+	r.folders[dir] = folder.Id
+	return folder.Id, nil
+}
+
+// markSynced records that a path now has the same content on both sides.
+func (r *syncRun) markSynced(rel string, l *localSyncFile, remoteID string) {
+	r.state.Files[rel] = &syncStateEntry{MD5: l.md5, Size: l.size, ModTime: l.modTime, RemoteID: remoteID}
+}
+
+// loadSyncState reads the state file. State recorded for another folder, or
+// a missing or unreadable file, yields an empty state: every difference is
+// then treated as a change on both sides.
+func loadSyncState(statePath, folderID string) *syncState {
+	empty := &syncState{Version: syncStateVersion, FolderID: folderID, Files: map[string]*syncStateEntry{}}
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return empty
+	}
+	var state syncState
+	if json.Unmarshal(data, &state) != nil || state.FolderID != folderID || state.Files == nil {
+		return empty
+	}
+	return &state
+}
+
+// saveSyncState writes the state file atomically.
+func saveSyncState(statePath string, state *syncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath), 0o700); err != nil {
+		return err
+	}
+	tmp := statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, statePath)
+}
+
+// fileMD5 returns the hex MD5 of a local file.
+func fileMD5(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
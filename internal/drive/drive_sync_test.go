@@ -0,0 +1,407 @@
+package drive
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// fakeDriveTree is an in-memory Drive folder tree behind the mock service.
+// Service calls are serialized, so handlers may call it concurrently.
+type fakeDriveTree struct {
+	mu       sync.Mutex
+	files    map[string]*drive.File
+	content  map[string][]byte
+	sessions map[string]*drive.File // session URI → file being created or updated
+	nextID   int
+}
+
+func newFakeDriveTree(m *MockDriveService) *fakeDriveTree {
+	tree := &fakeDriveTree{files: map[string]*drive.File{}, content: map[string][]byte{}, sessions: map[string]*drive.File{}}
+	m.ListFilesFunc = func(_ context.Context, opts *ListFilesOptions) (*drive.FileList, error) {
+		tree.mu.Lock()
+		defer tree.mu.Unlock()
+		parent := strings.SplitN(opts.Query, "'", 3)[1]
+		list := &drive.FileList{}
+		for _, f := range tree.files {
+			if !f.Trashed && len(f.Parents) > 0 && f.Parents[0] == parent {
+				list.Files = append(list.Files, f)
+			}
+		}
+		return list, nil
+	}
+	m.GetFileFunc = func(_ context.Context, fileID string, _ string) (*drive.File, error) {
+		tree.mu.Lock()
+		defer tree.mu.Unlock()
+		if f, ok := tree.files[fileID]; ok {
+			return f, nil
+		}
+		return nil, fmt.Errorf("file %s not found", fileID)
+	}
+	m.CreateFileFunc = func(_ context.Context, file *drive.File, _ io.Reader) (*drive.File, error) {
+		tree.mu.Lock()
+		defer tree.mu.Unlock()
+		return tree.add(file.Name, file.Parents[0], file.MimeType, nil), nil
+	}
+	m.CopyFileFunc = func(_ context.Context, fileID string, file *drive.File) (*drive.File, error) {
+		tree.mu.Lock()
+		defer tree.mu.Unlock()
+		src := tree.files[fileID]
+		return tree.add(file.Name, file.Parents[0], src.MimeType, tree.content[fileID]), nil
+	}
+	m.UpdateFileFunc = func(_ context.Context, fileID string, file *drive.File) (*drive.File, error) {
+		tree.mu.Lock()
+		defer tree.mu.Unlock()
+		tree.files[fileID].Trashed = file.Trashed
+		return tree.files[fileID], nil
+	}
+	m.DownloadFileRangeFunc = func(_ context.Context, fileID string, offset int64) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(tree.content[fileID][offset:])), nil
+	}
+	m.StartUploadFunc = func(_ context.Context, fileID string, file *drive.File, _ int64) (string, error) {
+		uri := fmt.Sprintf("https://upload.example/%d", len(tree.sessions))
+		if fileID != "" {
+			tree.sessions[uri] = tree.files[fileID]
+		} else {
+			tree.sessions[uri] = &drive.File{Name: file.Name, Parents: file.Parents}
+		}
+		return uri, nil
+	}
+	m.UploadChunkFunc = func(_ context.Context, uri string, chunk io.Reader, _, _, size int64) (int64, *drive.File, error) {
+		data, _ := io.ReadAll(chunk)
+		f := tree.sessions[uri]
+		if f.Id == "" {
+			return size, tree.add(f.Name, f.Parents[0], "text/plain", data), nil
+		}
+		tree.setContent(f.Id, data, time.Now())
+		return size, f, nil
+	}
+	return tree
+}
+
+// add creates a file, or a folder when mimeType is folderMimeType.
+func (tree *fakeDriveTree) add(name, parent, mimeType string, content []byte) *drive.File {
+	tree.nextID++
+	f := &drive.File{Id: fmt.Sprintf("id%d", tree.nextID), Name: name, Parents: []string{parent}, MimeType: mimeType}
+	tree.files[f.Id] = f
+	if mimeType != folderMimeType {
+		tree.setContent(f.Id, content, time.Now())
+	}
+	return f
+}
+
+func (tree *fakeDriveTree) setContent(id string, content []byte, modified time.Time) {
+	sum := md5.Sum(content)
+	f := tree.files[id]
+	f.Md5Checksum, f.Size, f.ModifiedTime = hex.EncodeToString(sum[:]), int64(len(content)), modified.UTC().Format(time.RFC3339)
+	tree.content[id] = content
+}
+
+// find returns the file at a slash-separated path under root, or nil.
+func (tree *fakeDriveTree) find(root, rel string) *drive.File {
+	parent := root
+	var found *drive.File
+	for _, name := range strings.Split(rel, "/") {
+		found = nil
+		for _, f := range tree.files {
+			if !f.Trashed && f.Name == name && f.Parents[0] == parent {
+				found = f
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		parent = found.Id
+	}
+	return found
+}
+
+func writeSyncFile(t *testing.T, dir, rel, content string, modTime time.Time) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(p, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func syncActions(report *SyncReport) map[string]string {
+	actions := map[string]string{}
+	for _, a := range report.Actions {
+		actions[a.Path] = a.Action
+		if a.Error != "" {
+			actions[a.Path] += ": " + a.Error
+		}
+	}
+	return actions
+}
+
+func TestRunSync_PushCreatesTreeAndIsIncremental(t *testing.T) {
+	dir := t.TempDir()
+	useTransferDirs(t)
+	past := time.Now().Add(-time.Hour)
+	writeSyncFile(t, dir, "a.txt", "alpha", past)
+	writeSyncFile(t, dir, "sub/deep/b.txt", "bravo", past)
+	writeSyncFile(t, dir, "cache/skip.txt", "skip", past)
+	writeSyncFile(t, dir, "notes.tmp", "skip", past)
+
+	fixtures := NewDriveTestFixtures()
+	tree := newFakeDriveTree(fixtures.MockService)
+	opts := SyncOptions{LocalDir: dir, FolderID: "root1", Exclude: []string{"*.tmp", "cache"}}
+
+	plan, err := RunSync(context.Background(), fixtures.MockService, SyncOptions{LocalDir: dir, FolderID: "root1", Exclude: opts.Exclude, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := syncActions(plan); len(got) != 2 || got["a.txt"] != syncUpload || got["sub/deep/b.txt"] != syncUpload {
+		t.Fatalf("unexpected plan: %v", got)
+	}
+	if len(tree.files) != 0 {
+		t.Fatalf("dry run changed Drive: %d files", len(tree.files))
+	}
+	if _, err := os.Stat(filepath.Join(dir, DefaultSyncStateFile)); !os.IsNotExist(err) {
+		t.Fatal("dry run wrote the state file")
+	}
+
+	report, err := RunSync(context.Background(), fixtures.MockService, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Errors != 0 || report.Counts[syncUpload] != 2 {
+		t.Fatalf("unexpected report: %v", syncActions(report))
+	}
+	if f := tree.find("root1", "sub/deep/b.txt"); f == nil || string(tree.content[f.Id]) != "bravo" {
+		t.Fatalf("sub/deep/b.txt not uploaded into nested folders")
+	}
+
+	// The state file records the sync, so nothing is left to do.
+	again, err := RunSync(context.Background(), fixtures.MockService, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Actions) != 0 || again.Unchanged != 2 {
+		t.Fatalf("second run should be a no-op, got %v (unchanged %d)", syncActions(again), again.Unchanged)
+	}
+}
+
+func TestRunSync_BothDirectionsFollowTheChangedSide(t *testing.T) {
+	dir := t.TempDir()
+	useTransferDirs(t)
+	past := time.Now().Add(-time.Hour)
+	writeSyncFile(t, dir, "local.txt", "v1", past)
+	writeSyncFile(t, dir, "remote.txt", "v1", past)
+
+	fixtures := NewDriveTestFixtures()
+	tree := newFakeDriveTree(fixtures.MockService)
+	opts := SyncOptions{LocalDir: dir, FolderID: "root1", Direction: SyncBoth}
+	if _, err := RunSync(context.Background(), fixtures.MockService, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	writeSyncFile(t, dir, "local.txt", "v2 local", time.Now())
+	tree.setContent(tree.find("root1", "remote.txt").Id, []byte("v2 remote"), time.Now().Add(-2*time.Hour))
+	tree.add("new-remote.txt", "root1", "text/plain", []byte("fresh"))
+
+	report, err := RunSync(context.Background(), fixtures.MockService, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"local.txt": syncUpdate, "remote.txt": syncDownload, "new-remote.txt": syncDownload}
+	if got := syncActions(report); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	// A remote-only change wins even though it is older than the local file.
+	if data, _ := os.ReadFile(filepath.Join(dir, "remote.txt")); string(data) != "v2 remote" {
+		t.Errorf("remote.txt = %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "new-remote.txt")); string(data) != "fresh" {
+		t.Errorf("new-remote.txt = %q", data)
+	}
+	if f := tree.find("root1", "local.txt"); string(tree.content[f.Id]) != "v2 local" {
+		t.Errorf("local.txt in Drive = %q", tree.content[f.Id])
+	}
+
+	again, err := RunSync(context.Background(), fixtures.MockService, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Actions) != 0 {
+		t.Errorf("expected no changes after a two-way sync, got %v", syncActions(again))
+	}
+}
+
+func TestRunSync_ConflictPolicies(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction string
+		conflict  string
+		want      string
+		content   string
+	}{
+		{"newer keeps Drive", SyncBoth, ConflictNewer, syncDownload, "remote edit"},
+		{"local wins", SyncBoth, ConflictLocal, syncUpdate, "local edit"},
+		{"skip reports", SyncBoth, ConflictSkip, syncConflict, "local edit"},
+		{"push cannot keep Drive", SyncPush, ConflictRemote, syncConflict, "local edit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			useTransferDirs(t)
+			writeSyncFile(t, dir, "doc.txt", "base", time.Now().Add(-3*time.Hour))
+
+			fixtures := NewDriveTestFixtures()
+			tree := newFakeDriveTree(fixtures.MockService)
+			opts := SyncOptions{LocalDir: dir, FolderID: "root1", Direction: SyncBoth}
+			if _, err := RunSync(context.Background(), fixtures.MockService, opts); err != nil {
+				t.Fatal(err)
+			}
+
+			writeSyncFile(t, dir, "doc.txt", "local edit", time.Now().Add(-2*time.Hour))
+			remote := tree.find("root1", "doc.txt")
+			tree.setContent(remote.Id, []byte("remote edit"), time.Now().Add(-time.Hour))
+
+			opts.Direction, opts.Conflict = tt.direction, tt.conflict
+			report, err := RunSync(context.Background(), fixtures.MockService, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := syncActions(report)["doc.txt"]; got != tt.want {
+				t.Fatalf("action = %q, want %q", got, tt.want)
+			}
+			local, _ := os.ReadFile(filepath.Join(dir, "doc.txt"))
+			if tt.want == syncDownload && string(local) != tt.content || tt.want != syncDownload && string(local) != "local edit" {
+				t.Errorf("local content = %q", local)
+			}
+			if tt.want == syncUpdate && string(tree.content[remote.Id]) != tt.content {
+				t.Errorf("Drive content = %q", tree.content[remote.Id])
+			}
+		})
+	}
+}
+
+func TestRunSync_Deletions(t *testing.T) {
+	dir := t.TempDir()
+	useTransferDirs(t)
+	past := time.Now().Add(-time.Hour)
+	writeSyncFile(t, dir, "keep.txt", "keep", past)
+	writeSyncFile(t, dir, "gone.txt", "gone", past)
+
+	fixtures := NewDriveTestFixtures()
+	tree := newFakeDriveTree(fixtures.MockService)
+	opts := SyncOptions{LocalDir: dir, FolderID: "root1"}
+	if _, err := RunSync(context.Background(), fixtures.MockService, opts); err != nil {
+		t.Fatal(err)
+	}
+	gone := tree.find("root1", "gone.txt")
+	if err := os.Remove(filepath.Join(dir, "gone.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without delete, a push leaves the Drive copy alone.
+	report, err := RunSync(context.Background(), fixtures.MockService, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := syncActions(report)["gone.txt"]; got != syncSkip || gone.Trashed {
+		t.Fatalf("action = %q, trashed = %v", got, gone.Trashed)
+	}
+
+	opts.Delete = true
+	report, err = RunSync(context.Background(), fixtures.MockService, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := syncActions(report)["gone.txt"]; got != syncTrashRemote || !gone.Trashed {
+		t.Fatalf("action = %q, trashed = %v", got, gone.Trashed)
+	}
+}
+
+func TestRunSync_SkipsWorkspaceFilesAndUnsafeNames(t *testing.T) {
+	dir := t.TempDir()
+	useTransferDirs(t)
+	fixtures := NewDriveTestFixtures()
+	tree := newFakeDriveTree(fixtures.MockService)
+	doc := tree.add("Plan", "root1", "application/vnd.google-apps.document", nil)
+	doc.Md5Checksum = ""
+	tree.add("..", "root1", "text/plain", []byte("escape"))
+
+	report, err := RunSync(context.Background(), fixtures.MockService, SyncOptions{LocalDir: dir, FolderID: "root1", Direction: SyncPull})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := syncActions(report); len(got) != 1 || got["Plan"] != syncSkip {
+		t.Fatalf("unexpected actions: %v", got)
+	}
+}
+
+func TestRunSync_PullStaysInsideSymlinkedDirs(t *testing.T) {
+	dir, outside := t.TempDir(), t.TempDir()
+	useTransferDirs(t)
+	if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	fixtures := NewDriveTestFixtures()
+	tree := newFakeDriveTree(fixtures.MockService)
+	link := tree.add("link", "root1", folderMimeType, nil)
+	tree.add("x.txt", link.Id, "text/plain", []byte("escape"))
+	tree.add("ok.txt", "root1", "text/plain", []byte("fine"))
+
+	report, err := RunSync(context.Background(), fixtures.MockService, SyncOptions{LocalDir: dir, FolderID: "root1", Direction: SyncPull})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := syncActions(report)
+	if !strings.Contains(got["link/x.txt"], "outside the allowed directories") || got["ok.txt"] != syncDownload {
+		t.Errorf("unexpected actions: %v", got)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "x.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing written through the symlink, got %v", err)
+	}
+}
+
+func TestDriveSync_Tool(t *testing.T) {
+	allowed := t.TempDir()
+	useTransferDirs(t, allowed)
+	fixtures := NewDriveTestFixtures()
+	newFakeDriveTree(fixtures.MockService)
+
+	_, errText := runDriveTransfer(t, TestableDriveSync, fixtures, map[string]any{"local_dir": t.TempDir(), "folder_id": "root1"})
+	if !strings.Contains(errText, "outside the allowed directories") {
+		t.Errorf("expected the allowed-dirs check, got %q", errText)
+	}
+	_, errText = runDriveTransfer(t, TestableDriveSync, fixtures, map[string]any{"local_dir": allowed, "folder_id": "root1", "direction": "sideways"})
+	if !strings.Contains(errText, "invalid direction") {
+		t.Errorf("expected an invalid direction error, got %q", errText)
+	}
+
+	writeSyncFile(t, allowed, "a.txt", "alpha", time.Now())
+	data, errText := runDriveTransfer(t, TestableDriveSync, fixtures, map[string]any{
+		"local_dir": allowed,
+		"folder_id": "https://drive.google.com/open?id=root1",
+		"exclude":   []any{"*.json"},
+		"dry_run":   true,
+	})
+	if errText != "" {
+		t.Fatal(errText)
+	}
+	actions, _ := data["actions"].([]any)
+	if data["dry_run"] != true || data["folder_id"] != "root1" || len(actions) != 1 {
+		t.Errorf("unexpected result: %v", data)
+	}
+}
@@ -0,0 +1,60 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestableDriveSync syncs a local directory with a Drive folder.
+func TestableDriveSync(ctx context.Context, request mcp.CallToolRequest, deps *DriveHandlerDeps) (*mcp.CallToolResult, error) {
+	srv, errResult, ok := ResolveDriveServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	localDir := common.ParseStringArg(args, "local_dir", "")
+	if localDir == "" {
+		return mcp.NewToolResultError("local_dir parameter is required"), nil
+	}
+	if common.ParseStringArg(args, "folder_id", "") == "" {
+		return mcp.NewToolResultError("folder_id parameter is required"), nil
+	}
+	folderID, errResult := resolveOptionalRef(ctx, srv, args, "folder_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	localDir, err := resolveTransferPath(localDir)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid local_dir: %v", err)), nil
+	}
+
+	opts := SyncOptions{
+		LocalDir:  localDir,
+		FolderID:  folderID,
+		Direction: common.ParseStringArg(args, "direction", SyncPush),
+		Conflict:  common.ParseStringArg(args, "conflict", ConflictNewer),
+		Delete:    common.ParseBoolArg(args, "delete", false),
+		DryRun:    common.ParseBoolArg(args, "dry_run", false),
+		Account:   common.ParseStringArg(args, "account", ""),
+	}
+	if raw, ok := args["exclude"].([]any); ok {
+		for _, item := range raw {
+			if pattern, ok := item.(string); ok && pattern != "" {
+				opts.Exclude = append(opts.Exclude, pattern)
+			}
+		}
+	}
+	if err := ValidateSyncOptions(&opts); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	report, err := RunSync(ctx, srv, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Drive sync error: %v", err)), nil
+	}
+	return common.MarshalToolResult(report)
+}
@@ -12,6 +12,8 @@ import (
 const (
 	// DriveFileListFields contains fields for file listings (search, list)
 	DriveFileListFields = "nextPageToken,files(id,name,mimeType,size,createdTime,modifiedTime,parents,driveId,webViewLink)"
+	// DriveFileListLabelFields adds the labels requested with IncludeLabels to file listings
+	DriveFileListLabelFields = "nextPageToken,files(id,name,mimeType,size,createdTime,modifiedTime,parents,driveId,webViewLink,labelInfo)"
 	// DriveFileGetFields contains fields for single file retrieval (full metadata)
 	DriveFileGetFields = "id,name,mimeType,size,createdTime,modifiedTime,parents,driveId,webViewLink,webContentLink,description,starred,trashed,owners,permissions"
 	// DriveFileDownloadFields contains minimal fields for download operations
@@ -25,9 +27,9 @@ const (
 	// DriveFileTrashFields contains fields for trash operation responses
 	DriveFileTrashFields = "id,name,trashed"
 	// DrivePermissionFields contains fields for permission responses
-	DrivePermissionFields = "id,type,role,emailAddress"
+	DrivePermissionFields = "id,type,role,emailAddress,displayName,domain,deleted,allowFileDiscovery,expirationTime,pendingOwner"
 	// DrivePermissionListFields contains fields for permission list responses
-	DrivePermissionListFields = "permissions(id,type,role,emailAddress,displayName,domain)"
+	DrivePermissionListFields = "nextPageToken,permissions(" + DrivePermissionFields + ",permissionDetails(inherited,inheritedFrom))"
 	// DriveFileUploadFields contains fields for upload operation responses
 	DriveFileUploadFields = "id,name,mimeType,size,createdTime,webViewLink"
 	// DriveShareableLinkFields contains fields for shareable link responses
@@ -40,8 +42,14 @@ const (
 	DriveReplyListFields = "nextPageToken,replies(id,content,author(displayName,emailAddress),createdTime,modifiedTime,action)"
 	// DriveRevisionListFields contains fields for revision list responses
 	DriveRevisionListFields = "nextPageToken,revisions(id,mimeType,modifiedTime,lastModifyingUser(displayName,emailAddress),size,keepForever,originalFilename)"
+	// DriveSharedDriveFields contains fields for shared drive responses
+	DriveSharedDriveFields = "id,name,hidden,createdTime,restrictions,capabilities(canManageMembers,canRenameDrive,canChangeDomainUsersOnlyRestriction,canChangeCopyRequiresWriterPermissionRestriction,canChangeDriveMembersOnlyRestriction)"
+	// DriveSharedDriveListFields contains fields for shared drive list responses
+	DriveSharedDriveListFields = "nextPageToken,drives(" + DriveSharedDriveFields + ")"
 	// DriveRevisionGetFields contains fields for single revision responses
 	DriveRevisionGetFields = "id,mimeType,modifiedTime,lastModifyingUser(displayName,emailAddress),size,keepForever,originalFilename,exportLinks"
+	// DriveFileRestoreFields contains fields for revision restore responses
+	DriveFileRestoreFields = "id,name,mimeType,size,modifiedTime,headRevisionId,webViewLink"
 )
 
 // friendlyFileTypes maps friendly names to Google Drive MIME types for search filtering.
@@ -64,19 +72,36 @@ var friendlyFileTypes = map[string]string{
 // === Handle functions - generated via WrapHandler ===
 
 var (
-	HandleDriveSearch           = common.WrapHandler[DriveService](TestableDriveSearch)
-	HandleDriveGet              = common.WrapHandler[DriveService](TestableDriveGet)
-	HandleDriveDownload         = common.WrapHandler[DriveService](TestableDriveDownload)
-	HandleDriveUpload           = common.WrapHandler[DriveService](TestableDriveUpload)
-	HandleDriveList             = common.WrapHandler[DriveService](TestableDriveList)
-	HandleDriveCreateFolder     = common.WrapHandler[DriveService](TestableDriveCreateFolder)
-	HandleDriveMove             = common.WrapHandler[DriveService](TestableDriveMove)
-	HandleDriveCopy             = common.WrapHandler[DriveService](TestableDriveCopy)
-	HandleDriveTrash            = common.WrapHandler[DriveService](TestableDriveTrash)
-	HandleDriveDelete           = common.WrapHandler[DriveService](TestableDriveDelete)
-	HandleDriveShare            = common.WrapHandler[DriveService](TestableDriveShare)
-	HandleDriveGetPermissions   = common.WrapHandler[DriveService](TestableDriveGetPermissions)
-	HandleDriveGetShareableLink = common.WrapHandler[DriveService](TestableDriveGetShareableLink)
+	HandleDriveSearch            = common.WrapHandler[DriveService](TestableDriveSearch)
+	HandleDriveGet               = common.WrapHandler[DriveService](TestableDriveGet)
+	HandleDriveDownload          = common.WrapHandler[DriveService](TestableDriveDownload)
+	HandleDriveUpload            = common.WrapHandler[DriveService](TestableDriveUpload)
+	HandleDriveSync              = common.WrapHandler[DriveService](TestableDriveSync)
+	HandleDriveList              = common.WrapHandler[DriveService](TestableDriveList)
+	HandleDriveListChanges       = common.WrapHandler[DriveService](TestableDriveListChanges)
+	HandleDriveCreateFolder      = common.WrapHandler[DriveService](TestableDriveCreateFolder)
+	HandleDriveMove              = common.WrapHandler[DriveService](TestableDriveMove)
+	HandleDriveCopy              = common.WrapHandler[DriveService](TestableDriveCopy)
+	HandleDriveCopyFolder        = common.WrapHandler[DriveService](TestableDriveCopyFolder)
+	HandleDriveTree              = common.WrapHandler[DriveService](TestableDriveTree)
+	HandleDriveFolderStats       = common.WrapHandler[DriveService](TestableDriveFolderStats)
+	HandleDriveTrash             = common.WrapHandler[DriveService](TestableDriveTrash)
+	HandleDriveDelete            = common.WrapHandler[DriveService](TestableDriveDelete)
+	HandleDriveShare             = common.WrapHandler[DriveService](TestableDriveShare)
+	HandleDriveGetPermissions    = common.WrapHandler[DriveService](TestableDriveGetPermissions)
+	HandleDriveGetShareableLink  = common.WrapHandler[DriveService](TestableDriveGetShareableLink)
+	HandleDriveUnshare           = common.WrapHandler[DriveService](TestableDriveUnshare)
+	HandleDriveUpdatePermission  = common.WrapHandler[DriveService](TestableDriveUpdatePermission)
+	HandleDriveTransferOwnership = common.WrapHandler[DriveService](TestableDriveTransferOwnership)
+	HandleDriveSharingAudit      = common.WrapHandler[DriveService](TestableDriveSharingAudit)
+
+	// Shared drives
+	HandleDriveListSharedDrives        = common.WrapHandler[DriveService](TestableDriveListSharedDrives)
+	HandleDriveCreateSharedDrive       = common.WrapHandler[DriveService](TestableDriveCreateSharedDrive)
+	HandleDriveUpdateSharedDrive       = common.WrapHandler[DriveService](TestableDriveUpdateSharedDrive)
+	HandleDriveListSharedDriveMembers  = common.WrapHandler[DriveService](TestableDriveListSharedDriveMembers)
+	HandleDriveSetSharedDriveMember    = common.WrapHandler[DriveService](TestableDriveSetSharedDriveMember)
+	HandleDriveRemoveSharedDriveMember = common.WrapHandler[DriveService](TestableDriveRemoveSharedDriveMember)
 
 	// Comments & Replies
 	HandleDriveListComments  = common.WrapHandler[DriveService](TestableDriveListComments)
@@ -91,6 +116,14 @@ var (
 	HandleDriveListRevisions    = common.WrapHandler[DriveService](TestableDriveListRevisions)
 	HandleDriveGetRevision      = common.WrapHandler[DriveService](TestableDriveGetRevision)
 	HandleDriveDownloadRevision = common.WrapHandler[DriveService](TestableDriveDownloadRevision)
+	HandleDriveRestoreRevision  = common.WrapHandler[DriveService](TestableDriveRestoreRevision)
+	HandleDrivePinRevision      = common.WrapHandler[DriveService](TestableDrivePinRevision)
+	HandleDriveDiffRevisions    = common.WrapHandler[DriveService](TestableDriveDiffRevisions)
+
+	// Labels
+	HandleDriveListLabels   = common.WrapHandler[DriveService](TestableDriveListLabels)
+	HandleDriveGetLabels    = common.WrapHandler[DriveService](TestableDriveGetLabels)
+	HandleDriveModifyLabels = common.WrapHandler[DriveService](TestableDriveModifyLabels)
 )
 
 // formatFile formats a file for compact output
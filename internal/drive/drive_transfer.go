@@ -0,0 +1,333 @@
+package drive
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"google.golang.org/api/drive/v3"
+)
+
+const (
+	// transferChunkSize is the resumable upload chunk size. Drive requires a
+	// multiple of 256 KiB for every chunk but the last.
+	transferChunkSize = 8 << 20
+	// transferMaxRetries bounds consecutive failed chunks before giving up;
+	// the saved session lets a later call resume.
+	transferMaxRetries = 3
+	// uploadSessionLifetime is how long Drive keeps a resumable session.
+	uploadSessionLifetime = 7 * 24 * time.Hour
+	// partialSuffix marks a download in progress next to its output path.
+	partialSuffix = ".partial"
+	// DriveFileTransferFields are the fields needed to download to a local file.
+	DriveFileTransferFields = "id,name,mimeType,size,md5Checksum,modifiedTime"
+)
+
+// transferDirs limits local_path and output_path to the configured allowed
+// directories. Nil allows any path. Set by InitDefaultDriveHandlerDeps.
+var transferDirs *common.LocalDirPolicy
+
+// uploadStateDir holds the saved sessions of unfinished uploads.
+var uploadStateDir = filepath.Join(os.TempDir(), "gsuite-mcp-uploads")
+
+// resolveTransferPath resolves a local path and checks it against the
+// allowed directories.
+func resolveTransferPath(path string) (string, error) {
+	return common.ResolveAllowedLocalPath(path, transferDirs)
+}
+
+// uploadState is the saved session of an unfinished upload. It is only
+// reused while the local file is unchanged.
+type uploadState struct {
+	SessionURI string    `json:"session_uri"`
+	LocalPath  string    `json:"local_path"`
+	Size       int64     `json:"size"`
+	ModTime    time.Time `json:"mod_time"`
+	StartedAt  time.Time `json:"started_at"`
+}
+
+// uploadTarget identifies what a local upload creates or replaces.
+type uploadTarget struct {
+	fileID  string      // file whose content is replaced; empty to create
+	file    *drive.File // metadata for the new or updated file
+	account string
+}
+
+// stateKey names the upload's state file, so a retry of the same upload
+// (same account, local file and destination) finds its session.
+func (t uploadTarget) stateKey(localPath string) string {
+	parent := ""
+	if len(t.file.Parents) > 0 {
+		parent = t.file.Parents[0]
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{t.account, localPath, t.fileID, parent, t.file.Name}, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// transferResult describes a finished local transfer.
+type transferResult struct {
+	file        *drive.File
+	path        string
+	bytes       int64
+	resumedFrom int64
+	exportedAs  string
+}
+
+// uploadLocalFile uploads a local file with the resumable protocol. If an
+// earlier attempt left a live session for the same file and destination, the
+// upload continues from the last byte Drive acknowledged. On failure the
+// session is kept so calling again resumes.
+func uploadLocalFile(ctx context.Context, srv DriveService, localPath string, target uploadTarget) (*transferResult, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("open local file: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat local file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("local_path %q is a directory", localPath)
+	}
+	size := info.Size()
+
+	statePath := filepath.Join(uploadStateDir, target.stateKey(localPath)+".json")
+	state := loadUploadState(statePath)
+	result := &transferResult{path: localPath, bytes: size}
+
+	var offset int64
+	sessionURI := ""
+	if state != nil && state.Size == size && state.ModTime.Equal(info.ModTime()) && time.Since(state.StartedAt) < uploadSessionLifetime {
+		received, file, err := srv.UploadStatus(ctx, state.SessionURI, size)
+		switch {
+		case err == nil && file != nil:
+			os.Remove(statePath)
+			result.file, result.resumedFrom = file, size
+			return result, nil
+		case err == nil:
+			sessionURI, offset, result.resumedFrom = state.SessionURI, received, received
+		case !errors.Is(err, errUploadSessionExpired):
+			return nil, fmt.Errorf("checking saved upload session: %w", err)
+		}
+	}
+	if sessionURI == "" {
+		if sessionURI, err = srv.StartUpload(ctx, target.fileID, target.file, size); err != nil {
+			return nil, err
+		}
+		saveUploadState(statePath, &uploadState{
+			SessionURI: sessionURI,
+			LocalPath:  localPath,
+			Size:       size,
+			ModTime:    info.ModTime(),
+			StartedAt:  time.Now(),
+		})
+	}
+
+	failures := 0
+	for {
+		length := min(int64(transferChunkSize), size-offset)
+		next, file, err := srv.UploadChunk(ctx, sessionURI, io.NewSectionReader(f, offset, length), offset, length, size)
+		if err == nil && file != nil {
+			os.Remove(statePath)
+			result.file = file
+			return result, nil
+		}
+		if errors.Is(err, errUploadSessionExpired) {
+			os.Remove(statePath)
+			return nil, fmt.Errorf("upload session expired after %d of %d bytes; call again to start over", offset, size)
+		}
+		if err == nil && next > offset {
+			offset, failures = next, 0
+			continue
+		}
+
+		// The chunk failed or made no progress: ask Drive where it stands.
+		failures++
+		if failures > transferMaxRetries || ctx.Err() != nil {
+			if err == nil {
+				err = errors.New("no progress")
+			}
+			return nil, fmt.Errorf("upload interrupted after %d of %d bytes (%w); call again with the same arguments to resume", offset, size, err)
+		}
+		received, file, statusErr := srv.UploadStatus(ctx, sessionURI, size)
+		if statusErr == nil && file != nil {
+			os.Remove(statePath)
+			result.file = file
+			return result, nil
+		}
+		if statusErr == nil {
+			offset = received
+		}
+	}
+}
+
+// loadUploadState reads a saved upload session, or returns nil.
+func loadUploadState(path string) *uploadState {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var state uploadState
+	if json.Unmarshal(data, &state) != nil || state.SessionURI == "" {
+		return nil
+	}
+	return &state
+}
+
+// saveUploadState records an upload session so an interrupted upload can
+// resume. Failing to save only loses that ability, so errors are ignored.
+func saveUploadState(path string, state *uploadState) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(path), 0o700) == nil {
+		_ = os.WriteFile(path, data, 0o600)
+	}
+}
+
+// downloadState identifies the Drive file a partial download belongs to, so
+// a changed file is downloaded afresh instead of appended to.
+type downloadState struct {
+	FileID       string `json:"file_id"`
+	Size         int64  `json:"size"`
+	MD5          string `json:"md5,omitempty"`
+	ModifiedTime string `json:"modified_time"`
+}
+
+// downloadToLocalFile streams a Drive file to outputPath. Binary content is
+// written to "<output>.partial" first; if a previous attempt left one for the
+// same revision of the file, the download continues from its end with a
+// ranged request. The finished file is checked against Drive's size and MD5
+// before being moved into place. Workspace files are exported instead, which
+// cannot be resumed.
+func downloadToLocalFile(ctx context.Context, srv DriveService, file *drive.File, outputPath string, overwrite bool) (*transferResult, error) {
+	if info, err := os.Stat(outputPath); err == nil {
+		if info.IsDir() {
+			return nil, fmt.Errorf("output path %q is a directory", outputPath)
+		}
+		if !overwrite {
+			return nil, fmt.Errorf("output file %q already exists; pass overwrite=true to replace it", outputPath)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o700); err != nil {
+		return nil, fmt.Errorf("create output directory: %w", err)
+	}
+
+	partialPath := outputPath + partialSuffix
+	statePath := partialPath + ".json"
+	result := &transferResult{file: file, path: outputPath}
+
+	var body io.ReadCloser
+	var err error
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if strings.HasPrefix(file.MimeType, "application/vnd.google-apps.") {
+		exportMimeType, ok := googleWorkspaceExportMIME[file.MimeType]
+		if !ok {
+			return nil, fmt.Errorf("cannot download Google Workspace file of type: %s", file.MimeType)
+		}
+		result.exportedAs = exportMimeType
+		if body, err = srv.ExportFile(ctx, file.Id, exportMimeType); err != nil {
+			return nil, fmt.Errorf("exporting file: %w", err)
+		}
+	} else {
+		want := downloadState{FileID: file.Id, Size: file.Size, MD5: file.Md5Checksum, ModifiedTime: file.ModifiedTime}
+		if info, err := os.Stat(partialPath); err == nil && loadDownloadState(statePath) == want && info.Size() <= file.Size {
+			result.resumedFrom = info.Size()
+			flags = os.O_WRONLY | os.O_APPEND
+		} else {
+			data, _ := json.Marshal(want)
+			if err := os.WriteFile(statePath, data, 0o600); err != nil {
+				return nil, fmt.Errorf("write download state: %w", err)
+			}
+		}
+		if result.resumedFrom < file.Size || file.Size == 0 {
+			if body, err = srv.DownloadFileRange(ctx, file.Id, result.resumedFrom); err != nil {
+				return nil, fmt.Errorf("downloading file: %w", err)
+			}
+		}
+	}
+
+	out, err := os.OpenFile(partialPath, flags, 0o600)
+	if err != nil {
+		if body != nil {
+			body.Close()
+		}
+		return nil, fmt.Errorf("open output file %q: %w", partialPath, err)
+	}
+	var written int64
+	if body != nil {
+		written, err = io.Copy(out, body)
+		body.Close()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	result.bytes = result.resumedFrom + written
+	if err != nil {
+		if result.exportedAs != "" {
+			os.Remove(partialPath)
+			return nil, fmt.Errorf("export interrupted: %w", err)
+		}
+		return nil, fmt.Errorf("download interrupted after %d of %d bytes (%w); call again with the same arguments to resume", result.bytes, file.Size, err)
+	}
+
+	if result.exportedAs == "" {
+		if err := verifyDownload(partialPath, file); err != nil {
+			os.Remove(partialPath)
+			os.Remove(statePath)
+			return nil, err
+		}
+		os.Remove(statePath)
+	}
+	if !overwrite {
+		if _, err := os.Stat(outputPath); err == nil {
+			return nil, fmt.Errorf("output file %q already exists; pass overwrite=true to replace it", outputPath)
+		}
+	}
+	if err := os.Rename(partialPath, outputPath); err != nil {
+		return nil, fmt.Errorf("move download into place: %w", err)
+	}
+	return result, nil
+}
+
+// loadDownloadState reads the state saved next to a partial download.
+func loadDownloadState(path string) downloadState {
+	var state downloadState
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+// verifyDownload checks a completed download against Drive's size and MD5.
+func verifyDownload(path string, file *drive.File) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("verify download: %w", err)
+	}
+	defer f.Close()
+	h := md5.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return fmt.Errorf("verify download: %w", err)
+	}
+	if n != file.Size {
+		return fmt.Errorf("download incomplete: got %d bytes, expected %d", n, file.Size)
+	}
+	if file.Md5Checksum != "" && hex.EncodeToString(h.Sum(nil)) != file.Md5Checksum {
+		return fmt.Errorf("downloaded content does not match Drive's MD5 checksum; removed the partial file")
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package drive
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/drive/v3"
+)
+
+// useTransferDirs points upload state at a temp dir and limits local paths
+// to allowed, restoring both when the test ends.
+func useTransferDirs(t *testing.T, allowed ...string) {
+	t.Helper()
+	policy, err := common.NewLocalDirPolicy(allowed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevDirs, prevState := transferDirs, uploadStateDir
+	transferDirs, uploadStateDir = policy, t.TempDir()
+	t.Cleanup(func() { transferDirs, uploadStateDir = prevDirs, prevState })
+}
+
+func runDriveTransfer(t *testing.T, fn func(context.Context, mcp.CallToolRequest, *DriveHandlerDeps) (*mcp.CallToolResult, error), fixtures *DriveTestFixtures, args map[string]any) (map[string]any, string) {
+	t.Helper()
+	result, err := fn(context.Background(), common.CreateMCPRequest(args), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if result.IsError {
+		return nil, text
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	return data, ""
+}
+
+// fakeUploadSession is a resumable upload session that can drop the
+// connection part-way through a chunk.
+type fakeUploadSession struct {
+	received   []byte
+	starts     int
+	failAfter  int  // bytes of the next chunk stored before failing; -1 disables
+	networkOff bool // status queries fail too
+}
+
+func (s *fakeUploadSession) install(m *MockDriveService) {
+	m.StartUploadFunc = func(_ context.Context, fileID string, file *drive.File, size int64) (string, error) {
+		s.starts++
+		s.received = nil
+		return "https://upload.example/session-1", nil
+	}
+	m.UploadStatusFunc = func(_ context.Context, _ string, _ int64) (int64, *drive.File, error) {
+		if s.networkOff {
+			return 0, nil, errors.New("network unreachable")
+		}
+		return int64(len(s.received)), nil, nil
+	}
+	m.UploadChunkFunc = func(_ context.Context, _ string, chunk io.Reader, offset, length, size int64) (int64, *drive.File, error) {
+		if offset != int64(len(s.received)) {
+			return 0, nil, errors.New("chunk does not start at the received offset")
+		}
+		data, _ := io.ReadAll(chunk)
+		if s.failAfter >= 0 {
+			s.received = append(s.received, data[:s.failAfter]...)
+			s.failAfter = -1
+			s.networkOff = true
+			return 0, nil, errors.New("connection reset")
+		}
+		s.received = append(s.received, data...)
+		if int64(len(s.received)) < size {
+			return int64(len(s.received)), nil, nil
+		}
+		sum := md5.Sum(s.received)
+		return size, &drive.File{Id: "uploaded-1", Name: "report.bin", Size: size, Md5Checksum: hex.EncodeToString(sum[:])}, nil
+	}
+}
+
+func TestDriveUpload_LocalPathResumes(t *testing.T) {
+	dir := t.TempDir()
+	useTransferDirs(t, dir)
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	localPath := filepath.Join(dir, "report.bin")
+	if err := os.WriteFile(localPath, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures := NewDriveTestFixtures()
+	session := &fakeUploadSession{failAfter: 4096}
+	session.install(fixtures.MockService)
+	args := map[string]any{"local_path": localPath, "parent_id": "folder001"}
+
+	_, errText := runDriveTransfer(t, TestableDriveUpload, fixtures, args)
+	if !strings.Contains(errText, "call again with the same arguments to resume") {
+		t.Fatalf("expected a resumable interruption, got %q", errText)
+	}
+
+	session.networkOff = false
+	data, errText := runDriveTransfer(t, TestableDriveUpload, fixtures, args)
+	if errText != "" {
+		t.Fatalf("resume failed: %s", errText)
+	}
+	if session.starts != 1 {
+		t.Errorf("expected the saved session to be reused, got %d sessions", session.starts)
+	}
+	if data["resumed_from"] != float64(4096) || data["file_id"] != "uploaded-1" || data["size"] != float64(len(content)) {
+		t.Errorf("unexpected result: %v", data)
+	}
+	if !bytes.Equal(session.received, content) {
+		t.Errorf("uploaded %d bytes, want the %d-byte file", len(session.received), len(content))
+	}
+	if entries, _ := os.ReadDir(uploadStateDir); len(entries) != 0 {
+		t.Errorf("upload state should be removed after completion, found %d files", len(entries))
+	}
+}
+
+func TestDriveUpload_LocalPathChangedFileStartsOver(t *testing.T) {
+	dir := t.TempDir()
+	useTransferDirs(t, dir)
+	localPath := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(localPath, []byte(strings.Repeat("a", 100)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures := NewDriveTestFixtures()
+	session := &fakeUploadSession{failAfter: 10}
+	session.install(fixtures.MockService)
+	args := map[string]any{"local_path": localPath}
+	if _, errText := runDriveTransfer(t, TestableDriveUpload, fixtures, args); errText == "" {
+		t.Fatal("expected the first upload to be interrupted")
+	}
+
+	if err := os.WriteFile(localPath, []byte(strings.Repeat("b", 120)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	session.networkOff = false
+	data, errText := runDriveTransfer(t, TestableDriveUpload, fixtures, args)
+	if errText != "" {
+		t.Fatalf("upload failed: %s", errText)
+	}
+	if session.starts != 2 || data["resumed_from"] != nil || string(session.received) != strings.Repeat("b", 120) {
+		t.Errorf("a changed file should start a new session: starts=%d result=%v", session.starts, data)
+	}
+}
+
+func TestDriveUpload_LocalPathValidation(t *testing.T) {
+	allowed := t.TempDir()
+	useTransferDirs(t, allowed)
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fixtures := NewDriveTestFixtures()
+
+	tests := []struct {
+		name       string
+		args       map[string]any
+		errContain string
+	}{
+		{"outside allowed dirs", map[string]any{"local_path": outside}, "outside the allowed directories"},
+		{"both content and local_path", map[string]any{"local_path": outside, "content": "hi", "name": "x"}, "not both"},
+		{"directory", map[string]any{"local_path": allowed}, "is a directory"},
+		{"missing file", map[string]any{"local_path": filepath.Join(allowed, "nope")}, "open local file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errText := runDriveTransfer(t, TestableDriveUpload, fixtures, tt.args)
+			if !strings.Contains(errText, tt.errContain) {
+				t.Errorf("expected error containing %q, got %q", tt.errContain, errText)
+			}
+		})
+	}
+}
+
+// flakyReader returns n bytes of data and then fails.
+type flakyReader struct {
+	data []byte
+	n    int
+}
+
+func (r *flakyReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, errors.New("connection reset")
+	}
+	k := copy(p, r.data[:min(len(r.data), r.n)])
+	r.data, r.n = r.data[k:], r.n-k
+	return k, nil
+}
+
+func (r *flakyReader) Close() error { return nil }
+
+func TestDriveDownload_OutputPathResumes(t *testing.T) {
+	dir := t.TempDir()
+	useTransferDirs(t, dir)
+	content := bytes.Repeat([]byte("drive-bytes "), 500)
+	sum := md5.Sum(content)
+
+	fixtures := NewDriveTestFixtures()
+	fixtures.MockService.GetFileFunc = func(_ context.Context, fileID string, fields string) (*drive.File, error) {
+		if fields != DriveFileTransferFields {
+			t.Errorf("GetFile fields = %q", fields)
+		}
+		return &drive.File{Id: fileID, Name: "big.bin", MimeType: "application/octet-stream", Size: int64(len(content)), Md5Checksum: hex.EncodeToString(sum[:]), ModifiedTime: "2026-10-01T00:00:00Z"}, nil
+	}
+	var offsets []int64
+	fixtures.MockService.DownloadFileRangeFunc = func(_ context.Context, _ string, offset int64) (io.ReadCloser, error) {
+		offsets = append(offsets, offset)
+		if len(offsets) == 1 {
+			return &flakyReader{data: content, n: 1000}, nil
+		}
+		return io.NopCloser(bytes.NewReader(content[offset:])), nil
+	}
+
+	args := map[string]any{"file_id": "bin-1", "output_path": dir}
+	_, errText := runDriveTransfer(t, TestableDriveDownload, fixtures, args)
+	if !strings.Contains(errText, "call again with the same arguments to resume") {
+		t.Fatalf("expected a resumable interruption, got %q", errText)
+	}
+
+	data, errText := runDriveTransfer(t, TestableDriveDownload, fixtures, args)
+	if errText != "" {
+		t.Fatalf("resume failed: %s", errText)
+	}
+	want := filepath.Join(dir, "big.bin")
+	if data["path"] != want || data["resumed_from"] != float64(1000) || data["size"] != float64(len(content)) || data["content"] != nil {
+		t.Errorf("unexpected result: %v", data)
+	}
+	if len(offsets) != 2 || offsets[1] != 1000 {
+		t.Errorf("expected a ranged request from byte 1000, got offsets %v", offsets)
+	}
+	got, err := os.ReadFile(want)
+	if err != nil || !bytes.Equal(got, content) {
+		t.Errorf("downloaded file differs from Drive content (err %v)", err)
+	}
+	if _, err := os.Stat(want + partialSuffix); !os.IsNotExist(err) {
+		t.Error("partial file should be gone after completion")
+	}
+
+	_, errText = runDriveTransfer(t, TestableDriveDownload, fixtures, args)
+	if !strings.Contains(errText, "already exists") {
+		t.Errorf("expected an overwrite error, got %q", errText)
+	}
+}
+
+func TestDriveDownload_OutputPathChecksumMismatch(t *testing.T) {
+	dir := t.TempDir()
+	useTransferDirs(t, dir)
+	fixtures := NewDriveTestFixtures()
+	fixtures.MockService.GetFileFunc = func(_ context.Context, fileID string, _ string) (*drive.File, error) {
+		return &drive.File{Id: fileID, Name: "a.bin", Size: 5, Md5Checksum: "00000000000000000000000000000000"}, nil
+	}
+	fixtures.MockService.DownloadFileRangeFunc = func(_ context.Context, _ string, _ int64) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("hello")), nil
+	}
+
+	out := filepath.Join(dir, "a.bin")
+	_, errText := runDriveTransfer(t, TestableDriveDownload, fixtures, map[string]any{"file_id": "a", "output_path": out})
+	if !strings.Contains(errText, "MD5") {
+		t.Errorf("expected a checksum error, got %q", errText)
+	}
+	for _, p := range []string{out, out + partialSuffix} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist after a checksum failure", p)
+		}
+	}
+}
+
+func TestDriveDownload_OutputPathExportAndPolicy(t *testing.T) {
+	dir := t.TempDir()
+	useTransferDirs(t, dir)
+	fixtures := NewDriveTestFixtures()
+	fixtures.MockService.GetFileFunc = func(_ context.Context, fileID string, _ string) (*drive.File, error) {
+		return &drive.File{Id: fileID, Name: "Plan/2026", MimeType: "application/vnd.google-apps.document"}, nil
+	}
+	fixtures.MockService.ExportFileFunc = func(_ context.Context, _ string, mimeType string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("exported " + mimeType)), nil
+	}
+
+	data, errText := runDriveTransfer(t, TestableDriveDownload, fixtures, map[string]any{"file_id": "doc-1", "output_path": dir})
+	if errText != "" {
+		t.Fatalf("export failed: %s", errText)
+	}
+	want := filepath.Join(dir, "Plan_2026")
+	if data["path"] != want || data["exported_as"] != "text/plain" {
+		t.Errorf("unexpected result: %v", data)
+	}
+	if got, _ := os.ReadFile(want); string(got) != "exported text/plain" {
+		t.Errorf("exported content = %q", got)
+	}
+
+	_, errText = runDriveTransfer(t, TestableDriveDownload, fixtures, map[string]any{"file_id": "doc-1", "output_path": filepath.Join(t.TempDir(), "x.txt")})
+	if !strings.Contains(errText, "outside the allowed directories") {
+		t.Errorf("expected a policy error, got %q", errText)
+	}
+}
@@ -2,58 +2,193 @@ package drive
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/drivelabels/v2"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
 )
 
 // FilteredDriveService wraps a DriveService and enforces drive access restrictions.
-// Files in restricted drives are filtered from list results and blocked from direct access.
+// Files in restricted drives or folders are filtered from list results and blocked from direct access.
 type FilteredDriveService struct {
 	inner  DriveService
 	filter *common.DriveAccessFilter
+	lookup *accessLookup
 }
 
 // NewFilteredDriveService creates a filtered service wrapping the given inner service.
 func NewFilteredDriveService(inner DriveService, filter *common.DriveAccessFilter) *FilteredDriveService {
-	return &FilteredDriveService{inner: inner, filter: filter}
+	return &FilteredDriveService{inner: inner, filter: filter, lookup: &accessLookup{srv: inner}}
 }
 
-// ensureResolved resolves drive names to IDs using the inner service's ListDrives.
-func (f *FilteredDriveService) ensureResolved(ctx context.Context) {
-	if f.filter.IsResolved() {
-		return
+// accessLookup answers the access filter's questions through the unfiltered
+// service, so rules can name drives and folders the filter itself hides.
+type accessLookup struct {
+	srv  DriveService
+	mu   sync.Mutex
+	root *common.FileLocation
+}
+
+// NewAccessLookup creates the lookup the drive access filter uses to check
+// files opened by other services, such as Docs and Sheets.
+func NewAccessLookup(ctx context.Context, client *http.Client) (common.DriveLookup, error) {
+	srv, err := drive.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("creating drive service: %w", err)
 	}
+	return &accessLookup{srv: NewRealDriveService(srv)}, nil
+}
 
-	var allDrives []common.DriveInfo
+func (l *accessLookup) ListDrives(ctx context.Context) ([]common.DriveInfo, error) {
+	var drives []common.DriveInfo
 	pageToken := ""
 	for {
-		result, err := f.inner.ListDrives(ctx, 100, pageToken)
+		result, err := l.srv.ListDrives(ctx, 100, pageToken)
 		if err != nil {
-			return // fail open
+			return nil, err
 		}
 		for _, d := range result.Drives {
-			allDrives = append(allDrives, common.DriveInfo{ID: d.Id, Name: d.Name})
+			drives = append(drives, common.DriveInfo{ID: d.Id, Name: d.Name})
 		}
 		if result.NextPageToken == "" {
-			break
+			return drives, nil
 		}
 		pageToken = result.NextPageToken
 	}
-	f.filter.ResolveDriveNames(allDrives)
 }
 
-// checkFileAccess verifies that the file is in an allowed drive.
-func (f *FilteredDriveService) checkFileAccess(ctx context.Context, fileID string) error {
-	f.ensureResolved(ctx)
-	file, err := f.inner.GetFile(ctx, fileID, "driveId")
+// GetFileLocation looks up a file's drive and parents. The account's My
+// Drive root is looked up once per lookup.
+func (l *accessLookup) GetFileLocation(ctx context.Context, fileID string) (*common.FileLocation, error) {
+	if fileID == "root" {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.root != nil {
+			return l.root, nil
+		}
+	}
+	file, err := l.srv.GetFile(ctx, fileID, "id,driveId,parents")
 	if err != nil {
-		return nil // fail open
+		return nil, err
+	}
+	loc := locationOf(file)
+	if fileID == "root" {
+		l.root = loc
+	}
+	return loc, nil
+}
+
+// LookupRule resolves a path rule with a PathResolver, or checks that a
+// file ID exists. A path matching several items names all of them.
+func (l *accessLookup) LookupRule(ctx context.Context, rule string) ([]string, error) {
+	if !common.IsDrivePathRule(rule) {
+		file, err := l.srv.GetFile(ctx, rule, "id")
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if file.Id == "" {
+			return nil, nil
+		}
+		return []string{file.Id}, nil
 	}
-	return f.filter.Check(file.DriveId)
+
+	file, err := NewPathResolver(l.srv).LookupPath(ctx, rule)
+	var ambiguous *AmbiguousPathError
+	switch {
+	case errors.As(err, &ambiguous):
+		ids := make([]string, 0, len(ambiguous.Candidates))
+		for _, c := range ambiguous.Candidates {
+			ids = append(ids, c.Id)
+		}
+		return ids, nil
+	case errors.Is(err, ErrPathNotFound), errors.Is(err, ErrNotAFolder):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return []string{file.Id}, nil
+}
+
+// locationOf returns where a file sits, for the access filter.
+func locationOf(file *drive.File) *common.FileLocation {
+	return &common.FileLocation{ID: file.Id, DriveID: file.DriveId, Parents: file.Parents}
+}
+
+// ensureResolved resolves drive names to IDs using the inner service's ListDrives.
+func (f *FilteredDriveService) ensureResolved(ctx context.Context) {
+	f.filter.Resolve(ctx, f.lookup)
+}
+
+// checkFileAccess verifies that the file is in an allowed drive and folder.
+func (f *FilteredDriveService) checkFileAccess(ctx context.Context, fileID string) error {
+	return f.filter.CheckFile(ctx, f.lookup, fileID)
+}
+
+// checkFile verifies a file the inner service returned. A file without
+// parents may just not have had them requested, so it is looked up again.
+func (f *FilteredDriveService) checkFile(ctx context.Context, file *drive.File) error {
+	if f.filter.HasFolderRules() && len(file.Parents) == 0 && file.Id != "" {
+		return f.checkFileAccess(ctx, file.Id)
+	}
+	return f.filter.CheckLocation(ctx, f.lookup, locationOf(file))
+}
+
+// accessFields adds the fields the filter needs to a file's field list.
+func (f *FilteredDriveService) accessFields(fields string) string {
+	fields = ensureDriveIDField(fields)
+	if f.filter.HasFolderRules() {
+		for _, name := range []string{"id", "parents"} {
+			if !hasField(fields, name) {
+				fields += "," + name
+			}
+		}
+	}
+	return fields
+}
+
+// accessListFields adds the fields the filter needs inside files(...) of a
+// file list's field list.
+func (f *FilteredDriveService) accessListFields(fields string) string {
+	if fields == "" {
+		return DriveFileListFields
+	}
+	prefix, rest, ok := strings.Cut(fields, "files(")
+	if !ok {
+		return fields
+	}
+	names := []string{"driveId"}
+	if f.filter.HasFolderRules() {
+		names = append(names, "id", "parents")
+	}
+	inner, _, _ := strings.Cut(rest, ")")
+	for _, name := range names {
+		if !hasField(inner, name) {
+			rest = name + "," + rest
+		}
+	}
+	return prefix + "files(" + rest
+}
+
+// hasField reports whether a flat field list includes name.
+func hasField(fields, name string) bool {
+	for _, field := range strings.Split(fields, ",") {
+		if strings.TrimSpace(field) == name {
+			return true
+		}
+	}
+	return false
 }
 
 // ensureDriveIDField ensures driveId is included in the requested fields.
@@ -73,23 +208,24 @@ func ensureDriveIDField(fields string) string {
 func (f *FilteredDriveService) ListFiles(ctx context.Context, opts *ListFilesOptions) (*drive.FileList, error) {
 	f.ensureResolved(ctx)
 
-	// Copy opts to avoid mutating the caller's struct when injecting driveId field
-	innerOpts := opts
-	if opts != nil && opts.Fields != "" {
+	// Copy opts to avoid mutating the caller's struct when injecting the
+	// fields the filter needs
+	innerOpts := &ListFilesOptions{}
+	if opts != nil {
 		cp := *opts
-		cp.Fields = ensureDriveIDField(opts.Fields)
 		innerOpts = &cp
 	}
+	innerOpts.Fields = f.accessListFields(innerOpts.Fields)
 
 	result, err := f.inner.ListFiles(ctx, innerOpts)
 	if err != nil {
 		return nil, fmt.Errorf("listing files: %w", err)
 	}
 
-	// Filter files by drive access
+	// Filter files by drive and folder access
 	filtered := make([]*drive.File, 0, len(result.Files))
 	for _, file := range result.Files {
-		if f.filter.Check(file.DriveId) == nil {
+		if f.checkFile(ctx, file) == nil {
 			filtered = append(filtered, file)
 		}
 	}
@@ -101,11 +237,11 @@ func (f *FilteredDriveService) ListFiles(ctx context.Context, opts *ListFilesOpt
 // GetFile checks drive access after retrieving file metadata.
 func (f *FilteredDriveService) GetFile(ctx context.Context, fileID string, fields string) (*drive.File, error) {
 	f.ensureResolved(ctx)
-	file, err := f.inner.GetFile(ctx, fileID, ensureDriveIDField(fields))
+	file, err := f.inner.GetFile(ctx, fileID, f.accessFields(fields))
 	if err != nil {
 		return nil, fmt.Errorf("getting file %s: %w", fileID, err)
 	}
-	if err := f.filter.Check(file.DriveId); err != nil {
+	if err := f.checkFile(ctx, file); err != nil {
 		return nil, fmt.Errorf("GetFile drive access check: %w", err)
 	}
 	return file, nil
@@ -113,14 +249,24 @@ func (f *FilteredDriveService) GetFile(ctx context.Context, fileID string, field
 
 // CreateFile checks the destination parent's drive before creating.
 func (f *FilteredDriveService) CreateFile(ctx context.Context, file *drive.File, content io.Reader) (*drive.File, error) {
-	if len(file.Parents) > 0 {
-		if err := f.checkFileAccess(ctx, file.Parents[0]); err != nil {
-			return nil, fmt.Errorf("CreateFile parent access check: %w", err)
-		}
+	if err := f.checkParentAccess(ctx, file); err != nil {
+		return nil, fmt.Errorf("CreateFile parent access check: %w", err)
 	}
 	return f.inner.CreateFile(ctx, file, content)
 }
 
+// checkParentAccess checks the folder a new file will be created in. A file
+// without parents lands in My Drive's root, which folder rules may exclude.
+func (f *FilteredDriveService) checkParentAccess(ctx context.Context, file *drive.File) error {
+	if len(file.Parents) > 0 {
+		return f.checkFileAccess(ctx, file.Parents[0])
+	}
+	if f.filter.HasFolderRules() {
+		return f.checkFileAccess(ctx, "root")
+	}
+	return nil
+}
+
 // UpdateFile checks drive access before updating.
 func (f *FilteredDriveService) UpdateFile(ctx context.Context, fileID string, file *drive.File) (*drive.File, error) {
 	if err := f.checkFileAccess(ctx, fileID); err != nil {
@@ -188,9 +334,46 @@ func (f *FilteredDriveService) GetDrive(ctx context.Context, driveID string) (*d
 	return f.inner.GetDrive(ctx, driveID)
 }
 
-// ListDrives passes through to inner service (needed for name resolution).
+// ListDrives returns only the shared drives the filter allows. Name
+// resolution lists drives through the inner service instead.
 func (f *FilteredDriveService) ListDrives(ctx context.Context, pageSize int64, pageToken string) (*drive.DriveList, error) {
-	return f.inner.ListDrives(ctx, pageSize, pageToken)
+	f.ensureResolved(ctx)
+	result, err := f.inner.ListDrives(ctx, pageSize, pageToken)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*drive.Drive, 0, len(result.Drives))
+	for _, d := range result.Drives {
+		if f.filter.Check(d.Id) == nil {
+			filtered = append(filtered, d)
+		}
+	}
+	result.Drives = filtered
+	return result, nil
+}
+
+// CreateDrive passes through; a new drive is blocked or allowed like any
+// other once the filter sees it.
+func (f *FilteredDriveService) CreateDrive(ctx context.Context, requestID string, d *drive.Drive) (*drive.Drive, error) {
+	return f.inner.CreateDrive(ctx, requestID, d)
+}
+
+// UpdateDrive checks drive access before updating.
+func (f *FilteredDriveService) UpdateDrive(ctx context.Context, driveID string, d *drive.Drive) (*drive.Drive, error) {
+	f.ensureResolved(ctx)
+	if err := f.filter.Check(driveID); err != nil {
+		return nil, fmt.Errorf("UpdateDrive access check: %w", err)
+	}
+	return f.inner.UpdateDrive(ctx, driveID, d)
+}
+
+// SetDriveHidden checks drive access before hiding or unhiding.
+func (f *FilteredDriveService) SetDriveHidden(ctx context.Context, driveID string, hidden bool) (*drive.Drive, error) {
+	f.ensureResolved(ctx)
+	if err := f.filter.Check(driveID); err != nil {
+		return nil, fmt.Errorf("SetDriveHidden access check: %w", err)
+	}
+	return f.inner.SetDriveHidden(ctx, driveID, hidden)
 }
 
 // === Permissions ===
@@ -216,6 +399,13 @@ func (f *FilteredDriveService) DeletePermission(ctx context.Context, fileID stri
 	return f.inner.DeletePermission(ctx, fileID, permissionID)
 }
 
+func (f *FilteredDriveService) UpdatePermission(ctx context.Context, fileID string, permissionID string, permission *drive.Permission, transferOwnership bool) (*drive.Permission, error) {
+	if err := f.checkFileAccess(ctx, fileID); err != nil {
+		return nil, fmt.Errorf("UpdatePermission access check: %w", err)
+	}
+	return f.inner.UpdatePermission(ctx, fileID, permissionID, permission, transferOwnership)
+}
+
 // === Comments ===
 
 func (f *FilteredDriveService) ListComments(ctx context.Context, fileID string, fields string, pageSize int64, pageToken string, includeDeleted bool) (*drive.CommentList, error) {
@@ -291,3 +481,120 @@ func (f *FilteredDriveService) DownloadRevision(ctx context.Context, fileID stri
 	}
 	return f.inner.DownloadRevision(ctx, fileID, revisionID)
 }
+
+func (f *FilteredDriveService) ExportRevision(ctx context.Context, fileID string, revisionID string, mimeType string) (io.ReadCloser, error) {
+	if err := f.checkFileAccess(ctx, fileID); err != nil {
+		return nil, fmt.Errorf("ExportRevision access check: %w", err)
+	}
+	return f.inner.ExportRevision(ctx, fileID, revisionID, mimeType)
+}
+
+func (f *FilteredDriveService) UpdateRevision(ctx context.Context, fileID string, revisionID string, revision *drive.Revision) (*drive.Revision, error) {
+	if err := f.checkFileAccess(ctx, fileID); err != nil {
+		return nil, fmt.Errorf("UpdateRevision access check: %w", err)
+	}
+	return f.inner.UpdateRevision(ctx, fileID, revisionID, revision)
+}
+
+// === Transfers ===
+
+func (f *FilteredDriveService) DownloadFileRange(ctx context.Context, fileID string, offset int64) (io.ReadCloser, error) {
+	if err := f.checkFileAccess(ctx, fileID); err != nil {
+		return nil, fmt.Errorf("DownloadFileRange access check: %w", err)
+	}
+	return f.inner.DownloadFileRange(ctx, fileID, offset)
+}
+
+// StartUpload checks the file being replaced, or the new file's parent,
+// before opening a session. Later calls on the session need no check.
+func (f *FilteredDriveService) StartUpload(ctx context.Context, fileID string, file *drive.File, size int64) (string, error) {
+	if fileID != "" {
+		if err := f.checkFileAccess(ctx, fileID); err != nil {
+			return "", fmt.Errorf("StartUpload access check: %w", err)
+		}
+	} else if err := f.checkParentAccess(ctx, file); err != nil {
+		return "", fmt.Errorf("StartUpload parent access check: %w", err)
+	}
+	return f.inner.StartUpload(ctx, fileID, file, size)
+}
+
+func (f *FilteredDriveService) UploadStatus(ctx context.Context, sessionURI string, size int64) (int64, *drive.File, error) {
+	return f.inner.UploadStatus(ctx, sessionURI, size)
+}
+
+func (f *FilteredDriveService) UploadChunk(ctx context.Context, sessionURI string, chunk io.Reader, offset, length, size int64) (int64, *drive.File, error) {
+	return f.inner.UploadChunk(ctx, sessionURI, chunk, offset, length, size)
+}
+
+func (f *FilteredDriveService) UpdateFileContent(ctx context.Context, fileID string, mimeType string, content io.Reader) (*drive.File, error) {
+	if err := f.checkFileAccess(ctx, fileID); err != nil {
+		return nil, fmt.Errorf("UpdateFileContent access check: %w", err)
+	}
+	return f.inner.UpdateFileContent(ctx, fileID, mimeType, content)
+}
+
+// === Changes ===
+
+// GetStartPageToken checks a shared drive scope before returning its token.
+func (f *FilteredDriveService) GetStartPageToken(ctx context.Context, driveID string) (string, error) {
+	if driveID != "" {
+		f.ensureResolved(ctx)
+		if err := f.filter.Check(driveID); err != nil {
+			return "", fmt.Errorf("GetStartPageToken access check: %w", err)
+		}
+	}
+	return f.inner.GetStartPageToken(ctx, driveID)
+}
+
+// ListChanges drops changes to files and shared drives outside the allowed
+// drives and folders. Changes for removed files carry no drive and are kept.
+func (f *FilteredDriveService) ListChanges(ctx context.Context, pageToken string, opts *ListChangesOptions) (*drive.ChangeList, error) {
+	f.ensureResolved(ctx)
+	if opts != nil && opts.DriveID != "" {
+		if err := f.filter.Check(opts.DriveID); err != nil {
+			return nil, fmt.Errorf("ListChanges access check: %w", err)
+		}
+	}
+
+	result, err := f.inner.ListChanges(ctx, pageToken, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing changes: %w", err)
+	}
+
+	filtered := make([]*drive.Change, 0, len(result.Changes))
+	for _, change := range result.Changes {
+		var err error
+		switch {
+		case change.File != nil:
+			err = f.checkFile(ctx, change.File)
+		case change.DriveId != "":
+			err = f.filter.Check(change.DriveId)
+		}
+		if err == nil {
+			filtered = append(filtered, change)
+		}
+	}
+	result.Changes = filtered
+	return result, nil
+}
+
+// === Labels ===
+
+// ListLabels passes through: label definitions are not stored in any drive.
+func (f *FilteredDriveService) ListLabels(ctx context.Context, minimumRole string, pageSize int64, pageToken string) (*drivelabels.GoogleAppsDriveLabelsV2ListLabelsResponse, error) {
+	return f.inner.ListLabels(ctx, minimumRole, pageSize, pageToken)
+}
+
+func (f *FilteredDriveService) ListFileLabels(ctx context.Context, fileID string, pageToken string) (*drive.LabelList, error) {
+	if err := f.checkFileAccess(ctx, fileID); err != nil {
+		return nil, fmt.Errorf("ListFileLabels access check: %w", err)
+	}
+	return f.inner.ListFileLabels(ctx, fileID, pageToken)
+}
+
+func (f *FilteredDriveService) ModifyFileLabels(ctx context.Context, fileID string, req *drive.ModifyLabelsRequest) (*drive.ModifyLabelsResponse, error) {
+	if err := f.checkFileAccess(ctx, fileID); err != nil {
+		return nil, fmt.Errorf("ModifyFileLabels access check: %w", err)
+	}
+	return f.inner.ModifyFileLabels(ctx, fileID, req)
+}
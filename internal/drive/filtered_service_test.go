@@ -176,3 +176,122 @@ func TestFilteredDriveService_MoveFile_BlockedDestination(t *testing.T) {
 		t.Fatal("expected error when moving to blocked drive")
 	}
 }
+
+func TestFilteredDriveService_ListChanges_DropsBlockedDrives(t *testing.T) {
+	filter := newTestFilter(nil, []string{"SENSITIVE"})
+	mock := &MockDriveService{
+		ListChangesFunc: func(ctx context.Context, pageToken string, opts *ListChangesOptions) (*drive.ChangeList, error) {
+			return &drive.ChangeList{
+				Changes: []*drive.Change{
+					{FileId: "1", File: &drive.File{Id: "1", DriveId: "drive-marketing"}},
+					{FileId: "2", File: &drive.File{Id: "2", DriveId: "drive-sensitive"}},
+					{FileId: "3", Removed: true},
+					{ChangeType: "drive", DriveId: "drive-sensitive"},
+				},
+			}, nil
+		},
+		ListDrivesFunc: func(ctx context.Context, pageSize int64, pageToken string) (*drive.DriveList, error) {
+			return &drive.DriveList{}, nil
+		},
+	}
+
+	srv := NewFilteredDriveService(mock, filter)
+	result, err := srv.ListChanges(context.Background(), "10", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Changes) != 2 || result.Changes[0].FileId != "1" || result.Changes[1].FileId != "3" {
+		t.Fatalf("expected changes 1 and 3, got %+v", result.Changes)
+	}
+
+	if _, err := srv.ListChanges(context.Background(), "10", &ListChangesOptions{DriveID: "drive-sensitive"}); err == nil {
+		t.Error("expected error listing a blocked shared drive's changes")
+	}
+	if _, err := srv.GetStartPageToken(context.Background(), "drive-sensitive"); err == nil {
+		t.Error("expected error getting a blocked shared drive's start token")
+	}
+}
+
+func TestFilteredDriveService_FolderPathAllowlist(t *testing.T) {
+	fixtures, tree := pathFixtures(t)
+	notes := tree.add("notes.txt", "root", "text/plain", []byte("notes"))
+	filter := common.NewDriveAccessFilter(&config.DriveAccess{Allowed: []string{"/My Drive/Reports/**"}})
+	srv := NewFilteredDriveService(fixtures.MockService, filter)
+	ctx := context.Background()
+
+	q3 := tree.find("root", "Reports/2026/Q3.xlsx")
+	if _, err := srv.GetFile(ctx, q3.Id, "id,name"); err != nil {
+		t.Errorf("expected a file below the allowed folder to be readable: %v", err)
+	}
+	for _, f := range []*drive.File{notes, tree.find("drive-eng", "Specs/design.md")} {
+		if _, err := srv.GetFile(ctx, f.Id, "id,name"); err == nil {
+			t.Errorf("expected %s outside the allowed folder to be refused", f.Name)
+		}
+	}
+
+	result, err := srv.ListFiles(ctx, &ListFilesOptions{Query: "'root' in parents", Fields: "files(name)"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Name != "Reports" {
+		t.Errorf("expected only Reports in My Drive, got %+v", result.Files)
+	}
+}
+
+func TestFilteredDriveService_FolderBlocklist(t *testing.T) {
+	fixtures, tree := pathFixtures(t)
+	year := tree.find("root", "Reports/2026")
+	specs := tree.find("drive-eng", "Specs")
+	filter := common.NewDriveAccessFilter(&config.DriveAccess{Blocked: []string{"/My Drive/Reports/2026", specs.Id}})
+	srv := NewFilteredDriveService(fixtures.MockService, filter)
+	ctx := context.Background()
+
+	for _, f := range []*drive.File{year, tree.find("root", "Reports/2026/Q3.xlsx"), tree.find("drive-eng", "Specs/design.md")} {
+		if _, err := srv.GetFile(ctx, f.Id, ""); err == nil {
+			t.Errorf("expected %s to be refused", f.Name)
+		}
+	}
+	if _, err := srv.GetFile(ctx, tree.find("root", "Reports").Id, ""); err != nil {
+		t.Errorf("expected the parent of a blocked folder to be readable: %v", err)
+	}
+	if err := srv.checkFileAccess(ctx, "missing"); err != nil {
+		t.Errorf("expected an unknown file to fail open, got %v", err)
+	}
+
+	q3 := tree.find("root", "Reports/2026/Q3.xlsx")
+	fixtures.MockService.ListChangesFunc = func(context.Context, string, *ListChangesOptions) (*drive.ChangeList, error) {
+		return &drive.ChangeList{Changes: []*drive.Change{
+			{FileId: q3.Id, File: &drive.File{Id: q3.Id}},
+			{FileId: "gone", Removed: true},
+		}}, nil
+	}
+	result, err := srv.ListChanges(ctx, "1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].FileId != "gone" {
+		t.Errorf("expected the blocked folder's change to be dropped, got %+v", result.Changes)
+	}
+}
+
+func TestFilteredDriveService_ParentlessCreate(t *testing.T) {
+	fixtures, tree := pathFixtures(t)
+	ctx := context.Background()
+
+	allowReports := NewFilteredDriveService(fixtures.MockService, common.NewDriveAccessFilter(&config.DriveAccess{Allowed: []string{"/My Drive/Reports/**"}}))
+	if _, err := allowReports.CreateFile(ctx, &drive.File{Name: "stray.txt"}, nil); err == nil {
+		t.Error("expected a create in My Drive's root to be refused outside the allowed folder")
+	}
+	if _, err := allowReports.StartUpload(ctx, "", &drive.File{Name: "stray.bin"}, 10); err == nil {
+		t.Error("expected an upload to My Drive's root to be refused outside the allowed folder")
+	}
+	reports := tree.find("root", "Reports")
+	if _, err := allowReports.CreateFile(ctx, &drive.File{Name: "ok.txt", Parents: []string{reports.Id}}, nil); err != nil {
+		t.Errorf("expected a create inside the allowed folder to succeed: %v", err)
+	}
+
+	allowMyDrive := NewFilteredDriveService(fixtures.MockService, common.NewDriveAccessFilter(&config.DriveAccess{Allowed: []string{"/My Drive/**"}}))
+	if err := allowMyDrive.checkParentAccess(ctx, &drive.File{Name: "root.txt"}); err != nil {
+		t.Errorf("expected a create in an allowed My Drive root to succeed: %v", err)
+	}
+}
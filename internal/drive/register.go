@@ -6,23 +6,33 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// withPathParam returns the path parameter accepted in place of idKey.
+func withPathParam(idKey string) mcp.ToolOption {
+	return mcp.WithString("path", mcp.Description("Path instead of "+idKey+", e.g. /My Drive/Reports/Q3.xlsx, /Shared drives/Engineering/Specs or shared:Engineering/Specs/design.md. Fails with the candidate IDs if a name is ambiguous"))
+}
+
 // RegisterTools registers all Drive tools with the MCP server.
 func RegisterTools(s *server.MCPServer) {
 	registerDriveCoreTools(s)
 	registerDriveSharingTools(s)
+	registerDriveSharedDriveTools(s)
 	registerDriveCommentsTools(s)
 	registerDriveRevisionsTools(s)
+	registerDriveLabelTools(s)
 }
 
 // registerDriveCoreTools registers core Drive file operations: search, get, download, upload, list, CRUD.
 func registerDriveCoreTools(s *server.MCPServer) {
 	// drive_search - Search files with query syntax
 	s.AddTool(mcp.NewTool("drive_search",
-		mcp.WithDescription("Search Google Drive files including shared drives. Supports metadata queries like \"name contains 'report'\" and full-text content search with \"fullText contains 'keyword'\" (searches inside PDFs, Docs, Sheets, Slides, Office files). Combine with \"and\"/\"or\" operators. Use file_type for easy filtering by type."),
-		mcp.WithString("query", mcp.Required(), mcp.Description("Drive search query. Examples: \"name contains 'budget'\", \"fullText contains 'keyword'\" (searches file content), \"fullText contains 'quarterly' and mimeType = 'application/pdf'\"")),
+		mcp.WithDescription("Search Google Drive files including shared drives. Supports metadata queries like \"name contains 'report'\" and full-text content search with \"fullText contains 'keyword'\" (searches inside PDFs, Docs, Sheets, Slides, Office files). Combine with \"and\"/\"or\" operators. Use file_type for easy filtering by type and label to find files by Drive label."),
+		mcp.WithString("query", mcp.Description("Drive search query (required unless label is given). Examples: \"name contains 'budget'\", \"fullText contains 'keyword'\" (searches file content), \"fullText contains 'quarterly' and mimeType = 'application/pdf'\"")),
 		mcp.WithString("file_type", mcp.Description("Friendly file type filter: doc, sheet, slides, pdf, folder, image, video, audio, form, drawing. Also accepts raw mimeType strings.")),
 		mcp.WithNumber("max_results", mcp.Description("Maximum results to return (1-100, default 20)")),
 		mcp.WithString("corpora", mcp.Description("Search scope: allDrives (default, includes shared drives), user (My Drive only), domain")),
+		mcp.WithString("label", mcp.Description("Only files with this Drive label, by ID or title. Results include the label's field values.")),
+		mcp.WithString("label_field", mcp.Description("Field of label to match, by ID or name (requires label_value)")),
+		mcp.WithString("label_value", mcp.Description("Value label_field must have: text, a whole number, a YYYY-MM-DD date, a selection choice name or ID, or a user's email")),
 		common.WithPageToken(),
 		common.WithAccountParam(),
 	), HandleDriveSearch)
@@ -30,44 +40,76 @@ func registerDriveCoreTools(s *server.MCPServer) {
 	// drive_get - Get file metadata
 	s.AddTool(mcp.NewTool("drive_get",
 		mcp.WithDescription("Get detailed metadata for a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		common.WithAccountParam(),
 	), HandleDriveGet)
 
 	// drive_download - Download file content
 	s.AddTool(mcp.NewTool("drive_download",
-		mcp.WithDescription("Download file content. Returns text for text files, base64 for binary. Max 10MB. Google Docs/Sheets are exported as text/CSV."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithDescription("Download file content. Returns text for text files, base64 for binary, max 10MB. With output_path the file is streamed to a local file instead, with no size limit; an interrupted download resumes when called again. Google Docs/Sheets are exported as text/CSV."),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
+		mcp.WithString("output_path", mcp.Description("Local file to write, or an existing directory to write into under the file's name. Must be inside local_files.allowed_dirs when configured.")),
+		mcp.WithBoolean("overwrite", mcp.Description("Replace an existing file at output_path (default: false)")),
 		common.WithAccountParam(),
 	), common.WithLargeContentHint(HandleDriveDownload))
 
 	// drive_upload - Upload new file
 	s.AddTool(mcp.NewTool("drive_upload",
-		mcp.WithDescription("Upload a new file to Google Drive."),
-		mcp.WithString("name", mcp.Required(), mcp.Description("File name")),
-		mcp.WithString("content", mcp.Required(), mcp.Description("File content (text or base64-encoded)")),
-		mcp.WithString("encoding", mcp.Description("Content encoding: utf-8 (default) or base64")),
+		mcp.WithDescription("Upload a new file to Google Drive from inline content (max 10MB) or a local file. Local files use a resumable upload; an interrupted upload resumes when called again with the same arguments."),
+		mcp.WithString("name", mcp.Description("File name (required with content; defaults to the local file's name)")),
+		mcp.WithString("content", mcp.Description("File content (text or base64-encoded). Use content or local_path.")),
+		mcp.WithString("local_path", mcp.Description("Local file to upload. Must be inside local_files.allowed_dirs when configured.")),
+		mcp.WithString("encoding", mcp.Description("Content encoding of content: utf-8 (default) or base64")),
 		mcp.WithString("mime_type", mcp.Description("MIME type (auto-detected if omitted)")),
-		mcp.WithString("parent_id", mcp.Description("Parent folder ID (uploads to root if omitted)")),
+		mcp.WithString("parent_id", mcp.Description("Parent folder ID, URL or path (uploads to root if omitted)")),
 		mcp.WithString("description", mcp.Description("File description")),
 		common.WithAccountParam(),
 	), HandleDriveUpload)
 
+	// drive_sync - Sync a local directory with a Drive folder
+	s.AddTool(mcp.NewTool("drive_sync",
+		mcp.WithDescription("Sync a local directory with a Drive folder, recursively. Files are compared by MD5; a state file in the local directory records the last sync, so later runs only hash changed files and can tell which side changed. Google Workspace files are skipped. Use dry_run to see the plan first."),
+		mcp.WithString("local_dir", mcp.Required(), mcp.Description("Local directory. Must be inside local_files.allowed_dirs when configured.")),
+		mcp.WithString("folder_id", mcp.Required(), mcp.Description("Drive folder ID, URL or path")),
+		mcp.WithString("direction", mcp.Description("push: local to Drive (default), pull: Drive to local, both: two-way")),
+		mcp.WithString("conflict", mcp.Description("When both sides changed, or the destination changed in a one-way sync: newer (default), local, remote or skip")),
+		mcp.WithBoolean("delete", mcp.Description("Propagate deletions: trash Drive files removed locally (push), delete local files removed from Drive (pull). Without it, missing files are restored (default: false)")),
+		mcp.WithArray("exclude", mcp.Description("Glob patterns to skip, matched against relative paths and each path element, e.g. [\"*.tmp\", \"node_modules\", \"build/*\"]")),
+		mcp.WithBoolean("dry_run", mcp.Description("Return the plan without changing anything (default: false)")),
+		common.WithAccountParam(),
+	), HandleDriveSync)
+
 	// drive_list - List files in folder
 	s.AddTool(mcp.NewTool("drive_list",
 		mcp.WithDescription("List files in a Google Drive folder."),
 		mcp.WithString("folder_id", mcp.Description("Folder ID (default: root folder)")),
+		withPathParam("folder_id"),
 		mcp.WithNumber("max_results", mcp.Description("Maximum results (1-1000, default 100)")),
 		common.WithPageToken(),
 		mcp.WithString("order_by", mcp.Description("Sort order: name, modifiedTime, createdTime (default: name)")),
 		common.WithAccountParam(),
 	), HandleDriveList)
 
+	// drive_list_changes - List files changed since the last call
+	s.AddTool(mcp.NewTool("drive_list_changes",
+		mcp.WithDescription("List files created, modified, trashed or removed since the last call, from Drive's change feed. The feed position is saved per account and scope, so repeated calls return only new changes. The first call for a scope records a starting point and returns no changes."),
+		mcp.WithString("folder_id", mcp.Description("Only report files in this folder or its subfolders (ID, URL or path)")),
+		mcp.WithString("drive_id", mcp.Description("Read a shared drive's feed instead of My Drive and shared-with-me files")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum changes to return (1-1000, default 100); the rest are returned by the next call")),
+		mcp.WithString("page_token", mcp.Description("Read from this token instead of the saved position, leaving the saved position unchanged")),
+		mcp.WithBoolean("reset", mcp.Description("Discard the saved position and start again from now (default: false)")),
+		common.WithAccountParam(),
+	), HandleDriveListChanges)
+
 	// drive_create_folder - Create new folder
 	s.AddTool(mcp.NewTool("drive_create_folder",
-		mcp.WithDescription("Create a new folder in Google Drive."),
-		mcp.WithString("name", mcp.Required(), mcp.Description("Folder name")),
-		mcp.WithString("parent_id", mcp.Description("Parent folder ID (creates in root if omitted)")),
+		mcp.WithDescription("Create a new folder in Google Drive, by name and parent or by path. With parents=true and a path, missing parent folders are created and an existing folder at the path is returned, like mkdir -p."),
+		mcp.WithString("name", mcp.Description("Folder name (required unless path is given)")),
+		mcp.WithString("parent_id", mcp.Description("Parent folder ID, URL or path (creates in root if omitted)")),
+		mcp.WithString("path", mcp.Description("Full path of the new folder instead of name and parent_id, e.g. /My Drive/Reports/2026 or shared:Engineering/Specs")),
+		mcp.WithBoolean("parents", mcp.Description("With path, create missing parent folders and reuse an existing folder at the path (default: false)")),
 		mcp.WithString("description", mcp.Description("Folder description")),
 		common.WithAccountParam(),
 	), HandleDriveCreateFolder)
@@ -75,31 +117,64 @@ func registerDriveCoreTools(s *server.MCPServer) {
 	// drive_move - Move file to different folder
 	s.AddTool(mcp.NewTool("drive_move",
 		mcp.WithDescription("Move a file to a different folder in Google Drive."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
-		mcp.WithString("new_parent_id", mcp.Required(), mcp.Description("Destination folder ID")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
+		mcp.WithString("new_parent_id", mcp.Required(), mcp.Description("Destination folder ID, URL or path")),
 		common.WithAccountParam(),
 	), HandleDriveMove)
 
 	// drive_copy - Copy a file
 	s.AddTool(mcp.NewTool("drive_copy",
 		mcp.WithDescription("Create a copy of a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithString("name", mcp.Description("New file name (default: Copy of original)")),
-		mcp.WithString("parent_id", mcp.Description("Destination folder ID (default: same folder)")),
+		mcp.WithString("parent_id", mcp.Description("Destination folder ID, URL or path (default: same folder)")),
 		common.WithAccountParam(),
 	), HandleDriveCopy)
 
+	// drive_copy_folder - Recursively copy a folder
+	s.AddTool(mcp.NewTool("drive_copy_folder",
+		mcp.WithDescription("Copy a Google Drive folder with all its subfolders and files, including Google Docs, Sheets and Slides, keeping the folder structure. Shortcuts are skipped. Items that fail to copy are reported in errors without stopping the copy."),
+		mcp.WithString("folder_id", mcp.Description("Source folder ID or Google Drive URL (required unless path is given)")),
+		withPathParam("folder_id"),
+		mcp.WithString("name", mcp.Description("Name of the new folder (default: the source name, or Copy of <name> in the same parent)")),
+		mcp.WithString("parent_id", mcp.Description("Destination parent folder ID, URL or path (default: the source folder's parent)")),
+		common.WithAccountParam(),
+	), HandleDriveCopyFolder)
+
+	// drive_tree - Nested folder listing
+	s.AddTool(mcp.NewTool("drive_tree",
+		mcp.WithDescription("List a Google Drive folder recursively as a nested tree with names, MIME types and sizes. Folder sizes and file counts total what was listed below them; folders at the depth limit are marked not_listed."),
+		mcp.WithString("folder_id", mcp.Description("Folder ID, Google Drive URL, or 'root' for My Drive (required unless path is given)")),
+		withPathParam("folder_id"),
+		mcp.WithNumber("depth", mcp.Description("Levels to list below the folder (default: 2, max: 10)")),
+		mcp.WithNumber("max_items", mcp.Description("Maximum items to list (default: 500, max: 5000)")),
+		common.WithAccountParam(),
+	), HandleDriveTree)
+
+	// drive_folder_stats - Folder size report
+	s.AddTool(mcp.NewTool("drive_folder_stats",
+		mcp.WithDescription("Total the files and bytes in a Google Drive folder and all its subfolders, broken down by MIME type and owner. Google Docs, Sheets and Slides use no storage and count as 0 bytes."),
+		mcp.WithString("folder_id", mcp.Description("Folder ID, Google Drive URL, or 'root' for My Drive (required unless path is given)")),
+		withPathParam("folder_id"),
+		mcp.WithNumber("max_depth", mcp.Description("Levels to include below the folder (default: all)")),
+		common.WithAccountParam(),
+	), HandleDriveFolderStats)
+
 	// drive_trash - Move file to trash
 	s.AddTool(mcp.NewTool("drive_trash",
 		mcp.WithDescription("Move a Google Drive file to trash."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		common.WithAccountParam(),
 	), HandleDriveTrash)
 
 	// drive_delete - Permanently delete file
 	s.AddTool(mcp.NewTool("drive_delete",
 		mcp.WithDescription("Permanently delete a Google Drive file (cannot be undone)."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		common.WithAccountParam(),
 	), HandleDriveDelete)
 
@@ -110,7 +185,8 @@ func registerDriveSharingTools(s *server.MCPServer) {
 	// drive_share - Share file with users
 	s.AddTool(mcp.NewTool("drive_share",
 		mcp.WithDescription("Share a Google Drive file with a user or group."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithString("email", mcp.Required(), mcp.Description("Email address to share with")),
 		mcp.WithString("role", mcp.Description("Permission role: reader, writer, commenter (default: reader)")),
 		mcp.WithString("type", mcp.Description("Permission type: user, group, domain, anyone (default: user)")),
@@ -121,17 +197,127 @@ func registerDriveSharingTools(s *server.MCPServer) {
 	// drive_get_permissions - Get file permissions
 	s.AddTool(mcp.NewTool("drive_get_permissions",
 		mcp.WithDescription("Get permissions/sharing settings for a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		common.WithAccountParam(),
 	), HandleDriveGetPermissions)
 
 	// drive_get_shareable_link - Get shareable link with sharing status
 	s.AddTool(mcp.NewTool("drive_get_shareable_link",
 		mcp.WithDescription("Get a shareable URL for a Google Drive file along with its current sharing status and permissions. Simpler than using drive_get + drive_get_permissions separately."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		common.WithAccountParam(),
 	), HandleDriveGetShareableLink)
 
+	// drive_unshare - Remove a permission
+	s.AddTool(mcp.NewTool("drive_unshare",
+		mcp.WithDescription("Remove someone's access to a Google Drive file, by permission ID or email. Use email \"anyone\" to turn off link sharing."),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
+		mcp.WithString("permission_id", mcp.Description("Permission ID from drive_get_permissions")),
+		mcp.WithString("email", mcp.Description("Email address to remove, or \"anyone\" for link sharing (used when permission_id is omitted)")),
+		common.WithAccountParam(),
+	), HandleDriveUnshare)
+
+	// drive_update_permission - Change role or expiration
+	s.AddTool(mcp.NewTool("drive_update_permission",
+		mcp.WithDescription("Change the role or expiration time of an existing permission on a Google Drive file."),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
+		mcp.WithString("permission_id", mcp.Description("Permission ID from drive_get_permissions")),
+		mcp.WithString("email", mcp.Description("Email address of the permission, or \"anyone\" (used when permission_id is omitted)")),
+		mcp.WithString("role", mcp.Description("New role: reader, commenter, writer, fileOrganizer or organizer")),
+		mcp.WithString("expiration_time", mcp.Description("When access ends: RFC 3339 time (2026-12-31T17:00:00Z) or a date (2026-12-31, end of day UTC). Only for user and group permissions")),
+		mcp.WithBoolean("remove_expiration", mcp.Description("Remove the expiration time (default: false)")),
+		common.WithAccountParam(),
+	), HandleDriveUpdatePermission)
+
+	// drive_transfer_ownership - Make another user the owner
+	s.AddTool(mcp.NewTool("drive_transfer_ownership",
+		mcp.WithDescription("Make another user the owner of a My Drive file; the current owner keeps edit access. Files in shared drives have no individual owner. Personal Google accounts need pending=true: the new owner is asked to accept."),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
+		mcp.WithString("email", mcp.Required(), mcp.Description("Email address of the new owner")),
+		mcp.WithBoolean("pending", mcp.Description("Send an ownership request the new owner must accept (default: false)")),
+		common.WithAccountParam(),
+	), HandleDriveTransferOwnership)
+
+	// drive_sharing_audit - Report and remediate risky sharing
+	s.AddTool(mcp.NewTool("drive_sharing_audit",
+		mcp.WithDescription("Audit sharing on every file in a folder tree or matching a search. Reports anyone-with-link sharing, external users, groups and domains, and permissions of deleted accounts (orphaned). A permission inherited from a folder is reported once, on that folder; one inherited from outside the audit, such as shared drive membership, is reported once and never revoked. With revoke, lists the permissions it would remove; pass confirm=true as well to remove them."),
+		mcp.WithString("folder_id", mcp.Description("Folder to audit recursively (ID, URL or 'root')")),
+		withPathParam("folder_id"),
+		mcp.WithString("query", mcp.Description("Drive search query selecting the files to audit instead of a folder, e.g. \"'me' in owners and modifiedTime > '2026-01-01'\"")),
+		mcp.WithArray("internal_domains", mcp.Description("Domains that are not external (default: the account's domain)")),
+		mcp.WithNumber("max_files", mcp.Description("Maximum files to audit (default: 500, max: 5000)")),
+		mcp.WithArray("revoke", mcp.Description("Categories to revoke: anyone, external, orphaned. Owner permissions are never removed")),
+		mcp.WithBoolean("confirm", mcp.Description("Actually revoke; without it, revoke only previews (default: false)")),
+		common.WithAccountParam(),
+	), HandleDriveSharingAudit)
+}
+
+// registerDriveSharedDriveTools registers shared drive administration tools.
+func registerDriveSharedDriveTools(s *server.MCPServer) {
+	// drive_list_shared_drives - List shared drives
+	s.AddTool(mcp.NewTool("drive_list_shared_drives",
+		mcp.WithDescription("List the shared drives this account can see, with their restrictions. Drives blocked by the drive access settings are not shown."),
+		mcp.WithString("name_contains", mcp.Description("Only drives whose name contains this text (case-insensitive)")),
+		mcp.WithBoolean("include_hidden", mcp.Description("Include drives hidden from the default view (default: false)")),
+		common.WithAccountParam(),
+	), HandleDriveListSharedDrives)
+
+	// drive_create_shared_drive - Create a shared drive
+	s.AddTool(mcp.NewTool("drive_create_shared_drive",
+		mcp.WithDescription("Create a shared drive. The account becomes its first manager."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Shared drive name")),
+		mcp.WithString("request_id", mcp.Description("Idempotency key: retrying with the same request_id returns the drive already created instead of a second one (default: random)")),
+		common.WithAccountParam(),
+	), HandleDriveCreateSharedDrive)
+
+	// drive_update_shared_drive - Rename, hide or restrict a shared drive
+	s.AddTool(mcp.NewTool("drive_update_shared_drive",
+		mcp.WithDescription("Rename a shared drive, hide or unhide it, or change its restrictions. Only the settings passed are changed."),
+		mcp.WithString("drive_id", mcp.Description("Shared drive ID (required unless drive_name is given)")),
+		mcp.WithString("drive_name", mcp.Description("Shared drive name, used when drive_id is omitted")),
+		mcp.WithString("name", mcp.Description("New name")),
+		mcp.WithBoolean("hidden", mcp.Description("Hide the drive from the default view, or unhide it")),
+		mcp.WithBoolean("domain_users_only", mcp.Description("Only users in the drive's domain can access it")),
+		mcp.WithBoolean("copy_requires_writer_permission", mcp.Description("Readers and commenters cannot copy, print or download files")),
+		mcp.WithBoolean("drive_members_only", mcp.Description("Files can only be shared with drive members")),
+		mcp.WithBoolean("admin_managed_restrictions", mcp.Description("Only administrators can change these restrictions")),
+		common.WithAccountParam(),
+	), HandleDriveUpdateSharedDrive)
+
+	// drive_list_shared_drive_members - List members and roles
+	s.AddTool(mcp.NewTool("drive_list_shared_drive_members",
+		mcp.WithDescription("List the members of a shared drive and their roles."),
+		mcp.WithString("drive_id", mcp.Description("Shared drive ID (required unless drive_name is given)")),
+		mcp.WithString("drive_name", mcp.Description("Shared drive name, used when drive_id is omitted")),
+		common.WithAccountParam(),
+	), HandleDriveListSharedDriveMembers)
+
+	// drive_set_shared_drive_member - Add a member or change their role
+	s.AddTool(mcp.NewTool("drive_set_shared_drive_member",
+		mcp.WithDescription("Add a member to a shared drive, or change the role of an existing member. Roles: organizer (Manager), fileOrganizer (Content manager), writer (Contributor), commenter, reader."),
+		mcp.WithString("drive_id", mcp.Description("Shared drive ID (required unless drive_name is given)")),
+		mcp.WithString("drive_name", mcp.Description("Shared drive name, used when drive_id is omitted")),
+		mcp.WithString("email", mcp.Required(), mcp.Description("Email address of the user or group, or the domain name for type domain")),
+		mcp.WithString("role", mcp.Required(), mcp.Description("Role: organizer, fileOrganizer, writer, commenter or reader")),
+		mcp.WithString("type", mcp.Description("Member type: user, group or domain (default: user)")),
+		mcp.WithBoolean("send_notification", mcp.Description("Send notification email to new members (default: true)")),
+		common.WithAccountParam(),
+	), HandleDriveSetSharedDriveMember)
+
+	// drive_remove_shared_drive_member - Remove a member
+	s.AddTool(mcp.NewTool("drive_remove_shared_drive_member",
+		mcp.WithDescription("Remove a member from a shared drive, by permission ID or email."),
+		mcp.WithString("drive_id", mcp.Description("Shared drive ID (required unless drive_name is given)")),
+		mcp.WithString("drive_name", mcp.Description("Shared drive name, used when drive_id is omitted")),
+		mcp.WithString("permission_id", mcp.Description("Permission ID from drive_list_shared_drive_members")),
+		mcp.WithString("email", mcp.Description("Email address of the member (used when permission_id is omitted)")),
+		common.WithAccountParam(),
+	), HandleDriveRemoveSharedDriveMember)
 }
 
 // registerDriveCommentsTools registers Drive comments and replies tools.
@@ -139,7 +325,8 @@ func registerDriveCommentsTools(s *server.MCPServer) {
 	// drive_list_comments - List comments on a file
 	s.AddTool(mcp.NewTool("drive_list_comments",
 		mcp.WithDescription("List comments on a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithNumber("max_results", mcp.Description("Maximum results to return (1-100, default 20)")),
 		mcp.WithBoolean("include_deleted", mcp.Description("Include deleted comments (default: false)")),
 		common.WithPageToken(),
@@ -149,7 +336,8 @@ func registerDriveCommentsTools(s *server.MCPServer) {
 	// drive_get_comment - Get a single comment
 	s.AddTool(mcp.NewTool("drive_get_comment",
 		mcp.WithDescription("Get a specific comment on a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithString("comment_id", mcp.Required(), mcp.Description("Comment ID")),
 		mcp.WithBoolean("include_deleted", mcp.Description("Include if deleted (default: false)")),
 		common.WithAccountParam(),
@@ -158,7 +346,8 @@ func registerDriveCommentsTools(s *server.MCPServer) {
 	// drive_create_comment - Create a comment
 	s.AddTool(mcp.NewTool("drive_create_comment",
 		mcp.WithDescription("Create a comment on a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithString("content", mcp.Required(), mcp.Description("Comment text")),
 		common.WithAccountParam(),
 	), HandleDriveCreateComment)
@@ -166,7 +355,8 @@ func registerDriveCommentsTools(s *server.MCPServer) {
 	// drive_update_comment - Update a comment
 	s.AddTool(mcp.NewTool("drive_update_comment",
 		mcp.WithDescription("Update a comment on a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithString("comment_id", mcp.Required(), mcp.Description("Comment ID")),
 		mcp.WithString("content", mcp.Required(), mcp.Description("Updated comment text")),
 		common.WithAccountParam(),
@@ -175,7 +365,8 @@ func registerDriveCommentsTools(s *server.MCPServer) {
 	// drive_delete_comment - Delete a comment
 	s.AddTool(mcp.NewTool("drive_delete_comment",
 		mcp.WithDescription("Delete a comment from a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithString("comment_id", mcp.Required(), mcp.Description("Comment ID")),
 		common.WithAccountParam(),
 	), HandleDriveDeleteComment)
@@ -183,7 +374,8 @@ func registerDriveCommentsTools(s *server.MCPServer) {
 	// drive_list_replies - List replies on a comment
 	s.AddTool(mcp.NewTool("drive_list_replies",
 		mcp.WithDescription("List replies on a comment of a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithString("comment_id", mcp.Required(), mcp.Description("Comment ID")),
 		mcp.WithNumber("max_results", mcp.Description("Maximum results to return (1-100, default 20)")),
 		mcp.WithBoolean("include_deleted", mcp.Description("Include deleted replies (default: false)")),
@@ -194,7 +386,8 @@ func registerDriveCommentsTools(s *server.MCPServer) {
 	// drive_create_reply - Create a reply on a comment
 	s.AddTool(mcp.NewTool("drive_create_reply",
 		mcp.WithDescription("Create a reply on a comment of a Google Drive file. Use action 'resolve' to resolve the comment."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithString("comment_id", mcp.Required(), mcp.Description("Comment ID")),
 		mcp.WithString("content", mcp.Required(), mcp.Description("Reply text")),
 		mcp.WithString("action", mcp.Description("Action to perform: resolve, reopen (optional)")),
@@ -208,7 +401,8 @@ func registerDriveRevisionsTools(s *server.MCPServer) {
 	// drive_list_revisions - List file version history
 	s.AddTool(mcp.NewTool("drive_list_revisions",
 		mcp.WithDescription("List version history (revisions) for a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithNumber("max_results", mcp.Description("Maximum results to return (1-100, default 20)")),
 		common.WithPageToken(),
 		common.WithAccountParam(),
@@ -217,7 +411,8 @@ func registerDriveRevisionsTools(s *server.MCPServer) {
 	// drive_get_revision - Get a specific revision
 	s.AddTool(mcp.NewTool("drive_get_revision",
 		mcp.WithDescription("Get metadata for a specific revision of a Google Drive file."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithString("revision_id", mcp.Required(), mcp.Description("Revision ID")),
 		common.WithAccountParam(),
 	), HandleDriveGetRevision)
@@ -225,8 +420,70 @@ func registerDriveRevisionsTools(s *server.MCPServer) {
 	// drive_download_revision - Download a specific revision
 	s.AddTool(mcp.NewTool("drive_download_revision",
 		mcp.WithDescription("Download the content of a specific revision of a Google Drive file. Returns text for text files, base64 for binary. Max 10MB."),
-		mcp.WithString("file_id", mcp.Required(), mcp.Description("File ID or Google Drive URL")),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
 		mcp.WithString("revision_id", mcp.Required(), mcp.Description("Revision ID")),
 		common.WithAccountParam(),
 	), HandleDriveDownloadRevision)
+
+	// drive_restore_revision - Make an earlier revision the current version
+	s.AddTool(mcp.NewTool("drive_restore_revision",
+		mcp.WithDescription("Restore an earlier revision of a Google Drive file by making its content the current version. Binary files get the revision's content uploaded as a new revision. Google Docs, Sheets and Slides are exported to Word, Excel or PowerPoint format and imported back, which may not preserve comments, suggestions or some formatting. Earlier revisions are kept."),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
+		mcp.WithString("revision_id", mcp.Required(), mcp.Description("Revision ID to restore (from drive_list_revisions)")),
+		common.WithAccountParam(),
+	), HandleDriveRestoreRevision)
+
+	// drive_pin_revision - Keep a revision forever
+	s.AddTool(mcp.NewTool("drive_pin_revision",
+		mcp.WithDescription("Pin a revision of a binary Google Drive file so it is kept forever instead of being purged after 30 days or 100 revisions, or unpin it. A file can have at most 200 pinned revisions. Google Docs, Sheets and Slides revisions cannot be pinned."),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
+		mcp.WithString("revision_id", mcp.Required(), mcp.Description("Revision ID")),
+		mcp.WithBoolean("keep_forever", mcp.Description("true to pin the revision, false to unpin it (default: true)")),
+		common.WithAccountParam(),
+	), HandleDrivePinRevision)
+
+	// drive_diff_revisions - Compare two revisions
+	s.AddTool(mcp.NewTool("drive_diff_revisions",
+		mcp.WithDescription("Show a unified text diff between two revisions of a text file or Google Doc, Sheet (as CSV) or Slides presentation (as text), or between a revision and the current version."),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
+		mcp.WithString("from_revision_id", mcp.Required(), mcp.Description("Older revision ID")),
+		mcp.WithString("to_revision_id", mcp.Description("Newer revision ID (default: the current version)")),
+		mcp.WithNumber("context_lines", mcp.Description("Unchanged lines shown around each change (default 3)")),
+		common.WithAccountParam(),
+	), HandleDriveDiffRevisions)
+}
+
+// registerDriveLabelTools registers Drive label tools.
+func registerDriveLabelTools(s *server.MCPServer) {
+	// drive_list_labels - List available labels
+	s.AddTool(mcp.NewTool("drive_list_labels",
+		mcp.WithDescription("List the published Drive labels available to the user, with their fields, field types and selection choices."),
+		mcp.WithString("minimum_role", mcp.Description("Only labels the user holds at least this role on: reader (default), applier, editor or organizer. Use applier to find labels that can be set on files.")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum labels to return (1-100, default 20)")),
+		common.WithPageToken(),
+		common.WithAccountParam(),
+	), HandleDriveListLabels)
+
+	// drive_get_labels - Read labels on a file
+	s.AddTool(mcp.NewTool("drive_get_labels",
+		mcp.WithDescription("List the Drive labels applied to a file and their field values."),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
+		common.WithAccountParam(),
+	), HandleDriveGetLabels)
+
+	// drive_modify_labels - Set or remove a label on a file
+	s.AddTool(mcp.NewTool("drive_modify_labels",
+		mcp.WithDescription("Apply a Drive label to a file and set its field values, or remove the label. Fields not named are left unchanged."),
+		mcp.WithString("file_id", mcp.Description("File ID or Google Drive URL (required unless path is given)")),
+		withPathParam("file_id"),
+		mcp.WithString("label", mcp.Required(), mcp.Description("Label ID or title")),
+		mcp.WithObject("fields", mcp.Description("Field values by field name or ID, e.g. {\"Confidentiality\": \"Internal\", \"Reviewers\": [\"a@example.com\"]}. Selection fields take choice names or IDs, date fields YYYY-MM-DD and user fields emails; a list sets a multi-value field. null clears a field.")),
+		mcp.WithBoolean("remove", mcp.Description("Remove the label from the file (default: false)")),
+		common.WithAccountParam(),
+	), HandleDriveModifyLabels)
 }
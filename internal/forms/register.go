@@ -15,14 +15,14 @@ func RegisterTools(s *server.MCPServer) {
 		mcp.WithDescription("Get a Google Form's metadata, questions, and structure including item types and options."),
 		mcp.WithString("form_id", mcp.Required(), mcp.Description("Form ID or full Google Forms URL")),
 		common.WithAccountParam(),
-	), HandleFormsGet)
+	), common.WithDriveAccessCheck(HandleFormsGet, "form_id"))
 
 	// forms_list_responses - List form responses
 	s.AddTool(mcp.NewTool("forms_list_responses",
 		mcp.WithDescription("List all responses submitted to a Google Form."),
 		mcp.WithString("form_id", mcp.Required(), mcp.Description("Form ID or full Google Forms URL")),
 		common.WithAccountParam(),
-	), HandleFormsListResponses)
+	), common.WithDriveAccessCheck(HandleFormsListResponses, "form_id"))
 
 	// forms_get_response - Get a single form response
 	s.AddTool(mcp.NewTool("forms_get_response",
@@ -30,7 +30,7 @@ func RegisterTools(s *server.MCPServer) {
 		mcp.WithString("form_id", mcp.Required(), mcp.Description("Form ID or full Google Forms URL")),
 		mcp.WithString("response_id", mcp.Required(), mcp.Description("Response ID (from forms_list_responses)")),
 		common.WithAccountParam(),
-	), HandleFormsGetResponse)
+	), common.WithDriveAccessCheck(HandleFormsGetResponse, "form_id"))
 
 	// === Forms Write ===
 
@@ -39,7 +39,7 @@ func RegisterTools(s *server.MCPServer) {
 		mcp.WithDescription("Create a new Google Form with the given title."),
 		mcp.WithString("title", mcp.Required(), mcp.Description("Form title")),
 		common.WithAccountParam(),
-	), HandleFormsCreate)
+	), common.WithDriveRootCreateCheck(HandleFormsCreate))
 
 	// forms_batch_update - Batch update form
 	s.AddTool(mcp.NewTool("forms_batch_update",
@@ -47,5 +47,5 @@ func RegisterTools(s *server.MCPServer) {
 		mcp.WithString("form_id", mcp.Required(), mcp.Description("Form ID or full Google Forms URL")),
 		mcp.WithString("requests", mcp.Required(), mcp.Description("JSON array of batch update requests (see Google Forms API docs)")),
 		common.WithAccountParam(),
-	), HandleFormsBatchUpdate)
+	), common.WithDriveAccessCheck(HandleFormsBatchUpdate, "form_id"))
 }
@@ -62,6 +62,26 @@ var (
 	HandleGmailGetProfile  = common.WrapHandler[GmailService](TestableGmailGetProfile)
 	HandleGmailGetVacation = common.WrapHandler[GmailService](TestableGmailGetVacation)
 	HandleGmailSetVacation = common.WrapHandler[GmailService](TestableGmailSetVacation)
+	HandleGmailGetSettings = common.WrapHandler[GmailService](TestableGmailGetSettings)
+)
+
+// Forwarding
+var (
+	HandleGmailListForwardingAddresses = common.WrapHandler[GmailService](TestableGmailListForwardingAddresses)
+	HandleGmailCreateForwardingAddress = common.WrapHandler[GmailService](TestableGmailCreateForwardingAddress)
+	HandleGmailDeleteForwardingAddress = common.WrapHandler[GmailService](TestableGmailDeleteForwardingAddress)
+	HandleGmailGetAutoForwarding       = common.WrapHandler[GmailService](TestableGmailGetAutoForwarding)
+	HandleGmailUpdateAutoForwarding    = common.WrapHandler[GmailService](TestableGmailUpdateAutoForwarding)
+)
+
+// IMAP / POP / Language
+var (
+	HandleGmailGetImap        = common.WrapHandler[GmailService](TestableGmailGetImap)
+	HandleGmailUpdateImap     = common.WrapHandler[GmailService](TestableGmailUpdateImap)
+	HandleGmailGetPop         = common.WrapHandler[GmailService](TestableGmailGetPop)
+	HandleGmailUpdatePop      = common.WrapHandler[GmailService](TestableGmailUpdatePop)
+	HandleGmailGetLanguage    = common.WrapHandler[GmailService](TestableGmailGetLanguage)
+	HandleGmailUpdateLanguage = common.WrapHandler[GmailService](TestableGmailUpdateLanguage)
 )
 
 // Spam Convenience
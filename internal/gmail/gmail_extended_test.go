@@ -4,8 +4,10 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"google.golang.org/api/gmail/v1"
 )
 
@@ -848,3 +850,43 @@ func TestGmailGetProfile_ServiceError(t *testing.T) {
 		t.Error("expected error when service fails")
 	}
 }
+
+func TestGmailDownloadAttachment_AllowedDirs(t *testing.T) {
+	allowed, outside := t.TempDir(), t.TempDir()
+	policy, err := common.NewLocalDirPolicy([]string{allowed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := attachmentDirs
+	attachmentDirs = policy
+	t.Cleanup(func() { attachmentDirs = prev })
+
+	fixtures := NewGmailTestFixtures()
+	fixtures.MockService.AddMessage(newTestMessageWithAttachment("msg-att-1"))
+	for dir, wantErr := range map[string]bool{allowed: false, outside: true} {
+		result, err := TestableGmailDownloadAttachment(context.Background(), makeRequest(map[string]any{"message_id": "msg-att-1", "output_dir": dir}), fixtures.Deps)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.IsError != wantErr || (wantErr && !strings.Contains(getTextResult(result), "outside the allowed directories")) {
+			t.Errorf("output_dir %s: expected error %v, got %s", dir, wantErr, getTextResult(result))
+		}
+	}
+
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	result, err := TestableGmailSend(context.Background(), makeRequest(map[string]any{
+		"to":          "recipient@example.com",
+		"subject":     "Test Email",
+		"body":        "This is a test email.",
+		"attachments": []any{secret},
+	}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError || !strings.Contains(getTextResult(result), "outside the allowed directories") || fixtures.MockService.WasMethodCalled("SendMessage") {
+		t.Errorf("expected an attachment outside the allowed directories to be refused, got %s", getTextResult(result))
+	}
+}
@@ -0,0 +1,255 @@
+package gmail
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/tasks"
+	"google.golang.org/api/gmail/v1"
+	gtasks "google.golang.org/api/tasks/v1"
+)
+
+// Follow-up Tools
+var (
+	HandleGmailAwaitingReply = common.WrapHandler[GmailService](TestableGmailAwaitingReply)
+)
+
+// Follow-up scan limits.
+const (
+	awaitingReplyDefaultDays     = 3
+	awaitingReplyDefaultLookback = 30
+	awaitingReplyMaxScanMessages = 500 // sent messages inspected per call
+	followUpTaskPageSize         = 100
+)
+
+// followUpThreadPrefix starts the task notes line that links a follow-up task
+// to its thread; it also identifies tasks created by earlier runs.
+const followUpThreadPrefix = "Gmail thread: "
+
+// FollowUpTasksHandlerDeps resolves the Tasks service used when gmail_awaiting_reply
+// creates follow-up tasks. Nil falls back to tasks.DefaultTasksHandlerDeps.
+var FollowUpTasksHandlerDeps *tasks.TasksHandlerDeps
+
+// AwaitingReplyThread is a thread whose latest message was sent by the user
+// and has not been answered.
+type AwaitingReplyThread struct {
+	ThreadID     string   `json:"thread_id"`
+	MessageID    string   `json:"message_id"`
+	Subject      string   `json:"subject"`
+	To           []string `json:"to"`
+	Cc           []string `json:"cc,omitempty"`
+	SentAt       string   `json:"sent_at"`
+	DaysWaiting  int      `json:"days_waiting"`
+	MessageCount int      `json:"message_count"`
+	WebURL       string   `json:"web_url,omitempty"`
+	Labelled     bool     `json:"labelled,omitempty"`
+	TaskID       string   `json:"task_id,omitempty"`
+	ExistingTask bool     `json:"existing_task,omitempty"` // a follow-up task from an earlier run
+	sentAt       time.Time
+}
+
+// awaitingReplyQuery builds the search for sent messages old enough to be
+// waiting, but still inside the lookback window.
+func awaitingReplyQuery(days, lookback int) string {
+	return fmt.Sprintf("in:sent newer_than:%dd older_than:%dd", lookback, days)
+}
+
+// collectSentThreadIDs pages through sent messages and returns distinct thread
+// IDs in the order first seen, up to awaitingReplyMaxScanMessages messages.
+func collectSentThreadIDs(ctx context.Context, svc GmailService, query string) ([]string, bool, error) {
+	seen := make(map[string]bool)
+	var threadIDs []string
+	scanned := 0
+	pageToken := ""
+	for {
+		resp, err := svc.ListMessages(ctx, query, common.GmailMaxResultsLimit, pageToken)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, m := range resp.Messages {
+			scanned++
+			if !seen[m.ThreadId] {
+				seen[m.ThreadId] = true
+				threadIDs = append(threadIDs, m.ThreadId)
+			}
+		}
+		if resp.NextPageToken == "" {
+			return threadIDs, false, nil
+		}
+		if scanned >= awaitingReplyMaxScanMessages {
+			return threadIDs, true, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// lastThreadMessage returns the most recent message in a thread, ignoring
+// drafts and messages in trash or spam.
+func lastThreadMessage(thread *gmail.Thread) *gmail.Message {
+	var last *gmail.Message
+	for _, msg := range thread.Messages {
+		if hasAnyLabel(msg.LabelIds, "DRAFT", "TRASH", "SPAM") {
+			continue
+		}
+		if last == nil || msg.InternalDate >= last.InternalDate {
+			last = msg
+		}
+	}
+	return last
+}
+
+func hasAnyLabel(labelIDs []string, want ...string) bool {
+	for _, id := range labelIDs {
+		for _, w := range want {
+			if id == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// awaitingReplyFromThread reports the thread as awaiting a reply when its last
+// message is ours (SENT) and was sent before cutoff. Returns nil otherwise.
+func awaitingReplyFromThread(thread *gmail.Thread, cutoff, now time.Time) *AwaitingReplyThread {
+	last := lastThreadMessage(thread)
+	if last == nil || !hasAnyLabel(last.LabelIds, "SENT") {
+		return nil
+	}
+	sentAt := time.UnixMilli(last.InternalDate)
+	if sentAt.After(cutoff) {
+		return nil
+	}
+
+	item := &AwaitingReplyThread{
+		ThreadID:     thread.Id,
+		MessageID:    last.Id,
+		To:           []string{},
+		SentAt:       sentAt.UTC().Format(time.RFC3339),
+		DaysWaiting:  int(now.Sub(sentAt).Hours() / 24),
+		MessageCount: len(thread.Messages),
+		sentAt:       sentAt,
+	}
+	if last.Payload != nil {
+		for _, h := range last.Payload.Headers {
+			switch strings.ToLower(h.Name) {
+			case "subject":
+				item.Subject = h.Value
+			case "to":
+				item.To = splitAddressList(h.Value)
+			case "cc":
+				item.Cc = splitAddressList(h.Value)
+			}
+		}
+	}
+	if len(item.To) == 0 && len(item.Cc) == 0 {
+		// Notes-to-self and BCC-only sends have nobody to reply.
+		return nil
+	}
+	return item
+}
+
+// splitAddressList splits a To/Cc header into trimmed addresses, keeping
+// display names and ignoring commas inside quotes.
+func splitAddressList(value string) []string {
+	var out []string
+	var cur strings.Builder
+	inQuotes := false
+	for _, r := range value {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			cur.WriteRune(r)
+		case r == ',' && !inQuotes:
+			if s := strings.TrimSpace(cur.String()); s != "" {
+				out = append(out, s)
+			}
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if s := strings.TrimSpace(cur.String()); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+// sortAwaitingReply orders threads oldest first, so the longest waits lead.
+func sortAwaitingReply(items []*AwaitingReplyThread) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].sentAt.Before(items[j].sentAt)
+	})
+}
+
+// findOrCreateLabel returns the ID of the user label with the given name
+// (case-insensitive), creating it when it does not exist.
+func findOrCreateLabel(ctx context.Context, svc GmailService, name string) (string, bool, error) {
+	resp, err := svc.ListLabels(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	for _, l := range resp.Labels {
+		if strings.EqualFold(l.Name, name) {
+			return l.Id, false, nil
+		}
+	}
+	created, err := svc.CreateLabel(ctx, &gmail.Label{
+		Name:                  name,
+		LabelListVisibility:   "labelShow",
+		MessageListVisibility: "show",
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return created.Id, true, nil
+}
+
+// followUpTask builds the Google Task created for a thread awaiting reply.
+func followUpTask(item *AwaitingReplyThread) *gtasks.Task {
+	subject := item.Subject
+	if subject == "" {
+		subject = "(no subject)"
+	}
+	recipients := append(append([]string{}, item.To...), item.Cc...)
+	notes := fmt.Sprintf("No reply from %s since %s (%d days).\n%s%s",
+		strings.Join(recipients, ", "), item.SentAt, item.DaysWaiting, followUpThreadPrefix, followUpTaskLink(item))
+	return &gtasks.Task{Title: "Follow up: " + subject, Notes: notes}
+}
+
+// followUpTaskLink is the thread reference written into a follow-up task.
+func followUpTaskLink(item *AwaitingReplyThread) string {
+	if item.WebURL != "" {
+		return item.WebURL
+	}
+	return item.ThreadID
+}
+
+// existingFollowUpLinks returns the thread references of the follow-up tasks
+// already in a task list, completed ones included, so a thread never gets a
+// second task.
+func existingFollowUpLinks(ctx context.Context, svc tasks.TasksService, taskListID string) (map[string]bool, error) {
+	links := make(map[string]bool)
+	opts := &tasks.ListTasksOptions{MaxResults: followUpTaskPageSize, ShowCompleted: true, ShowHidden: true}
+	for {
+		resp, err := svc.ListTasks(ctx, taskListID, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range resp.Items {
+			for _, line := range strings.Split(task.Notes, "\n") {
+				if link, ok := strings.CutPrefix(line, followUpThreadPrefix); ok {
+					links[strings.TrimSpace(link)] = true
+				}
+			}
+		}
+		if resp.NextPageToken == "" {
+			return links, nil
+		}
+		opts.PageToken = resp.NextPageToken
+	}
+}
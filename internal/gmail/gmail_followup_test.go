@@ -0,0 +1,264 @@
+package gmail
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/tasks"
+	"google.golang.org/api/gmail/v1"
+	gtasks "google.golang.org/api/tasks/v1"
+)
+
+// newFollowUpMessage builds a metadata-format message sent daysAgo days ago.
+func newFollowUpMessage(id, threadID, to, subject string, daysAgo int, labels ...string) *gmail.Message {
+	headers := []*gmail.MessagePartHeader{{Name: "Subject", Value: subject}}
+	if to != "" {
+		headers = append(headers, &gmail.MessagePartHeader{Name: "To", Value: to})
+	}
+	return &gmail.Message{
+		Id:           id,
+		ThreadId:     threadID,
+		LabelIds:     labels,
+		InternalDate: time.Now().AddDate(0, 0, -daysAgo).UnixMilli(),
+		Payload:      &gmail.MessagePart{Headers: headers},
+	}
+}
+
+// seedFollowUpThreads stores each thread and its messages in the mock.
+func seedFollowUpThreads(mock *MockGmailService, threads ...*gmail.Thread) {
+	for _, th := range threads {
+		mock.AddThread(th)
+		for _, msg := range th.Messages {
+			mock.AddMessage(msg)
+		}
+	}
+}
+
+func defaultFollowUpThreads() []*gmail.Thread {
+	return []*gmail.Thread{
+		// Our message, unanswered for 10 days.
+		{Id: "t-waiting", Messages: []*gmail.Message{
+			newFollowUpMessage("m1", "t-waiting", `"Smith, Bob" <bob@example.com>, carol@example.com`, "Contract", 10, "SENT"),
+		}},
+		// They replied after us.
+		{Id: "t-replied", Messages: []*gmail.Message{
+			newFollowUpMessage("m2", "t-replied", "dave@example.com", "Lunch", 12, "SENT"),
+			newFollowUpMessage("m3", "t-replied", "test@example.com", "Re: Lunch", 11, "INBOX"),
+		}},
+		// Sent too recently to chase.
+		{Id: "t-recent", Messages: []*gmail.Message{
+			newFollowUpMessage("m4", "t-recent", "erin@example.com", "Draft plan", 1, "SENT"),
+		}},
+		// Older and still waiting, with an unsent draft reply that must be ignored.
+		{Id: "t-older", Messages: []*gmail.Message{
+			newFollowUpMessage("m5", "t-older", "frank@example.com", "Invoice", 20, "SENT"),
+			newFollowUpMessage("m6", "t-older", "frank@example.com", "Re: Invoice", 2, "DRAFT"),
+		}},
+		// Note to self with no recipients.
+		{Id: "t-self", Messages: []*gmail.Message{
+			newFollowUpMessage("m7", "t-self", "", "Reminder", 15, "SENT"),
+		}},
+	}
+}
+
+func TestGmailAwaitingReply_FindsUnansweredThreads(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	seedFollowUpThreads(fixtures.MockService, defaultFollowUpThreads()...)
+
+	result, err := TestableGmailAwaitingReply(context.Background(), makeRequest(map[string]any{"days": float64(5)}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", getTextResult(result))
+	}
+
+	response := extractResponse(t, result)
+	if q := response["query"]; q != "in:sent newer_than:30d older_than:5d" {
+		t.Errorf("query = %v", q)
+	}
+	threads := response["threads"].([]any)
+	if len(threads) != 2 {
+		t.Fatalf("expected 2 threads awaiting reply, got %d: %v", len(threads), threads)
+	}
+	first := threads[0].(map[string]any)
+	if first["thread_id"] != "t-older" || first["message_id"] != "m5" {
+		t.Errorf("expected oldest wait first, got %v", first)
+	}
+	second := threads[1].(map[string]any)
+	if second["subject"] != "Contract" {
+		t.Errorf("subject = %v", second["subject"])
+	}
+	to := second["to"].([]any)
+	if len(to) != 2 || to[0] != `"Smith, Bob" <bob@example.com>` {
+		t.Errorf("to = %v", to)
+	}
+	if days := second["days_waiting"].(float64); days < 9 || days > 10 {
+		t.Errorf("days_waiting = %v", days)
+	}
+	if fixtures.MockService.WasMethodCalled("ModifyThread") {
+		t.Error("expected no labels to be applied without label parameter")
+	}
+}
+
+func TestGmailAwaitingReply_AppliesLabel(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	seedFollowUpThreads(fixtures.MockService, defaultFollowUpThreads()...)
+
+	result, err := TestableGmailAwaitingReply(context.Background(), makeRequest(map[string]any{
+		"days":  float64(5),
+		"label": "Follow-up",
+	}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	response := extractResponse(t, result)
+	label := response["label"].(map[string]any)
+	if label["created"] != true {
+		t.Errorf("expected Follow-up label to be created, got %v", label)
+	}
+	labelID := label["id"].(string)
+	for _, id := range []string{"t-waiting", "t-older"} {
+		if !threadHasLabel(fixtures.MockService.Threads[id], labelID) {
+			t.Errorf("thread %s not labelled", id)
+		}
+	}
+	if threadHasLabel(fixtures.MockService.Threads["t-replied"], labelID) {
+		t.Error("answered thread should not be labelled")
+	}
+
+	// A second run reuses the label rather than creating another.
+	result, _ = TestableGmailAwaitingReply(context.Background(), makeRequest(map[string]any{
+		"days":  float64(5),
+		"label": "follow-up",
+	}), fixtures.Deps)
+	response = extractResponse(t, result)
+	if got := response["label"].(map[string]any)["created"]; got != false {
+		t.Errorf("expected existing label to be reused, created = %v", got)
+	}
+}
+
+func TestGmailAwaitingReply_CreatesTasks(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	seedFollowUpThreads(fixtures.MockService, defaultFollowUpThreads()...)
+
+	var created []*gtasks.Task
+	var taskListIDs []string
+	tasksMock := &tasks.MockTasksService{
+		CreateTaskFunc: func(_ context.Context, taskListID string, task *gtasks.Task, _ *tasks.CreateTaskOptions) (*gtasks.Task, error) {
+			task.Id = "task-" + task.Title
+			created = append(created, task)
+			taskListIDs = append(taskListIDs, taskListID)
+			return task, nil
+		},
+	}
+	FollowUpTasksHandlerDeps = common.NewTestFixtures[tasks.TasksService](tasksMock).Deps
+	t.Cleanup(func() { FollowUpTasksHandlerDeps = nil })
+
+	args := map[string]any{
+		"days":         float64(5),
+		"label":        "Follow-up",
+		"create_tasks": true,
+	}
+	result, err := TestableGmailAwaitingReply(context.Background(), makeRequest(args), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", getTextResult(result))
+	}
+	if len(created) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(created))
+	}
+	if created[0].Title != "Follow up: Invoice" || !strings.Contains(created[0].Notes, "frank@example.com") {
+		t.Errorf("unexpected task: %+v", created[0])
+	}
+	if taskListIDs[0] != common.DefaultTaskListID {
+		t.Errorf("tasklist = %q, want default", taskListIDs[0])
+	}
+	threads := extractResponse(t, result)["threads"].([]any)
+	if threads[0].(map[string]any)["task_id"] != "task-Follow up: Invoice" {
+		t.Errorf("expected task_id in result, got %v", threads[0])
+	}
+
+	// Threads labelled by the first run do not get duplicate tasks.
+	if _, err := TestableGmailAwaitingReply(context.Background(), makeRequest(args), fixtures.Deps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created) != 2 {
+		t.Errorf("expected no new tasks on second run, got %d total", len(created))
+	}
+}
+
+func TestGmailAwaitingReply_TasksWithoutLabel(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	seedFollowUpThreads(fixtures.MockService, defaultFollowUpThreads()...)
+
+	// The task list keeps what was created, as the Tasks API would.
+	var stored []*gtasks.Task
+	var listOpts *tasks.ListTasksOptions
+	tasksMock := &tasks.MockTasksService{
+		ListTasksFunc: func(_ context.Context, _ string, opts *tasks.ListTasksOptions) (*gtasks.Tasks, error) {
+			listOpts = opts
+			return &gtasks.Tasks{Items: stored}, nil
+		},
+		CreateTaskFunc: func(_ context.Context, _ string, task *gtasks.Task, _ *tasks.CreateTaskOptions) (*gtasks.Task, error) {
+			task.Id = fmt.Sprintf("task-%d", len(stored))
+			stored = append(stored, task)
+			return task, nil
+		},
+	}
+	FollowUpTasksHandlerDeps = common.NewTestFixtures[tasks.TasksService](tasksMock).Deps
+	t.Cleanup(func() { FollowUpTasksHandlerDeps = nil })
+
+	args := map[string]any{"days": float64(5), "create_tasks": true}
+	if _, err := TestableGmailAwaitingReply(context.Background(), makeRequest(args), fixtures.Deps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(stored))
+	}
+	// Completed follow-ups count too.
+	stored[0].Status = "completed"
+
+	result, err := TestableGmailAwaitingReply(context.Background(), makeRequest(args), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("expected no new tasks on second run, got %d total", len(stored))
+	}
+	if !listOpts.ShowCompleted || !listOpts.ShowHidden {
+		t.Errorf("expected completed tasks to be listed, got %+v", listOpts)
+	}
+	for _, th := range extractResponse(t, result)["threads"].([]any) {
+		if th.(map[string]any)["existing_task"] != true {
+			t.Errorf("expected existing_task on %v", th)
+		}
+	}
+}
+
+func TestGmailAwaitingReply_InvalidWindow(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	result, err := TestableGmailAwaitingReply(context.Background(), makeRequest(map[string]any{
+		"days":          float64(30),
+		"lookback_days": float64(7),
+	}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error when lookback_days <= days")
+	}
+}
+
+func TestSplitAddressList(t *testing.T) {
+	got := splitAddressList(`"Doe, Jane" <jane@example.com>, bob@example.com ,`)
+	if len(got) != 2 || got[0] != `"Doe, Jane" <jane@example.com>` || got[1] != "bob@example.com" {
+		t.Errorf("splitAddressList = %q", got)
+	}
+}
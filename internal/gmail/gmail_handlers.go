@@ -23,9 +23,18 @@ func NewGmailService(ctx context.Context, client *http.Client) (GmailService, er
 	return NewRealGmailService(srv), nil
 }
 
+// NewGmailThreadReader creates the common.GmailThreadReader that packages
+// gmail imports, such as calendar's meeting brief, read mail through.
+func NewGmailThreadReader(ctx context.Context, client *http.Client) (common.GmailThreadReader, error) {
+	return NewGmailService(ctx, client)
+}
+
 // InitDefaultGmailHandlerDeps initializes the default Gmail handler deps with explicit deps,
 // avoiding reliance on the global singleton at call time.
 func InitDefaultGmailHandlerDeps(appDeps *common.Deps) {
+	if appDeps != nil {
+		attachmentDirs = appDeps.LocalDirs
+	}
 	DefaultGmailHandlerDeps = common.NewDefaultHandlerDeps(NewGmailService, appDeps)
 }
 
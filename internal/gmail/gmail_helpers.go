@@ -26,6 +26,11 @@ const (
 	gmailMaxOutgoingRawBytes       = 25 * 1024 * 1024
 )
 
+// attachmentDirs limits the local files attached to outgoing mail and the
+// downloaded attachment paths to the configured allowed directories. Nil
+// allows any path. Set by InitDefaultGmailHandlerDeps.
+var attachmentDirs *common.LocalDirPolicy
+
 // parseBodyFormat parses the "body_format" argument from a request and returns the
 // corresponding BodyFormat. Defaults to BodyFormatText if not specified.
 func parseBodyFormat(args map[string]any) BodyFormat {
@@ -206,6 +211,10 @@ func loadEmailAttachments(value any) ([]emailAttachment, error) {
 		if path == "" {
 			return nil, fmt.Errorf("attachments[%d] must be a non-empty file path", index)
 		}
+		path, err := common.ResolveAllowedLocalPath(path, attachmentDirs)
+		if err != nil {
+			return nil, fmt.Errorf("attachments[%d]: %w", index, err)
+		}
 
 		info, err := os.Stat(path)
 		if err != nil {
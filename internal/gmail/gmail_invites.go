@@ -0,0 +1,348 @@
+package gmail
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/calendar"
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	gcalendar "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+)
+
+// Invitation Tools
+var (
+	HandleGmailRSVP = common.WrapHandler[GmailService](TestableGmailRSVP)
+)
+
+// CalendarInvite is the structured form of a VEVENT found in a text/calendar part.
+type CalendarInvite struct {
+	Method            string            `json:"method,omitempty"`
+	UID               string            `json:"uid"`
+	Summary           string            `json:"summary,omitempty"`
+	Description       string            `json:"description,omitempty"`
+	Location          string            `json:"location,omitempty"`
+	Status            string            `json:"status,omitempty"`
+	Sequence          int64             `json:"sequence,omitempty"`
+	RecurrenceID      string            `json:"recurrence_id,omitempty"`
+	Recurrence        []string          `json:"recurrence,omitempty"`
+	Start             *InviteTime       `json:"start,omitempty"`
+	End               *InviteTime       `json:"end,omitempty"`
+	Organizer         *InviteParty      `json:"organizer,omitempty"`
+	Attendees         []InviteParty     `json:"attendees,omitempty"`
+	CalendarEvent     *InviteEventLink  `json:"calendar_event,omitempty"`
+	CalendarLinkError string            `json:"calendar_link_error,omitempty"`
+	recurrenceID      *calendar.ICSTime // parsed RECURRENCE-ID, used for instance lookup
+}
+
+// InviteTime is an invitation start or end time. DateTime carries the offset of
+// TimeZone; UTC is the same instant normalised to UTC for easy comparison.
+type InviteTime struct {
+	DateTime string `json:"date_time,omitempty"`
+	Date     string `json:"date,omitempty"`
+	TimeZone string `json:"timezone,omitempty"`
+	UTC      string `json:"utc,omitempty"`
+}
+
+// InviteParty is an organizer or attendee. ResponseStatus uses Calendar API
+// vocabulary (needsAction, accepted, declined, tentative).
+type InviteParty struct {
+	Email          string `json:"email"`
+	Name           string `json:"name,omitempty"`
+	Role           string `json:"role,omitempty"`
+	ResponseStatus string `json:"response_status,omitempty"`
+	RSVP           bool   `json:"rsvp,omitempty"`
+}
+
+// InviteEventLink identifies the Calendar event an invitation corresponds to.
+type InviteEventLink struct {
+	EventID        string `json:"event_id"`
+	CalendarID     string `json:"calendar_id"`
+	HTMLLink       string `json:"html_link,omitempty"`
+	Status         string `json:"status,omitempty"`
+	ResponseStatus string `json:"response_status,omitempty"`
+}
+
+// InviteCalendarHandlerDeps resolves the Calendar service used to match invitations
+// to events by iCalUID. Nil falls back to calendar.DefaultCalendarHandlerDeps.
+var InviteCalendarHandlerDeps *calendar.CalendarHandlerDeps
+
+// partstatToResponseStatus maps iCalendar PARTSTAT values to Calendar responseStatus.
+var partstatToResponseStatus = map[string]string{
+	"NEEDS-ACTION": "needsAction",
+	"ACCEPTED":     "accepted",
+	"DECLINED":     "declined",
+	"TENTATIVE":    "tentative",
+}
+
+// isCalendarPart reports whether a MIME part carries iCalendar data.
+func isCalendarPart(part *gmail.MessagePart) bool {
+	mimeType := strings.ToLower(part.MimeType)
+	return strings.HasPrefix(mimeType, "text/calendar") ||
+		mimeType == "application/ics" ||
+		strings.HasSuffix(strings.ToLower(part.Filename), ".ics")
+}
+
+// collectCalendarParts walks the payload tree and returns every iCalendar part.
+func collectCalendarParts(part *gmail.MessagePart, out *[]*gmail.MessagePart) {
+	if part == nil {
+		return
+	}
+	if isCalendarPart(part) && part.Body != nil && (part.Body.Data != "" || part.Body.AttachmentId != "") {
+		*out = append(*out, part)
+	}
+	for _, p := range part.Parts {
+		collectCalendarParts(p, out)
+	}
+}
+
+// ExtractCalendarInvites returns the invitations carried by a message's
+// text/calendar parts. Parts stored as attachments are fetched via GetAttachment.
+// The same VEVENT is often sent both inline and as invite.ics, so duplicates
+// (same UID, RECURRENCE-ID and SEQUENCE) are dropped.
+func ExtractCalendarInvites(ctx context.Context, svc GmailService, msg *gmail.Message) ([]CalendarInvite, []string) {
+	if msg == nil || msg.Payload == nil {
+		return nil, nil
+	}
+
+	var parts []*gmail.MessagePart
+	collectCalendarParts(msg.Payload, &parts)
+
+	var invites []CalendarInvite
+	var errs []string
+	seen := make(map[string]bool)
+	for _, part := range parts {
+		encoded := part.Body.Data
+		if encoded == "" {
+			attach, err := svc.GetAttachment(ctx, msg.Id, part.Body.AttachmentId)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("part %s: %v", part.PartId, err))
+				continue
+			}
+			encoded = attach.Data
+		}
+		data, err := decodeGmailAttachmentData(encoded)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("part %s: %v", part.PartId, err))
+			continue
+		}
+		parsed, err := ParseCalendarInvites(string(data))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("part %s: %v", part.PartId, err))
+			continue
+		}
+		for _, inv := range parsed {
+			key := inv.UID + "|" + inv.RecurrenceID + "|" + strconv.FormatInt(inv.Sequence, 10)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			invites = append(invites, inv)
+		}
+	}
+	return invites, errs
+}
+
+// ParseCalendarInvites parses an iCalendar document into one invite per VEVENT.
+func ParseCalendarInvites(data string) ([]CalendarInvite, error) {
+	root, err := calendar.ParseICS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	method := strings.ToUpper(root.Get("METHOD").Text())
+	events := root.Children("VEVENT")
+	invites := make([]CalendarInvite, 0, len(events))
+	for _, ev := range events {
+		inv := CalendarInvite{
+			Method:      method,
+			UID:         ev.Get("UID").Text(),
+			Summary:     ev.Get("SUMMARY").Text(),
+			Description: ev.Get("DESCRIPTION").Text(),
+			Location:    ev.Get("LOCATION").Text(),
+			Status:      strings.ToUpper(ev.Get("STATUS").Text()),
+		}
+		if seq := ev.Get("SEQUENCE"); seq != nil {
+			inv.Sequence, _ = strconv.ParseInt(strings.TrimSpace(seq.Value), 10, 64)
+		}
+		if p := ev.Get("DTSTART"); p != nil {
+			if t, err := calendar.ParseICSTime(p); err == nil {
+				inv.Start = newInviteTime(t)
+			}
+		}
+		if p := ev.Get("DTEND"); p != nil {
+			if t, err := calendar.ParseICSTime(p); err == nil {
+				inv.End = newInviteTime(t)
+			}
+		}
+		if p := ev.Get("RECURRENCE-ID"); p != nil {
+			if t, err := calendar.ParseICSTime(p); err == nil {
+				inv.recurrenceID = t
+				inv.RecurrenceID = t.Format()
+			}
+		}
+		for _, name := range []string{"RRULE", "RDATE", "EXDATE"} {
+			for _, p := range ev.GetAll(name) {
+				inv.Recurrence = append(inv.Recurrence, name+":"+p.Value)
+			}
+		}
+		if p := ev.Get("ORGANIZER"); p != nil {
+			inv.Organizer = &InviteParty{
+				Email: calendar.ICSMailto(p.Value),
+				Name:  p.Param("CN"),
+			}
+		}
+		for _, p := range ev.GetAll("ATTENDEE") {
+			inv.Attendees = append(inv.Attendees, InviteParty{
+				Email:          calendar.ICSMailto(p.Value),
+				Name:           p.Param("CN"),
+				Role:           p.Param("ROLE"),
+				ResponseStatus: partstatToResponseStatus[strings.ToUpper(p.Param("PARTSTAT"))],
+				RSVP:           strings.EqualFold(p.Param("RSVP"), "TRUE"),
+			})
+		}
+		invites = append(invites, inv)
+	}
+	return invites, nil
+}
+
+// newInviteTime converts a parsed ICS time into its JSON representation.
+func newInviteTime(t *calendar.ICSTime) *InviteTime {
+	if t.AllDay {
+		return &InviteTime{Date: t.Format()}
+	}
+	it := &InviteTime{DateTime: t.Format(), TimeZone: t.TimeZone}
+	if !t.Floating {
+		it.UTC = t.Time.UTC().Format(time.RFC3339)
+	}
+	return it
+}
+
+// linkInvitesToCalendar looks up each invitation's Calendar event by iCalUID and
+// records the link (or the reason it could not be made) on the invite.
+func linkInvitesToCalendar(ctx context.Context, request mcp.CallToolRequest, invites []CalendarInvite) {
+	if len(invites) == 0 {
+		return
+	}
+
+	calSvc, errResult, ok := calendar.ResolveCalendarServiceOrError(ctx, request, InviteCalendarHandlerDeps)
+	if !ok {
+		msg := "calendar unavailable"
+		if len(errResult.Content) > 0 {
+			if text, isText := errResult.Content[0].(mcp.TextContent); isText {
+				msg = text.Text
+			}
+		}
+		for i := range invites {
+			invites[i].CalendarLinkError = msg
+		}
+		return
+	}
+
+	for i := range invites {
+		if invites[i].UID == "" {
+			continue
+		}
+		event, err := findInviteEvent(ctx, calSvc, common.DefaultCalendarID, &invites[i])
+		if err != nil {
+			invites[i].CalendarLinkError = err.Error()
+			continue
+		}
+		if event == nil {
+			continue
+		}
+		invites[i].CalendarEvent = &InviteEventLink{
+			EventID:        event.Id,
+			CalendarID:     common.DefaultCalendarID,
+			HTMLLink:       event.HtmlLink,
+			Status:         event.Status,
+			ResponseStatus: selfResponseStatus(event),
+		}
+	}
+}
+
+// findInviteEvent returns the Calendar event matching an invitation's UID, or nil
+// if the calendar has no such event. For an invitation that targets a single
+// occurrence (RECURRENCE-ID), the matching exception or expanded instance is returned.
+func findInviteEvent(ctx context.Context, svc calendar.CalendarService, calendarID string, inv *CalendarInvite) (*gcalendar.Event, error) {
+	resp, err := svc.ListEvents(ctx, calendarID, &calendar.ListEventsOptions{ICalUID: inv.UID})
+	if err != nil {
+		return nil, fmt.Errorf("calendar API error: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return nil, nil
+	}
+
+	if inv.recurrenceID == nil {
+		for _, ev := range resp.Items {
+			if ev.RecurringEventId == "" {
+				return ev, nil
+			}
+		}
+		return resp.Items[0], nil
+	}
+
+	var master *gcalendar.Event
+	for _, ev := range resp.Items {
+		if originalStartMatches(ev, inv.recurrenceID) {
+			return ev, nil
+		}
+		if ev.RecurringEventId == "" && len(ev.Recurrence) > 0 {
+			master = ev
+		}
+	}
+	if master == nil {
+		return nil, nil
+	}
+
+	start := inv.recurrenceID.Time
+	instances, err := svc.ListInstances(ctx, calendarID, master.Id, &calendar.ListInstancesOptions{
+		TimeMin: start.Add(-time.Minute).Format(time.RFC3339),
+		TimeMax: start.Add(24 * time.Hour).Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calendar API error: %w", err)
+	}
+	for _, ev := range instances.Items {
+		if originalStartMatches(ev, inv.recurrenceID) {
+			return ev, nil
+		}
+	}
+	return nil, nil
+}
+
+// originalStartMatches reports whether an event instance's originalStartTime is
+// the occurrence identified by recurrenceID.
+func originalStartMatches(ev *gcalendar.Event, recurrenceID *calendar.ICSTime) bool {
+	if ev.OriginalStartTime == nil {
+		return false
+	}
+	if recurrenceID.AllDay {
+		return ev.OriginalStartTime.Date == recurrenceID.Format()
+	}
+	if ev.OriginalStartTime.DateTime == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, ev.OriginalStartTime.DateTime)
+	if err != nil {
+		return false
+	}
+	if recurrenceID.Floating {
+		return t.Format("2006-01-02T15:04:05") == recurrenceID.Format()
+	}
+	return t.Equal(recurrenceID.Time)
+}
+
+// selfResponseStatus returns the authenticated user's responseStatus on an event.
+func selfResponseStatus(event *gcalendar.Event) string {
+	for _, a := range event.Attendees {
+		if a.Self {
+			return a.ResponseStatus
+		}
+	}
+	return ""
+}
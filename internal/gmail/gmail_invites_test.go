@@ -0,0 +1,230 @@
+package gmail
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/calendar"
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	gcalendar "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+)
+
+const testInviteICS = "BEGIN:VCALENDAR\r\n" +
+	"METHOD:REQUEST\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20261102T090000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20261102T093000\r\n" +
+	"ORGANIZER;CN=Alice:mailto:alice@example.com\r\n" +
+	"ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE;CN=Test User:\r\n" +
+	" mailto:test@example.com\r\n" +
+	"UID:invite-uid-1@google.com\r\n" +
+	"SUMMARY:Planning\r\n" +
+	"SEQUENCE:0\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// newTestInviteMessage builds a multipart/alternative invite with the ICS sent
+// both inline and as an invite.ics attachment, as Google Calendar does.
+func newTestInviteMessage(id string) *gmail.Message {
+	return &gmail.Message{
+		Id:       id,
+		ThreadId: "thread-" + id,
+		Payload: &gmail.MessagePart{
+			MimeType: "multipart/mixed",
+			Parts: []*gmail.MessagePart{
+				{
+					PartId:   "0",
+					MimeType: "multipart/alternative",
+					Parts: []*gmail.MessagePart{
+						{PartId: "0.0", MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: encodeBase64("You have been invited")}},
+						{PartId: "0.1", MimeType: "text/calendar; charset=UTF-8; method=REQUEST", Body: &gmail.MessagePartBody{Data: encodeBase64(testInviteICS)}},
+					},
+				},
+				{
+					PartId:   "1",
+					MimeType: "application/ics",
+					Filename: "invite.ics",
+					Body:     &gmail.MessagePartBody{AttachmentId: "ATT-ICS", Size: int64(len(testInviteICS))},
+				},
+			},
+		},
+	}
+}
+
+// setupInviteCalendar points invitation lookups at a mock calendar holding the
+// invite's event and returns the mock.
+func setupInviteCalendar(t *testing.T) *calendar.MockCalendarService {
+	t.Helper()
+	fixtures := calendar.NewCalendarTestFixtures()
+	fixtures.MockService.Events["primary"]["evt-invite"] = &gcalendar.Event{
+		Id:       "evt-invite",
+		ICalUID:  "invite-uid-1@google.com",
+		Summary:  "Planning",
+		Status:   "confirmed",
+		HtmlLink: "https://calendar.google.com/event?eid=evt-invite",
+		Attendees: []*gcalendar.EventAttendee{
+			{Email: "alice@example.com", Organizer: true, ResponseStatus: "accepted"},
+			{Email: common.TestEmail, Self: true, ResponseStatus: "needsAction"},
+		},
+	}
+	InviteCalendarHandlerDeps = fixtures.Deps
+	t.Cleanup(func() { InviteCalendarHandlerDeps = nil })
+	return fixtures.MockService
+}
+
+func TestGmailGetMessage_ParsesCalendarInvite(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	fixtures.MockService.AddMessage(newTestInviteMessage("msg-invite"))
+	setupInviteCalendar(t)
+
+	result, err := TestableGmailGetMessage(context.Background(), makeRequest(map[string]any{"message_id": "msg-invite"}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	response := extractResponse(t, result)
+	invites, ok := response["calendar_invites"].([]any)
+	if !ok {
+		t.Fatalf("expected calendar_invites array, got %T", response["calendar_invites"])
+	}
+	if len(invites) != 1 {
+		t.Fatalf("expected inline and attached copies to be de-duplicated, got %d invites", len(invites))
+	}
+	if fixtures.MockService.WasMethodCalled("GetAttachment") != true {
+		t.Error("expected the invite.ics attachment to be fetched")
+	}
+
+	invite := invites[0].(map[string]any)
+	if invite["method"] != "REQUEST" || invite["uid"] != "invite-uid-1@google.com" {
+		t.Errorf("unexpected method/uid: %v / %v", invite["method"], invite["uid"])
+	}
+	start := invite["start"].(map[string]any)
+	if start["date_time"] != "2026-11-02T09:00:00+01:00" || start["timezone"] != "Europe/Berlin" || start["utc"] != "2026-11-02T08:00:00Z" {
+		t.Errorf("unexpected start: %v", start)
+	}
+	organizer := invite["organizer"].(map[string]any)
+	if organizer["email"] != "alice@example.com" {
+		t.Errorf("organizer email = %v", organizer["email"])
+	}
+	attendees := invite["attendees"].([]any)
+	if got := attendees[0].(map[string]any)["response_status"]; got != "needsAction" {
+		t.Errorf("attendee response_status = %v, want needsAction", got)
+	}
+	link, ok := invite["calendar_event"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected calendar_event link, got %v", invite["calendar_link_error"])
+	}
+	if link["event_id"] != "evt-invite" || link["response_status"] != "needsAction" {
+		t.Errorf("unexpected calendar_event: %v", link)
+	}
+}
+
+func TestGmailGetMessage_NoInviteOmitsField(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	fixtures.MockService.AddMessage(newTestMessage("msg1", "thread1", "Hi", "a@example.com", "b@example.com", "hello", nil))
+
+	result, err := TestableGmailGetMessage(context.Background(), makeRequest(map[string]any{"message_id": "msg1"}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	response := extractResponse(t, result)
+	if _, ok := response["calendar_invites"]; ok {
+		t.Error("expected no calendar_invites for a plain message")
+	}
+}
+
+func TestGmailRSVP_UpdatesSelfAttendee(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	fixtures.MockService.AddMessage(newTestInviteMessage("msg-invite"))
+	calMock := setupInviteCalendar(t)
+
+	result, err := TestableGmailRSVP(context.Background(), makeRequest(map[string]any{
+		"message_id": "msg-invite",
+		"response":   "accepted",
+		"comment":    "See you there",
+	}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	response := extractResponse(t, result)
+	if response["response_status"] != "accepted" || response["previous_response"] != "needsAction" {
+		t.Errorf("unexpected response: %v", response)
+	}
+
+	event := calMock.Events["primary"]["evt-invite"]
+	if got := event.Attendees[1].ResponseStatus; got != "accepted" {
+		t.Errorf("self responseStatus = %q, want accepted", got)
+	}
+	if got := event.Attendees[1].Comment; got != "See you there" {
+		t.Errorf("comment = %q", got)
+	}
+	if got := event.Attendees[0].ResponseStatus; got != "accepted" {
+		t.Errorf("organizer responseStatus changed to %q", got)
+	}
+	for _, call := range calMock.Calls() {
+		if call.Method == "UpdateEvent" {
+			t.Error("expected only the self attendee to be patched, got a full UpdateEvent")
+		}
+	}
+}
+
+func TestGmailRSVP_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       map[string]any
+		errContain string
+	}{
+		{"missing response", map[string]any{"message_id": "msg-invite"}, "response"},
+		{"invalid response", map[string]any{"message_id": "msg-invite", "response": "perhaps"}, "invalid response"},
+		{"no invite", map[string]any{"message_id": "msg-plain", "response": "accepted"}, "no calendar invitation"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fixtures := NewGmailTestFixtures()
+			fixtures.MockService.AddMessage(newTestInviteMessage("msg-invite"))
+			fixtures.MockService.AddMessage(newTestMessage("msg-plain", "t", "Hi", "a@example.com", "b@example.com", "hello", nil))
+			setupInviteCalendar(t)
+
+			result, err := TestableGmailRSVP(context.Background(), makeRequest(tc.args), fixtures.Deps)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+			if text := getTextResult(result); !strings.Contains(text, tc.errContain) {
+				t.Errorf("error %q does not mention %q", text, tc.errContain)
+			}
+		})
+	}
+}
+
+func TestGmailRSVP_EventNotInCalendar(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	fixtures.MockService.AddMessage(newTestInviteMessage("msg-invite"))
+	calMock := setupInviteCalendar(t)
+	delete(calMock.Events["primary"], "evt-invite")
+
+	result, err := TestableGmailRSVP(context.Background(), makeRequest(map[string]any{
+		"message_id": "msg-invite",
+		"response":   "declined",
+	}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error when the event is not in the calendar")
+	}
+	if text := getTextResult(result); !strings.Contains(text, "invite-uid-1@google.com") {
+		t.Errorf("expected error to name the iCalUID, got %q", text)
+	}
+}
@@ -28,6 +28,7 @@ type GmailMessageWriter interface {
 
 // GmailThreadService manages Gmail conversation threads.
 type GmailThreadService interface {
+	ListThreads(ctx context.Context, query string, maxResults int64) (*gmail.ListThreadsResponse, error)
 	GetThread(ctx context.Context, threadID, format string) (*gmail.Thread, error)
 	ModifyThread(ctx context.Context, threadID string, req *gmail.ModifyThreadRequest) (*gmail.Thread, error)
 	TrashThread(ctx context.Context, threadID string) (*gmail.Thread, error)
@@ -84,6 +85,29 @@ type GmailDelegateService interface {
 	DeleteDelegate(ctx context.Context, delegateEmail string) error
 }
 
+// GmailForwardingService manages forwarding addresses and auto-forwarding.
+type GmailForwardingService interface {
+	ListForwardingAddresses(ctx context.Context) ([]*gmail.ForwardingAddress, error)
+	CreateForwardingAddress(ctx context.Context, address *gmail.ForwardingAddress) (*gmail.ForwardingAddress, error)
+	DeleteForwardingAddress(ctx context.Context, forwardingEmail string) error
+	GetAutoForwarding(ctx context.Context) (*gmail.AutoForwarding, error)
+	UpdateAutoForwarding(ctx context.Context, settings *gmail.AutoForwarding) (*gmail.AutoForwarding, error)
+}
+
+// GmailClientAccessService manages IMAP and POP access settings.
+type GmailClientAccessService interface {
+	GetImapSettings(ctx context.Context) (*gmail.ImapSettings, error)
+	UpdateImapSettings(ctx context.Context, settings *gmail.ImapSettings) (*gmail.ImapSettings, error)
+	GetPopSettings(ctx context.Context) (*gmail.PopSettings, error)
+	UpdatePopSettings(ctx context.Context, settings *gmail.PopSettings) (*gmail.PopSettings, error)
+}
+
+// GmailLanguageService manages the Gmail display language.
+type GmailLanguageService interface {
+	GetLanguageSettings(ctx context.Context) (*gmail.LanguageSettings, error)
+	UpdateLanguageSettings(ctx context.Context, settings *gmail.LanguageSettings) (*gmail.LanguageSettings, error)
+}
+
 // GmailService defines the complete interface for Gmail operations.
 // It is composed from focused sub-interfaces, each covering a single domain.
 // This abstraction enables dependency injection and testing.
@@ -97,6 +121,9 @@ type GmailService interface {
 	GmailSettingsService
 	GmailSendAsService
 	GmailDelegateService
+	GmailForwardingService
+	GmailClientAccessService
+	GmailLanguageService
 }
 
 // RealGmailService wraps the actual Gmail API service.
@@ -145,6 +172,10 @@ func (s *RealGmailService) BatchModifyMessages(ctx context.Context, req *gmail.B
 
 // === Threads ===
 
+func (s *RealGmailService) ListThreads(ctx context.Context, query string, maxResults int64) (*gmail.ListThreadsResponse, error) {
+	return s.service.Users.Threads.List(common.GmailUserMe).Q(query).MaxResults(maxResults).Context(ctx).Do()
+}
+
 func (s *RealGmailService) GetThread(ctx context.Context, threadID, format string) (*gmail.Thread, error) {
 	return s.service.Users.Threads.Get(common.GmailUserMe, threadID).Format(format).Context(ctx).Do()
 }
@@ -294,3 +325,57 @@ func (s *RealGmailService) CreateDelegate(ctx context.Context, delegate *gmail.D
 func (s *RealGmailService) DeleteDelegate(ctx context.Context, delegateEmail string) error {
 	return s.service.Users.Settings.Delegates.Delete(common.GmailUserMe, delegateEmail).Context(ctx).Do()
 }
+
+// === Forwarding ===
+
+func (s *RealGmailService) ListForwardingAddresses(ctx context.Context) ([]*gmail.ForwardingAddress, error) {
+	resp, err := s.service.Users.Settings.ForwardingAddresses.List(common.GmailUserMe).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("listing forwarding addresses: %w", err)
+	}
+	return resp.ForwardingAddresses, nil
+}
+
+func (s *RealGmailService) CreateForwardingAddress(ctx context.Context, address *gmail.ForwardingAddress) (*gmail.ForwardingAddress, error) {
+	return s.service.Users.Settings.ForwardingAddresses.Create(common.GmailUserMe, address).Context(ctx).Do()
+}
+
+func (s *RealGmailService) DeleteForwardingAddress(ctx context.Context, forwardingEmail string) error {
+	return s.service.Users.Settings.ForwardingAddresses.Delete(common.GmailUserMe, forwardingEmail).Context(ctx).Do()
+}
+
+func (s *RealGmailService) GetAutoForwarding(ctx context.Context) (*gmail.AutoForwarding, error) {
+	return s.service.Users.Settings.GetAutoForwarding(common.GmailUserMe).Context(ctx).Do()
+}
+
+func (s *RealGmailService) UpdateAutoForwarding(ctx context.Context, settings *gmail.AutoForwarding) (*gmail.AutoForwarding, error) {
+	return s.service.Users.Settings.UpdateAutoForwarding(common.GmailUserMe, settings).Context(ctx).Do()
+}
+
+// === IMAP / POP ===
+
+func (s *RealGmailService) GetImapSettings(ctx context.Context) (*gmail.ImapSettings, error) {
+	return s.service.Users.Settings.GetImap(common.GmailUserMe).Context(ctx).Do()
+}
+
+func (s *RealGmailService) UpdateImapSettings(ctx context.Context, settings *gmail.ImapSettings) (*gmail.ImapSettings, error) {
+	return s.service.Users.Settings.UpdateImap(common.GmailUserMe, settings).Context(ctx).Do()
+}
+
+func (s *RealGmailService) GetPopSettings(ctx context.Context) (*gmail.PopSettings, error) {
+	return s.service.Users.Settings.GetPop(common.GmailUserMe).Context(ctx).Do()
+}
+
+func (s *RealGmailService) UpdatePopSettings(ctx context.Context, settings *gmail.PopSettings) (*gmail.PopSettings, error) {
+	return s.service.Users.Settings.UpdatePop(common.GmailUserMe, settings).Context(ctx).Do()
+}
+
+// === Language ===
+
+func (s *RealGmailService) GetLanguageSettings(ctx context.Context) (*gmail.LanguageSettings, error) {
+	return s.service.Users.Settings.GetLanguage(common.GmailUserMe).Context(ctx).Do()
+}
+
+func (s *RealGmailService) UpdateLanguageSettings(ctx context.Context, settings *gmail.LanguageSettings) (*gmail.LanguageSettings, error) {
+	return s.service.Users.Settings.UpdateLanguage(common.GmailUserMe, settings).Context(ctx).Do()
+}
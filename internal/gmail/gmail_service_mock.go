@@ -3,6 +3,7 @@ package gmail
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"google.golang.org/api/gmail/v1"
@@ -24,11 +25,19 @@ type MockGmailService struct {
 	SendAs    map[string]*gmail.SendAs
 	Delegates map[string]*gmail.Delegate
 
+	ForwardingAddresses map[string]*gmail.ForwardingAddress
+	AutoForwarding      *gmail.AutoForwarding
+	Imap                *gmail.ImapSettings
+	Pop                 *gmail.PopSettings
+	Language            *gmail.LanguageSettings
+
 	// Error to return (if set, operations return this error)
 	Error error
 
-	// MethodCalls records all method invocations for verification
+	// MethodCalls records all method invocations for verification. Handlers
+	// may call the mock from several goroutines; callsMu guards the slice.
 	MethodCalls []MethodCall
+	callsMu     sync.Mutex
 
 	// Counters for generating IDs
 	nextMessageID int
@@ -40,13 +49,18 @@ type MockGmailService struct {
 // NewMockGmailService creates a new MockGmailService with initialized maps.
 func NewMockGmailService() *MockGmailService {
 	return &MockGmailService{
-		Messages:  make(map[string]*gmail.Message),
-		Threads:   make(map[string]*gmail.Thread),
-		Labels:    make(map[string]*gmail.Label),
-		Drafts:    make(map[string]*gmail.Draft),
-		Filters:   make(map[string]*gmail.Filter),
-		SendAs:    make(map[string]*gmail.SendAs),
-		Delegates: make(map[string]*gmail.Delegate),
+		Messages:            make(map[string]*gmail.Message),
+		Threads:             make(map[string]*gmail.Thread),
+		Labels:              make(map[string]*gmail.Label),
+		Drafts:              make(map[string]*gmail.Draft),
+		Filters:             make(map[string]*gmail.Filter),
+		SendAs:              make(map[string]*gmail.SendAs),
+		Delegates:           make(map[string]*gmail.Delegate),
+		ForwardingAddresses: make(map[string]*gmail.ForwardingAddress),
+		AutoForwarding:      &gmail.AutoForwarding{Enabled: false},
+		Imap:                &gmail.ImapSettings{Enabled: true, AutoExpunge: true, ExpungeBehavior: "archive"},
+		Pop:                 &gmail.PopSettings{AccessWindow: "disabled"},
+		Language:            &gmail.LanguageSettings{DisplayLanguage: "en"},
 		Profile: &gmail.Profile{
 			EmailAddress:  common.TestEmail,
 			MessagesTotal: 100,
@@ -60,6 +74,8 @@ func NewMockGmailService() *MockGmailService {
 }
 
 func (m *MockGmailService) recordCall(method string, args ...any) {
+	m.callsMu.Lock()
+	defer m.callsMu.Unlock()
 	m.MethodCalls = append(m.MethodCalls, MethodCall{Method: method, Args: args})
 }
 
@@ -72,6 +88,7 @@ func (m *MockGmailService) Reset() {
 	m.Filters = make(map[string]*gmail.Filter)
 	m.SendAs = make(map[string]*gmail.SendAs)
 	m.Delegates = make(map[string]*gmail.Delegate)
+	m.ForwardingAddresses = make(map[string]*gmail.ForwardingAddress)
 	m.MethodCalls = nil
 	m.Error = nil
 }
@@ -246,6 +263,23 @@ func (m *MockGmailService) BatchModifyMessages(ctx context.Context, req *gmail.B
 
 // === Threads ===
 
+func (m *MockGmailService) ListThreads(ctx context.Context, query string, maxResults int64) (*gmail.ListThreadsResponse, error) {
+	m.recordCall("ListThreads", query, maxResults)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+
+	// Return all threads (simplified - doesn't filter by query)
+	var threads []*gmail.Thread
+	for _, thread := range m.Threads {
+		threads = append(threads, &gmail.Thread{Id: thread.Id, Snippet: thread.Snippet})
+		if int64(len(threads)) >= maxResults {
+			break
+		}
+	}
+	return &gmail.ListThreadsResponse{Threads: threads, ResultSizeEstimate: int64(len(m.Threads))}, nil
+}
+
 func (m *MockGmailService) GetThread(ctx context.Context, threadID, format string) (*gmail.Thread, error) {
 	m.recordCall("GetThread", threadID, format)
 	if m.Error != nil {
@@ -609,6 +643,8 @@ func (m *MockGmailService) AddFilter(filter *gmail.Filter) {
 
 // GetLastCall returns the last method call recorded.
 func (m *MockGmailService) GetLastCall() *MethodCall {
+	m.callsMu.Lock()
+	defer m.callsMu.Unlock()
 	if len(m.MethodCalls) == 0 {
 		return nil
 	}
@@ -617,6 +653,8 @@ func (m *MockGmailService) GetLastCall() *MethodCall {
 
 // WasMethodCalled checks if a method was called.
 func (m *MockGmailService) WasMethodCalled(method string) bool {
+	m.callsMu.Lock()
+	defer m.callsMu.Unlock()
 	for _, call := range m.MethodCalls {
 		if call.Method == method {
 			return true
@@ -764,3 +802,120 @@ func (m *MockGmailService) AddSendAs(sendAs *gmail.SendAs) {
 func (m *MockGmailService) AddDelegate(delegate *gmail.Delegate) {
 	m.Delegates[delegate.DelegateEmail] = delegate
 }
+
+// === Forwarding ===
+
+func (m *MockGmailService) ListForwardingAddresses(ctx context.Context) ([]*gmail.ForwardingAddress, error) {
+	m.recordCall("ListForwardingAddresses")
+	if m.Error != nil {
+		return nil, m.Error
+	}
+
+	var addresses []*gmail.ForwardingAddress
+	for _, fa := range m.ForwardingAddresses {
+		addresses = append(addresses, fa)
+	}
+	return addresses, nil
+}
+
+func (m *MockGmailService) CreateForwardingAddress(ctx context.Context, address *gmail.ForwardingAddress) (*gmail.ForwardingAddress, error) {
+	m.recordCall("CreateForwardingAddress", address)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+
+	address.VerificationStatus = "pending"
+	m.ForwardingAddresses[address.ForwardingEmail] = address
+	return address, nil
+}
+
+func (m *MockGmailService) DeleteForwardingAddress(ctx context.Context, forwardingEmail string) error {
+	m.recordCall("DeleteForwardingAddress", forwardingEmail)
+	if m.Error != nil {
+		return m.Error
+	}
+
+	if _, ok := m.ForwardingAddresses[forwardingEmail]; !ok {
+		return fmt.Errorf("forwarding address not found: %s", forwardingEmail)
+	}
+
+	delete(m.ForwardingAddresses, forwardingEmail)
+	return nil
+}
+
+func (m *MockGmailService) GetAutoForwarding(ctx context.Context) (*gmail.AutoForwarding, error) {
+	m.recordCall("GetAutoForwarding")
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.AutoForwarding, nil
+}
+
+func (m *MockGmailService) UpdateAutoForwarding(ctx context.Context, settings *gmail.AutoForwarding) (*gmail.AutoForwarding, error) {
+	m.recordCall("UpdateAutoForwarding", settings)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	m.AutoForwarding = settings
+	return settings, nil
+}
+
+// === IMAP / POP ===
+
+func (m *MockGmailService) GetImapSettings(ctx context.Context) (*gmail.ImapSettings, error) {
+	m.recordCall("GetImapSettings")
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.Imap, nil
+}
+
+func (m *MockGmailService) UpdateImapSettings(ctx context.Context, settings *gmail.ImapSettings) (*gmail.ImapSettings, error) {
+	m.recordCall("UpdateImapSettings", settings)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	m.Imap = settings
+	return settings, nil
+}
+
+func (m *MockGmailService) GetPopSettings(ctx context.Context) (*gmail.PopSettings, error) {
+	m.recordCall("GetPopSettings")
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.Pop, nil
+}
+
+func (m *MockGmailService) UpdatePopSettings(ctx context.Context, settings *gmail.PopSettings) (*gmail.PopSettings, error) {
+	m.recordCall("UpdatePopSettings", settings)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	m.Pop = settings
+	return settings, nil
+}
+
+// === Language ===
+
+func (m *MockGmailService) GetLanguageSettings(ctx context.Context) (*gmail.LanguageSettings, error) {
+	m.recordCall("GetLanguageSettings")
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.Language, nil
+}
+
+func (m *MockGmailService) UpdateLanguageSettings(ctx context.Context, settings *gmail.LanguageSettings) (*gmail.LanguageSettings, error) {
+	m.recordCall("UpdateLanguageSettings", settings)
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	m.Language = settings
+	return settings, nil
+}
+
+// AddForwardingAddress adds a forwarding address to the mock store.
+func (m *MockGmailService) AddForwardingAddress(address *gmail.ForwardingAddress) {
+	m.ForwardingAddresses[address.ForwardingEmail] = address
+}
@@ -0,0 +1,434 @@
+package gmail
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/gmail/v1"
+)
+
+// Analytics Tools
+var (
+	HandleGmailStats = common.WrapHandler[GmailService](TestableGmailStats)
+)
+
+// gmail_stats limits.
+const (
+	statsDefaultMaxMessages = 500
+	statsMaxMessagesLimit   = 2000
+	statsDefaultTopN        = 10
+	statsCacheMaxEntries    = 32
+	statsThreadConcurrency  = 5
+)
+
+// statsGroups lists the aggregate sections gmail_stats can return.
+var statsGroups = []string{"senders", "domains", "labels", "days", "unread", "response_time"}
+
+// statsMessage is the per-message data gmail_stats aggregates over.
+type statsMessage struct {
+	From     string
+	Name     string
+	Labels   []string
+	Received time.Time
+	Unread   bool
+	Sent     bool
+}
+
+// statsSnapshot is everything fetched for one query. It is cached per account
+// and query, and reused while the mailbox history ID is unchanged.
+type statsSnapshot struct {
+	HistoryID     uint64
+	Messages      []statsMessage
+	ResponseTimes map[string][]time.Duration // correspondent → our reply delays
+	ThreadCount   int
+	Truncated     bool
+	Errors        []string // threads that could not be read
+	LabelNames    map[string]string
+	FetchedAt     time.Time
+}
+
+// statsCache holds recent snapshots keyed by account, query and cap.
+var statsCache = struct {
+	sync.Mutex
+	entries map[string]*statsSnapshot
+}{entries: make(map[string]*statsSnapshot)}
+
+func statsCacheKey(email, query string, maxMessages int) string {
+	return fmt.Sprintf("%s\x00%s\x00%d", strings.ToLower(email), query, maxMessages)
+}
+
+// cachedStatsSnapshot returns the snapshot for key if it was taken at historyID.
+func cachedStatsSnapshot(key string, historyID uint64) *statsSnapshot {
+	statsCache.Lock()
+	defer statsCache.Unlock()
+	if snap, ok := statsCache.entries[key]; ok && snap.HistoryID == historyID {
+		return snap
+	}
+	return nil
+}
+
+// storeStatsSnapshot caches snap, evicting the oldest entry when full.
+func storeStatsSnapshot(key string, snap *statsSnapshot) {
+	statsCache.Lock()
+	defer statsCache.Unlock()
+	if _, exists := statsCache.entries[key]; !exists && len(statsCache.entries) >= statsCacheMaxEntries {
+		var oldestKey string
+		for k, v := range statsCache.entries {
+			if oldestKey == "" || v.FetchedAt.Before(statsCache.entries[oldestKey].FetchedAt) {
+				oldestKey = k
+			}
+		}
+		delete(statsCache.entries, oldestKey)
+	}
+	statsCache.entries[key] = snap
+}
+
+// parseStatsGroups validates group_by, accepting an array or comma-separated
+// string. An empty value selects every group.
+func parseStatsGroups(value any) (map[string]bool, error) {
+	var names []string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		for _, n := range strings.Split(v, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				names = append(names, n)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				names = append(names, strings.TrimSpace(s))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("group_by must be a string or array")
+	}
+
+	groups := make(map[string]bool)
+	if len(names) == 0 {
+		for _, g := range statsGroups {
+			groups[g] = true
+		}
+		return groups, nil
+	}
+	for _, n := range names {
+		n = strings.ToLower(n)
+		valid := false
+		for _, g := range statsGroups {
+			if n == g {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid group_by %q: must be one of %s", n, strings.Join(statsGroups, ", "))
+		}
+		groups[n] = true
+	}
+	return groups, nil
+}
+
+// fetchStatsSnapshot pages through the search up to maxMessages matches, then
+// reads each thread once in metadata format, a few at a time. Matched messages
+// feed the volume aggregates; whole threads feed response times. Threads that
+// cannot be read are recorded in Errors and skipped; only a failure to read
+// every thread fails the snapshot.
+func fetchStatsSnapshot(ctx context.Context, svc GmailService, query string, maxMessages int, selfEmail string) (*statsSnapshot, error) {
+	matched := make(map[string]bool)
+	var threadIDs []string
+	seenThreads := make(map[string]bool)
+	truncated := false
+	pageToken := ""
+	for {
+		pageSize := int64(common.GmailMaxResultsLimit)
+		if remaining := maxMessages - len(matched); int64(remaining) < pageSize {
+			pageSize = int64(remaining)
+		}
+		resp, err := svc.ListMessages(ctx, query, pageSize, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range resp.Messages {
+			if len(matched) >= maxMessages {
+				truncated = true
+				break
+			}
+			matched[m.Id] = true
+			if !seenThreads[m.ThreadId] {
+				seenThreads[m.ThreadId] = true
+				threadIDs = append(threadIDs, m.ThreadId)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		if len(matched) >= maxMessages {
+			truncated = true
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	snap := &statsSnapshot{
+		ResponseTimes: make(map[string][]time.Duration),
+		Truncated:     truncated,
+		LabelNames:    make(map[string]string),
+		FetchedAt:     time.Now(),
+	}
+	threads := make([]*gmail.Thread, len(threadIDs))
+	errs := make([]error, len(threadIDs))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(statsThreadConcurrency)
+	for i, threadID := range threadIDs {
+		g.Go(func() error {
+			threads[i], errs[i] = svc.GetThread(gCtx, threadID, "metadata")
+			return nil
+		})
+	}
+	g.Wait() //nolint:errcheck // goroutines return nil unconditionally; failures stored in errs
+
+	for i, thread := range threads {
+		if errs[i] != nil {
+			snap.Errors = append(snap.Errors, fmt.Sprintf("%s: %v", threadIDs[i], errs[i]))
+			continue
+		}
+		snap.ThreadCount++
+		for _, msg := range thread.Messages {
+			if matched[msg.Id] {
+				snap.Messages = append(snap.Messages, newStatsMessage(msg))
+			}
+		}
+		collectResponseTimes(thread, selfEmail, snap.ResponseTimes)
+	}
+
+	if len(threadIDs) > 0 && snap.ThreadCount == 0 {
+		return nil, errs[0]
+	}
+
+	if labels, err := svc.ListLabels(ctx); err == nil {
+		for _, l := range labels.Labels {
+			snap.LabelNames[l.Id] = l.Name
+		}
+	}
+	return snap, nil
+}
+
+func newStatsMessage(msg *gmail.Message) statsMessage {
+	sm := statsMessage{
+		Labels:   msg.LabelIds,
+		Received: time.UnixMilli(msg.InternalDate),
+		Unread:   hasAnyLabel(msg.LabelIds, "UNREAD"),
+		Sent:     hasAnyLabel(msg.LabelIds, "SENT"),
+	}
+	if msg.Payload != nil {
+		for _, h := range msg.Payload.Headers {
+			if strings.EqualFold(h.Name, "From") {
+				sm.From, sm.Name = parseStatsAddress(h.Value)
+				break
+			}
+		}
+	}
+	return sm
+}
+
+// parseStatsAddress returns the lower-cased address and display name of a
+// From header, falling back to the raw value when it does not parse.
+func parseStatsAddress(value string) (string, string) {
+	if addr, err := mail.ParseAddress(value); err == nil {
+		return strings.ToLower(addr.Address), addr.Name
+	}
+	return strings.ToLower(strings.TrimSpace(value)), ""
+}
+
+// collectResponseTimes records, per correspondent, how long we took to send a
+// message after the earliest unanswered message they sent in the thread.
+func collectResponseTimes(thread *gmail.Thread, selfEmail string, out map[string][]time.Duration) {
+	msgs := make([]*gmail.Message, 0, len(thread.Messages))
+	for _, msg := range thread.Messages {
+		if !hasAnyLabel(msg.LabelIds, "DRAFT", "TRASH", "SPAM") {
+			msgs = append(msgs, msg)
+		}
+	}
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].InternalDate < msgs[j].InternalDate })
+
+	var pendingFrom string
+	var pendingAt int64
+	for _, msg := range msgs {
+		sm := newStatsMessage(msg)
+		ours := sm.Sent || (selfEmail != "" && strings.EqualFold(sm.From, selfEmail))
+		switch {
+		case ours && pendingFrom != "":
+			out[pendingFrom] = append(out[pendingFrom], time.Duration(msg.InternalDate-pendingAt)*time.Millisecond)
+			pendingFrom = ""
+		case !ours && pendingFrom == "" && sm.From != "":
+			pendingFrom, pendingAt = sm.From, msg.InternalDate
+		}
+	}
+}
+
+// statsCount is one row of a ranked count aggregate.
+type statsCount struct {
+	Key   string `json:"key"`
+	Name  string `json:"name,omitempty"`
+	Count int    `json:"count"`
+}
+
+// topCounts ranks counts descending (ties by key) and keeps the first n.
+func topCounts(counts map[string]int, names map[string]string, n int) []statsCount {
+	rows := make([]statsCount, 0, len(counts))
+	for k, c := range counts {
+		rows = append(rows, statsCount{Key: k, Name: names[k], Count: c})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Count != rows[j].Count {
+			return rows[i].Count > rows[j].Count
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	if n > 0 && len(rows) > n {
+		rows = rows[:n]
+	}
+	return rows
+}
+
+func medianDuration(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+func roundHours(d time.Duration) float64 {
+	return float64(int(d.Hours()*10+0.5)) / 10
+}
+
+// buildStatsResult renders the requested aggregates from a snapshot. Ages are
+// computed against now, so cached snapshots still report current backlog age.
+func buildStatsResult(snap *statsSnapshot, groups map[string]bool, topN int, now time.Time) map[string]any {
+	result := map[string]any{}
+
+	if groups["senders"] || groups["domains"] {
+		senders := make(map[string]int)
+		names := make(map[string]string)
+		domains := make(map[string]int)
+		for _, m := range snap.Messages {
+			if m.Sent || m.From == "" {
+				continue
+			}
+			senders[m.From]++
+			if m.Name != "" {
+				names[m.From] = m.Name
+			}
+			if at := strings.LastIndex(m.From, "@"); at >= 0 {
+				domains[m.From[at+1:]]++
+			}
+		}
+		if groups["senders"] {
+			result["top_senders"] = topCounts(senders, names, topN)
+		}
+		if groups["domains"] {
+			result["top_domains"] = topCounts(domains, nil, topN)
+		}
+	}
+
+	if groups["labels"] {
+		labels := make(map[string]int)
+		for _, m := range snap.Messages {
+			for _, l := range m.Labels {
+				labels[l]++
+			}
+		}
+		result["labels"] = topCounts(labels, snap.LabelNames, 0)
+	}
+
+	if groups["days"] {
+		perDay := make(map[string]int)
+		for _, m := range snap.Messages {
+			perDay[m.Received.UTC().Format("2006-01-02")]++
+		}
+		days := make([]statsCount, 0, len(perDay))
+		for d, c := range perDay {
+			days = append(days, statsCount{Key: d, Count: c})
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Key < days[j].Key })
+		result["messages_per_day"] = days
+	}
+
+	if groups["unread"] {
+		var ages []time.Duration
+		buckets := map[string]int{"under_1d": 0, "1d_to_7d": 0, "7d_to_30d": 0, "over_30d": 0}
+		for _, m := range snap.Messages {
+			if !m.Unread {
+				continue
+			}
+			age := now.Sub(m.Received)
+			ages = append(ages, age)
+			switch {
+			case age < 24*time.Hour:
+				buckets["under_1d"]++
+			case age < 7*24*time.Hour:
+				buckets["1d_to_7d"]++
+			case age < 30*24*time.Hour:
+				buckets["7d_to_30d"]++
+			default:
+				buckets["over_30d"]++
+			}
+		}
+		unread := map[string]any{"count": len(ages), "buckets": buckets}
+		if len(ages) > 0 {
+			oldest := ages[0]
+			for _, a := range ages {
+				if a > oldest {
+					oldest = a
+				}
+			}
+			unread["oldest_age_hours"] = roundHours(oldest)
+			unread["median_age_hours"] = roundHours(medianDuration(ages))
+		}
+		result["unread_backlog"] = unread
+	}
+
+	if groups["response_time"] {
+		type responseRow struct {
+			Correspondent string  `json:"correspondent"`
+			Replies       int     `json:"replies"`
+			MedianHours   float64 `json:"median_hours"`
+		}
+		var all []time.Duration
+		rows := make([]responseRow, 0, len(snap.ResponseTimes))
+		for who, ds := range snap.ResponseTimes {
+			all = append(all, ds...)
+			rows = append(rows, responseRow{Correspondent: who, Replies: len(ds), MedianHours: roundHours(medianDuration(ds))})
+		}
+		sort.Slice(rows, func(i, j int) bool {
+			if rows[i].Replies != rows[j].Replies {
+				return rows[i].Replies > rows[j].Replies
+			}
+			return rows[i].Correspondent < rows[j].Correspondent
+		})
+		if len(rows) > topN {
+			rows = rows[:topN]
+		}
+		rt := map[string]any{"by_correspondent": rows, "replies": len(all)}
+		if len(all) > 0 {
+			rt["median_hours"] = roundHours(medianDuration(all))
+		}
+		result["response_time"] = rt
+	}
+
+	return result
+}
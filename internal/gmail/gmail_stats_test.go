@@ -0,0 +1,214 @@
+package gmail
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// newStatsTestMessage builds a metadata-format message from sender at the given time.
+func newStatsTestMessage(id, threadID, from string, at time.Time, labels ...string) *gmail.Message {
+	return &gmail.Message{
+		Id:           id,
+		ThreadId:     threadID,
+		LabelIds:     labels,
+		InternalDate: at.UnixMilli(),
+		Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+			{Name: "From", Value: from},
+		}},
+	}
+}
+
+func resetStatsCache(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		statsCache.Lock()
+		statsCache.entries = make(map[string]*statsSnapshot)
+		statsCache.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func seedStatsMailbox(mock *MockGmailService) {
+	base := time.Now().Add(-48 * time.Hour)
+	seedFollowUpThreads(mock,
+		&gmail.Thread{Id: "t1", Messages: []*gmail.Message{
+			newStatsTestMessage("a1", "t1", "Alice <alice@acme.com>", base, "INBOX", "Label_1"),
+			newStatsTestMessage("a2", "t1", "Test <test@example.com>", base.Add(2*time.Hour), "SENT"),
+			newStatsTestMessage("a3", "t1", "Alice <alice@acme.com>", base.Add(3*time.Hour), "INBOX", "UNREAD"),
+			newStatsTestMessage("a4", "t1", "Test <test@example.com>", base.Add(7*time.Hour), "SENT"),
+		}},
+		&gmail.Thread{Id: "t2", Messages: []*gmail.Message{
+			newStatsTestMessage("b1", "t2", "bob@acme.com", base.Add(-24*time.Hour), "INBOX", "UNREAD"),
+		}},
+		&gmail.Thread{Id: "t3", Messages: []*gmail.Message{
+			newStatsTestMessage("c1", "t3", "Carol <carol@other.org>", base, "INBOX", "Label_1"),
+		}},
+	)
+	mock.AddLabel(&gmail.Label{Id: "Label_1", Name: "Clients"})
+}
+
+func TestGmailStats_Aggregates(t *testing.T) {
+	resetStatsCache(t)
+	fixtures := NewGmailTestFixtures()
+	seedStatsMailbox(fixtures.MockService)
+
+	result, err := TestableGmailStats(context.Background(), makeRequest(map[string]any{"query": "newer_than:7d"}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", getTextResult(result))
+	}
+	response := extractResponse(t, result)
+
+	if got := response["messages_analyzed"]; got != float64(6) {
+		t.Errorf("messages_analyzed = %v, want 6", got)
+	}
+	if got := response["threads_analyzed"]; got != float64(3) {
+		t.Errorf("threads_analyzed = %v, want 3", got)
+	}
+
+	senders := response["top_senders"].([]any)
+	top := senders[0].(map[string]any)
+	if top["key"] != "alice@acme.com" || top["count"] != float64(2) || top["name"] != "Alice" {
+		t.Errorf("top sender = %v", top)
+	}
+	domains := response["top_domains"].([]any)
+	if d := domains[0].(map[string]any); d["key"] != "acme.com" || d["count"] != float64(3) {
+		t.Errorf("top domain = %v", d)
+	}
+
+	var clients map[string]any
+	for _, l := range response["labels"].([]any) {
+		if row := l.(map[string]any); row["key"] == "Label_1" {
+			clients = row
+		}
+	}
+	if clients == nil || clients["name"] != "Clients" || clients["count"] != float64(2) {
+		t.Errorf("Label_1 row = %v", clients)
+	}
+
+	unread := response["unread_backlog"].(map[string]any)
+	if unread["count"] != float64(2) {
+		t.Errorf("unread count = %v", unread["count"])
+	}
+	if buckets := unread["buckets"].(map[string]any); buckets["1d_to_7d"] != float64(2) {
+		t.Errorf("unread buckets = %v", buckets)
+	}
+
+	rt := response["response_time"].(map[string]any)
+	if rt["replies"] != float64(2) {
+		t.Errorf("replies = %v, want 2", rt["replies"])
+	}
+	// Replies after 2h and 4h: median 3h.
+	if rt["median_hours"] != float64(3) {
+		t.Errorf("median_hours = %v, want 3", rt["median_hours"])
+	}
+	rows := rt["by_correspondent"].([]any)
+	if len(rows) != 1 || rows[0].(map[string]any)["correspondent"] != "alice@acme.com" {
+		t.Errorf("by_correspondent = %v", rows)
+	}
+}
+
+func TestGmailStats_GroupByAndCache(t *testing.T) {
+	resetStatsCache(t)
+	fixtures := NewGmailTestFixtures()
+	seedStatsMailbox(fixtures.MockService)
+
+	args := map[string]any{"group_by": "senders, days"}
+	result, _ := TestableGmailStats(context.Background(), makeRequest(args), fixtures.Deps)
+	response := extractResponse(t, result)
+	if _, ok := response["top_senders"]; !ok {
+		t.Error("expected top_senders")
+	}
+	if _, ok := response["messages_per_day"]; !ok {
+		t.Error("expected messages_per_day")
+	}
+	if _, ok := response["labels"]; ok {
+		t.Error("did not expect labels when not requested")
+	}
+	if response["cached"] != false {
+		t.Error("first call should not be cached")
+	}
+
+	// Same history ID: served from cache without reading threads again.
+	fixtures.MockService.MethodCalls = nil
+	result, _ = TestableGmailStats(context.Background(), makeRequest(map[string]any{"group_by": []any{"labels"}}), fixtures.Deps)
+	response = extractResponse(t, result)
+	if response["cached"] != true {
+		t.Error("expected cached result for unchanged history ID")
+	}
+	if fixtures.MockService.WasMethodCalled("GetThread") || fixtures.MockService.WasMethodCalled("ListMessages") {
+		t.Error("cached call should not hit the message APIs")
+	}
+	if _, ok := response["labels"]; !ok {
+		t.Error("cached snapshot should serve any group_by")
+	}
+
+	// New mail changes the history ID and invalidates the cache.
+	fixtures.MockService.Profile.HistoryId++
+	result, _ = TestableGmailStats(context.Background(), makeRequest(args), fixtures.Deps)
+	if extractResponse(t, result)["cached"] != false {
+		t.Error("expected refetch after history ID change")
+	}
+}
+
+func TestGmailStats_ThreadErrors(t *testing.T) {
+	resetStatsCache(t)
+	fixtures := NewGmailTestFixtures()
+	seedStatsMailbox(fixtures.MockService)
+	// A match whose thread cannot be read.
+	fixtures.MockService.AddMessage(newStatsTestMessage("d1", "t-gone", "dave@acme.com", time.Now()))
+
+	result, _ := TestableGmailStats(context.Background(), makeRequest(map[string]any{}), fixtures.Deps)
+	if result.IsError {
+		t.Fatalf("expected the other threads to be analyzed, got error: %v", getTextResult(result))
+	}
+	response := extractResponse(t, result)
+	if response["threads_analyzed"] != float64(3) || response["messages_analyzed"] != float64(6) {
+		t.Errorf("unexpected counts: %v threads, %v messages", response["threads_analyzed"], response["messages_analyzed"])
+	}
+	if errs, _ := response["errors"].([]any); len(errs) != 1 || !strings.HasPrefix(errs[0].(string), "t-gone:") {
+		t.Errorf("expected the failed thread reported, got %v", response["errors"])
+	}
+
+	// The partial snapshot is not cached.
+	result, _ = TestableGmailStats(context.Background(), makeRequest(map[string]any{}), fixtures.Deps)
+	if extractResponse(t, result)["cached"] != false {
+		t.Error("expected a partial snapshot to be refetched")
+	}
+
+	fixtures.MockService.Threads = nil
+	result, _ = TestableGmailStats(context.Background(), makeRequest(map[string]any{}), fixtures.Deps)
+	if !result.IsError || !strings.Contains(getTextResult(result), "thread not found") {
+		t.Errorf("expected an error when no thread can be read, got %v", getTextResult(result))
+	}
+}
+
+func TestGmailStats_InvalidGroupBy(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	result, err := TestableGmailStats(context.Background(), makeRequest(map[string]any{"group_by": "weather"}), fixtures.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error for unknown group_by")
+	}
+}
+
+func TestMedianDuration(t *testing.T) {
+	if got := medianDuration([]time.Duration{3, 1, 2}); got != 2 {
+		t.Errorf("odd median = %v", got)
+	}
+	if got := medianDuration([]time.Duration{4, 1, 2, 3}); got != 2 {
+		t.Errorf("even median = %v, want 2 (integer mean of 2 and 3)", got)
+	}
+	if got := medianDuration(nil); got != 0 {
+		t.Errorf("empty median = %v", got)
+	}
+}
@@ -230,3 +230,24 @@ func ParseGmailWebID(input string) (*ResolvedWebID, error) {
 
 	return nil, fmt.Errorf("gmail web ID: unrecognised ID form %q (not a Gmail URL, thread-f:, msg-f:, FMfcg…, or API hex ID)", bare)
 }
+
+// gmailWebBaseURL is the Gmail web client root used by GmailWebURL.
+const gmailWebBaseURL = "https://mail.google.com/mail/u/0/"
+
+// GmailWebURL returns a clickable Gmail web link for an API thread or message ID.
+//
+// The #all/<id> fragment accepts either ID form: a thread ID opens the
+// conversation, a message ID opens its conversation scrolled to the message.
+// When email is set it is passed as authuser= so the link opens in the right
+// account regardless of the browser's signed-in account order (the /u/0/
+// index is then ignored by Gmail). Returns "" for an empty id.
+func GmailWebURL(id, email string) string {
+	if id == "" {
+		return ""
+	}
+	link := gmailWebBaseURL
+	if email != "" {
+		link += "?authuser=" + url.QueryEscape(email)
+	}
+	return link + "#all/" + url.PathEscape(id)
+}
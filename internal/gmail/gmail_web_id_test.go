@@ -522,3 +522,62 @@ func TestGmailResolveWebID_WhitespaceStripped(t *testing.T) {
 		t.Fatalf("expected success after whitespace strip, got error: %v", result.Content)
 	}
 }
+
+// === GmailWebURL (reverse resolution) ===
+
+func TestGmailWebURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    string
+		email string
+		want  string
+	}{
+		{"with account", "18c2f0a1b2c3d4e5", "alice@example.com", "https://mail.google.com/mail/u/0/?authuser=alice%40example.com#all/18c2f0a1b2c3d4e5"},
+		{"plus address", "18c2f0a1b2c3d4e5", "a+b@example.com", "https://mail.google.com/mail/u/0/?authuser=a%2Bb%40example.com#all/18c2f0a1b2c3d4e5"},
+		{"no account", "18c2f0a1b2c3d4e5", "", "https://mail.google.com/mail/u/0/#all/18c2f0a1b2c3d4e5"},
+		{"empty id", "", "alice@example.com", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := GmailWebURL(tc.id, tc.email); got != tc.want {
+				t.Errorf("GmailWebURL = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGmailWebURL_RoundTrip(t *testing.T) {
+	link := GmailWebURL("18c2f0a1b2c3d4e5", "alice@example.com")
+	resolved, err := ParseGmailWebID(link)
+	if err != nil {
+		t.Fatalf("ParseGmailWebID(%q): %v", link, err)
+	}
+	if resolved.Kind != WebIDKindAPIID || resolved.ThreadID != "18c2f0a1b2c3d4e5" {
+		t.Errorf("round trip resolved to %+v", resolved)
+	}
+}
+
+func TestGmailWebURL_InToolResults(t *testing.T) {
+	fixtures := NewGmailTestFixtures()
+	fixtures.MockService.AddMessage(newTestMessage("abc123", "thr456", "Hi", "a@example.com", "b@example.com", "hello", nil))
+	fixtures.MockService.AddThread(&gmail.Thread{Id: "thr456", Messages: []*gmail.Message{fixtures.MockService.Messages["abc123"]}})
+	ctx := context.Background()
+	const account = "work@example.com"
+	wantPrefix := "https://mail.google.com/mail/u/0/?authuser=work%40example.com#all/"
+
+	result, _ := TestableGmailGetMessage(ctx, makeRequest(map[string]any{"message_id": "abc123", "account": account}), fixtures.Deps)
+	if got := extractResponse(t, result)["web_url"]; got != wantPrefix+"abc123" {
+		t.Errorf("gmail_get web_url = %v", got)
+	}
+
+	result, _ = TestableGmailGetThread(ctx, makeRequest(map[string]any{"thread_id": "thr456", "account": account}), fixtures.Deps)
+	if got := extractResponse(t, result)["web_url"]; got != wantPrefix+"thr456" {
+		t.Errorf("gmail_get_thread web_url = %v", got)
+	}
+
+	result, _ = TestableGmailSearch(ctx, makeRequest(map[string]any{"query": "hello", "account": account}), fixtures.Deps)
+	messages := extractResponse(t, result)["messages"].([]any)
+	if got := messages[0].(map[string]any)["web_url"]; got != wantPrefix+"abc123" {
+		t.Errorf("gmail_search web_url = %v", got)
+	}
+}
@@ -50,7 +50,7 @@ func RegisterTools(s *server.MCPServer) {
 func registerCoreTools(s *server.MCPServer) {
 	// gmail_search - Search messages with query
 	s.AddTool(mcp.NewTool("gmail_search",
-		mcp.WithDescription("Search Gmail messages with query. Returns message IDs for use with gmail_get/gmail_get_messages."),
+		mcp.WithDescription("Search Gmail messages with query. Returns message IDs for use with gmail_get/gmail_get_messages, each with a web_url that opens the message in Gmail for the selected account."),
 		mcp.WithString("query", mcp.Required(), mcp.Description("Gmail search query (e.g., 'is:unread', 'from:amazon newer_than:7d')")),
 		mcp.WithNumber("max_results", mcp.Description("Maximum results to return (1-100, default 20)")),
 		common.WithPageToken(),
@@ -59,13 +59,47 @@ func registerCoreTools(s *server.MCPServer) {
 
 	// gmail_get / gmail_get_message - Read single message
 	s.AddTool(newGetMessageTool("gmail_get",
-		"Get a single Gmail message by ID. Returns message metadata, body/raw content when requested by format, payload_headers preserving Gmail's full ordered header list and repeated headers, and a curated convenience headers map. "+defaultGmailHeaderDescription,
+		"Get a single Gmail message by ID. Returns message metadata, body/raw content when requested by format, payload_headers preserving Gmail's full ordered header list and repeated headers, and a curated convenience headers map. "+
+			"Calendar invitations (text/calendar parts) are parsed into calendar_invites with method, organizer, times with timezone, attendees, UID, and the matching Calendar event when found. web_url links to the message in the Gmail web client. "+defaultGmailHeaderDescription,
 	), HandleGmailGetMessage)
 
 	s.AddTool(newGetMessageTool("gmail_get_message",
 		"Alias for gmail_get. Get a single Gmail message by ID.",
 	), HandleGmailGetMessage)
 
+	// gmail_rsvp - Respond to a calendar invitation
+	s.AddTool(mcp.NewTool("gmail_rsvp",
+		mcp.WithDescription("Respond to the calendar invitation in a Gmail message. Finds the Calendar event by the invitation's iCalUID (see calendar_invites in gmail_get) and sets your attendee response. Invitations for a single occurrence update only that instance."),
+		mcp.WithString("message_id", mcp.Required(), mcp.Description("Gmail message ID containing the invitation")),
+		mcp.WithString("response", mcp.Required(), mcp.Description("Response: accepted, declined, or tentative")),
+		mcp.WithString("comment", mcp.Description("Optional note to the organizer")),
+		mcp.WithString("calendar_id", mcp.Description("Calendar holding the event (default: 'primary')")),
+		common.WithAccountParam(),
+	), HandleGmailRSVP)
+
+	// gmail_awaiting_reply - Find sent threads still waiting on a reply
+	s.AddTool(mcp.NewTool("gmail_awaiting_reply",
+		mcp.WithDescription("Find threads where the last message is one you sent more than N days ago and nobody has replied. Returns thread ID, subject, recipients, and days waiting, oldest first. Optionally applies a follow-up label and/or creates a Google Task per thread; threads that already carry the label are skipped for task creation so repeated runs do not duplicate tasks."),
+		mcp.WithNumber("days", mcp.Description("Minimum days since your last message (default: 3)")),
+		mcp.WithNumber("lookback_days", mcp.Description("How far back to scan sent mail, in days (default: 30)")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum threads to return (default: 20, max: 100)")),
+		mcp.WithString("label", mcp.Description("Label to apply to matching threads, e.g. 'Follow-up' (created if missing)")),
+		mcp.WithBoolean("create_tasks", mcp.Description("Create a Google Task for each newly found thread; threads that already have one in the task list (or carry the label) are skipped (default: false)")),
+		mcp.WithString("tasklist_id", mcp.Description("Task list for created tasks (default: '@default')")),
+		common.WithAccountParam(),
+	), HandleGmailAwaitingReply)
+
+	// gmail_stats - Mailbox analytics over a search
+	s.AddTool(mcp.NewTool("gmail_stats",
+		mcp.WithDescription("Aggregate mailbox statistics over a Gmail search: top senders and domains, counts by label, messages per day, unread backlog age, and your median response time per correspondent. Reads at most max_messages matches (metadata only); threads that cannot be read are listed in 'errors' and left out. Results are cached per account and query until the mailbox history ID changes (partial results are not cached); 'cached' reports whether the API was skipped."),
+		mcp.WithString("query", mcp.Description("Gmail search query to analyze, e.g. 'newer_than:30d' (default: all mail)")),
+		mcp.WithString("group_by", mcp.Description("Comma-separated sections to return: senders, domains, labels, days, unread, response_time (default: all)")),
+		mcp.WithNumber("max_messages", mcp.Description("Maximum matching messages to analyze (default: 500, max: 2000)")),
+		mcp.WithNumber("top_n", mcp.Description("Rows to return in ranked sections (default: 10)")),
+		mcp.WithBoolean("refresh", mcp.Description("Ignore the cache and refetch (default: false)")),
+		common.WithAccountParam(),
+	), HandleGmailStats)
+
 	// gmail_get_messages - Read batch of messages
 	s.AddTool(mcp.NewTool("gmail_get_messages",
 		mcp.WithDescription("Get multiple Gmail messages by ID (max 25). Each message includes payload_headers preserving Gmail's full ordered header list and repeated headers, plus a curated convenience headers map. "+defaultGmailHeaderDescription),
@@ -77,7 +111,7 @@ func registerCoreTools(s *server.MCPServer) {
 
 	// gmail_get_thread - Read full conversation
 	s.AddTool(mcp.NewTool("gmail_get_thread",
-		mcp.WithDescription("Get all messages in a Gmail thread/conversation. Each message includes payload_headers preserving Gmail's full ordered header list and repeated headers, plus a curated convenience headers map. "+defaultGmailHeaderDescription),
+		mcp.WithDescription("Get all messages in a Gmail thread/conversation, with a web_url linking to the conversation in Gmail. Each message includes payload_headers preserving Gmail's full ordered header list and repeated headers, plus a curated convenience headers map. "+defaultGmailHeaderDescription),
 		mcp.WithString("thread_id", mcp.Required(), mcp.Description("Gmail thread ID")),
 		mcp.WithString("format", mcp.Description("Response format: full (default, includes payload_headers and body), metadata (includes payload_headers), minimal")),
 		mcp.WithString("body_format", mcp.Description("Body content format: text (default, plain text for reduced tokens), html (full HTML), full (both text and html)")),
@@ -92,7 +126,7 @@ func registerCoreTools(s *server.MCPServer) {
 		mcp.WithString("body", mcp.Required(), mcp.Description("Email body (plain text)")),
 		mcp.WithString("cc", mcp.Description("CC recipients, comma-separated")),
 		mcp.WithString("bcc", mcp.Description("BCC recipients, comma-separated")),
-		mcp.WithArray("attachments", mcp.Description("Optional local file paths to attach, for example [\"/abs/path/to/resume.pdf\"]. Total outgoing message must stay under about 25 MiB.")),
+		mcp.WithArray("attachments", mcp.Description("Optional local file paths to attach, for example [\"/abs/path/to/resume.pdf\"]. Total outgoing message must stay under about 25 MiB. Must be inside local_files.allowed_dirs when configured.")),
 		common.WithAccountParam(),
 	), HandleGmailSend)
 
@@ -104,7 +138,7 @@ func registerCoreTools(s *server.MCPServer) {
 		mcp.WithBoolean("reply_all", mcp.Description("Reply to all recipients (default: false)")),
 		mcp.WithString("to", mcp.Description("Override recipient (default: reply to sender)")),
 		mcp.WithString("cc", mcp.Description("Additional CC recipients")),
-		mcp.WithArray("attachments", mcp.Description("Optional local file paths to attach, for example [\"/abs/path/to/resume.pdf\"]. Total outgoing message must stay under about 25 MiB.")),
+		mcp.WithArray("attachments", mcp.Description("Optional local file paths to attach, for example [\"/abs/path/to/resume.pdf\"]. Total outgoing message must stay under about 25 MiB. Must be inside local_files.allowed_dirs when configured.")),
 		common.WithAccountParam(),
 	), HandleGmailReply)
 
@@ -117,7 +151,7 @@ func registerCoreTools(s *server.MCPServer) {
 		mcp.WithString("cc", mcp.Description("CC recipients")),
 		mcp.WithString("bcc", mcp.Description("BCC recipients")),
 		mcp.WithString("thread_id", mcp.Description("Thread ID for reply drafts")),
-		mcp.WithArray("attachments", mcp.Description("Optional local file paths to attach, for example [\"/abs/path/to/resume.pdf\"]. Total outgoing message must stay under about 25 MiB.")),
+		mcp.WithArray("attachments", mcp.Description("Optional local file paths to attach, for example [\"/abs/path/to/resume.pdf\"]. Total outgoing message must stay under about 25 MiB. Must be inside local_files.allowed_dirs when configured.")),
 		common.WithAccountParam(),
 	), HandleGmailDraft)
 
@@ -199,8 +233,8 @@ func registerExtendedTools(s *server.MCPServer) {
 		mcp.WithString("message_id", mcp.Required(), mcp.Description("Gmail message ID containing the attachment")),
 		mcp.WithString("attachment_id", mcp.Description("Attachment ID from gmail_list_attachments or message payload")),
 		mcp.WithString("part_id", mcp.Description("Part ID from gmail_list_attachments; useful when selecting among multiple attachments")),
-		mcp.WithString("output_path", mcp.Description("Optional local file path to write. Parent directories are created. If omitted, writes to a temporary gsuite-mcp-attachments directory.")),
-		mcp.WithString("output_dir", mcp.Description("Optional local directory to write the sanitized attachment filename into. Mutually exclusive with output_path.")),
+		mcp.WithString("output_path", mcp.Description("Optional local file path to write. Parent directories are created. Must be inside local_files.allowed_dirs when configured. If omitted, writes to a temporary gsuite-mcp-attachments directory.")),
+		mcp.WithString("output_dir", mcp.Description("Optional local directory to write the sanitized attachment filename into. Mutually exclusive with output_path. Must be inside local_files.allowed_dirs when configured.")),
 		mcp.WithBoolean("overwrite", mcp.Description("Replace an existing output file (default: false)")),
 		common.WithAccountParam(),
 	), HandleGmailDownloadAttachment)
@@ -450,4 +484,92 @@ func registerExtendedTools(s *server.MCPServer) {
 		mcp.WithNumber("end_time", mcp.Description("End time (Unix timestamp in ms)")),
 		common.WithAccountParam(),
 	), HandleGmailSetVacation)
+
+	// gmail_get_settings - Account settings snapshot
+	s.AddTool(mcp.NewTool("gmail_get_settings",
+		mcp.WithDescription("Get a snapshot of all Gmail account settings for auditing: vacation, auto-forwarding, forwarding addresses, IMAP, POP, language, send-as aliases, delegates, and filter count. Sections that cannot be read are listed under 'errors'."),
+		common.WithAccountParam(),
+	), HandleGmailGetSettings)
+
+	// === Forwarding ===
+
+	// gmail_list_forwarding_addresses - List forwarding addresses
+	s.AddTool(mcp.NewTool("gmail_list_forwarding_addresses",
+		mcp.WithDescription("List forwarding addresses and their verification status"),
+		common.WithAccountParam(),
+	), HandleGmailListForwardingAddresses)
+
+	// gmail_create_forwarding_address - Add forwarding address
+	s.AddTool(mcp.NewTool("gmail_create_forwarding_address",
+		mcp.WithDescription("Add a forwarding address. Google sends a confirmation email; the address is 'pending' until the recipient accepts it."),
+		mcp.WithString("forwarding_email", mcp.Required(), mcp.Description("Address to forward to")),
+		common.WithAccountParam(),
+	), HandleGmailCreateForwardingAddress)
+
+	// gmail_delete_forwarding_address - Remove forwarding address
+	s.AddTool(mcp.NewTool("gmail_delete_forwarding_address",
+		mcp.WithDescription("Remove a forwarding address"),
+		mcp.WithString("forwarding_email", mcp.Required(), mcp.Description("Forwarding address to remove")),
+		common.WithAccountParam(),
+	), HandleGmailDeleteForwardingAddress)
+
+	// gmail_get_auto_forwarding - Get auto-forwarding
+	s.AddTool(mcp.NewTool("gmail_get_auto_forwarding",
+		mcp.WithDescription("Get the auto-forwarding setting"),
+		common.WithAccountParam(),
+	), HandleGmailGetAutoForwarding)
+
+	// gmail_update_auto_forwarding - Update auto-forwarding
+	s.AddTool(mcp.NewTool("gmail_update_auto_forwarding",
+		mcp.WithDescription("Enable, disable, or change auto-forwarding. Omitted fields keep their current values. The target must be a verified (accepted) forwarding address."),
+		mcp.WithBoolean("enabled", mcp.Description("Enable or disable auto-forwarding")),
+		mcp.WithString("email_address", mcp.Description("Verified forwarding address to forward all mail to")),
+		mcp.WithString("disposition", mcp.Description("What to do with forwarded mail: leaveInInbox, archive, trash, or markRead")),
+		common.WithAccountParam(),
+	), HandleGmailUpdateAutoForwarding)
+
+	// === IMAP / POP / Language ===
+
+	// gmail_get_imap - Get IMAP settings
+	s.AddTool(mcp.NewTool("gmail_get_imap",
+		mcp.WithDescription("Get IMAP access settings"),
+		common.WithAccountParam(),
+	), HandleGmailGetImap)
+
+	// gmail_update_imap - Update IMAP settings
+	s.AddTool(mcp.NewTool("gmail_update_imap",
+		mcp.WithDescription("Update IMAP access settings. Omitted fields keep their current values."),
+		mcp.WithBoolean("enabled", mcp.Description("Enable or disable IMAP access")),
+		mcp.WithBoolean("auto_expunge", mcp.Description("Expunge immediately when a message is marked deleted")),
+		mcp.WithString("expunge_behavior", mcp.Description("What to do with expunged messages: archive, trash, or deleteForever")),
+		mcp.WithNumber("max_folder_size", mcp.Description("Maximum messages per IMAP folder (0 for no limit)")),
+		common.WithAccountParam(),
+	), HandleGmailUpdateImap)
+
+	// gmail_get_pop - Get POP settings
+	s.AddTool(mcp.NewTool("gmail_get_pop",
+		mcp.WithDescription("Get POP access settings"),
+		common.WithAccountParam(),
+	), HandleGmailGetPop)
+
+	// gmail_update_pop - Update POP settings
+	s.AddTool(mcp.NewTool("gmail_update_pop",
+		mcp.WithDescription("Update POP access settings. Omitted fields keep their current values."),
+		mcp.WithString("access_window", mcp.Description("Messages available over POP: disabled, fromNowOn, or allMail")),
+		mcp.WithString("disposition", mcp.Description("What to do after POP download: leaveInInbox, archive, trash, or markRead")),
+		common.WithAccountParam(),
+	), HandleGmailUpdatePop)
+
+	// gmail_get_language - Get display language
+	s.AddTool(mcp.NewTool("gmail_get_language",
+		mcp.WithDescription("Get the Gmail display language"),
+		common.WithAccountParam(),
+	), HandleGmailGetLanguage)
+
+	// gmail_update_language - Set display language
+	s.AddTool(mcp.NewTool("gmail_update_language",
+		mcp.WithDescription("Set the Gmail display language. Gmail may store a close supported variant; the stored value is returned."),
+		mcp.WithString("display_language", mcp.Required(), mcp.Description("RFC 3066 language tag, e.g. 'en-GB' or 'fr'")),
+		common.WithAccountParam(),
+	), HandleGmailUpdateLanguage)
 }
@@ -0,0 +1,148 @@
+package gmail
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/aliwatters/gsuite-mcp/internal/tasks"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+// TestableGmailAwaitingReply finds sent threads whose last message is ours and
+// older than the requested number of days, optionally labelling them and
+// creating follow-up tasks.
+func TestableGmailAwaitingReply(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	days := common.ParseIntArg(args, "days", awaitingReplyDefaultDays)
+	lookback := common.ParseIntArg(args, "lookback_days", awaitingReplyDefaultLookback)
+	if lookback <= days {
+		return mcp.NewToolResultError(fmt.Sprintf("lookback_days (%d) must be greater than days (%d)", lookback, days)), nil
+	}
+	maxResults := int(common.ParseMaxResults(args, common.GmailDefaultMaxResults, common.GmailMaxResultsLimit))
+	labelName := common.ParseStringArg(args, "label", "")
+	createTasks := common.ParseBoolArg(args, "create_tasks", false)
+
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	query := awaitingReplyQuery(days, lookback)
+	threadIDs, truncated, err := collectSentThreadIDs(ctx, svc, query)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -days)
+	var items []*AwaitingReplyThread
+	threads := make(map[string]*gmail.Thread)
+	var errors []string
+	email := common.ResolveAccountEmail(request, deps, DefaultGmailHandlerDeps)
+	for _, threadID := range threadIDs {
+		thread, err := svc.GetThread(ctx, threadID, "metadata")
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", threadID, err))
+			continue
+		}
+		if item := awaitingReplyFromThread(thread, cutoff, now); item != nil {
+			item.WebURL = GmailWebURL(threadID, email)
+			items = append(items, item)
+			threads[threadID] = thread
+		}
+	}
+	sortAwaitingReply(items)
+	total := len(items)
+	if len(items) > maxResults {
+		items = items[:maxResults]
+	}
+
+	result := map[string]any{
+		"query":        query,
+		"days":         days,
+		"scanned":      len(threadIDs),
+		"total":        total,
+		"scan_limited": truncated,
+	}
+
+	// Threads that already carry the follow-up label, or already have a
+	// follow-up task, were handled by an earlier run; they are reported but
+	// get no duplicate task.
+	alreadyLabelled := make(map[string]bool)
+	if labelName != "" && len(items) > 0 {
+		labelID, created, err := findOrCreateLabel(ctx, svc, labelName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+		}
+		result["label"] = map[string]any{"id": labelID, "name": labelName, "created": created}
+		for _, item := range items {
+			if threadHasLabel(threads[item.ThreadID], labelID) {
+				alreadyLabelled[item.ThreadID] = true
+				item.Labelled = true
+				continue
+			}
+			if _, err := svc.ModifyThread(ctx, item.ThreadID, &gmail.ModifyThreadRequest{AddLabelIds: []string{labelID}}); err != nil {
+				errors = append(errors, fmt.Sprintf("%s: label: %v", item.ThreadID, err))
+				continue
+			}
+			item.Labelled = true
+		}
+	}
+
+	if createTasks && len(items) > 0 {
+		tasksSvc, errResult, ok := tasks.ResolveTasksServiceOrError(ctx, request, FollowUpTasksHandlerDeps)
+		if !ok {
+			return errResult, nil
+		}
+		taskListID := common.ParseStringArg(args, "tasklist_id", common.DefaultTaskListID)
+		// Without a label, earlier runs are recognised by the thread link
+		// in the tasks they created.
+		existing, err := existingFollowUpLinks(ctx, tasksSvc, taskListID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Tasks API error: %v", err)), nil
+		}
+		for _, item := range items {
+			if alreadyLabelled[item.ThreadID] {
+				continue
+			}
+			if existing[followUpTaskLink(item)] {
+				item.ExistingTask = true
+				continue
+			}
+			task, err := tasksSvc.CreateTask(ctx, taskListID, followUpTask(item), nil)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s: task: %v", item.ThreadID, err))
+				continue
+			}
+			item.TaskID = task.Id
+		}
+		result["tasklist_id"] = taskListID
+	}
+
+	if items == nil {
+		items = []*AwaitingReplyThread{}
+	}
+	result["threads"] = items
+	result["count"] = len(items)
+	if len(errors) > 0 {
+		result["errors"] = errors
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// threadHasLabel reports whether any message in the thread carries labelID.
+func threadHasLabel(thread *gmail.Thread, labelID string) bool {
+	if thread == nil {
+		return false
+	}
+	for _, msg := range thread.Messages {
+		if hasAnyLabel(msg.LabelIds, labelID) {
+			return true
+		}
+	}
+	return false
+}
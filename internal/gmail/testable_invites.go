@@ -0,0 +1,124 @@
+package gmail
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aliwatters/gsuite-mcp/internal/calendar"
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	gcalendar "google.golang.org/api/calendar/v3"
+)
+
+// TestableGmailRSVP answers the calendar invitation carried by a message by
+// setting the user's responseStatus on the matching Calendar event.
+func TestableGmailRSVP(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	messageID, errResult := common.RequireStringArg(args, "message_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	responseArg, errResult := common.RequireStringArg(args, "response")
+	if errResult != nil {
+		return errResult, nil
+	}
+	response, err := calendar.ParseResponseStatus(responseArg)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	msg, err := svc.GetMessage(ctx, messageID, "full")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	invites, parseErrs := ExtractCalendarInvites(ctx, svc, msg)
+	invite := selectRSVPInvite(invites)
+	if invite == nil {
+		if len(parseErrs) > 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("message %s has no readable calendar invitation: %s", messageID, strings.Join(parseErrs, "; "))), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("message %s has no calendar invitation", messageID)), nil
+	}
+	if invite.Method == "CANCEL" || invite.Status == "CANCELLED" {
+		return mcp.NewToolResultError(fmt.Sprintf("invitation %q was cancelled by the organizer; nothing to respond to", invite.Summary)), nil
+	}
+
+	calSvc, errResult, ok := calendar.ResolveCalendarServiceOrError(ctx, request, InviteCalendarHandlerDeps)
+	if !ok {
+		return errResult, nil
+	}
+
+	calendarID := common.ParseStringArg(args, "calendar_id", common.DefaultCalendarID)
+	event, err := findInviteEvent(ctx, calSvc, calendarID, invite)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if event == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("no event with iCalUID %s found in calendar %s; the invitation may not have been added to the calendar yet", invite.UID, calendarID)), nil
+	}
+
+	var email string
+	if !slices.ContainsFunc(event.Attendees, func(a *gcalendar.EventAttendee) bool { return a.Self }) {
+		// Self is only populated on attendee lists read back from the API;
+		// fall back to matching the account's address.
+		profile, err := svc.GetProfile(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+		}
+		email = profile.EmailAddress
+	}
+
+	var comment *string
+	if c, ok := args["comment"].(string); ok {
+		comment = &c
+	}
+	resp, err := calendar.RespondToEvent(ctx, calSvc, calendarID, event, email, response, comment)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	updated := resp.Event
+
+	result := map[string]any{
+		"success":           true,
+		"message_id":        messageID,
+		"event_id":          updated.Id,
+		"calendar_id":       calendarID,
+		"ical_uid":          invite.UID,
+		"summary":           updated.Summary,
+		"response_status":   response,
+		"previous_response": resp.Previous,
+		"html_link":         updated.HtmlLink,
+	}
+	if invite.RecurrenceID != "" {
+		result["recurrence_id"] = invite.RecurrenceID
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// selectRSVPInvite picks the invitation gmail_rsvp should answer: the first
+// REQUEST, falling back to the first invite with a UID.
+func selectRSVPInvite(invites []CalendarInvite) *CalendarInvite {
+	var fallback *CalendarInvite
+	for i := range invites {
+		if invites[i].UID == "" {
+			continue
+		}
+		if invites[i].Method == "REQUEST" {
+			return &invites[i]
+		}
+		if fallback == nil {
+			fallback = &invites[i]
+		}
+	}
+	return fallback
+}
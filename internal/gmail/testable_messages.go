@@ -37,13 +37,16 @@ func TestableGmailSearch(ctx context.Context, request mcp.CallToolRequest, deps
 	type messageInfo struct {
 		ID       string `json:"id"`
 		ThreadID string `json:"thread_id"`
+		WebURL   string `json:"web_url"`
 	}
 
+	email := common.ResolveAccountEmail(request, deps, DefaultGmailHandlerDeps)
 	messages := make([]messageInfo, 0, len(resp.Messages))
 	for _, msg := range resp.Messages {
 		messages = append(messages, messageInfo{
 			ID:       msg.Id,
 			ThreadID: msg.ThreadId,
+			WebURL:   GmailWebURL(msg.Id, email),
 		})
 	}
 
@@ -76,6 +79,18 @@ func TestableGmailGetMessage(ctx context.Context, request mcp.CallToolRequest, d
 	}
 
 	result := FormatMessageWithOptions(msg, FormatMessageOptions{BodyFormat: parseBodyFormat(request.GetArguments())})
+	result["web_url"] = GmailWebURL(msg.Id, common.ResolveAccountEmail(request, deps, DefaultGmailHandlerDeps))
+
+	// Interpret text/calendar parts so invitations can be answered with gmail_rsvp.
+	invites, inviteErrs := ExtractCalendarInvites(ctx, svc, msg)
+	if len(invites) > 0 {
+		linkInvitesToCalendar(ctx, request, invites)
+		result["calendar_invites"] = invites
+	}
+	if len(inviteErrs) > 0 {
+		result["calendar_invite_errors"] = inviteErrs
+	}
+
 	return common.MarshalToolResult(result)
 }
 
@@ -446,7 +461,7 @@ func TestableGmailDownloadAttachment(ctx context.Context, request mcp.CallToolRe
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	if err := writeAttachmentFile(outputPath, data, common.ParseBoolArg(args, "overwrite", false)); err != nil {
+	if err := common.WriteLocalFile(outputPath, data, common.ParseBoolArg(args, "overwrite", false)); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
@@ -535,46 +550,23 @@ func resolveAttachmentOutputPath(args map[string]any, filename string) (string,
 	}
 
 	var path string
+	dirs := attachmentDirs
 	switch {
 	case outputPath != "":
 		path = outputPath
 	case outputDir != "":
 		path = filepath.Join(outputDir, filename)
 	default:
+		// The default location is chosen here, not by the caller.
 		path = filepath.Join(os.TempDir(), "gsuite-mcp-attachments", filename)
+		dirs = nil
 	}
 
-	expanded, err := expandUserPath(path)
+	resolved, err := common.ResolveAllowedLocalPath(path, dirs)
 	if err != nil {
-		return "", err
-	}
-	if strings.ContainsRune(expanded, 0) {
-		return "", fmt.Errorf("output path contains an invalid NUL byte")
-	}
-	if expanded == "" {
-		return "", fmt.Errorf("output path is required")
-	}
-	if !filepath.IsAbs(expanded) {
-		expanded, err = filepath.Abs(expanded)
-		if err != nil {
-			return "", fmt.Errorf("resolve output path: %w", err)
-		}
-	}
-	return filepath.Clean(expanded), nil
-}
-
-func expandUserPath(path string) (string, error) {
-	if path == "~" || strings.HasPrefix(path, "~/") {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("resolve home directory: %w", err)
-		}
-		if path == "~" {
-			return home, nil
-		}
-		return filepath.Join(home, strings.TrimPrefix(path, "~/")), nil
+		return "", fmt.Errorf("output %w", err)
 	}
-	return path, nil
+	return resolved, nil
 }
 
 func sanitizeAttachmentFilename(filename string, fallback string) string {
@@ -603,42 +595,6 @@ func sanitizeAttachmentFilename(filename string, fallback string) string {
 	return cleaned
 }
 
-func writeAttachmentFile(path string, data []byte, overwrite bool) error {
-	if info, err := os.Stat(path); err == nil && info.IsDir() {
-		return fmt.Errorf("output path %q is a directory", path)
-	} else if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("check output path %q: %w", path, err)
-	}
-
-	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
-		return fmt.Errorf("create output directory: %w", err)
-	}
-
-	flags := os.O_WRONLY | os.O_CREATE
-	if overwrite {
-		flags |= os.O_TRUNC
-	} else {
-		flags |= os.O_EXCL
-	}
-
-	file, err := os.OpenFile(path, flags, 0o600)
-	if os.IsExist(err) {
-		return fmt.Errorf("output file %q already exists; pass overwrite=true to replace it", path)
-	}
-	if err != nil {
-		return fmt.Errorf("open output file %q: %w", path, err)
-	}
-
-	if _, err := file.Write(data); err != nil {
-		_ = file.Close()
-		return fmt.Errorf("write output file %q: %w", path, err)
-	}
-	if err := file.Close(); err != nil {
-		return fmt.Errorf("close output file %q: %w", path, err)
-	}
-	return nil
-}
-
 // TestableGmailArchive archives a message.
 func TestableGmailArchive(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
 	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
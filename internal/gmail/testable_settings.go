@@ -3,6 +3,7 @@ package gmail
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/aliwatters/gsuite-mcp/internal/common"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -109,3 +110,468 @@ func TestableGmailSetVacation(ctx context.Context, request mcp.CallToolRequest,
 
 	return common.MarshalToolResult(result)
 }
+
+// === Forwarding ===
+
+// TestableGmailListForwardingAddresses lists forwarding addresses and their verification status.
+func TestableGmailListForwardingAddresses(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	addresses, err := svc.ListForwardingAddresses(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	return common.MarshalToolResult(map[string]any{
+		"forwarding_addresses": formatForwardingAddresses(addresses),
+		"count":                len(addresses),
+	})
+}
+
+// TestableGmailCreateForwardingAddress registers a forwarding address. Google
+// emails the address a confirmation link; it stays "pending" until accepted.
+func TestableGmailCreateForwardingAddress(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	email, errResult := common.RequireStringArg(request.GetArguments(), "forwarding_email")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	created, err := svc.CreateForwardingAddress(ctx, &gmail.ForwardingAddress{ForwardingEmail: email})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	result := formatForwardingAddress(created)
+	result["success"] = true
+	if created.VerificationStatus == "pending" {
+		result["note"] = "A confirmation email was sent to " + email + "; auto-forwarding can use it once accepted."
+	}
+
+	return common.MarshalToolResult(result)
+}
+
+// TestableGmailDeleteForwardingAddress removes a forwarding address.
+func TestableGmailDeleteForwardingAddress(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	email, errResult := common.RequireStringArg(request.GetArguments(), "forwarding_email")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	if err := svc.DeleteForwardingAddress(ctx, email); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	return common.MarshalToolResult(map[string]any{
+		"success":          true,
+		"forwarding_email": email,
+		"action":           "deleted",
+	})
+}
+
+// TestableGmailGetAutoForwarding gets the auto-forwarding setting.
+func TestableGmailGetAutoForwarding(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	settings, err := svc.GetAutoForwarding(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	return common.MarshalToolResult(formatAutoForwarding(settings))
+}
+
+// TestableGmailUpdateAutoForwarding updates auto-forwarding. Unset arguments
+// keep their current values. Enabling requires a verified forwarding address.
+func TestableGmailUpdateAutoForwarding(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	settings, err := svc.GetAutoForwarding(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	args := request.GetArguments()
+	if val, ok := args["enabled"].(bool); ok {
+		settings.Enabled = val
+	}
+	if val, ok := args["email_address"].(string); ok && val != "" {
+		settings.EmailAddress = val
+	}
+	if val, ok := args["disposition"].(string); ok && val != "" {
+		if !autoForwardingDispositions[val] {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid disposition %q: must be leaveInInbox, archive, trash, or markRead", val)), nil
+		}
+		settings.Disposition = val
+	}
+
+	if settings.Enabled {
+		if settings.EmailAddress == "" {
+			return mcp.NewToolResultError("email_address is required to enable auto-forwarding"), nil
+		}
+		addresses, err := svc.ListForwardingAddresses(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+		}
+		status := ""
+		for _, fa := range addresses {
+			if strings.EqualFold(fa.ForwardingEmail, settings.EmailAddress) {
+				status = fa.VerificationStatus
+				break
+			}
+		}
+		if status != "accepted" {
+			if status == "" {
+				status = "not registered"
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("forwarding address %s is %s; add it with gmail_create_forwarding_address and accept the confirmation email first", settings.EmailAddress, status)), nil
+		}
+		if settings.Disposition == "" {
+			settings.Disposition = "leaveInInbox"
+		}
+	}
+
+	updated, err := svc.UpdateAutoForwarding(ctx, settings)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	result := formatAutoForwarding(updated)
+	result["success"] = true
+	return common.MarshalToolResult(result)
+}
+
+// === IMAP / POP ===
+
+// TestableGmailGetImap gets IMAP access settings.
+func TestableGmailGetImap(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	settings, err := svc.GetImapSettings(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	return common.MarshalToolResult(formatImap(settings))
+}
+
+// TestableGmailUpdateImap updates IMAP access settings. Unset arguments keep their current values.
+func TestableGmailUpdateImap(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	settings, err := svc.GetImapSettings(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	args := request.GetArguments()
+	if val, ok := args["enabled"].(bool); ok {
+		settings.Enabled = val
+	}
+	if val, ok := args["auto_expunge"].(bool); ok {
+		settings.AutoExpunge = val
+	}
+	if val, ok := args["expunge_behavior"].(string); ok && val != "" {
+		if !imapExpungeBehaviors[val] {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid expunge_behavior %q: must be archive, trash, or deleteForever", val)), nil
+		}
+		settings.ExpungeBehavior = val
+	}
+	if val, ok := args["max_folder_size"].(float64); ok {
+		settings.MaxFolderSize = int64(val)
+	}
+
+	updated, err := svc.UpdateImapSettings(ctx, settings)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	result := formatImap(updated)
+	result["success"] = true
+	return common.MarshalToolResult(result)
+}
+
+// TestableGmailGetPop gets POP access settings.
+func TestableGmailGetPop(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	settings, err := svc.GetPopSettings(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	return common.MarshalToolResult(formatPop(settings))
+}
+
+// TestableGmailUpdatePop updates POP access settings. Unset arguments keep their current values.
+func TestableGmailUpdatePop(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	settings, err := svc.GetPopSettings(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	args := request.GetArguments()
+	if val, ok := args["access_window"].(string); ok && val != "" {
+		if !popAccessWindows[val] {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid access_window %q: must be disabled, fromNowOn, or allMail", val)), nil
+		}
+		settings.AccessWindow = val
+	}
+	if val, ok := args["disposition"].(string); ok && val != "" {
+		if !autoForwardingDispositions[val] {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid disposition %q: must be leaveInInbox, archive, trash, or markRead", val)), nil
+		}
+		settings.Disposition = val
+	}
+
+	updated, err := svc.UpdatePopSettings(ctx, settings)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	result := formatPop(updated)
+	result["success"] = true
+	return common.MarshalToolResult(result)
+}
+
+// === Language ===
+
+// TestableGmailGetLanguage gets the Gmail display language.
+func TestableGmailGetLanguage(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	settings, err := svc.GetLanguageSettings(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	return common.MarshalToolResult(map[string]any{"display_language": settings.DisplayLanguage})
+}
+
+// TestableGmailUpdateLanguage sets the Gmail display language. Gmail may
+// substitute a close supported variant, so the stored value is returned.
+func TestableGmailUpdateLanguage(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	language, errResult := common.RequireStringArg(request.GetArguments(), "display_language")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	updated, err := svc.UpdateLanguageSettings(ctx, &gmail.LanguageSettings{DisplayLanguage: language})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	return common.MarshalToolResult(map[string]any{
+		"success":          true,
+		"requested":        language,
+		"display_language": updated.DisplayLanguage,
+	})
+}
+
+// === Settings Snapshot ===
+
+// TestableGmailGetSettings returns every account setting in one response for
+// auditing. Sections that fail (e.g. delegates on consumer accounts) are
+// reported under "errors" instead of failing the whole snapshot.
+func TestableGmailGetSettings(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	result := map[string]any{}
+	errors := map[string]string{}
+	section := func(name string, fetch func() (any, error)) {
+		val, err := fetch()
+		if err != nil {
+			errors[name] = err.Error()
+			return
+		}
+		result[name] = val
+	}
+
+	section("email_address", func() (any, error) {
+		profile, err := svc.GetProfile(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return profile.EmailAddress, nil
+	})
+	section("vacation", func() (any, error) {
+		v, err := svc.GetVacationSettings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"enabled":              v.EnableAutoReply,
+			"subject":              v.ResponseSubject,
+			"restrict_to_contacts": v.RestrictToContacts,
+			"restrict_to_domain":   v.RestrictToDomain,
+			"start_time":           v.StartTime,
+			"end_time":             v.EndTime,
+		}, nil
+	})
+	section("auto_forwarding", func() (any, error) {
+		v, err := svc.GetAutoForwarding(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return formatAutoForwarding(v), nil
+	})
+	section("forwarding_addresses", func() (any, error) {
+		v, err := svc.ListForwardingAddresses(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return formatForwardingAddresses(v), nil
+	})
+	section("imap", func() (any, error) {
+		v, err := svc.GetImapSettings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return formatImap(v), nil
+	})
+	section("pop", func() (any, error) {
+		v, err := svc.GetPopSettings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return formatPop(v), nil
+	})
+	section("language", func() (any, error) {
+		v, err := svc.GetLanguageSettings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return v.DisplayLanguage, nil
+	})
+	section("send_as", func() (any, error) {
+		v, err := svc.ListSendAs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]map[string]any, 0, len(v))
+		for _, sa := range v {
+			out = append(out, formatSendAs(sa))
+		}
+		return out, nil
+	})
+	section("delegates", func() (any, error) {
+		v, err := svc.ListDelegates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]map[string]any, 0, len(v))
+		for _, d := range v {
+			out = append(out, formatDelegate(d))
+		}
+		return out, nil
+	})
+	section("filter_count", func() (any, error) {
+		v, err := svc.ListFilters(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return len(v.Filter), nil
+	})
+
+	if len(errors) > 0 {
+		result["errors"] = errors
+	}
+	return common.MarshalToolResult(result)
+}
+
+// === Settings Format Helpers ===
+
+var (
+	autoForwardingDispositions = map[string]bool{"leaveInInbox": true, "archive": true, "trash": true, "markRead": true}
+	imapExpungeBehaviors       = map[string]bool{"archive": true, "trash": true, "deleteForever": true}
+	popAccessWindows           = map[string]bool{"disabled": true, "fromNowOn": true, "allMail": true}
+)
+
+// formatForwardingAddress formats a ForwardingAddress for MCP output.
+func formatForwardingAddress(fa *gmail.ForwardingAddress) map[string]any {
+	return map[string]any{
+		"forwarding_email":    fa.ForwardingEmail,
+		"verification_status": fa.VerificationStatus,
+	}
+}
+
+func formatForwardingAddresses(addresses []*gmail.ForwardingAddress) []map[string]any {
+	out := make([]map[string]any, 0, len(addresses))
+	for _, fa := range addresses {
+		out = append(out, formatForwardingAddress(fa))
+	}
+	return out
+}
+
+// formatAutoForwarding formats AutoForwarding settings for MCP output.
+func formatAutoForwarding(af *gmail.AutoForwarding) map[string]any {
+	result := map[string]any{"enabled": af.Enabled}
+	if af.EmailAddress != "" {
+		result["email_address"] = af.EmailAddress
+	}
+	if af.Disposition != "" {
+		result["disposition"] = af.Disposition
+	}
+	return result
+}
+
+// formatImap formats ImapSettings for MCP output.
+func formatImap(s *gmail.ImapSettings) map[string]any {
+	return map[string]any{
+		"enabled":          s.Enabled,
+		"auto_expunge":     s.AutoExpunge,
+		"expunge_behavior": s.ExpungeBehavior,
+		"max_folder_size":  s.MaxFolderSize,
+	}
+}
+
+// formatPop formats PopSettings for MCP output.
+func formatPop(s *gmail.PopSettings) map[string]any {
+	return map[string]any{
+		"access_window": s.AccessWindow,
+		"disposition":   s.Disposition,
+	}
+}
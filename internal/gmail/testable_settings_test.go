@@ -0,0 +1,167 @@
+package gmail
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestTestableGmailForwarding(t *testing.T) {
+	fix := NewGmailTestFixtures()
+	ctx := context.Background()
+
+	fix.MockService.AddForwardingAddress(&gmail.ForwardingAddress{
+		ForwardingEmail:    "archive@example.com",
+		VerificationStatus: "accepted",
+	})
+
+	t.Run("CreateForwardingAddress", func(t *testing.T) {
+		req := common.CreateMCPRequest(map[string]any{"forwarding_email": "new@example.com"})
+		result, err := TestableGmailCreateForwardingAddress(ctx, req, fix.Deps)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.IsError {
+			t.Fatalf("unexpected tool error: %s", getTextResult(result))
+		}
+		resp := extractResponse(t, result)
+		if resp["verification_status"] != "pending" || resp["note"] == nil {
+			t.Errorf("expected pending status with confirmation note, got %v", resp)
+		}
+	})
+
+	t.Run("ListForwardingAddresses", func(t *testing.T) {
+		result, _ := TestableGmailListForwardingAddresses(ctx, common.CreateMCPRequest(map[string]any{}), fix.Deps)
+		if resp := extractResponse(t, result); resp["count"] != float64(2) {
+			t.Errorf("count = %v, want 2", resp["count"])
+		}
+	})
+
+	t.Run("EnableAutoForwarding_unverified", func(t *testing.T) {
+		req := common.CreateMCPRequest(map[string]any{"enabled": true, "email_address": "new@example.com"})
+		result, err := TestableGmailUpdateAutoForwarding(ctx, req, fix.Deps)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.IsError || !strings.Contains(getTextResult(result), "pending") {
+			t.Errorf("expected pending-verification error, got %s", getTextResult(result))
+		}
+		if fix.MockService.WasMethodCalled("UpdateAutoForwarding") {
+			t.Error("UpdateAutoForwarding should not be called for an unverified address")
+		}
+	})
+
+	t.Run("EnableAutoForwarding", func(t *testing.T) {
+		req := common.CreateMCPRequest(map[string]any{"enabled": true, "email_address": "archive@example.com"})
+		result, _ := TestableGmailUpdateAutoForwarding(ctx, req, fix.Deps)
+		if result.IsError {
+			t.Fatalf("unexpected tool error: %s", getTextResult(result))
+		}
+		af := fix.MockService.AutoForwarding
+		if !af.Enabled || af.EmailAddress != "archive@example.com" || af.Disposition != "leaveInInbox" {
+			t.Errorf("unexpected auto-forwarding: %+v", af)
+		}
+	})
+
+	t.Run("UpdateAutoForwarding_keeps_unset_fields", func(t *testing.T) {
+		req := common.CreateMCPRequest(map[string]any{"disposition": "archive"})
+		result, _ := TestableGmailUpdateAutoForwarding(ctx, req, fix.Deps)
+		if result.IsError {
+			t.Fatalf("unexpected tool error: %s", getTextResult(result))
+		}
+		af := fix.MockService.AutoForwarding
+		if !af.Enabled || af.EmailAddress != "archive@example.com" || af.Disposition != "archive" {
+			t.Errorf("unexpected auto-forwarding: %+v", af)
+		}
+	})
+
+	t.Run("DeleteForwardingAddress", func(t *testing.T) {
+		req := common.CreateMCPRequest(map[string]any{"forwarding_email": "new@example.com"})
+		result, _ := TestableGmailDeleteForwardingAddress(ctx, req, fix.Deps)
+		if result.IsError {
+			t.Fatalf("unexpected tool error: %s", getTextResult(result))
+		}
+		if _, ok := fix.MockService.ForwardingAddresses["new@example.com"]; ok {
+			t.Error("expected forwarding address to be removed")
+		}
+	})
+}
+
+func TestTestableGmailClientAccessAndLanguage(t *testing.T) {
+	fix := NewGmailTestFixtures()
+	ctx := context.Background()
+
+	t.Run("UpdateImap_merges", func(t *testing.T) {
+		req := common.CreateMCPRequest(map[string]any{"expunge_behavior": "trash"})
+		result, _ := TestableGmailUpdateImap(ctx, req, fix.Deps)
+		if result.IsError {
+			t.Fatalf("unexpected tool error: %s", getTextResult(result))
+		}
+		imap := fix.MockService.Imap
+		if !imap.Enabled || imap.ExpungeBehavior != "trash" {
+			t.Errorf("unexpected IMAP settings: %+v", imap)
+		}
+	})
+
+	t.Run("UpdateImap_invalid", func(t *testing.T) {
+		req := common.CreateMCPRequest(map[string]any{"expunge_behavior": "shred"})
+		result, _ := TestableGmailUpdateImap(ctx, req, fix.Deps)
+		if !result.IsError {
+			t.Error("expected error for invalid expunge_behavior")
+		}
+	})
+
+	t.Run("UpdatePop", func(t *testing.T) {
+		req := common.CreateMCPRequest(map[string]any{"access_window": "fromNowOn", "disposition": "archive"})
+		result, _ := TestableGmailUpdatePop(ctx, req, fix.Deps)
+		if result.IsError {
+			t.Fatalf("unexpected tool error: %s", getTextResult(result))
+		}
+		if pop := fix.MockService.Pop; pop.AccessWindow != "fromNowOn" || pop.Disposition != "archive" {
+			t.Errorf("unexpected POP settings: %+v", pop)
+		}
+	})
+
+	t.Run("UpdateLanguage", func(t *testing.T) {
+		req := common.CreateMCPRequest(map[string]any{"display_language": "fr"})
+		result, _ := TestableGmailUpdateLanguage(ctx, req, fix.Deps)
+		if resp := extractResponse(t, result); resp["display_language"] != "fr" {
+			t.Errorf("display_language = %v", resp["display_language"])
+		}
+	})
+}
+
+func TestTestableGmailGetSettings(t *testing.T) {
+	fix := NewGmailTestFixtures()
+	ctx := context.Background()
+	fix.MockService.AddForwardingAddress(&gmail.ForwardingAddress{ForwardingEmail: "archive@example.com", VerificationStatus: "accepted"})
+	fix.MockService.AddSendAs(&gmail.SendAs{SendAsEmail: "test@example.com", IsPrimary: true})
+
+	result, err := TestableGmailGetSettings(ctx, common.CreateMCPRequest(map[string]any{}), fix.Deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp := extractResponse(t, result)
+	for _, key := range []string{"email_address", "vacation", "auto_forwarding", "forwarding_addresses", "imap", "pop", "language", "send_as", "delegates", "filter_count"} {
+		if _, ok := resp[key]; !ok {
+			t.Errorf("missing section %q", key)
+		}
+	}
+	if _, ok := resp["errors"]; ok {
+		t.Errorf("unexpected errors: %v", resp["errors"])
+	}
+
+	// A failing API is reported per section rather than failing the snapshot.
+	fix.MockService.SetError("insufficient permissions")
+	result, _ = TestableGmailGetSettings(ctx, common.CreateMCPRequest(map[string]any{}), fix.Deps)
+	if result.IsError {
+		t.Fatal("snapshot should not fail as a whole")
+	}
+	errs := extractResponse(t, result)["errors"].(map[string]any)
+	if errs["delegates"] == nil || errs["imap"] == nil {
+		t.Errorf("expected per-section errors, got %v", errs)
+	}
+}
@@ -0,0 +1,69 @@
+package gmail
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aliwatters/gsuite-mcp/internal/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestableGmailStats aggregates mailbox volume and response-time statistics
+// over a search. Results are cached per account and query until the mailbox
+// history ID changes.
+func TestableGmailStats(ctx context.Context, request mcp.CallToolRequest, deps *GmailHandlerDeps) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	query := common.ParseStringArg(args, "query", "")
+	groups, err := parseStatsGroups(args["group_by"])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	maxMessages := common.ParseIntArg(args, "max_messages", statsDefaultMaxMessages)
+	if maxMessages > statsMaxMessagesLimit {
+		maxMessages = statsMaxMessagesLimit
+	}
+	topN := common.ParseIntArg(args, "top_n", statsDefaultTopN)
+	refresh := common.ParseBoolArg(args, "refresh", false)
+
+	svc, errResult, ok := ResolveGmailServiceOrError(ctx, request, deps)
+	if !ok {
+		return errResult, nil
+	}
+
+	profile, err := svc.GetProfile(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+	}
+
+	key := statsCacheKey(profile.EmailAddress, query, maxMessages)
+	snap := cachedStatsSnapshot(key, profile.HistoryId)
+	cached := snap != nil && !refresh
+	if !cached {
+		snap, err = fetchStatsSnapshot(ctx, svc, query, maxMessages, profile.EmailAddress)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Gmail API error: %v", err)), nil
+		}
+		snap.HistoryID = profile.HistoryId
+		// A partial snapshot is not cached, so the failed threads are
+		// retried on the next call.
+		if len(snap.Errors) == 0 {
+			storeStatsSnapshot(key, snap)
+		}
+	}
+
+	result := buildStatsResult(snap, groups, topN, time.Now())
+	result["query"] = query
+	result["messages_analyzed"] = len(snap.Messages)
+	result["threads_analyzed"] = snap.ThreadCount
+	result["truncated"] = snap.Truncated
+	result["max_messages"] = maxMessages
+	result["history_id"] = snap.HistoryID
+	result["cached"] = cached
+	result["fetched_at"] = snap.FetchedAt.UTC().Format(time.RFC3339)
+	if len(snap.Errors) > 0 {
+		result["errors"] = snap.Errors
+	}
+
+	return common.MarshalToolResult(result)
+}
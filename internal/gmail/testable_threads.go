@@ -36,6 +36,7 @@ func TestableGmailGetThread(ctx context.Context, request mcp.CallToolRequest, de
 
 	result := map[string]any{
 		"thread_id": thread.Id,
+		"web_url":   GmailWebURL(thread.Id, common.ResolveAccountEmail(request, deps, DefaultGmailHandlerDeps)),
 		"messages":  messages,
 		"count":     len(messages),
 	}
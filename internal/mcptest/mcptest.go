@@ -24,9 +24,9 @@ const (
 // ServiceToolCounts maps each service to its expected tool count.
 // Update these when adding/removing tools.
 var ServiceToolCounts = map[string]int{
-	"gmail":    53,
-	"calendar": 12,
-	"drive":    23,
+	"gmail":    68,
+	"calendar": 30,
+	"drive":    44,
 	"docs":     29,
 	"sheets":   16,
 	"slides":   5,
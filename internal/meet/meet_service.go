@@ -10,7 +10,8 @@ import (
 // This interface enables dependency injection and testing with mocks.
 type MeetService interface {
 	// ListConferenceRecords lists conference records visible to the authenticated user.
-	ListConferenceRecords(ctx context.Context, pageToken string, pageSize int64) ([]*meet.ConferenceRecord, string, error)
+	// A non-empty filter (e.g. `space.meeting_code = "abc-mnop-xyz"`) narrows the results.
+	ListConferenceRecords(ctx context.Context, filter string, pageToken string, pageSize int64) ([]*meet.ConferenceRecord, string, error)
 
 	// GetConferenceRecord retrieves a specific conference record by name.
 	GetConferenceRecord(ctx context.Context, name string) (*meet.ConferenceRecord, error)
@@ -42,8 +43,11 @@ func NewRealMeetService(service *meet.Service) *RealMeetService {
 const defaultPageSize = 25
 
 // ListConferenceRecords lists conference records.
-func (s *RealMeetService) ListConferenceRecords(ctx context.Context, pageToken string, pageSize int64) ([]*meet.ConferenceRecord, string, error) {
+func (s *RealMeetService) ListConferenceRecords(ctx context.Context, filter string, pageToken string, pageSize int64) ([]*meet.ConferenceRecord, string, error) {
 	call := s.service.ConferenceRecords.List().Context(ctx)
+	if filter != "" {
+		call = call.Filter(filter)
+	}
 	if pageToken != "" {
 		call = call.PageToken(pageToken)
 	}
@@ -126,7 +126,7 @@ func NewMockMeetService() *MockMeetService {
 }
 
 // ListConferenceRecords lists mock conference records.
-func (m *MockMeetService) ListConferenceRecords(ctx context.Context, pageToken string, pageSize int64) ([]*meet.ConferenceRecord, string, error) {
+func (m *MockMeetService) ListConferenceRecords(ctx context.Context, filter string, pageToken string, pageSize int64) ([]*meet.ConferenceRecord, string, error) {
 	m.Calls.ListConferenceRecords = append(m.Calls.ListConferenceRecords, pageToken)
 
 	if m.Errors.ListConferenceRecords != nil {
@@ -30,7 +30,7 @@ func TestableListConferenceRecords(ctx context.Context, request mcp.CallToolRequ
 	pageToken := common.ParseStringArg(request.GetArguments(), "page_token", "")
 	pageSize := common.ParseMaxResults(request.GetArguments(), defaultPageSize, 100)
 
-	records, nextPageToken, err := srv.ListConferenceRecords(ctx, pageToken, pageSize)
+	records, nextPageToken, err := srv.ListConferenceRecords(ctx, "", pageToken, pageSize)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Meet API error: %v", err)), nil
 	}
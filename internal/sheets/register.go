@@ -57,7 +57,7 @@ func registerSheetsCoreTools(s *server.MCPServer) {
 		mcp.WithDescription("Create a new Google Sheets spreadsheet."),
 		mcp.WithString("title", mcp.Required(), mcp.Description("Title for the new spreadsheet")),
 		common.WithAccountParam(),
-	), HandleSheetsCreate)
+	), common.WithDriveRootCreateCheck(HandleSheetsCreate))
 
 	// sheets_batch_read - Read multiple ranges at once
 	s.AddTool(mcp.NewTool("sheets_batch_read",
@@ -40,7 +40,7 @@ func RegisterTools(s *server.MCPServer) {
 		mcp.WithDescription("Create a new Google Slides presentation with the given title."),
 		mcp.WithString("title", mcp.Required(), mcp.Description("Presentation title")),
 		common.WithAccountParam(),
-	), HandleSlidesCreate)
+	), common.WithDriveRootCreateCheck(HandleSlidesCreate))
 
 	// slides_batch_update - Batch update presentation
 	s.AddTool(mcp.NewTool("slides_batch_update",